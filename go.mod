@@ -16,6 +16,7 @@ require (
 	go.mongodb.org/mongo-driver/v2 v2.2.2
 	go.uber.org/atomic v1.7.0
 	go.uber.org/mock v0.5.2
+	golang.org/x/net v0.40.0
 	golang.org/x/sync v0.14.0
 )
 
@@ -72,7 +73,7 @@ require (
 	go.opentelemetry.io/otel/sdk v1.36.0 // indirect
 	go.opentelemetry.io/otel/trace v1.36.0 // indirect
 	go.opentelemetry.io/proto/otlp v1.7.0 // indirect
-	golang.org/x/crypto v0.37.0 // indirect
+	golang.org/x/crypto v0.38.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/text v0.25.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250603155806-513f23925822 // indirect
@@ -3,6 +3,7 @@ package adapter
 import (
 	"context"
 	"github/michaellimmm/turakkingu/internal/adapter/api"
+	"github/michaellimmm/turakkingu/internal/adapter/job"
 	"github/michaellimmm/turakkingu/internal/adapter/web"
 	"github/michaellimmm/turakkingu/internal/core"
 	"github/michaellimmm/turakkingu/internal/usecase"
@@ -18,14 +19,17 @@ type AdapterCloser interface {
 type adapter struct {
 	api api.API
 	web web.Web
+	job job.Job
 }
 
 func NewAdapter(config *core.Config, uc usecase.UseCase) AdapterCloser {
 	api := api.NewApi(config, uc)
 	web := web.NewWeb(config, uc)
+	job := job.NewJob(config, uc)
 	return &adapter{
 		api: api,
 		web: web,
+		job: job,
 	}
 }
 
@@ -36,11 +40,14 @@ func (a *adapter) Run() error {
 
 	eg.Go(a.web.Run)
 
+	eg.Go(a.job.Run)
+
 	return eg.Wait()
 }
 
 func (a *adapter) Close(ctx context.Context) error {
 	_ = a.api.Close(ctx)
 	_ = a.web.Close(ctx)
+	_ = a.job.Close(ctx)
 	return nil
 }
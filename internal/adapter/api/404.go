@@ -1,6 +1,9 @@
 package api
 
 import (
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"html"
 	"html/template"
 	"net/http"
 )
@@ -15,3 +18,51 @@ func render404(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNotFound)
 	_ = tmpl.Execute(w, nil)
 }
+
+// renderExpired is the page of an inactive link without fallback url
+func renderExpired(w http.ResponseWriter, reason entity.LinkInactiveReason, message string) {
+	tmpl, err := template.ParseFiles("./web/expired.html")
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusGone
+	if reason == entity.LinkInactiveReasonNotStarted {
+		status = http.StatusNotFound
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = tmpl.Execute(w, struct {
+		Reason  entity.LinkInactiveReason
+		Message string
+	}{Reason: reason, Message: message})
+}
+
+// renderInterstitial loads the scripts of the link, then forwards to the destination with a meta refresh
+func renderInterstitial(w http.ResponseWriter, destination string, interstitial *entity.Interstitial) {
+	// a javascript: or data: destination would run in the page of the link domain
+	if !entity.IsWebURL(destination) {
+		render404(w)
+		return
+	}
+
+	tmpl, err := template.ParseFiles("./web/interstitial.html")
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	// html/template doesn't fill the content of a meta refresh
+	refresh := template.HTMLAttr(fmt.Sprintf(`content="%d;url=%s"`, interstitial.Delay, html.EscapeString(destination)))
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_ = tmpl.Execute(w, struct {
+		Refresh     template.HTMLAttr
+		Destination string
+		Delay       int
+		Scripts     []string
+	}{Refresh: refresh, Destination: destination, Delay: interstitial.Delay, Scripts: interstitial.Scripts})
+}
@@ -5,8 +5,6 @@ import (
 	"github/michaellimmm/turakkingu/internal/core"
 	"github/michaellimmm/turakkingu/internal/usecase"
 	"net/http"
-
-	"github.com/rs/cors"
 )
 
 type API interface {
@@ -22,15 +20,30 @@ func NewApi(config *core.Config, uc usecase.UseCase) API {
 	linkApi := NewLinkAPI(config, uc)
 	trackingAPI := NewTrackAPI(config, uc)
 	trackingSettingAPI := NewTrackingSettingAPI(config, uc)
+	identityAPI := NewIdentityAPI(config, uc)
+	privacyAPI := NewPrivacyAPI(config, uc)
+	visitorAPI := NewVisitorAPI(config, uc)
+	linkCampaignAPI := NewLinkCampaignAPI(config, uc)
+	reportAPI := NewReportAPI(config, uc)
+	funnelAPI := NewFunnelAPI(config, uc)
+	metricsAPI := NewMetricsAPI(config, uc)
 
 	router := &router{
 		linkAPI:            linkApi,
 		trackingAPI:        trackingAPI,
 		trackingSettingAPI: trackingSettingAPI,
+		identityAPI:        identityAPI,
+		privacyAPI:         privacyAPI,
+		visitorAPI:         visitorAPI,
+		linkCampaignAPI:    linkCampaignAPI,
+		reportAPI:          reportAPI,
+		funnelAPI:          funnelAPI,
+		metricsAPI:         metricsAPI,
+		originPolicy:       newOriginPolicy(config, uc),
 	}
 	server := &http.Server{
 		Addr:    config.HttpPort,
-		Handler: cors.Default().Handler(router.Mux()),
+		Handler: router.Handler(),
 	}
 
 	return &api{
@@ -54,6 +67,27 @@ type router struct {
 	linkAPI            *linkAPI
 	trackingAPI        *trackAPI
 	trackingSettingAPI *trackingSettingAPI
+	identityAPI        *identityAPI
+	privacyAPI         *privacyAPI
+	visitorAPI         *visitorAPI
+	linkCampaignAPI    *linkCampaignAPI
+	reportAPI          *reportAPI
+	funnelAPI          *funnelAPI
+	metricsAPI         *metricsAPI
+	originPolicy       *originPolicy
+}
+
+func (r *router) Handler() http.Handler {
+	mux := r.Mux()
+
+	handler := http.NewServeMux()
+	handler.Handle("/v1/tracks/events", r.originPolicy.ingestion(mux))
+	handler.Handle("/v1/tracking-settings/{id}/script-config", r.originPolicy.ingestion(mux))
+	handler.Handle("/v1/visitor/", r.originPolicy.visitor(mux))
+	handler.Handle("/v1/", r.originPolicy.management(mux))
+	// redirects and static files are not called with fetch
+	handler.Handle("/", mux)
+	return handler
 }
 
 func (r *router) Mux() *http.ServeMux {
@@ -62,13 +96,63 @@ func (r *router) Mux() *http.ServeMux {
 	mux.Handle("/static/", http.StripPrefix("/static/", fs))
 
 	mux.HandleFunc("POST /v1/links", r.linkAPI.CreateLink)
-	mux.HandleFunc("GET /r/{id}", r.linkAPI.Redirect)
+	mux.HandleFunc("GET /v1/tenants/{tenant_id}/links", r.linkAPI.SearchLinks)
+	mux.HandleFunc("POST /v1/tenants/{tenant_id}/links/import", r.linkAPI.ImportLinks)
+	mux.HandleFunc("GET /v1/tenants/{tenant_id}/links/export", r.linkAPI.ExportLinks)
+	mux.HandleFunc("GET /r/{id}", r.metricsAPI.observeRedirect(r.linkAPI.Redirect))
+	mux.HandleFunc("GET /{slug}", r.metricsAPI.observeRedirect(r.linkAPI.RedirectBranded))
+	mux.HandleFunc("GET /v1/links/{id}/clicks/breakdown", r.linkAPI.GetClickBreakdown)
+	mux.HandleFunc("GET /v1/links/{id}/experiment", r.linkAPI.GetExperimentReport)
+	mux.HandleFunc("PUT /v1/links/{id}/lifecycle", r.linkAPI.UpdateLifecycle)
+	mux.HandleFunc("GET /v1/links/{id}/qr", r.linkAPI.GetQR)
+	mux.HandleFunc("PUT /v1/links/{id}/rules", r.linkAPI.UpdateRules)
+	mux.HandleFunc("PUT /v1/links/{id}/redirect", r.linkAPI.UpdateRedirect)
+	mux.HandleFunc("POST /v1/links/{id}/rules/dry-run", r.linkAPI.DryRunRules)
+	mux.HandleFunc("PUT /v1/links/{id}/tags", r.linkAPI.UpdateTags)
+
+	mux.HandleFunc("POST /v1/tenants/{tenant_id}/campaigns", r.linkCampaignAPI.CreateCampaign)
+	mux.HandleFunc("GET /v1/tenants/{tenant_id}/campaigns", r.linkCampaignAPI.GetCampaigns)
+	mux.HandleFunc("GET /v1/tenants/{tenant_id}/campaigns/report", r.linkCampaignAPI.GetReport)
+	mux.HandleFunc("PUT /v1/tenants/{tenant_id}/campaigns/links", r.linkCampaignAPI.AssignLinks)
+	mux.HandleFunc("PUT /v1/campaigns/{id}", r.linkCampaignAPI.UpdateCampaign)
+	mux.HandleFunc("DELETE /v1/campaigns/{id}", r.linkCampaignAPI.DeleteCampaign)
 
 	mux.HandleFunc("GET /v1/tenants/{tenant_id}/tracking-settings", r.trackingSettingAPI.GetTrackingSetting)
 	mux.HandleFunc("POST /v1/tracking-settings/pages", r.trackingSettingAPI.AddThankYouPage)
+	mux.HandleFunc("PUT /v1/tenants/{tenant_id}/tracking-settings/retention", r.trackingSettingAPI.UpdateRetentionPolicy)
+	mux.HandleFunc("PUT /v1/tenants/{tenant_id}/tracking-settings/consent", r.trackingSettingAPI.UpdateConsentPolicy)
+	mux.HandleFunc("PUT /v1/tenants/{tenant_id}/tracking-settings/domains", r.trackingSettingAPI.UpdateDomains)
+	mux.HandleFunc("PUT /v1/tenants/{tenant_id}/tracking-settings/channel-rules", r.trackingSettingAPI.UpdateChannelRules)
+	mux.HandleFunc("PUT /v1/tenants/{tenant_id}/tracking-settings/bots", r.trackingSettingAPI.UpdateBotPolicy)
+	mux.HandleFunc("PUT /v1/tenants/{tenant_id}/tracking-settings/link-domains", r.trackingSettingAPI.UpdateLinkDomains)
+	mux.HandleFunc("PUT /v1/tenants/{tenant_id}/tracking-settings/time-zone", r.trackingSettingAPI.UpdateTimeZone)
+	mux.HandleFunc("GET /v1/tracking-settings/{id}/script-config", r.trackingSettingAPI.GetScriptConfig)
 
 	mux.HandleFunc("POST /v1/tracks", r.trackingAPI.CreateTrack)
 	mux.HandleFunc("POST /v1/tracks/events", r.trackingAPI.TrackEvent)
+	mux.HandleFunc("GET /v1/tenants/{tenant_id}/tracks/breakdown", r.trackingAPI.GetBreakdown)
+
+	mux.HandleFunc("GET /v1/tenants/{tenant_id}/reports", r.reportAPI.GetReport)
+
+	mux.HandleFunc("POST /v1/tenants/{tenant_id}/funnels", r.funnelAPI.CreateFunnel)
+	mux.HandleFunc("GET /v1/tenants/{tenant_id}/funnels", r.funnelAPI.GetFunnels)
+	mux.HandleFunc("PUT /v1/funnels/{id}", r.funnelAPI.UpdateFunnel)
+	mux.HandleFunc("DELETE /v1/funnels/{id}", r.funnelAPI.DeleteFunnel)
+	mux.HandleFunc("GET /v1/funnels/{id}/report", r.funnelAPI.GetReport)
+
+	mux.HandleFunc("GET /v1/tenants/{tenant_id}/identities", r.identityAPI.FindIdentities)
+	mux.HandleFunc("GET /v1/tenants/{tenant_id}/identities/{id}", r.identityAPI.GetIdentity)
+	mux.HandleFunc("POST /v1/tenants/{tenant_id}/identities/{id}/unmerge", r.identityAPI.UnmergeIdentity)
+
+	mux.HandleFunc("GET /v1/visitor/identity", r.visitorAPI.GetIdentity)
+	mux.HandleFunc("POST /v1/visitor/identity", r.visitorAPI.SetIdentity)
+	mux.HandleFunc("DELETE /v1/visitor/identity", r.visitorAPI.DeleteIdentity)
+
+	mux.HandleFunc("POST /v1/tenants/{tenant_id}/privacy/exports", r.privacyAPI.Export)
+	mux.HandleFunc("POST /v1/tenants/{tenant_id}/privacy/erasures", r.privacyAPI.Erase)
+	mux.HandleFunc("GET /v1/tenants/{tenant_id}/privacy/requests", r.privacyAPI.GetRequests)
+
+	mux.HandleFunc("GET /metrics", r.metricsAPI.GetMetrics)
 
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/" {
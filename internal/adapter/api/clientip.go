@@ -0,0 +1,79 @@
+package api
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// clientIPResolver takes the client ip from X-Forwarded-For only when the request comes through a trusted proxy
+type clientIPResolver struct {
+	trustedProxies []netip.Prefix
+}
+
+func newClientIPResolver(trustedProxies []string) *clientIPResolver {
+	prefixes := []netip.Prefix{}
+	for _, proxy := range trustedProxies {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(proxy)
+			if addrErr != nil {
+				slog.Warn("invalid trusted proxy", slog.String("proxy", proxy), slog.String("error", err.Error()))
+				continue
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return &clientIPResolver{trustedProxies: prefixes}
+}
+
+func (c *clientIPResolver) isTrusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range c.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromRequest walks X-Forwarded-For from the right, the first untrusted hop is the client
+func (c *clientIPResolver) FromRequest(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	remote, err := netip.ParseAddr(host)
+	if err != nil {
+		return ""
+	}
+
+	if !c.isTrusted(remote) {
+		return remote.Unmap().String()
+	}
+
+	hops := []string{}
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+
+		client = addr
+		if !c.isTrusted(addr) {
+			break
+		}
+	}
+
+	return client.Unmap().String()
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/rs/cors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const originCacheSize = 10000
+
+// originPolicy decides which browser origins can call the api.
+// Ingestion routes are called by conversion.js from the tenant sites, so only origins on the tenant
+// domains are allowed. Management routes are only called by the console.
+type originPolicy struct {
+	uc     usecase.UseCase
+	config *core.Config
+
+	mu    sync.Mutex
+	cache map[string]originCacheEntry
+}
+
+type originCacheEntry struct {
+	allowed  bool
+	expireAt time.Time
+}
+
+func newOriginPolicy(config *core.Config, uc usecase.UseCase) *originPolicy {
+	return &originPolicy{
+		uc:     uc,
+		config: config,
+		cache:  map[string]originCacheEntry{},
+	}
+}
+
+func (p *originPolicy) ingestion(next http.Handler) http.Handler {
+	return p.handler("ingestion", p.isTenantOrigin, cors.Options{
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders: []string{"Content-Type"},
+	}, next)
+}
+
+// visitor routes work with cookies, so they need credentials and are restricted to the site of the CNAME
+func (p *originPolicy) visitor(next http.Handler) http.Handler {
+	return p.handler("visitor", func(r *http.Request, origin string) bool {
+		return isSameSite(r, origin) && p.isTenantOrigin(r, origin)
+	}, cors.Options{
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost, http.MethodDelete},
+		AllowedHeaders:   []string{"Content-Type"},
+		AllowCredentials: true,
+	}, next)
+}
+
+func (p *originPolicy) management(next http.Handler) http.Handler {
+	return p.handler("management", func(r *http.Request, origin string) bool {
+		return origin == p.config.ConsoleOrigin
+	}, cors.Options{
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+		AllowedHeaders: []string{"Content-Type"},
+	}, next)
+}
+
+func (p *originPolicy) handler(route string, allow func(r *http.Request, origin string) bool,
+	options cors.Options, next http.Handler) http.Handler {
+	options.AllowOriginVaryRequestFunc = func(r *http.Request, origin string) (bool, []string) {
+		allowed := allow(r, origin)
+		if !allowed && r.Method == http.MethodOptions {
+			logCorsViolation(route, r, origin)
+		}
+		return allowed, nil
+	}
+
+	// cors only omits the headers for a denied origin, the request itself has to be refused
+	return cors.New(options).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && !allow(r, origin) {
+			logCorsViolation(route, r, origin)
+			_ = sendError(w, http.StatusForbidden, fmt.Errorf("origin is not allowed"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// isTenantOrigin checks the origin against the tracking setting of the request (tracking_id query
+// param or {id} path value), or against every tenant when the request has none
+func (p *originPolicy) isTenantOrigin(r *http.Request, origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+
+	trackingID := r.URL.Query().Get("tracking_id")
+	if trackingID == "" {
+		trackingID = r.PathValue("id")
+	}
+
+	var trackingSettingID *bson.ObjectID
+	if trackingID != "" {
+		id, err := bson.ObjectIDFromHex(trackingID)
+		if err != nil {
+			return false
+		}
+		trackingSettingID = &id
+	}
+
+	key := trackingID + "|" + u.Hostname()
+	if allowed, ok := p.cached(key); ok {
+		return allowed
+	}
+
+	allowed, err := p.uc.IsOriginAllowed(r.Context(), trackingSettingID, u.Hostname())
+	if err != nil {
+		// don't cache failures
+		return false
+	}
+
+	p.store(key, allowed)
+	return allowed
+}
+
+func (p *originPolicy) cached(key string) (bool, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.cache[key]
+	if !ok || time.Now().After(entry.expireAt) {
+		return false, false
+	}
+	return entry.allowed, true
+}
+
+func (p *originPolicy) store(key string, allowed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.cache) >= originCacheSize {
+		p.cache = map[string]originCacheEntry{}
+	}
+	p.cache[key] = originCacheEntry{allowed: allowed, expireAt: time.Now().Add(p.config.CorsCacheTTL)}
+}
+
+func logCorsViolation(route string, r *http.Request, origin string) {
+	slog.Warn("security event",
+		slog.String("event", "cors_violation"),
+		slog.String("route", route),
+		slog.String("origin", origin),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("tracking_id", r.URL.Query().Get("tracking_id")),
+		slog.String("remote_addr", r.RemoteAddr),
+	)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// originUseCase allows the hosts of its list, for any tracking setting
+type originUseCase struct {
+	usecase.UseCase
+	hosts map[string]bool
+	err   error
+	calls int
+}
+
+func (uc *originUseCase) IsOriginAllowed(_ context.Context, _ *bson.ObjectID, host string) (bool, error) {
+	uc.calls++
+	return uc.hosts[host], uc.err
+}
+
+func newTestOriginPolicy(uc *originUseCase) *originPolicy {
+	config := &core.Config{ConsoleOrigin: "http://localhost:8083", CorsCacheTTL: time.Minute}
+	return newOriginPolicy(config, uc)
+}
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func TestOriginPolicy(t *testing.T) {
+	trackingID := bson.NewObjectID().Hex()
+
+	testcases := []struct {
+		name   string
+		route  string // ingestion, visitor or management
+		method string
+		host   string
+		target string
+		origin string
+		status int
+		allow  string // Access-Control-Allow-Origin
+	}{
+		{"ingestion from a tenant site", "ingestion", http.MethodPost, "t.dealer.com", "/v1/events?tracking_id=" + trackingID,
+			"https://www.dealer.com", http.StatusOK, "https://www.dealer.com"},
+		{"ingestion from another site", "ingestion", http.MethodPost, "t.dealer.com", "/v1/events?tracking_id=" + trackingID,
+			"https://evil.com", http.StatusForbidden, ""},
+		{"ingestion with an invalid tracking id", "ingestion", http.MethodPost, "t.dealer.com", "/v1/events?tracking_id=x",
+			"https://www.dealer.com", http.StatusForbidden, ""},
+		{"ingestion without origin", "ingestion", http.MethodPost, "t.dealer.com", "/v1/events",
+			"", http.StatusOK, ""},
+		{"denied preflight", "ingestion", http.MethodOptions, "t.dealer.com", "/v1/events",
+			"https://evil.com", http.StatusNoContent, ""},
+		{"visitor from the site of the host", "visitor", http.MethodGet, "t.dealer.com", "/v1/visitor/identity",
+			"https://www.dealer.com", http.StatusOK, "https://www.dealer.com"},
+		{"visitor from a tenant site of another host", "visitor", http.MethodGet, "tracker.local", "/v1/visitor/identity",
+			"https://www.dealer.com", http.StatusForbidden, ""},
+		{"management from the console", "management", http.MethodGet, "localhost:8080", "/v1/tenants/tenant1/links",
+			"http://localhost:8083", http.StatusOK, "http://localhost:8083"},
+		{"management from a tenant site", "management", http.MethodGet, "localhost:8080", "/v1/tenants/tenant1/links",
+			"https://www.dealer.com", http.StatusForbidden, ""},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			policy := newTestOriginPolicy(&originUseCase{hosts: map[string]bool{"www.dealer.com": true}})
+			handler := map[string]http.Handler{
+				"ingestion":  policy.ingestion(okHandler),
+				"visitor":    policy.visitor(okHandler),
+				"management": policy.management(okHandler),
+			}[tcase.route]
+
+			r := httptest.NewRequest(tcase.method, tcase.target, nil)
+			r.Host = tcase.host
+			if tcase.origin != "" {
+				r.Header.Set("Origin", tcase.origin)
+			}
+			if tcase.method == http.MethodOptions {
+				r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tcase.status, w.Code)
+			assert.Equal(t, tcase.allow, w.Header().Get("Access-Control-Allow-Origin"))
+		})
+	}
+}
+
+func TestOriginPolicy_Cache(t *testing.T) {
+	origin := "https://www.dealer.com"
+	request := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/v1/events", nil)
+		r.Header.Set("Origin", origin)
+		return r
+	}
+
+	t.Run("should cache the decisions", func(t *testing.T) {
+		uc := &originUseCase{hosts: map[string]bool{"www.dealer.com": true}}
+		policy := newTestOriginPolicy(uc)
+
+		for range 3 {
+			assert.True(t, policy.isTenantOrigin(request(), origin))
+		}
+		assert.Equal(t, 1, uc.calls)
+	})
+
+	t.Run("should not cache the failures", func(t *testing.T) {
+		uc := &originUseCase{hosts: map[string]bool{"www.dealer.com": true}, err: errors.New("connection refused")}
+		policy := newTestOriginPolicy(uc)
+
+		assert.False(t, policy.isTenantOrigin(request(), origin))
+		uc.err = nil
+		assert.True(t, policy.isTenantOrigin(request(), origin))
+		assert.Equal(t, 2, uc.calls)
+	})
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// defaultFunnelWindowHours is the conversion window of a funnel that doesn't set one
+const defaultFunnelWindowHours = 7 * 24
+
+type funnelAPI struct {
+	uc     usecase.UseCase
+	config *core.Config
+}
+
+func NewFunnelAPI(config *core.Config, uc usecase.UseCase) *funnelAPI {
+	return &funnelAPI{config: config, uc: uc}
+}
+
+type FunnelRequest struct {
+	Name        string               `json:"name"`
+	Steps       []*entity.FunnelStep `json:"steps"`
+	WindowHours int                  `json:"window_hours"` // 7 days without it
+}
+
+func (f *FunnelRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(f)
+}
+
+func (f *FunnelRequest) Validate() error {
+	funnel := f.ToEntity("")
+	return funnel.Validate()
+}
+
+func (f *FunnelRequest) ToEntity(tenantID string) *entity.Funnel {
+	funnel := &entity.Funnel{
+		TenantID:    tenantID,
+		Name:        f.Name,
+		Steps:       f.Steps,
+		WindowHours: f.WindowHours,
+	}
+	if funnel.WindowHours == 0 {
+		funnel.WindowHours = defaultFunnelWindowHours
+	}
+	return funnel
+}
+
+type FunnelsResponse struct {
+	Funnels []*entity.Funnel `json:"funnels"`
+}
+
+func (f *funnelAPI) CreateFunnel(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &FunnelRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	funnel := req.ToEntity(tenantID)
+	if err := f.uc.CreateFunnel(r.Context(), funnel); err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to create funnel"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusCreated, funnel)
+}
+
+func (f *funnelAPI) GetFunnels(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	funnels, err := f.uc.GetFunnels(r.Context(), tenantID)
+	if err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to get funnels"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, FunnelsResponse{Funnels: funnels})
+}
+
+func (f *funnelAPI) UpdateFunnel(w http.ResponseWriter, r *http.Request) {
+	req := &FunnelRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	funnel, err := f.uc.UpdateFunnel(r.Context(), r.PathValue("id"), req.ToEntity(""))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("funnel not found"))
+		return
+	} else if err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update funnel"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, funnel)
+}
+
+func (f *funnelAPI) DeleteFunnel(w http.ResponseWriter, r *http.Request) {
+	err := f.uc.DeleteFunnel(r.Context(), r.PathValue("id"))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("funnel not found"))
+		return
+	} else if err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to delete funnel"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type FunnelReportRequest struct {
+	Dimension string // link or channel, no split without it
+	From      string // 2006-01-02 or RFC 3339, inclusive
+	To        string // 2006-01-02 or RFC 3339, exclusive
+}
+
+func (r *FunnelReportRequest) FromQuery(query url.Values) {
+	r.Dimension = query.Get("dimension")
+	r.From = query.Get("from")
+	r.To = query.Get("to")
+}
+
+func (r *FunnelReportRequest) Validate() error {
+	if r.Dimension != "" && !entity.FunnelDimension(r.Dimension).IsValid() {
+		return fmt.Errorf("dimension must be link or channel")
+	}
+
+	if _, err := core.ParseTime(r.From); err != nil {
+		return fmt.Errorf("from is not valid")
+	}
+
+	if _, err := core.ParseTime(r.To); err != nil {
+		return fmt.Errorf("to is not valid")
+	}
+
+	return nil
+}
+
+func (f *funnelAPI) GetReport(w http.ResponseWriter, r *http.Request) {
+	req := &FunnelReportRequest{}
+	req.FromQuery(r.URL.Query())
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	from, _ := core.ParseTime(req.From)
+	to, _ := core.ParseTime(req.To)
+	report, err := f.uc.GetFunnelReport(r.Context(), r.PathValue("id"), entity.FunnelDimension(req.Dimension), from, to)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("funnel not found"))
+		return
+	} else if errors.Is(err, usecase.ErrInvalidFunnelQuery) {
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to get report"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, report)
+}
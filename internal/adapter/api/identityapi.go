@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type identityAPI struct {
+	uc     usecase.UseCase
+	config *core.Config
+}
+
+type UnmergeIdentityRequest struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func (r *UnmergeIdentityRequest) GetIdentifier() entity.Identifier {
+	return entity.Identifier{Type: entity.IdentifierType(r.Type), Value: r.Value}
+}
+
+func (r *UnmergeIdentityRequest) Validate() error {
+	if !entity.IdentifierType(r.Type).IsValid() {
+		return fmt.Errorf("type is not valid")
+	}
+
+	if r.Value == "" {
+		return fmt.Errorf("value can not be empty")
+	}
+
+	return nil
+}
+
+func (r *UnmergeIdentityRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(r)
+}
+
+type IdentitiesResponse struct {
+	Identities []*entity.Identity `json:"identities"`
+}
+
+func NewIdentityAPI(config *core.Config, uc usecase.UseCase) *identityAPI {
+	return &identityAPI{config: config, uc: uc}
+}
+
+func (i *identityAPI) FindIdentities(w http.ResponseWriter, r *http.Request) {
+	identifier := entity.Identifier{
+		Type:  entity.IdentifierType(r.URL.Query().Get("type")),
+		Value: r.URL.Query().Get("value"),
+	}
+	if !identifier.Type.IsValid() || identifier.Value == "" {
+		slog.Error("identifier is not valid", slog.String("identifier", identifier.String()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("type and value are required"))
+		return
+	}
+
+	identities, err := i.uc.FindIdentities(r.Context(), r.PathValue("tenant_id"), identifier)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("tracking setting not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to find identities", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to find identities"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, IdentitiesResponse{Identities: identities})
+}
+
+func (i *identityAPI) GetIdentity(w http.ResponseWriter, r *http.Request) {
+	id, err := bson.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("id is not valid"))
+		return
+	}
+
+	identity, err := i.uc.GetIdentity(r.Context(), r.PathValue("tenant_id"), id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("identity not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to get identity", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to get identity"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, identity)
+}
+
+func (i *identityAPI) UnmergeIdentity(w http.ResponseWriter, r *http.Request) {
+	id, err := bson.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("id is not valid"))
+		return
+	}
+
+	req := &UnmergeIdentityRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	identities, err := i.uc.UnmergeIdentity(r.Context(), r.PathValue("tenant_id"), id, req.GetIdentifier())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("identity not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to unmerge identity", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, IdentitiesResponse{Identities: identities})
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github/michaellimmm/turakkingu/internal/core"
 	"github/michaellimmm/turakkingu/internal/entity"
@@ -10,17 +13,43 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
+// variantCookieName is suffixed with the short id, the cookie holds the A/B variant id
+const variantCookieName = "_zt_v_"
+
 type linkAPI struct {
-	uc     usecase.UseCase
-	config *core.Config
+	uc       usecase.UseCase
+	config   *core.Config
+	clientIP *clientIPResolver
 }
 
 type CreateLinkRequest struct {
-	TenantID string `json:"tenant_id"`
-	Name     string `json:"name"`
-	Url      string `json:"url"`
+	TenantID   string               `json:"tenant_id"`
+	Name       string               `json:"name"`
+	Url        string               `json:"url"`    // defaults to the first variant
+	Slug       string               `json:"slug"`   // custom short id, random without it
+	Domain     string               `json:"domain"` // one of the link domains of the tenant, config.Domain without it
+	Tags       []string             `json:"tags"`
+	CampaignID string               `json:"campaign_id"`
+	Variants   []LinkVariantRequest `json:"variants"`
+	AutoTrack  bool                 `json:"auto_track"`
+	LinkLifecycleRequest
+	LinkRulesRequest
+	entity.LinkRedirect
+}
+
+type LinkVariantRequest struct {
+	ID     string `json:"id"` // defaults to A, B, C...
+	Name   string `json:"name"`
+	Url    string `json:"url"`
+	Weight int    `json:"weight"`
 }
 
 func (c *CreateLinkRequest) Validate() error {
@@ -32,18 +61,73 @@ func (c *CreateLinkRequest) Validate() error {
 		return fmt.Errorf("name can not be empty")
 	}
 
+	if c.CampaignID != "" {
+		if _, err := bson.ObjectIDFromHex(c.CampaignID); err != nil {
+			return fmt.Errorf("campaign_id is not valid")
+		}
+	}
+
+	if err := c.LinkLifecycleRequest.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.LinkRulesRequest.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.LinkRedirect.Validate(); err != nil {
+		return err
+	}
+
+	if len(c.Variants) > maxLinkVariants {
+		return fmt.Errorf("a link can have at most %d variants", maxLinkVariants)
+	}
+
+	ids := map[string]bool{}
+	for i, variant := range c.ToVariants() {
+		if ids[variant.ID] {
+			return fmt.Errorf("variants[%d].id %q is duplicated", i, variant.ID)
+		}
+		ids[variant.ID] = true
+
+		if !entity.IsWebURL(variant.Url) {
+			return fmt.Errorf("variants[%d].url is not valid", i)
+		}
+
+		if variant.Weight <= 0 {
+			return fmt.Errorf("variants[%d].weight must be positive", i)
+		}
+	}
+
+	if c.Url == "" && len(c.Variants) > 0 {
+		return nil
+	}
+
 	if c.Url == "" {
 		return fmt.Errorf("url can not be empty")
 	}
 
-	_, err := url.ParseRequestURI(c.Url)
-	if err != nil {
+	if !entity.IsWebURL(c.Url) {
 		return fmt.Errorf("url is not valid")
 	}
 
 	return nil
 }
 
+const maxLinkVariants = 26
+
+func (c *CreateLinkRequest) ToVariants() []entity.LinkVariant {
+	variants := []entity.LinkVariant{}
+	for i, v := range c.Variants {
+		id := v.ID
+		if id == "" {
+			id = string(rune('A' + i))
+		}
+		variants = append(variants, entity.LinkVariant{ID: id, Name: v.Name, Url: v.Url, Weight: v.Weight})
+	}
+	return variants
+}
+
 func (c *CreateLinkRequest) FromReader(r io.ReadCloser) error {
 	defer func() {
 		_ = r.Close()
@@ -51,14 +135,111 @@ func (c *CreateLinkRequest) FromReader(r io.ReadCloser) error {
 	return json.NewDecoder(r).Decode(c)
 }
 
+type LinkLifecycleRequest struct {
+	StartsAt       string `json:"starts_at"` // 2006-01-02 or RFC 3339
+	EndsAt         string `json:"ends_at"`   // 2006-01-02 or RFC 3339
+	MaxClicks      int64  `json:"max_clicks"`
+	FallbackUrl    string `json:"fallback_url"`
+	ExpiredMessage string `json:"expired_message"`
+}
+
+func (l *LinkLifecycleRequest) Validate() error {
+	startsAt, err := core.ParseTime(l.StartsAt)
+	if err != nil {
+		return fmt.Errorf("starts_at is not valid")
+	}
+
+	endsAt, err := core.ParseTime(l.EndsAt)
+	if err != nil {
+		return fmt.Errorf("ends_at is not valid")
+	}
+
+	if !startsAt.IsZero() && !endsAt.IsZero() && !endsAt.After(startsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+
+	if l.MaxClicks < 0 {
+		return fmt.Errorf("max_clicks can not be negative")
+	}
+
+	if l.FallbackUrl != "" {
+		if !entity.IsWebURL(l.FallbackUrl) {
+			return fmt.Errorf("fallback_url is not valid")
+		}
+	}
+
+	return nil
+}
+
+func (l *LinkLifecycleRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(l)
+}
+
+func (l *LinkLifecycleRequest) ToEntity() entity.LinkLifecycle {
+	lifecycle := entity.LinkLifecycle{
+		MaxClicks:      l.MaxClicks,
+		FallbackUrl:    l.FallbackUrl,
+		ExpiredMessage: l.ExpiredMessage,
+	}
+	if startsAt, _ := core.ParseTime(l.StartsAt); !startsAt.IsZero() {
+		startsAt = startsAt.UTC()
+		lifecycle.StartsAt = &startsAt
+	}
+	if endsAt, _ := core.ParseTime(l.EndsAt); !endsAt.IsZero() {
+		endsAt = endsAt.UTC()
+		lifecycle.EndsAt = &endsAt
+	}
+	return lifecycle
+}
+
 type CreateLinkResponse struct {
 	Link string `json:"link"`
 }
 
+// LinkResponse is a link in the listings
+type LinkResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Url        string     `json:"url"`
+	FixedUrl   string     `json:"fixed_url"`
+	Tags       []string   `json:"tags"`
+	CampaignID string     `json:"campaign_id,omitempty"`
+	Clicks     int64      `json:"clicks"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+}
+
+func NewLinkResponse(link *entity.Link, baseUrl string) LinkResponse {
+	tags := link.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	response := LinkResponse{
+		ID:        link.ID.Hex(),
+		Name:      link.Name,
+		Url:       link.Url,
+		FixedUrl:  link.ConstructFixedUrl(baseUrl),
+		Tags:      tags,
+		Clicks:    link.Clicks,
+		CreatedAt: link.CreatedAt,
+		UpdatedAt: link.UpdatedAt,
+		DeletedAt: link.DeletedAt,
+	}
+	if !link.CampaignID.IsZero() {
+		response.CampaignID = link.CampaignID.Hex()
+	}
+	return response
+}
+
 func NewLinkAPI(config *core.Config, uc usecase.UseCase) *linkAPI {
 	return &linkAPI{
-		uc:     uc,
-		config: config,
+		uc:       uc,
+		config:   config,
+		clientIP: newClientIPResolver(config.TrustedProxies),
 	}
 }
 
@@ -79,12 +260,33 @@ func (f *linkAPI) CreateLink(w http.ResponseWriter, r *http.Request) {
 	}
 
 	link := &entity.Link{
-		Name:     req.Name,
-		Url:      req.Url,
-		TenantID: req.TenantID,
+		Name:          req.Name,
+		Url:           req.Url,
+		ShortID:       req.Slug,
+		Domain:        req.Domain,
+		Tags:          req.Tags,
+		TenantID:      req.TenantID,
+		AutoTrack:     req.AutoTrack,
+		Rules:         req.Rules,
+		LinkRedirect:  req.LinkRedirect,
+		LinkLifecycle: req.LinkLifecycleRequest.ToEntity(),
+	}
+	link.CampaignID, _ = bson.ObjectIDFromHex(req.CampaignID)
+	if len(req.Variants) > 0 {
+		link.Variants = req.ToVariants()
+		if link.Url == "" {
+			link.Url = link.Variants[0].Url
+		}
 	}
 	err = f.uc.CreateLink(r.Context(), link)
-	if err != nil {
+	if errors.Is(err, usecase.ErrInvalidSlug) || errors.Is(err, usecase.ErrLinkDomainNotRegistered) ||
+		errors.Is(err, usecase.ErrLinkCampaignNotFound) {
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, usecase.ErrSlugTaken) {
+		_ = sendError(w, http.StatusConflict, err)
+		return
+	} else if err != nil {
 		slog.Error("failed to create new link", slog.String("error", err.Error()))
 		sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to create new link"))
 		return
@@ -94,23 +296,663 @@ func (f *linkAPI) CreateLink(w http.ResponseWriter, r *http.Request) {
 }
 
 func (f *linkAPI) Redirect(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
-	link, err := f.uc.GetLink(r.Context(), id)
+	link, err := f.uc.ResolveLink(r.Context(), r.Host, r.PathValue("id"))
+	if err != nil {
+		render404(w)
+		return
+	}
+
+	f.redirect(w, r, link)
+}
+
+// RedirectBranded serves /{slug} on the link domains of the tenants, the default domain
+// keeps its links under /r/
+func (f *linkAPI) RedirectBranded(w http.ResponseWriter, r *http.Request) {
+	link, err := f.uc.ResolveBrandedLink(r.Context(), r.Host, r.PathValue("slug"))
 	if err != nil {
-		slog.Error("failed to get link", slog.String("error", err.Error()))
 		render404(w)
 		return
 	}
 
-	newParams := r.URL.Query()
-	u, _ := url.Parse(link.Url)
-	existingParams := u.Query()
-	for key, values := range newParams {
-		for _, v := range values {
-			existingParams.Add(key, v) // Use .Set() if you want to replace instead
+	f.redirect(w, r, link)
+}
+
+func (f *linkAPI) redirect(w http.ResponseWriter, r *http.Request, link *entity.Link) {
+	query := r.URL.Query()
+	source := entity.ClickSource(query.Get(entity.ClickSourceParam))
+	query.Del(entity.ClickSourceParam)
+
+	// the redirect rules and the params of the short link are applied with the click
+	click := &entity.Click{
+		Url:       link.Url,
+		Query:     query,
+		Referrer:  r.Referer(),
+		UserAgent: r.UserAgent(),
+		ClientHints: entity.ClientHints{
+			Brands:   r.Header.Get("Sec-CH-UA"),
+			Mobile:   r.Header.Get("Sec-CH-UA-Mobile"),
+			Platform: r.Header.Get("Sec-CH-UA-Platform"),
+		},
+		Language: r.Header.Get("Accept-Language"),
+		IP:       f.clientIP.FromRequest(r),
+	}
+	if source.IsValid() {
+		click.Source = source
+	}
+
+	// the variant of the visitor is kept in a cookie scoped to the link, the redirect rules win over it
+	variantCookie := variantCookieName + link.ShortID
+	if len(link.Variants) > 0 {
+		if cookie, err := r.Cookie(variantCookie); err == nil {
+			click.Variant = cookie.Value
+		}
+		click.VisitorKey = f.visitorKey(r)
+	}
+	keptVariant := click.Variant
+
+	// a failed click record must not break the link
+	track, err := f.uc.RecordClick(r.Context(), link, click)
+	if err != nil {
+		slog.Error("failed to record click", slog.String("error", err.Error()))
+	}
+
+	if click.Variant != "" && click.Variant != keptVariant {
+		http.SetCookie(w, &http.Cookie{
+			Name:     variantCookie,
+			Value:    click.Variant,
+			Path:     link.Path(),
+			MaxAge:   int(f.config.VisitorCookieMaxAge.Seconds()),
+			HttpOnly: true,
+			Secure:   isSecure(r),
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	switch click.Outcome {
+	case entity.ClickOutcomeFallback:
+		http.Redirect(w, r, click.Url, http.StatusFound)
+		return
+	case entity.ClickOutcomeExpiredPage:
+		renderExpired(w, click.Reason, link.ExpiredMessage)
+		return
+	}
+
+	// the landing page picks the track up like the query_param of a created track
+	u, _ := url.Parse(click.Url)
+	if track != nil {
+		params := u.Query()
+		params.Set("ztid", track.ID.Hex())
+		params.Set("ztts", strconv.FormatInt(track.CreatedAt.Unix(), 10))
+		u.RawQuery = params.Encode()
+	}
+
+	if link.Interstitial != nil {
+		renderInterstitial(w, u.String(), link.Interstitial)
+		return
+	}
+
+	http.Redirect(w, r, u.String(), link.LinkRedirect.Status())
+}
+
+// visitorKey is the first-party visitor id, or a fingerprint of the request without one,
+// so the assignment survives a lost variant cookie
+func (f *linkAPI) visitorKey(r *http.Request) string {
+	if identity := readVisitorCookie(r); identity != nil {
+		return identity.ZTID
+	}
+
+	sum := sha256.Sum256([]byte(f.clientIP.FromRequest(r) + "|" + r.UserAgent() + "|" + r.Header.Get("Accept-Language")))
+	return hex.EncodeToString(sum[:])
+}
+
+type ClickBreakdownRequest struct {
+	Dimension string
+	From      string // 2006-01-02 or RFC 3339, inclusive
+	To        string // 2006-01-02 or RFC 3339, exclusive
+}
+
+func (r *ClickBreakdownRequest) FromQuery(query url.Values) {
+	r.Dimension = query.Get("dimension")
+	r.From = query.Get("from")
+	r.To = query.Get("to")
+}
+
+func (r *ClickBreakdownRequest) Validate() error {
+	if !entity.ClickDimension(r.Dimension).IsValid() {
+		return fmt.Errorf("dimension is not valid")
+	}
+
+	if _, err := core.ParseTime(r.From); err != nil {
+		return fmt.Errorf("from is not valid")
+	}
+
+	if _, err := core.ParseTime(r.To); err != nil {
+		return fmt.Errorf("to is not valid")
+	}
+
+	return nil
+}
+
+func (f *linkAPI) GetClickBreakdown(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	req := &ClickBreakdownRequest{}
+	req.FromQuery(r.URL.Query())
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	from, _ := core.ParseTime(req.From)
+	to, _ := core.ParseTime(req.To)
+	response, err := f.uc.GetClickBreakdown(r.Context(), id, entity.ClickDimension(req.Dimension), from, to)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("link not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to get click breakdown", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to get click breakdown"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, response)
+}
+
+type ExperimentReportRequest struct {
+	From string // 2006-01-02 or RFC 3339, inclusive
+	To   string // 2006-01-02 or RFC 3339, exclusive
+}
+
+func (r *ExperimentReportRequest) FromQuery(query url.Values) {
+	r.From = query.Get("from")
+	r.To = query.Get("to")
+}
+
+func (r *ExperimentReportRequest) Validate() error {
+	if _, err := core.ParseTime(r.From); err != nil {
+		return fmt.Errorf("from is not valid")
+	}
+
+	if _, err := core.ParseTime(r.To); err != nil {
+		return fmt.Errorf("to is not valid")
+	}
+
+	return nil
+}
+
+func (f *linkAPI) GetExperimentReport(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	req := &ExperimentReportRequest{}
+	req.FromQuery(r.URL.Query())
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	from, _ := core.ParseTime(req.From)
+	to, _ := core.ParseTime(req.To)
+	response, err := f.uc.GetExperimentReport(r.Context(), id, from, to)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("link not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to get experiment report", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to get experiment report"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, response)
+}
+
+func (f *linkAPI) UpdateLifecycle(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	req := &LinkLifecycleRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	link, err := f.uc.UpdateLinkLifecycle(r.Context(), id, req.ToEntity())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("link not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to update link lifecycle", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update link lifecycle"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, link.LinkLifecycle)
+}
+
+const maxLinkRules = 50
+
+type LinkRulesRequest struct {
+	Rules []entity.RedirectRule `json:"rules"` // tried in order
+}
+
+func (l *LinkRulesRequest) Validate() error {
+	if len(l.Rules) > maxLinkRules {
+		return fmt.Errorf("a link can have at most %d rules", maxLinkRules)
+	}
+
+	for i := range l.Rules {
+		if err := l.Rules[i].Validate(); err != nil {
+			return fmt.Errorf("rules[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (l *LinkRulesRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(l)
+}
+
+func (f *linkAPI) UpdateRules(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	req := &LinkRulesRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rules := req.Rules
+	if rules == nil {
+		rules = []entity.RedirectRule{}
+	}
+
+	link, err := f.uc.UpdateLinkRules(r.Context(), id, rules)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("link not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to update link rules", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update link rules"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, LinkRulesRequest{Rules: link.Rules})
+}
+
+// DryRunRulesRequest holds synthetic attributes of a click, the user agent fills the device
+// type and os when they are empty
+type DryRunRulesRequest struct {
+	DeviceType string `json:"device_type"`
+	OS         string `json:"os"`
+	UserAgent  string `json:"user_agent"`
+	Country    string `json:"country"`
+	Language   string `json:"language"` // Accept-Language
+	Time       string `json:"time"`     // RFC 3339, now when empty
+	Query      string `json:"query"`    // query string of the short link, e.g. "utm_source=line"
+}
+
+func (r *DryRunRulesRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(r)
+}
+
+func (r *DryRunRulesRequest) Validate() error {
+	if r.Time != "" {
+		if _, err := time.Parse(time.RFC3339, r.Time); err != nil {
+			return fmt.Errorf("time is not valid")
+		}
+	}
+
+	if _, err := url.ParseQuery(strings.TrimPrefix(r.Query, "?")); err != nil {
+		return fmt.Errorf("query is not valid")
+	}
+
+	return nil
+}
+
+func (r *DryRunRulesRequest) ToEntity() entity.RuleRequest {
+	req := entity.RuleRequest{
+		DeviceType: entity.DeviceType(r.DeviceType),
+		OS:         r.OS,
+		Country:    r.Country,
+		Language:   r.Language,
+		UserAgent:  r.UserAgent,
+		Time:       time.Now().UTC(),
+	}
+
+	if t, err := time.Parse(time.RFC3339, r.Time); err == nil {
+		req.Time = t
+	}
+	req.Query, _ = url.ParseQuery(strings.TrimPrefix(r.Query, "?"))
+	return req
+}
+
+func (f *linkAPI) DryRunRules(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	req := &DryRunRulesRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	match, err := f.uc.DryRunLinkRules(r.Context(), id, req.ToEntity())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("link not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to dry run link rules", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to dry run link rules"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, match)
+}
+
+type LinkRedirectRequest struct {
+	entity.LinkRedirect
+}
+
+func (l *LinkRedirectRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(l)
+}
+
+func (f *linkAPI) UpdateRedirect(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	req := &LinkRedirectRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	link, err := f.uc.UpdateLinkRedirect(r.Context(), id, req.LinkRedirect)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("link not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to update link redirect", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update link redirect"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, link.LinkRedirect)
+}
+
+type QRRequest struct {
+	Format string
+	Size   string
+	Level  string
+	Logo   string
+}
+
+func (r *QRRequest) FromQuery(query url.Values) {
+	r.Format = query.Get("format")
+	r.Size = query.Get("size")
+	r.Level = query.Get("level")
+	r.Logo = query.Get("logo")
+}
+
+func (r *QRRequest) Validate() error {
+	if r.Format != "" && !entity.QRFormat(r.Format).IsValid() {
+		return fmt.Errorf("format must be png or svg")
+	}
+
+	if r.Size != "" {
+		size, err := strconv.Atoi(r.Size)
+		if err != nil || size < entity.QRMinSize || size > entity.QRMaxSize {
+			return fmt.Errorf("size must be between %d and %d", entity.QRMinSize, entity.QRMaxSize)
+		}
+	}
+
+	switch strings.ToUpper(r.Level) {
+	case "", "L", "M", "Q", "H":
+	default:
+		return fmt.Errorf("level must be L, M, Q or H")
+	}
+
+	if r.Logo != "" {
+		if _, err := url.ParseRequestURI(r.Logo); err != nil {
+			return fmt.Errorf("logo is not valid")
+		}
+	}
+
+	return nil
+}
+
+func (r *QRRequest) ToEntity() entity.QROptions {
+	opts := entity.QROptions{
+		Format: entity.QRFormat(r.Format),
+		Size:   entity.QRDefaultSize,
+		Level:  strings.ToUpper(r.Level),
+		Logo:   r.Logo,
+	}
+	if opts.Format == "" {
+		opts.Format = entity.QRFormatPNG
+	}
+	if size, err := strconv.Atoi(r.Size); err == nil {
+		opts.Size = size
+	}
+	if opts.Level == "" {
+		opts.Level = "M"
+	}
+	return opts
+}
+
+func (f *linkAPI) GetQR(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	req := &QRRequest{}
+	req.FromQuery(r.URL.Query())
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	opts := req.ToEntity()
+	link, b, err := f.uc.GetLinkQR(r.Context(), id, opts)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("link not found"))
+		return
+	} else if errors.Is(err, usecase.ErrInvalidQRLogo) {
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		slog.Error("failed to get qr code", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to get qr code"))
+		return
+	}
+
+	w.Header().Set("Content-Type", opts.Format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", link.ShortID+"."+string(opts.Format)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
+
+type SearchLinksRequest struct {
+	Query       string
+	Tags        []string
+	CampaignID  string
+	CreatedFrom string // 2006-01-02 or RFC 3339, inclusive
+	CreatedTo   string // 2006-01-02 or RFC 3339, exclusive
+	Deleted     string
+	Page        string
+	PageSize    string
+}
+
+func (r *SearchLinksRequest) FromQuery(query url.Values) {
+	r.Query = query.Get("q")
+	r.Tags = query["tag"]
+	r.CampaignID = query.Get("campaign_id")
+	r.CreatedFrom = query.Get("created_from")
+	r.CreatedTo = query.Get("created_to")
+	r.Deleted = query.Get("deleted")
+	r.Page = query.Get("page")
+	r.PageSize = query.Get("page_size")
+}
+
+func (r *SearchLinksRequest) Validate() error {
+	if r.CampaignID != "" {
+		if _, err := bson.ObjectIDFromHex(r.CampaignID); err != nil {
+			return fmt.Errorf("campaign_id is not valid")
+		}
+	}
+
+	if _, err := core.ParseTime(r.CreatedFrom); err != nil {
+		return fmt.Errorf("created_from is not valid")
+	}
+
+	if _, err := core.ParseTime(r.CreatedTo); err != nil {
+		return fmt.Errorf("created_to is not valid")
+	}
+
+	if r.Deleted != "" {
+		if _, err := strconv.ParseBool(r.Deleted); err != nil {
+			return fmt.Errorf("deleted must be true or false")
+		}
+	}
+
+	if r.Page != "" {
+		if page, err := strconv.Atoi(r.Page); err != nil || page < 1 {
+			return fmt.Errorf("page must be a positive number")
+		}
+	}
+
+	if r.PageSize != "" {
+		if size, err := strconv.Atoi(r.PageSize); err != nil || size < 1 || size > entity.LinkSearchMaxPageSize {
+			return fmt.Errorf("page_size must be between 1 and %d", entity.LinkSearchMaxPageSize)
 		}
 	}
-	u.RawQuery = existingParams.Encode()
 
-	http.Redirect(w, r, u.String(), http.StatusFound)
+	return nil
+}
+
+func (r *SearchLinksRequest) ToEntity() entity.LinkSearch {
+	search := entity.LinkSearch{Query: r.Query, Tags: r.Tags}
+	if campaignID, err := bson.ObjectIDFromHex(r.CampaignID); err == nil {
+		search.CampaignID = &campaignID
+	}
+	if from, _ := core.ParseTime(r.CreatedFrom); !from.IsZero() {
+		search.CreatedFrom = &from
+	}
+	if to, _ := core.ParseTime(r.CreatedTo); !to.IsZero() {
+		search.CreatedTo = &to
+	}
+	search.Deleted, _ = strconv.ParseBool(r.Deleted)
+	search.Page, _ = strconv.Atoi(r.Page)
+	search.PageSize, _ = strconv.Atoi(r.PageSize)
+	return search
+}
+
+type SearchLinksResponse struct {
+	Links    []LinkResponse `json:"links"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+}
+
+func (f *linkAPI) SearchLinks(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &SearchLinksRequest{}
+	req.FromQuery(r.URL.Query())
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := f.uc.SearchLinks(r.Context(), tenantID, req.ToEntity())
+	if err != nil {
+		slog.Error("failed to search links", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to search links"))
+		return
+	}
+
+	response := SearchLinksResponse{
+		Links:    []LinkResponse{},
+		Total:    result.Total,
+		Page:     result.Page,
+		PageSize: result.PageSize,
+	}
+	for _, link := range result.Links {
+		response.Links = append(response.Links, NewLinkResponse(link, f.config.Domain))
+	}
+
+	_ = sendJson(w, http.StatusOK, response)
+}
+
+type LinkTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+func (l *LinkTagsRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(l)
+}
+
+func (f *linkAPI) UpdateTags(w http.ResponseWriter, r *http.Request) {
+	req := &LinkTagsRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	link, err := f.uc.UpdateLinkTags(r.Context(), r.PathValue("id"), req.Tags)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("link not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to update link tags", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update link tags"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, LinkTagsRequest{Tags: link.Tags})
 }
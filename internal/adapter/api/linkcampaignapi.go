@@ -0,0 +1,284 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type linkCampaignAPI struct {
+	uc     usecase.UseCase
+	config *core.Config
+}
+
+func NewLinkCampaignAPI(config *core.Config, uc usecase.UseCase) *linkCampaignAPI {
+	return &linkCampaignAPI{config: config, uc: uc}
+}
+
+type LinkCampaignRequest struct {
+	Name     string  `json:"name"`
+	StartsAt string  `json:"starts_at"` // 2006-01-02 or RFC 3339
+	EndsAt   string  `json:"ends_at"`   // 2006-01-02 or RFC 3339
+	Budget   float64 `json:"budget"`
+	Currency string  `json:"currency"`
+	Owner    string  `json:"owner"`
+}
+
+func (l *LinkCampaignRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(l)
+}
+
+func (l *LinkCampaignRequest) Validate() error {
+	if _, err := core.ParseTime(l.StartsAt); err != nil {
+		return fmt.Errorf("starts_at is not valid")
+	}
+
+	if _, err := core.ParseTime(l.EndsAt); err != nil {
+		return fmt.Errorf("ends_at is not valid")
+	}
+
+	campaign := l.ToEntity("")
+	return campaign.Validate()
+}
+
+func (l *LinkCampaignRequest) ToEntity(tenantID string) *entity.LinkCampaign {
+	campaign := &entity.LinkCampaign{
+		TenantID: tenantID,
+		Name:     l.Name,
+		Budget:   l.Budget,
+		Currency: l.Currency,
+		Owner:    l.Owner,
+	}
+	if startsAt, _ := core.ParseTime(l.StartsAt); !startsAt.IsZero() {
+		startsAt = startsAt.UTC()
+		campaign.StartsAt = &startsAt
+	}
+	if endsAt, _ := core.ParseTime(l.EndsAt); !endsAt.IsZero() {
+		endsAt = endsAt.UTC()
+		campaign.EndsAt = &endsAt
+	}
+	return campaign
+}
+
+type LinkCampaignsResponse struct {
+	Campaigns []*entity.LinkCampaign `json:"campaigns"`
+}
+
+func (l *linkCampaignAPI) CreateCampaign(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &LinkCampaignRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	campaign := req.ToEntity(tenantID)
+	if err := l.uc.CreateLinkCampaign(r.Context(), campaign); err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to create campaign"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusCreated, campaign)
+}
+
+func (l *linkCampaignAPI) GetCampaigns(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	campaigns, err := l.uc.GetLinkCampaigns(r.Context(), tenantID)
+	if err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to get campaigns"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, LinkCampaignsResponse{Campaigns: campaigns})
+}
+
+func (l *linkCampaignAPI) UpdateCampaign(w http.ResponseWriter, r *http.Request) {
+	req := &LinkCampaignRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	campaign, err := l.uc.UpdateLinkCampaign(r.Context(), r.PathValue("id"), req.ToEntity(""))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("campaign not found"))
+		return
+	} else if err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update campaign"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, campaign)
+}
+
+func (l *linkCampaignAPI) DeleteCampaign(w http.ResponseWriter, r *http.Request) {
+	err := l.uc.DeleteLinkCampaign(r.Context(), r.PathValue("id"))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("campaign not found"))
+		return
+	} else if err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to delete campaign"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+const maxAssignedLinks = 1000
+
+type AssignLinksRequest struct {
+	CampaignID string   `json:"campaign_id"` // empty removes the links from their campaign
+	LinkIDs    []string `json:"link_ids"`
+}
+
+func (a *AssignLinksRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(a)
+}
+
+func (a *AssignLinksRequest) Validate() error {
+	if len(a.LinkIDs) == 0 {
+		return fmt.Errorf("link_ids can not be empty")
+	}
+
+	if len(a.LinkIDs) > maxAssignedLinks {
+		return fmt.Errorf("at most %d links can be assigned at once", maxAssignedLinks)
+	}
+
+	return nil
+}
+
+type AssignLinksResponse struct {
+	Links int64 `json:"links"` // found among link_ids
+}
+
+func (l *linkCampaignAPI) AssignLinks(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &AssignLinksRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	count, err := l.uc.AssignLinksToCampaign(r.Context(), tenantID, req.CampaignID, req.LinkIDs)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("campaign not found"))
+		return
+	} else if errors.Is(err, usecase.ErrLinkNotFound) {
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to assign links"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, AssignLinksResponse{Links: count})
+}
+
+type LinkGroupReportRequest struct {
+	Group string
+	From  string // 2006-01-02 or RFC 3339, inclusive
+	To    string // 2006-01-02 or RFC 3339, exclusive
+}
+
+func (r *LinkGroupReportRequest) FromQuery(query url.Values) {
+	r.Group = query.Get("group")
+	r.From = query.Get("from")
+	r.To = query.Get("to")
+}
+
+func (r *LinkGroupReportRequest) Validate() error {
+	if !entity.LinkGroup(r.Group).IsValid() {
+		return fmt.Errorf("group must be campaign or tag")
+	}
+
+	if _, err := core.ParseTime(r.From); err != nil {
+		return fmt.Errorf("from is not valid")
+	}
+
+	if _, err := core.ParseTime(r.To); err != nil {
+		return fmt.Errorf("to is not valid")
+	}
+
+	return nil
+}
+
+func (l *linkCampaignAPI) GetReport(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &LinkGroupReportRequest{}
+	req.FromQuery(r.URL.Query())
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	from, _ := core.ParseTime(req.From)
+	to, _ := core.ParseTime(req.To)
+	report, err := l.uc.GetLinkGroupReport(r.Context(), tenantID, entity.LinkGroup(req.Group), from, to)
+	if err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to get report"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, report)
+}
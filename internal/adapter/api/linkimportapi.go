@@ -0,0 +1,145 @@
+package api
+
+import (
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type ImportLinksRequest struct {
+	Format string // csv or json, from the content type without it
+	Atomic string // true writes nothing unless every row is valid
+}
+
+func (i *ImportLinksRequest) FromRequest(r *http.Request) {
+	query := r.URL.Query()
+	i.Format = query.Get("format")
+	i.Atomic = query.Get("atomic")
+	if i.Format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		i.Format = string(entity.LinkFileFormatJSON)
+		if mediaType == entity.LinkFileFormatCSV.ContentType() {
+			i.Format = string(entity.LinkFileFormatCSV)
+		}
+	}
+}
+
+func (i *ImportLinksRequest) Validate() error {
+	if !entity.LinkFileFormat(i.Format).IsValid() {
+		return fmt.Errorf("format must be csv or json")
+	}
+
+	if i.Atomic != "" {
+		if _, err := strconv.ParseBool(i.Atomic); err != nil {
+			return fmt.Errorf("atomic is not valid")
+		}
+	}
+
+	return nil
+}
+
+func (i *ImportLinksRequest) IsAtomic() bool {
+	atomic, _ := strconv.ParseBool(i.Atomic)
+	return atomic
+}
+
+func (f *linkAPI) ImportLinks(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &ImportLinksRequest{}
+	req.FromRequest(r)
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, entity.MaxLinkImportSize)
+	defer func() {
+		_ = body.Close()
+	}()
+
+	rows, err := entity.ParseLinkImport(entity.LinkFileFormat(req.Format), body)
+	if err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := f.uc.ImportLinks(r.Context(), tenantID, rows, req.IsAtomic())
+	if err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to import links"))
+		return
+	}
+
+	status := http.StatusOK
+	if !report.Applied {
+		status = http.StatusUnprocessableEntity
+	}
+	_ = sendJson(w, status, report)
+}
+
+type ExportLinksRequest struct {
+	Format string // csv or json, json by default
+}
+
+func (e *ExportLinksRequest) FromQuery(query url.Values) {
+	e.Format = query.Get("format")
+	if e.Format == "" {
+		e.Format = string(entity.LinkFileFormatJSON)
+	}
+}
+
+func (e *ExportLinksRequest) Validate() error {
+	if !entity.LinkFileFormat(e.Format).IsValid() {
+		return fmt.Errorf("format must be csv or json")
+	}
+	return nil
+}
+
+type ExportLinksResponse struct {
+	Links []*entity.LinkExportRow `json:"links"`
+}
+
+func (f *linkAPI) ExportLinks(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &ExportLinksRequest{}
+	req.FromQuery(r.URL.Query())
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rows, err := f.uc.ExportLinks(r.Context(), tenantID)
+	if err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to export links"))
+		return
+	}
+
+	if entity.LinkFileFormat(req.Format) == entity.LinkFileFormatJSON {
+		_ = sendJson(w, http.StatusOK, ExportLinksResponse{Links: rows})
+		return
+	}
+
+	w.Header().Set("Content-Type", entity.LinkFileFormatCSV.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", tenantID+"-links.csv"))
+	if err := entity.WriteLinkExportCSV(w, rows); err != nil {
+		slog.Error("failed to write links", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redirectBuckets are the upper bounds of the redirect durations in seconds
+var redirectBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+type metricsAPI struct {
+	uc     usecase.UseCase
+	config *core.Config
+
+	mu        sync.Mutex
+	redirects histogram
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, the last one is +Inf
+	sum    float64
+	count  uint64
+}
+
+func NewMetricsAPI(config *core.Config, uc usecase.UseCase) *metricsAPI {
+	return &metricsAPI{
+		config:    config,
+		uc:        uc,
+		redirects: histogram{counts: make([]uint64, len(redirectBuckets)+1)},
+	}
+}
+
+// observeRedirect measures the duration of the redirect handler
+func (m *metricsAPI) observeRedirect(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next(w, r)
+		m.observe(time.Since(start).Seconds())
+	}
+}
+
+func (m *metricsAPI) observe(seconds float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := 0
+	for i < len(redirectBuckets) && seconds > redirectBuckets[i] {
+		i++
+	}
+	m.redirects.counts[i]++
+	m.redirects.sum += seconds
+	m.redirects.count++
+}
+
+// GetMetrics renders the metrics in the Prometheus text format
+func (m *metricsAPI) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	b := &strings.Builder{}
+
+	stats := m.uc.GetCacheStats()
+	metrics := []struct {
+		name, kind, help string
+		value            func(i int) string
+	}{
+		{"turakkingu_cache_hits_total", "counter", "Lookups served by the cache.",
+			func(i int) string { return strconv.FormatUint(stats[i].Hits, 10) }},
+		{"turakkingu_cache_misses_total", "counter", "Lookups missing the cache.",
+			func(i int) string { return strconv.FormatUint(stats[i].Misses, 10) }},
+		{"turakkingu_cache_evictions_total", "counter", "Entries evicted for the size of the cache.",
+			func(i int) string { return strconv.FormatUint(stats[i].Evictions, 10) }},
+		{"turakkingu_cache_entries", "gauge", "Entries in the cache.",
+			func(i int) string { return strconv.Itoa(stats[i].Size) }},
+	}
+	for _, metric := range metrics {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		for i := range stats {
+			fmt.Fprintf(b, "%s{cache=%q} %s\n", metric.name, stats[i].Name, metric.value(i))
+		}
+	}
+
+	m.mu.Lock()
+	redirects := m.redirects
+	redirects.counts = append([]uint64{}, m.redirects.counts...)
+	m.mu.Unlock()
+
+	name := "turakkingu_redirect_duration_seconds"
+	fmt.Fprintf(b, "# HELP %s Duration of the short link redirects.\n# TYPE %s histogram\n", name, name)
+	var cumulative uint64
+	for i, count := range redirects.counts {
+		cumulative += count
+		le := "+Inf"
+		if i < len(redirectBuckets) {
+			le = strconv.FormatFloat(redirectBuckets[i], 'g', -1, 64)
+		}
+		fmt.Fprintf(b, "%s_bucket{le=%q} %d\n", name, le, cumulative)
+	}
+	fmt.Fprintf(b, "%s_sum %s\n", name, strconv.FormatFloat(redirects.sum, 'g', -1, 64))
+	fmt.Fprintf(b, "%s_count %d\n", name, redirects.count)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(b.String()))
+}
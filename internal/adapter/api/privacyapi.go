@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type privacyAPI struct {
+	uc     usecase.UseCase
+	config *core.Config
+}
+
+type DataSubjectRequest struct {
+	Type        string `json:"type"`  // end_user_id, fingerprint or ztid
+	Value       string `json:"value"` // identifier value
+	Mode        string `json:"mode"`  // delete or anonymize, only for erasure
+	RequestedBy string `json:"requested_by"`
+}
+
+func (r *DataSubjectRequest) Validate() error {
+	if !entity.IdentifierType(r.Type).IsValid() {
+		return fmt.Errorf("type is not valid")
+	}
+
+	if r.Value == "" {
+		return fmt.Errorf("value can not be empty")
+	}
+
+	if r.RequestedBy == "" {
+		return fmt.Errorf("requested_by can not be empty")
+	}
+
+	return nil
+}
+
+func (r *DataSubjectRequest) ValidateErasure() error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	mode := entity.DataSubjectRequestType(r.Mode)
+	if mode != entity.DataSubjectRequestTypeDelete && mode != entity.DataSubjectRequestTypeAnonymize {
+		return fmt.Errorf("mode must be delete or anonymize")
+	}
+
+	return nil
+}
+
+func (r *DataSubjectRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(r)
+}
+
+func (r *DataSubjectRequest) ToEntity(tenantID string,
+	requestType entity.DataSubjectRequestType) *entity.DataSubjectRequest {
+	return &entity.DataSubjectRequest{
+		TenantID:    tenantID,
+		Type:        requestType,
+		Subject:     entity.Identifier{Type: entity.IdentifierType(r.Type), Value: r.Value},
+		RequestedBy: r.RequestedBy,
+		Channel:     "api",
+	}
+}
+
+type DataSubjectRequestsResponse struct {
+	Requests []*entity.DataSubjectRequest `json:"requests"`
+}
+
+func NewPrivacyAPI(config *core.Config, uc usecase.UseCase) *privacyAPI {
+	return &privacyAPI{config: config, uc: uc}
+}
+
+func (p *privacyAPI) Export(w http.ResponseWriter, r *http.Request) {
+	req := &DataSubjectRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data, err := p.uc.ExportSubjectData(r.Context(), req.ToEntity(r.PathValue("tenant_id"), entity.DataSubjectRequestTypeExport))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("tracking setting not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to export subject data", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to export subject data"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, data)
+}
+
+func (p *privacyAPI) Erase(w http.ResponseWriter, r *http.Request) {
+	req := &DataSubjectRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.ValidateErasure(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	request := req.ToEntity(r.PathValue("tenant_id"), entity.DataSubjectRequestType(req.Mode))
+	err := p.uc.EraseSubjectData(r.Context(), request)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("tracking setting not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to erase subject data", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to erase subject data"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, request)
+}
+
+func (p *privacyAPI) GetRequests(w http.ResponseWriter, r *http.Request) {
+	requests, err := p.uc.GetDataSubjectRequests(r.Context(), r.PathValue("tenant_id"))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("tracking setting not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to get data subject requests", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to get data subject requests"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, DataSubjectRequestsResponse{Requests: requests})
+}
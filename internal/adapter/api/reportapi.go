@@ -0,0 +1,151 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type reportAPI struct {
+	uc     usecase.UseCase
+	config *core.Config
+}
+
+func NewReportAPI(config *core.Config, uc usecase.UseCase) *reportAPI {
+	return &reportAPI{config: config, uc: uc}
+}
+
+type ReportRequest struct {
+	Metrics     []string // comma separated or repeated, every metric by default
+	Dimensions  []string // comma separated or repeated
+	Granularity string   // hour, day, week, month or total, day by default
+	From        string   // 2006-01-02 in the tenant time zone or RFC 3339, inclusive
+	To          string   // 2006-01-02 in the tenant time zone or RFC 3339, exclusive
+}
+
+// listParam reads a param given as a comma separated list or repeated
+func listParam(query url.Values, name string) []string {
+	values := []string{}
+	for _, param := range query[name] {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+func (r *ReportRequest) FromQuery(query url.Values) {
+	r.Metrics = listParam(query, "metrics")
+	r.Dimensions = listParam(query, "dimensions")
+	r.Granularity = query.Get("granularity")
+	r.From = query.Get("from")
+	r.To = query.Get("to")
+}
+
+func (r *ReportRequest) Validate() error {
+	query := r.ToEntity()
+	return query.Validate()
+}
+
+func (r *ReportRequest) ToEntity() entity.ReportQuery {
+	query := entity.ReportQuery{
+		Granularity: entity.ReportGranularity(r.Granularity),
+		From:        r.From,
+		To:          r.To,
+	}
+	for _, metric := range r.Metrics {
+		query.Metrics = append(query.Metrics, entity.ReportMetric(metric))
+	}
+	for _, dimension := range r.Dimensions {
+		query.Dimensions = append(query.Dimensions, entity.ReportDimension(dimension))
+	}
+	return query
+}
+
+type ReportRowResponse struct {
+	Time       *time.Time                        `json:"time,omitempty"`
+	Dimensions map[entity.ReportDimension]string `json:"dimensions"`
+	Metrics    map[entity.ReportMetric]float64   `json:"metrics"`
+}
+
+type ReportResponse struct {
+	TimeZone    string                   `json:"time_zone"`
+	Granularity entity.ReportGranularity `json:"granularity"`
+	From        time.Time                `json:"from"`
+	To          time.Time                `json:"to"`
+	Metrics     []entity.ReportMetric    `json:"metrics"`
+	Dimensions  []entity.ReportDimension `json:"dimensions"`
+	Rows        []*ReportRowResponse     `json:"rows"`
+	Totals      *ReportRowResponse       `json:"totals"`
+	Labels      map[string]string        `json:"labels"`
+}
+
+// reportRowResponse keeps the asked metrics of the row
+func reportRowResponse(row *entity.ReportRow, metrics []entity.ReportMetric) *ReportRowResponse {
+	res := &ReportRowResponse{
+		Time:       row.Time,
+		Dimensions: row.Dimensions,
+		Metrics:    map[entity.ReportMetric]float64{},
+	}
+	for _, metric := range metrics {
+		res.Metrics[metric] = row.Value(metric)
+	}
+	return res
+}
+
+func (rp *reportAPI) GetReport(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &ReportRequest{}
+	req.FromQuery(r.URL.Query())
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := rp.uc.GetReport(r.Context(), tenantID, req.ToEntity())
+	if errors.Is(err, usecase.ErrInvalidReportQuery) {
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("tracking setting not found"))
+		return
+	} else if err != nil {
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to get report"))
+		return
+	}
+
+	res := ReportResponse{
+		TimeZone:    report.TimeZone,
+		Granularity: report.Granularity,
+		From:        report.From,
+		To:          report.To,
+		Metrics:     report.Metrics,
+		Dimensions:  report.Dimensions,
+		Rows:        []*ReportRowResponse{},
+		Totals:      reportRowResponse(report.Totals, report.Metrics),
+		Labels:      report.Labels,
+	}
+	for _, row := range report.Rows {
+		res.Rows = append(res.Rows, reportRowResponse(row, report.Metrics))
+	}
+
+	_ = sendJson(w, http.StatusOK, res)
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github/michaellimmm/turakkingu/internal/core"
 	"github/michaellimmm/turakkingu/internal/entity"
@@ -9,15 +10,18 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 type trackAPI struct {
-	uc     usecase.UseCase
-	config *core.Config
+	uc       usecase.UseCase
+	config   *core.Config
+	clientIP *clientIPResolver
 }
 
 type CreateTrackRequest struct {
@@ -29,6 +33,8 @@ type CreateTrackRequest struct {
 	Platform          string            `json:"platform"`
 	GeneratedFrom     string            `json:"generated_from"` // source
 	Metadata          map[string]string `json:"metadata"`
+	UserAgent         string            `json:"user_agent"` // optional, user agent of the click
+	IP                string            `json:"ip"`         // optional, ip of the click
 }
 
 func (r *CreateTrackRequest) GetTrackingSettingID() (bson.ObjectID, error) {
@@ -53,6 +59,10 @@ func (r *CreateTrackRequest) Validate() error {
 		return fmt.Errorf("url is not valid")
 	}
 
+	if _, err := netip.ParseAddr(r.IP); r.IP != "" && err != nil {
+		return fmt.Errorf("ip is not valid")
+	}
+
 	if r.EndUserID == "" {
 		return fmt.Errorf("end_user_id can not be empty")
 	}
@@ -74,10 +84,14 @@ type CreateTrackResponse struct {
 }
 
 type TrackEventRequest struct {
-	TrackID     string `json:"track_id"`
-	URL         string `json:"url"`
-	Fingerprint string `json:"fp"`
-	PublishedAt int64  `json:"published_at"`
+	TrackID     string  `json:"track_id"`
+	URL         string  `json:"url"`
+	Fingerprint string  `json:"fp"`
+	PublishedAt int64   `json:"published_at"`
+	Consent     string  `json:"consent"` // granted, denied or unknown
+	Webdriver   bool    `json:"wd"`      // navigator.webdriver
+	Revenue     float64 `json:"revenue"` // of a conversion, used only up to the max_revenue of the thank you page
+	Event       string  `json:"event"`   // name of a custom event, a page view without it
 }
 
 func (t *TrackEventRequest) GetPublishedAt() time.Time {
@@ -99,9 +113,40 @@ func (t *TrackEventRequest) Validate() error {
 		return fmt.Errorf("url is not valid")
 	}
 
+	if t.Consent != "" && !entity.ConsentState(t.Consent).IsValid() {
+		return fmt.Errorf("consent is not valid")
+	}
+
+	if t.Revenue < 0 {
+		return fmt.Errorf("revenue can not be negative")
+	}
+
+	if t.Event != "" && !entity.IsValidCustomEventName(t.Event) {
+		return fmt.Errorf("event is not valid")
+	}
+
 	return nil
 }
 
+// GetConsent combines the script signal with the browser opt-out headers
+func (t *TrackEventRequest) GetConsent(header http.Header) entity.Consent {
+	gpc := header.Get("Sec-GPC") == "1"
+	dnt := header.Get("DNT") == "1"
+	return entity.NewConsent(entity.ConsentState(t.Consent), gpc, dnt)
+}
+
+// GetClientHints reads the user agent client hints, the high entropy ones are only sent after Accept-CH
+func (t *TrackEventRequest) GetClientHints(header http.Header) entity.ClientHints {
+	return entity.ClientHints{
+		Brands:          header.Get("Sec-CH-UA"),
+		FullVersionList: header.Get("Sec-CH-UA-Full-Version-List"),
+		Mobile:          header.Get("Sec-CH-UA-Mobile"),
+		Platform:        header.Get("Sec-CH-UA-Platform"),
+		PlatformVersion: header.Get("Sec-CH-UA-Platform-Version"),
+		Model:           header.Get("Sec-CH-UA-Model"),
+	}
+}
+
 func (t *TrackEventRequest) FromReader(rc io.ReadCloser) error {
 	defer func() {
 		_ = rc.Close()
@@ -117,7 +162,7 @@ func (t *TrackEventRequest) ToString() string {
 type TrackEventResponse struct{}
 
 func NewTrackAPI(config *core.Config, uc usecase.UseCase) *trackAPI {
-	return &trackAPI{config: config, uc: uc}
+	return &trackAPI{config: config, uc: uc, clientIP: newClientIPResolver(config.TrustedProxies)}
 }
 
 func (t *trackAPI) CreateTrack(w http.ResponseWriter, r *http.Request) {
@@ -143,8 +188,14 @@ func (t *trackAPI) CreateTrack(w http.ResponseWriter, r *http.Request) {
 		Platform:          req.Platform,
 		GeneratedFrom:     req.GeneratedFrom,
 		Metadata:          req.Metadata,
+		UserAgent:         req.UserAgent,
+		IP:                req.IP,
 	}
-	if err := t.uc.CreateTrack(r.Context(), track); err != nil {
+	err := t.uc.CreateTrack(r.Context(), track)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tracking setting not found"))
+		return
+	} else if err != nil {
 		slog.Error("failed to create new track", slog.String("error", err.Error()))
 		sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to create new track"))
 		return
@@ -176,11 +227,26 @@ func (t *trackAPI) TrackEvent(w http.ResponseWriter, r *http.Request) {
 	event := &entity.Event{
 		TrackID:     req.TrackID,
 		UserAgent:   r.UserAgent(),
+		Fingerprint: req.Fingerprint,
 		Url:         req.URL,
 		PublishedAt: req.GetPublishedAt(),
+		Consent:     req.GetConsent(r.Header),
+		ClientHints: req.GetClientHints(r.Header),
+		IP:          t.clientIP.FromRequest(r),
+		Language:    r.Header.Get("Accept-Language"),
+		Webdriver:   req.Webdriver,
+		Revenue:     req.Revenue,
+		Name:        req.Event,
+	}
+	if trackingSettingID, err := bson.ObjectIDFromHex(r.URL.Query().Get("tracking_id")); err == nil {
+		event.TrackingSettingID = trackingSettingID
 	}
 	err := t.uc.ProcessEvent(r.Context(), event)
-	if err != nil {
+	if errors.Is(err, usecase.ErrEventOriginNotAllowed) {
+		slog.Warn("event rejected", slog.String("url", event.Url), slog.String("track_id", event.TrackID))
+		_ = sendError(w, http.StatusForbidden, err)
+		return
+	} else if err != nil {
 		slog.Error("failed to process event", slog.String("error", err.Error()))
 		sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to add new event"))
 		return
@@ -188,3 +254,59 @@ func (t *trackAPI) TrackEvent(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+type TrackBreakdownRequest struct {
+	Dimension string
+	From      string // 2006-01-02 or RFC 3339, inclusive
+	To        string // 2006-01-02 or RFC 3339, exclusive
+}
+
+func (r *TrackBreakdownRequest) FromQuery(query url.Values) {
+	r.Dimension = query.Get("dimension")
+	r.From = query.Get("from")
+	r.To = query.Get("to")
+}
+
+func (r *TrackBreakdownRequest) Validate() error {
+	if !entity.TrackDimension(r.Dimension).IsValid() {
+		return fmt.Errorf("dimension is not valid")
+	}
+
+	if _, err := core.ParseTime(r.From); err != nil {
+		return fmt.Errorf("from is not valid")
+	}
+
+	if _, err := core.ParseTime(r.To); err != nil {
+		return fmt.Errorf("to is not valid")
+	}
+
+	return nil
+}
+
+func (t *trackAPI) GetBreakdown(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &TrackBreakdownRequest{}
+	req.FromQuery(r.URL.Query())
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	from, _ := core.ParseTime(req.From)
+	to, _ := core.ParseTime(req.To)
+	response, err := t.uc.GetTrackBreakdown(r.Context(), tenantID, entity.TrackDimension(req.Dimension), from, to)
+	if err != nil {
+		slog.Error("failed to get track breakdown", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to get track breakdown"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, response)
+}
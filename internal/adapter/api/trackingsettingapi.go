@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github/michaellimmm/turakkingu/internal/core"
 	"github/michaellimmm/turakkingu/internal/entity"
@@ -10,8 +11,10 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 type trackingSettingAPI struct {
@@ -20,10 +23,12 @@ type trackingSettingAPI struct {
 }
 
 type AddThankYouPageRequest struct {
-	TrackingSettingID string `json:"tracking_setting_id"`
-	URL               string `json:"url"`
-	Name              string `json:"name"`
-	Point             int    `json:"point"`
+	TrackingSettingID string  `json:"tracking_setting_id"`
+	URL               string  `json:"url"`
+	Name              string  `json:"name"`
+	Point             int     `json:"point"`
+	Revenue           float64 `json:"revenue"`     // of a conversion, unless the page sends its own
+	MaxRevenue        float64 `json:"max_revenue"` // a revenue sent by the page is only used up to it
 }
 
 func (r *AddThankYouPageRequest) GetTrackingSettingID() (bson.ObjectID, error) {
@@ -48,6 +53,14 @@ func (r *AddThankYouPageRequest) Validate() error {
 		return fmt.Errorf("url is not valid")
 	}
 
+	if r.Revenue < 0 {
+		return fmt.Errorf("revenue can not be negative")
+	}
+
+	if r.MaxRevenue < 0 {
+		return fmt.Errorf("max_revenue can not be negative")
+	}
+
 	return nil
 }
 
@@ -101,6 +114,8 @@ func (t *trackingSettingAPI) AddThankYouPage(w http.ResponseWriter, r *http.Requ
 		TrackingSettingID: trackingSettingID,
 		URL:               req.URL,
 		Point:             req.Point,
+		Revenue:           req.Revenue,
+		MaxRevenue:        req.MaxRevenue,
 		Name:              req.Name,
 	}
 	err = t.uc.AddThankYouPage(r.Context(), thankYouPage)
@@ -119,3 +134,439 @@ func (t *trackingSettingAPI) AddThankYouPage(w http.ResponseWriter, r *http.Requ
 
 	sendJson(w, http.StatusCreated, response)
 }
+
+type UpdateRetentionPolicyRequest struct {
+	EventDays int  `json:"event_days"`
+	TrackDays int  `json:"track_days"`
+	ClickDays int  `json:"click_days"`
+	Archive   bool `json:"archive"`
+}
+
+func (r *UpdateRetentionPolicyRequest) Validate() error {
+	if r.EventDays < 0 {
+		return fmt.Errorf("event_days can not be negative")
+	}
+
+	if r.TrackDays < 0 {
+		return fmt.Errorf("track_days can not be negative")
+	}
+
+	if r.ClickDays < 0 {
+		return fmt.Errorf("click_days can not be negative")
+	}
+
+	return nil
+}
+
+func (r *UpdateRetentionPolicyRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(r)
+}
+
+func (r *UpdateRetentionPolicyRequest) ToEntity() entity.RetentionPolicy {
+	return entity.RetentionPolicy{
+		EventDays: r.EventDays,
+		TrackDays: r.TrackDays,
+		ClickDays: r.ClickDays,
+		Archive:   r.Archive,
+	}
+}
+
+func (t *trackingSettingAPI) UpdateRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &UpdateRetentionPolicyRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := t.uc.UpdateRetentionPolicy(r.Context(), tenantID, req.ToEntity())
+	if errors.Is(err, usecase.ErrArchiveNotConfigured) {
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		slog.Error("failed to update retention policy", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update retention policy"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, response)
+}
+
+type UpdateConsentPolicyRequest struct {
+	DeniedMode string `json:"denied_mode"` // cookieless or drop
+}
+
+func (r *UpdateConsentPolicyRequest) Validate() error {
+	if !entity.ConsentMode(r.DeniedMode).IsValid() {
+		return fmt.Errorf("denied_mode must be cookieless or drop")
+	}
+
+	return nil
+}
+
+func (r *UpdateConsentPolicyRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(r)
+}
+
+func (r *UpdateConsentPolicyRequest) ToEntity() entity.ConsentPolicy {
+	return entity.ConsentPolicy{
+		DeniedMode: entity.ConsentMode(r.DeniedMode),
+	}
+}
+
+func (t *trackingSettingAPI) UpdateConsentPolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &UpdateConsentPolicyRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := t.uc.UpdateConsentPolicy(r.Context(), tenantID, req.ToEntity())
+	if err != nil {
+		slog.Error("failed to update consent policy", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update consent policy"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, response)
+}
+
+type UpdateDomainsRequest struct {
+	Domains []string `json:"domains"`
+}
+
+func (r *UpdateDomainsRequest) Validate() error {
+	if r.Domains == nil {
+		return fmt.Errorf("domains can not be empty")
+	}
+
+	return nil
+}
+
+func (r *UpdateDomainsRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(r)
+}
+
+const acceptClientHints = "Sec-CH-UA-Full-Version-List, Sec-CH-UA-Platform-Version, Sec-CH-UA-Model"
+
+// ScriptConfigResponse is the public part of the tracking setting loaded by conversion.js
+type ScriptConfigResponse struct {
+	Domains []string `json:"domains"`
+}
+
+func (t *trackingSettingAPI) UpdateDomains(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &UpdateDomainsRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := t.uc.UpdateDomains(r.Context(), tenantID, req.Domains)
+	if errors.Is(err, usecase.ErrInvalidDomain) {
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		slog.Error("failed to update domains", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update domains"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, response)
+}
+
+type UpdateLinkDomainsRequest struct {
+	LinkDomains []string `json:"link_domains"`
+}
+
+func (r *UpdateLinkDomainsRequest) Validate() error {
+	if r.LinkDomains == nil {
+		return fmt.Errorf("link_domains can not be empty")
+	}
+
+	return nil
+}
+
+func (r *UpdateLinkDomainsRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(r)
+}
+
+func (t *trackingSettingAPI) UpdateLinkDomains(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &UpdateLinkDomainsRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := t.uc.UpdateLinkDomains(r.Context(), tenantID, req.LinkDomains)
+	if errors.Is(err, usecase.ErrInvalidDomain) {
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, usecase.ErrLinkDomainTaken) {
+		_ = sendError(w, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		slog.Error("failed to update link domains", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update link domains"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, response)
+}
+
+func (t *trackingSettingAPI) GetScriptConfig(w http.ResponseWriter, r *http.Request) {
+	trackingSettingID, err := bson.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("id is not valid"))
+		return
+	}
+
+	trackingSetting, err := t.uc.GetTrackingSettingByID(r.Context(), trackingSettingID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("tracking setting not found"))
+		return
+	} else if err != nil {
+		slog.Error("failed to get tracking setting", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to get tracking setting"))
+		return
+	}
+
+	domains := trackingSetting.Domains
+	if domains == nil {
+		domains = []string{}
+	}
+	// ask for the high entropy client hints, they are sent on the following event requests
+	w.Header().Set("Accept-CH", acceptClientHints)
+	_ = sendJson(w, http.StatusOK, ScriptConfigResponse{Domains: domains})
+}
+
+type UpdateChannelRulesRequest struct {
+	Rules []entity.ChannelRule `json:"rules"`
+}
+
+func (r *UpdateChannelRulesRequest) Validate() error {
+	if r.Rules == nil {
+		return fmt.Errorf("rules can not be empty")
+	}
+
+	for i, rule := range r.Rules {
+		if !rule.Channel.IsValid() {
+			return fmt.Errorf("rules[%d].channel is not valid", i)
+		}
+		if rule.IsEmpty() {
+			return fmt.Errorf("rules[%d] needs sources, mediums or click_ids", i)
+		}
+	}
+
+	return nil
+}
+
+func (r *UpdateChannelRulesRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(r)
+}
+
+func (t *trackingSettingAPI) UpdateChannelRules(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &UpdateChannelRulesRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := t.uc.UpdateChannelRules(r.Context(), tenantID, req.Rules)
+	if err != nil {
+		slog.Error("failed to update channel rules", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update channel rules"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, response)
+}
+
+type UpdateBotPolicyRequest struct {
+	Mode string `json:"mode"` // exclude or discard
+}
+
+func (r *UpdateBotPolicyRequest) Validate() error {
+	if !entity.BotMode(r.Mode).IsValid() {
+		return fmt.Errorf("mode must be exclude or discard")
+	}
+
+	return nil
+}
+
+func (r *UpdateBotPolicyRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(r)
+}
+
+func (r *UpdateBotPolicyRequest) ToEntity() entity.BotPolicy {
+	return entity.BotPolicy{
+		Mode: entity.BotMode(r.Mode),
+	}
+}
+
+func (t *trackingSettingAPI) UpdateBotPolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &UpdateBotPolicyRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := t.uc.UpdateBotPolicy(r.Context(), tenantID, req.ToEntity())
+	if err != nil {
+		slog.Error("failed to update bot policy", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update bot policy"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, response)
+}
+
+type UpdateTimeZoneRequest struct {
+	TimeZone string `json:"time_zone"` // IANA name, e.g. Asia/Tokyo
+}
+
+func (r *UpdateTimeZoneRequest) Validate() error {
+	if _, err := time.LoadLocation(r.TimeZone); err != nil {
+		return fmt.Errorf("time_zone is not valid")
+	}
+
+	return nil
+}
+
+func (r *UpdateTimeZoneRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(r)
+}
+
+func (t *trackingSettingAPI) UpdateTimeZone(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.PathValue("tenant_id")
+	if tenantID == "" {
+		slog.Error("tenant id is empty")
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("tenant_id can not be empty"))
+		return
+	}
+
+	req := &UpdateTimeZoneRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := t.uc.UpdateTimeZone(r.Context(), tenantID, req.TimeZone)
+	if err != nil {
+		slog.Error("failed to update time zone", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusInternalServerError, fmt.Errorf("failed to update time zone"))
+		return
+	}
+
+	_ = sendJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+const visitorCookieName = "_zt_id"
+
+// visitorAPI serves the first-party identity endpoint. It is meant to be reached through a CNAME
+// on the client domain (e.g. t.client.com), so the HttpOnly cookie it sets is first-party and not
+// capped by Safari ITP like cookies set from javascript.
+type visitorAPI struct {
+	uc     usecase.UseCase
+	config *core.Config
+}
+
+type SetVisitorIdentityRequest struct {
+	ZTID string `json:"ztid"`
+	TS   int64  `json:"ts"`
+}
+
+func (r *SetVisitorIdentityRequest) Validate() error {
+	identity := entity.VisitorIdentity{ZTID: r.ZTID}
+	if !identity.IsValid() {
+		return fmt.Errorf("ztid is not valid")
+	}
+
+	return nil
+}
+
+func (r *SetVisitorIdentityRequest) FromReader(rc io.ReadCloser) error {
+	defer func() {
+		_ = rc.Close()
+	}()
+	return json.NewDecoder(rc).Decode(r)
+}
+
+func NewVisitorAPI(config *core.Config, uc usecase.UseCase) *visitorAPI {
+	return &visitorAPI{config: config, uc: uc}
+}
+
+func (v *visitorAPI) GetIdentity(w http.ResponseWriter, r *http.Request) {
+	identity := readVisitorCookie(r)
+	if identity == nil {
+		_ = sendError(w, http.StatusNotFound, fmt.Errorf("identity not found"))
+		return
+	}
+
+	// every read extends the cookie for another max age
+	identity.Refreshed = time.Now().UnixMilli()
+	v.writeCookie(w, r, identity)
+
+	_ = sendJson(w, http.StatusOK, identity)
+}
+
+func (v *visitorAPI) SetIdentity(w http.ResponseWriter, r *http.Request) {
+	req := &SetVisitorIdentityRequest{}
+	if err := req.FromReader(r.Body); err != nil {
+		slog.Error("failed to read request", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		slog.Error("request is not valid", slog.String("error", err.Error()))
+		_ = sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// browser opt-out, the script keeps the identity in memory
+	if r.Header.Get("Sec-GPC") == "1" || r.Header.Get("DNT") == "1" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	now := time.Now().UnixMilli()
+	identity := &entity.VisitorIdentity{ZTID: req.ZTID, TS: req.TS, Created: now}
+	if identity.TS == 0 {
+		identity.TS = now
+	}
+	if existing := readVisitorCookie(r); existing != nil && existing.ZTID == identity.ZTID {
+		identity.Created = existing.Created
+	}
+	v.writeCookie(w, r, identity)
+
+	_ = sendJson(w, http.StatusOK, identity)
+}
+
+func (v *visitorAPI) DeleteIdentity(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     visitorCookieName,
+		Value:    "",
+		Path:     "/",
+		Domain:   cookieDomain(r.Host),
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isSecure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readVisitorCookie returns the most recent valid identity, the request can carry a host-only and a domain cookie
+func readVisitorCookie(r *http.Request) *entity.VisitorIdentity {
+	var result *entity.VisitorIdentity
+	for _, cookie := range r.CookiesNamed(visitorCookieName) {
+		identity, ok := entity.DecodeVisitorIdentity(cookie.Value)
+		if !ok {
+			continue
+		}
+		if result == nil || identity.Created > result.Created {
+			result = identity
+		}
+	}
+	return result
+}
+
+func (v *visitorAPI) writeCookie(w http.ResponseWriter, r *http.Request, identity *entity.VisitorIdentity) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     visitorCookieName,
+		Value:    identity.Encode(),
+		Path:     "/",
+		Domain:   cookieDomain(r.Host),
+		MaxAge:   int(v.config.VisitorCookieMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   isSecure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// cookieDomain is the registrable domain of the host, so the cookie is shared with the client site
+func cookieDomain(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if net.ParseIP(host) != nil {
+		return ""
+	}
+
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return ""
+	}
+	return domain
+}
+
+func isSecure(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// isSameSite reports whether the origin is on the site of the requested host (the client CNAME)
+func isSameSite(r *http.Request, origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+
+	requestHost := r.Host
+	if h, _, err := net.SplitHostPort(requestHost); err == nil {
+		requestHost = h
+	}
+
+	originDomain := cookieDomain(u.Host)
+	requestDomain := cookieDomain(r.Host)
+	if originDomain == "" || requestDomain == "" {
+		// localhost or IP address
+		return u.Hostname() == requestHost
+	}
+	return originDomain == requestDomain
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookieDomain(t *testing.T) {
+	testcases := []struct {
+		name string
+		host string
+		want string
+	}{
+		{"subdomain of the client site", "t.dealer.com", "dealer.com"},
+		{"with a port", "t.dealer.com:8080", "dealer.com"},
+		{"deep subdomain", "a.b.dealer.com", "dealer.com"},
+		{"multi-label public suffix", "t.dealer.co.jp", "dealer.co.jp"},
+		{"registrable domain itself", "dealer.com", "dealer.com"},
+		{"ip address", "192.0.2.1:8080", ""},
+		{"ipv6 address", "[::1]:8080", ""},
+		{"localhost", "localhost:8080", ""},
+		{"public suffix only", "co.jp", ""},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.want, cookieDomain(tcase.host))
+		})
+	}
+}
+
+func TestIsSameSite(t *testing.T) {
+	testcases := []struct {
+		name   string
+		host   string
+		origin string
+		want   bool
+	}{
+		{"client site", "t.dealer.com", "https://www.dealer.com", true},
+		{"client site with ports", "t.dealer.com:8080", "https://dealer.com:8443", true},
+		{"other site", "t.dealer.com", "https://evil.com", false},
+		{"other site on the same public suffix", "t.dealer.co.jp", "https://other.co.jp", false},
+		{"same localhost", "localhost:8080", "http://localhost:3000", true},
+		{"same ip", "192.0.2.1:8080", "http://192.0.2.1", true},
+		{"other ip", "192.0.2.1:8080", "http://192.0.2.2", false},
+		{"invalid origin", "t.dealer.com", "null", false},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/v1/visitor/identity", nil)
+			r.Host = tcase.host
+			assert.Equal(t, tcase.want, isSameSite(r, tcase.origin))
+		})
+	}
+}
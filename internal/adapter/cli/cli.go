@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"io"
+	"os"
+)
+
+type CLI interface {
+	Run(ctx context.Context, args []string) error
+}
+
+type cli struct {
+	uc     usecase.UseCase
+	config *core.Config
+	stdout io.Writer
+}
+
+func NewCLI(config *core.Config, uc usecase.UseCase) CLI {
+	return &cli{
+		uc:     uc,
+		config: config,
+		stdout: os.Stdout,
+	}
+}
+
+const usage = `usage: turakkingu <command> <subcommand> [flags]
+
+commands:
+  privacy export|delete|anonymize|requests   data subject requests (GDPR/CCPA)
+  retention archive|restore                  archive expired raw data, restore an archive
+  rollup run|rebuild                         catch the report rollups up, count them again`
+
+func (c *cli) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", usage)
+	}
+
+	switch args[0] {
+	case "privacy":
+		return c.privacy(ctx, args[1:])
+	case "retention":
+		return c.retention(ctx, args[1:])
+	case "rollup":
+		return c.rollup(ctx, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"io"
+	"os"
+)
+
+const privacyUsage = `usage: turakkingu privacy <export|delete|anonymize|requests> -tenant <tenant_id> -type <end_user_id|fingerprint|ztid> -value <value> [-requested-by <name>] [-out <file>]`
+
+func (c *cli) privacy(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", privacyUsage)
+	}
+
+	subcommand := args[0]
+	requestType := entity.DataSubjectRequestType(subcommand)
+	if subcommand != "requests" && !requestType.IsValid() {
+		return fmt.Errorf("unknown subcommand %q\n%s", subcommand, privacyUsage)
+	}
+
+	fs := flag.NewFlagSet("privacy "+subcommand, flag.ContinueOnError)
+	tenantID := fs.String("tenant", "", "tenant of the subject")
+	identifierType := fs.String("type", "", "identifier type: end_user_id, fingerprint or ztid")
+	value := fs.String("value", "", "identifier value")
+	requestedBy := fs.String("requested-by", os.Getenv("USER"), "who requested the operation, kept in the audit record")
+	out := fs.String("out", "", "export output file, stdout when empty")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *tenantID == "" {
+		return fmt.Errorf("-tenant is required\n%s", privacyUsage)
+	}
+	if subcommand == "requests" {
+		requests, err := c.uc.GetDataSubjectRequests(ctx, *tenantID)
+		if err != nil {
+			return err
+		}
+		return writeJson(c.stdout, requests)
+	}
+
+	if !entity.IdentifierType(*identifierType).IsValid() || *value == "" {
+		return fmt.Errorf("-type and -value are required\n%s", privacyUsage)
+	}
+	if *requestedBy == "" {
+		return fmt.Errorf("-requested-by can not be empty")
+	}
+
+	request := &entity.DataSubjectRequest{
+		TenantID:    *tenantID,
+		Type:        requestType,
+		Subject:     entity.Identifier{Type: entity.IdentifierType(*identifierType), Value: *value},
+		RequestedBy: *requestedBy,
+		Channel:     "cli",
+	}
+
+	if requestType != entity.DataSubjectRequestTypeExport {
+		if err := c.uc.EraseSubjectData(ctx, request); err != nil {
+			return err
+		}
+		return writeJson(c.stdout, request)
+	}
+
+	data, err := c.uc.ExportSubjectData(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return writeJson(c.stdout, data)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return writeJson(f, data)
+}
+
+func writeJson(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
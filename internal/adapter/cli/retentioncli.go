@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+)
+
+const retentionUsage = `usage: turakkingu retention <archive|restore> [-key <archive key>]`
+
+func (c *cli) retention(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", retentionUsage)
+	}
+
+	switch args[0] {
+	case "archive":
+		keys, err := c.uc.ArchiveExpired(ctx)
+		if err != nil {
+			return err
+		}
+		return writeJson(c.stdout, map[string]any{"archives": keys})
+	case "restore":
+		fs := flag.NewFlagSet("retention restore", flag.ContinueOnError)
+		key := fs.String("key", "", "archive key, e.g. event/2025/01/31/event-3f2a9c1d5e7b8a60.ndjson.gz")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *key == "" {
+			return fmt.Errorf("-key is required\n%s", retentionUsage)
+		}
+
+		restored, err := c.uc.RestoreArchive(ctx, *key)
+		if err != nil {
+			return err
+		}
+		return writeJson(c.stdout, map[string]any{"key": *key, "restored": restored})
+	default:
+		return fmt.Errorf("unknown subcommand %q\n%s", args[0], retentionUsage)
+	}
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+)
+
+const rollupUsage = `usage: turakkingu rollup <run|rebuild -from <time> [-to <time>]>`
+
+func (c *cli) rollup(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", rollupUsage)
+	}
+
+	switch args[0] {
+	case "run":
+		run, err := c.uc.RollupReports(ctx)
+		if err != nil {
+			return err
+		}
+		return writeJson(c.stdout, run)
+	case "rebuild":
+		fs := flag.NewFlagSet("rollup rebuild", flag.ContinueOnError)
+		from := fs.String("from", "", "first hour to count again, 2006-01-02 (UTC) or RFC 3339, required")
+		to := fs.String("to", "", "hour to stop at, 2006-01-02 (UTC) or RFC 3339, now when empty")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		fromTime, err := core.ParseTime(*from)
+		if err != nil || fromTime.IsZero() {
+			return fmt.Errorf("-from is not valid\n%s", rollupUsage)
+		}
+		toTime, err := core.ParseTime(*to)
+		if err != nil {
+			return fmt.Errorf("-to is not valid\n%s", rollupUsage)
+		}
+
+		run, err := c.uc.RebuildRollups(ctx, fromTime, toTime)
+		if err != nil {
+			return err
+		}
+		return writeJson(c.stdout, run)
+	default:
+		return fmt.Errorf("unknown subcommand %q\n%s", args[0], rollupUsage)
+	}
+}
@@ -0,0 +1,101 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job runs the periodic background work next to the servers
+type Job interface {
+	Run() error
+	Close(context.Context) error
+}
+
+type job struct {
+	uc     usecase.UseCase
+	config *core.Config
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewJob(config *core.Config, uc usecase.UseCase) Job {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &job{
+		uc:     uc,
+		config: config,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+}
+
+func (j *job) Run() error {
+	defer close(j.done)
+
+	var wg sync.WaitGroup
+	if j.config.ArchiveInterval > 0 {
+		j.every(&wg, j.config.ArchiveInterval, j.archive)
+	}
+	if j.config.RollupInterval > 0 {
+		j.every(&wg, j.config.RollupInterval, j.rollup)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// every runs the task now and then at each interval until the job is closed
+func (j *job) every(wg *sync.WaitGroup, interval time.Duration, task func()) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			task()
+
+			select {
+			case <-j.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (j *job) archive() {
+	_, err := j.uc.ArchiveExpired(j.ctx)
+	if errors.Is(err, usecase.ErrArchiveNotConfigured) || errors.Is(err, context.Canceled) {
+		return
+	} else if err != nil {
+		slog.Error("failed to archive expired documents", slog.String("error", err.Error()))
+	}
+}
+
+func (j *job) rollup() {
+	_, err := j.uc.RollupReports(j.ctx)
+	if errors.Is(err, context.Canceled) {
+		return
+	} else if err != nil {
+		slog.Error("failed to roll up reports", slog.String("error", err.Error()))
+	}
+}
+
+func (j *job) Close(ctx context.Context) error {
+	j.cancel()
+
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
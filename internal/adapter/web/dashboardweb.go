@@ -0,0 +1,333 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	webui "github/michaellimmm/turakkingu/web"
+)
+
+// dashboardTopLinks is the size of the top links table
+const dashboardTopLinks = 10
+
+// dashboard chart size, see DashboardChartView
+const (
+	chartWidth  = 800
+	chartHeight = 200
+)
+
+type dashboardWeb struct {
+	uc     usecase.UseCase
+	config *core.Config
+}
+
+func NewDashboardWeb(config *core.Config, uc usecase.UseCase) *dashboardWeb {
+	return &dashboardWeb{
+		uc:     uc,
+		config: config,
+	}
+}
+
+// dashboardRange is the days of the dashboard in the tenant time zone, to is exclusive
+type dashboardRange struct {
+	from time.Time
+	to   time.Time
+	days int
+}
+
+func (d dashboardRange) granularity() entity.ReportGranularity {
+	switch {
+	case d.days <= 2:
+		return entity.ReportGranularityHour
+	case d.days <= 92:
+		return entity.ReportGranularityDay
+	default:
+		return entity.ReportGranularityWeek
+	}
+}
+
+func (d dashboardRange) previous() dashboardRange {
+	return dashboardRange{from: d.from.AddDate(0, 0, -d.days), to: d.from, days: d.days}
+}
+
+func (d dashboardRange) query(granularity entity.ReportGranularity, dimensions ...entity.ReportDimension) entity.ReportQuery {
+	return entity.ReportQuery{
+		Granularity: granularity,
+		Dimensions:  dimensions,
+		From:        d.from.Format(time.RFC3339),
+		To:          d.to.Format(time.RFC3339),
+	}
+}
+
+// label is the inclusive range as the form shows it
+func (d dashboardRange) label() string {
+	return d.from.Format(time.DateOnly) + " to " + d.to.AddDate(0, 0, -1).Format(time.DateOnly)
+}
+
+// parseDashboardRange reads the from and to dates of the form, the last 30 days by default
+func parseDashboardRange(r *http.Request, loc *time.Location) (dashboardRange, error) {
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	to := today
+	if s := r.FormValue("to"); s != "" {
+		t, err := time.ParseInLocation(time.DateOnly, s, loc)
+		if err != nil {
+			return dashboardRange{}, fmt.Errorf("to is not a valid date")
+		}
+		to = t
+	}
+
+	from := to.AddDate(0, 0, -29)
+	if s := r.FormValue("from"); s != "" {
+		t, err := time.ParseInLocation(time.DateOnly, s, loc)
+		if err != nil {
+			return dashboardRange{}, fmt.Errorf("from is not a valid date")
+		}
+		from = t
+	}
+
+	if from.After(to) {
+		return dashboardRange{}, fmt.Errorf("from must be before to")
+	}
+
+	end := to.AddDate(0, 0, 1)
+	days := 0
+	for t := from; t.Before(end); t = t.AddDate(0, 0, 1) {
+		days++
+	}
+	return dashboardRange{from: from, to: end, days: days}, nil
+}
+
+// TODO: fix this
+func (d *dashboardWeb) Index(w http.ResponseWriter, r *http.Request) {
+	dashboard := d.dashboard(r)
+
+	component := webui.DashboardContent(dashboard)
+	if r.Header.Get("HX-Target") == "dashboard-body" {
+		component = webui.DashboardBody(dashboard)
+	}
+	component.Render(context.Background(), w)
+}
+
+func (d *dashboardWeb) dashboard(r *http.Request) webui.Dashboard {
+	ctx := r.Context()
+	dashboard := webui.Dashboard{
+		From:    r.FormValue("from"),
+		To:      r.FormValue("to"),
+		Compare: r.FormValue("compare") == "true" || r.FormValue("from") == "",
+	}
+
+	loc := time.UTC
+	if setting, err := d.uc.GetTrackingSettingByTenantID(ctx, "tenant1"); err == nil && setting.TimeZone != "" {
+		if l, err := time.LoadLocation(setting.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	dashboard.TimeZone = loc.String()
+
+	rng, err := parseDashboardRange(r, loc)
+	if err != nil {
+		dashboard.Error = err.Error()
+		return dashboard
+	}
+	dashboard.From = rng.from.Format(time.DateOnly)
+	dashboard.To = rng.to.AddDate(0, 0, -1).Format(time.DateOnly)
+
+	series, err := d.uc.GetReport(ctx, "tenant1", rng.query(rng.granularity()))
+	if err != nil {
+		dashboard.Error = "failed to get the report, " + err.Error()
+		return dashboard
+	}
+
+	links, err := d.uc.GetReport(ctx, "tenant1", rng.query(entity.ReportGranularityTotal, entity.ReportDimensionLink))
+	if err != nil {
+		dashboard.Error = "failed to get the report of the links, " + err.Error()
+		return dashboard
+	}
+
+	var previous *entity.ReportRow
+	if dashboard.Compare {
+		prev := rng.previous()
+		report, err := d.uc.GetReport(ctx, "tenant1", prev.query(entity.ReportGranularityTotal))
+		if err != nil {
+			dashboard.Error = "failed to get the report of the previous period, " + err.Error()
+			return dashboard
+		}
+		previous = report.Totals
+		dashboard.Previous = prev.label()
+	}
+
+	dashboard.KPIs = dashboardKPIs(series.Totals, previous)
+	dashboard.Chart = dashboardChart(series, rng)
+	dashboard.TopLinks = dashboardTopLinkRows(links)
+	return dashboard
+}
+
+func dashboardKPIs(current, previous *entity.ReportRow) []webui.DashboardKPI {
+	kpis := []webui.DashboardKPI{}
+	for _, metric := range []entity.ReportMetric{
+		entity.ReportMetricClicks,
+		entity.ReportMetricLandings,
+		entity.ReportMetricConversions,
+		entity.ReportMetricConversionRate,
+		entity.ReportMetricRevenue,
+	} {
+		value := current.Value(metric)
+		kpi := webui.DashboardKPI{
+			Label: strings.ToUpper(string(metric[:1])) + strings.ReplaceAll(string(metric[1:]), "_", " "),
+			Value: formatMetric(metric, value),
+		}
+
+		if previous != nil {
+			prev := previous.Value(metric)
+			kpi.Up = value >= prev
+			switch {
+			case metric == entity.ReportMetricConversionRate:
+				// rates are compared in percentage points
+				kpi.Change = fmt.Sprintf("%+.1f pt", (value-prev)*100)
+			case prev == 0 && value == 0:
+				kpi.Change = "+0.0%"
+			case prev == 0:
+				kpi.Change = "new"
+			default:
+				kpi.Change = fmt.Sprintf("%+.1f%%", (value-prev)/prev*100)
+			}
+		}
+		kpis = append(kpis, kpi)
+	}
+	return kpis
+}
+
+func formatMetric(metric entity.ReportMetric, value float64) string {
+	switch metric {
+	case entity.ReportMetricConversionRate:
+		return fmt.Sprintf("%.1f%%", value*100)
+	case entity.ReportMetricRevenue:
+		return fmt.Sprintf("%.2f", value)
+	default:
+		return strconv.FormatInt(int64(value), 10)
+	}
+}
+
+// dashboardChart plots every bucket of the range, the buckets without traffic are zero
+func dashboardChart(report *entity.Report, rng dashboardRange) webui.DashboardChart {
+	rows := map[int64]*entity.ReportRow{}
+	for _, row := range report.Rows {
+		if row.Time != nil {
+			rows[row.Time.Unix()] = row
+		}
+	}
+
+	start := rng.from
+	step := func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	layout := time.DateOnly
+	switch report.Granularity {
+	case entity.ReportGranularityHour:
+		step = func(t time.Time) time.Time { return t.Add(time.Hour) }
+		layout = "2006-01-02 15:04"
+	case entity.ReportGranularityWeek:
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7)) // monday
+		step = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	}
+
+	buckets := []*entity.ReportRow{}
+	chart := webui.DashboardChart{Start: start.Format(layout)}
+	for t := start; t.Before(rng.to); t = step(t) {
+		row, ok := rows[t.Unix()]
+		if !ok {
+			row = &entity.ReportRow{}
+		}
+		buckets = append(buckets, row)
+		chart.End = t.Format(layout)
+	}
+
+	metrics := []struct {
+		metric entity.ReportMetric
+		name   string
+		color  string
+	}{
+		{entity.ReportMetricClicks, "Clicks", "#2563eb"},
+		{entity.ReportMetricLandings, "Landings", "#f59e0b"},
+		{entity.ReportMetricConversions, "Conversions", "#16a34a"},
+	}
+
+	top := 0.0
+	for _, row := range buckets {
+		for _, m := range metrics {
+			if v := row.Value(m.metric); v > top {
+				top = v
+			}
+		}
+	}
+	chart.Empty = top == 0
+	chart.Max = strconv.FormatInt(int64(top), 10)
+	if top == 0 {
+		top = 1
+	}
+
+	for _, m := range metrics {
+		points := []string{}
+		for i, row := range buckets {
+			x := float64(chartWidth) / 2
+			if len(buckets) > 1 {
+				x = float64(i*chartWidth) / float64(len(buckets)-1)
+			}
+			y := chartHeight - row.Value(m.metric)*chartHeight/top
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+		}
+		chart.Lines = append(chart.Lines, webui.DashboardLine{
+			Name:   m.name,
+			Color:  m.color,
+			Points: strings.Join(points, " "),
+			Total:  formatMetric(m.metric, report.Totals.Value(m.metric)),
+		})
+	}
+	return chart
+}
+
+// dashboardTopLinkRows keeps the links with the most conversions, then clicks
+func dashboardTopLinkRows(report *entity.Report) []webui.DashboardTopLink {
+	rows := []*entity.ReportRow{}
+	for _, row := range report.Rows {
+		if row.Dimensions[entity.ReportDimensionLink] != "" {
+			rows = append(rows, row)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Conversions != rows[j].Conversions {
+			return rows[i].Conversions > rows[j].Conversions
+		}
+		return rows[i].Clicks > rows[j].Clicks
+	})
+	if len(rows) > dashboardTopLinks {
+		rows = rows[:dashboardTopLinks]
+	}
+
+	res := []webui.DashboardTopLink{}
+	for _, row := range rows {
+		id := row.Dimensions[entity.ReportDimensionLink]
+		name, ok := report.Labels[id]
+		if !ok {
+			name = id // removed link
+		}
+		res = append(res, webui.DashboardTopLink{
+			Name:           name,
+			Clicks:         formatMetric(entity.ReportMetricClicks, row.Value(entity.ReportMetricClicks)),
+			Landings:       formatMetric(entity.ReportMetricLandings, row.Value(entity.ReportMetricLandings)),
+			Conversions:    formatMetric(entity.ReportMetricConversions, row.Value(entity.ReportMetricConversions)),
+			ConversionRate: formatMetric(entity.ReportMetricConversionRate, row.Value(entity.ReportMetricConversionRate)),
+			Revenue:        formatMetric(entity.ReportMetricRevenue, row.Value(entity.ReportMetricRevenue)),
+		})
+	}
+	return res
+}
@@ -0,0 +1,113 @@
+package web
+
+import (
+	"github/michaellimmm/turakkingu/internal/entity"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	_ "time/tzdata" // time zones of the tenants, without relying on the host
+
+	webui "github/michaellimmm/turakkingu/web"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDashboardRange(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	testcases := []struct {
+		name    string
+		query   string
+		loc     *time.Location
+		from    time.Time
+		to      time.Time // exclusive
+		days    int
+		isValid bool
+	}{
+		{
+			"single day", "from=2025-03-01&to=2025-03-01", tokyo,
+			time.Date(2025, 3, 1, 0, 0, 0, 0, tokyo), time.Date(2025, 3, 2, 0, 0, 0, 0, tokyo), 1, true,
+		},
+		{
+			"last 30 days to the date", "to=2025-03-31", tokyo,
+			time.Date(2025, 3, 2, 0, 0, 0, 0, tokyo), time.Date(2025, 4, 1, 0, 0, 0, 0, tokyo), 30, true,
+		},
+		{
+			"days across a daylight saving change", "from=2025-03-08&to=2025-03-10", newYork,
+			time.Date(2025, 3, 8, 0, 0, 0, 0, newYork), time.Date(2025, 3, 11, 0, 0, 0, 0, newYork), 3, true,
+		},
+		{"from after to", "from=2025-03-02&to=2025-03-01", tokyo, time.Time{}, time.Time{}, 0, false},
+		{"invalid date", "from=2025-02-30", tokyo, time.Time{}, time.Time{}, 0, false},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/dashboard?"+tcase.query, nil)
+			rng, err := parseDashboardRange(r, tcase.loc)
+			assert.Equal(t, tcase.isValid, err == nil, err)
+			assert.True(t, tcase.from.Equal(rng.from), rng.from)
+			assert.True(t, tcase.to.Equal(rng.to), rng.to)
+			assert.Equal(t, tcase.days, rng.days)
+		})
+	}
+}
+
+func TestDashboardRange_Previous(t *testing.T) {
+	rng := dashboardRange{
+		from: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		to:   time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC),
+		days: 7,
+	}
+
+	previous := rng.previous()
+	assert.Equal(t, time.Date(2025, 2, 22, 0, 0, 0, 0, time.UTC), previous.from)
+	assert.Equal(t, rng.from, previous.to)
+	assert.Equal(t, 7, previous.days)
+	assert.Equal(t, "2025-02-22 to 2025-02-28", previous.label())
+	assert.Equal(t, entity.ReportGranularityDay, previous.granularity())
+}
+
+func TestDashboardKPIs(t *testing.T) {
+	current := &entity.ReportRow{Clicks: 120, Landings: 0, Conversions: 5, ConversionRate: 0.05, Revenue: 250}
+
+	t.Run("should show the values without comparison", func(t *testing.T) {
+		kpis := dashboardKPIs(current, nil)
+		assert.Equal(t, []webui.DashboardKPI{
+			{Label: "Clicks", Value: "120"},
+			{Label: "Landings", Value: "0"},
+			{Label: "Conversions", Value: "5"},
+			{Label: "Conversion rate", Value: "5.0%"},
+			{Label: "Revenue", Value: "250.00"},
+		}, kpis)
+	})
+
+	t.Run("should compare with the previous period", func(t *testing.T) {
+		previous := &entity.ReportRow{Clicks: 100, Landings: 0, Conversions: 0, ConversionRate: 0.08, Revenue: 500}
+		kpis := dashboardKPIs(current, previous)
+
+		testcases := []struct {
+			label  string
+			change string
+			up     bool
+		}{
+			{"Clicks", "+20.0%", true},
+			{"Landings", "+0.0%", true},
+			{"Conversions", "new", true},
+			{"Conversion rate", "-3.0 pt", false},
+			{"Revenue", "-50.0%", false},
+		}
+
+		assert.Len(t, kpis, len(testcases))
+		for i, tcase := range testcases {
+			t.Run(tcase.label, func(t *testing.T) {
+				assert.Equal(t, tcase.label, kpis[i].Label)
+				assert.Equal(t, tcase.change, kpis[i].Change)
+				assert.Equal(t, tcase.up, kpis[i].Up)
+			})
+		}
+	})
+}
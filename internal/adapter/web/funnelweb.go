@@ -0,0 +1,204 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	webui "github/michaellimmm/turakkingu/web"
+)
+
+type funnelWeb struct {
+	uc     usecase.UseCase
+	config *core.Config
+}
+
+func NewFunnelWeb(config *core.Config, uc usecase.UseCase) *funnelWeb {
+	return &funnelWeb{
+		uc:     uc,
+		config: config,
+	}
+}
+
+// TODO: fix this
+func (f *funnelWeb) Index(w http.ResponseWriter, r *http.Request) {
+	funnels := f.funnels(r, r.FormValue("funnel_id"))
+
+	component := webui.FunnelsContent(funnels)
+	if r.Header.Get("HX-Target") == "funnels-body" {
+		component = webui.FunnelsBody(funnels)
+	}
+	component.Render(context.Background(), w)
+}
+
+// Create adds the funnel of the form and shows it
+func (f *funnelWeb) Create(w http.ResponseWriter, r *http.Request) {
+	funnel, err := parseFunnelForm(r)
+	if err == nil {
+		err = funnel.Validate()
+	}
+	if err == nil {
+		err = f.uc.CreateFunnel(r.Context(), funnel)
+	}
+
+	selected := r.FormValue("funnel_id")
+	if funnel != nil && !funnel.ID.IsZero() {
+		selected = funnel.ID.Hex()
+	}
+	funnels := f.funnels(r, selected)
+	if err != nil {
+		funnels.FormError = err.Error()
+	}
+	webui.FunnelsContent(funnels).Render(context.Background(), w)
+}
+
+func (f *funnelWeb) funnels(r *http.Request, selected string) webui.Funnels {
+	ctx := r.Context()
+	view := webui.Funnels{
+		Dimension: r.FormValue("dimension"),
+		From:      r.FormValue("from"),
+		To:        r.FormValue("to"),
+	}
+
+	loc := time.UTC
+	if setting, err := f.uc.GetTrackingSettingByTenantID(ctx, "tenant1"); err == nil && setting.TimeZone != "" {
+		if l, err := time.LoadLocation(setting.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	view.TimeZone = loc.String()
+
+	funnels, err := f.uc.GetFunnels(ctx, "tenant1")
+	if err != nil {
+		view.Error = "failed to get the funnels, " + err.Error()
+		return view
+	}
+	if len(funnels) == 0 {
+		return view
+	}
+
+	var funnel *entity.Funnel
+	for _, candidate := range funnels {
+		if candidate.ID.Hex() == selected {
+			funnel = candidate
+		}
+	}
+	if funnel == nil {
+		funnel = funnels[0]
+	}
+	for _, candidate := range funnels {
+		view.Funnels = append(view.Funnels, webui.FunnelOption{
+			ID:       candidate.ID.Hex(),
+			Name:     candidate.Name,
+			Selected: candidate == funnel,
+		})
+	}
+	view.FunnelID = funnel.ID.Hex()
+	view.Window = fmt.Sprintf("%d hours", funnel.WindowHours)
+
+	rng, err := parseDashboardRange(r, loc)
+	if err != nil {
+		view.Error = err.Error()
+		return view
+	}
+	view.From = rng.from.Format(time.DateOnly)
+	view.To = rng.to.AddDate(0, 0, -1).Format(time.DateOnly)
+
+	dimension := entity.FunnelDimension(view.Dimension)
+	if !dimension.IsValid() {
+		dimension = ""
+		view.Dimension = ""
+	}
+
+	report, err := f.uc.GetFunnelReport(ctx, view.FunnelID, dimension, rng.from, rng.to)
+	if err != nil {
+		view.Error = "failed to get the funnel report, " + err.Error()
+		return view
+	}
+
+	for _, step := range report.Total.Steps {
+		view.StepNames = append(view.StepNames, step.Name)
+		view.Steps = append(view.Steps, webui.FunnelStepView{
+			Name:        step.Name,
+			Tracks:      strconv.FormatInt(step.Tracks, 10),
+			Rate:        formatRate(step.Rate),
+			DropOff:     strconv.FormatInt(step.DropOff, 10),
+			DropOffRate: formatRate(step.DropOffRate),
+			Width:       fmt.Sprintf("width: %.1f%%", step.Rate*100),
+		})
+	}
+
+	for _, row := range report.Rows {
+		group := webui.FunnelGroupView{Name: row.Value}
+		if name, ok := report.Labels[row.Value]; ok {
+			group.Name = name
+		} else if group.Name == "" {
+			group.Name = "(none)"
+		}
+		for _, step := range row.Steps {
+			group.Tracks = append(group.Tracks, strconv.FormatInt(step.Tracks, 10))
+		}
+		group.Rate = formatRate(row.Steps[len(row.Steps)-1].Rate)
+		view.Groups = append(view.Groups, group)
+	}
+
+	return view
+}
+
+func formatRate(rate float64) string {
+	return fmt.Sprintf("%.1f%%", rate*100)
+}
+
+// parseFunnelForm reads the name, the window and a step per line of the form, e.g.
+// "Car page: url prefix /cars/"
+func parseFunnelForm(r *http.Request) (*entity.Funnel, error) {
+	funnel := &entity.Funnel{
+		TenantID:    "tenant1",
+		Name:        strings.TrimSpace(r.FormValue("name")),
+		WindowHours: 7 * 24,
+	}
+
+	if s := strings.TrimSpace(r.FormValue("window_hours")); s != "" {
+		hours, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("window is not a number of hours")
+		}
+		funnel.WindowHours = hours
+	}
+
+	for _, line := range strings.Split(r.FormValue("steps"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name, rule, ok := strings.Cut(line, ":")
+		fields := strings.Fields(rule)
+		if !ok || len(fields) == 0 {
+			return nil, fmt.Errorf("step %q is not name: rule", line)
+		}
+
+		step := &entity.FunnelStep{Name: strings.TrimSpace(name), Type: entity.FunnelStepType(fields[0])}
+		switch step.Type {
+		case entity.FunnelStepURL:
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("step %q is not url exact|prefix|contains value", line)
+			}
+			step.Match = entity.URLMatch(fields[1])
+			step.Value = strings.Join(fields[2:], " ")
+		case entity.FunnelStepEvent, entity.FunnelStepConversion:
+			if len(fields) > 1 {
+				step.Value = fields[1]
+			}
+		}
+		funnel.Steps = append(funnel.Steps, step)
+	}
+
+	return funnel, nil
+}
@@ -2,14 +2,20 @@ package web
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github/michaellimmm/turakkingu/internal/core"
 	"github/michaellimmm/turakkingu/internal/entity"
 	"github/michaellimmm/turakkingu/internal/usecase"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	webui "github/michaellimmm/turakkingu/web"
 
 	"github.com/a-h/templ"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type linkWeb struct {
@@ -24,47 +30,63 @@ func NewLinkWeb(config *core.Config, uc usecase.UseCase) *linkWeb {
 	}
 }
 
+func (l *linkWeb) landingPages(links []*entity.Link) []webui.LandingPage {
+	res := []webui.LandingPage{}
+	for _, link := range links {
+		res = append(res, webui.LandingPage{
+			ID:              link.ID.Hex(),
+			LandingPageName: link.Name,
+			LandingPageURL:  link.Url,
+			FixedURL:        link.ConstructFixedUrl(l.config.Domain),
+			Tags:            link.Tags,
+		})
+	}
+	return res
+}
+
+func (l *linkWeb) campaigns(ctx context.Context) []webui.LandingPageCampaign {
+	res := []webui.LandingPageCampaign{}
+	campaigns, err := l.uc.GetLinkCampaigns(ctx, "tenant1")
+	if err != nil {
+		return res
+	}
+	for _, campaign := range campaigns {
+		res = append(res, webui.LandingPageCampaign{ID: campaign.ID.Hex(), Name: campaign.Name})
+	}
+	return res
+}
+
 func (l *linkWeb) Index(w http.ResponseWriter, r *http.Request) {
 	var component templ.Component
 
 	// TODO: fix this
 	links, err := l.uc.GetAllLinks(r.Context(), "tenant1")
 	if err != nil {
-		component = webui.LandingPagesContent([]webui.LandingPage{})
+		component = webui.LandingPagesContent([]webui.LandingPage{}, l.campaigns(r.Context()))
 	} else {
-		res := []webui.LandingPage{}
-		for _, link := range links {
-			res = append(res, webui.LandingPage{
-				ID:              link.ID.Hex(),
-				LandingPageName: link.Name,
-				LandingPageURL:  link.Url,
-				FixedURL:        link.ConstructFixedUrl(l.config.Domain),
-			})
-		}
-		component = webui.LandingPagesContent(res)
+		component = webui.LandingPagesContent(l.landingPages(links), l.campaigns(r.Context()))
 	}
 
 	component.Render(context.Background(), w)
 }
 
 func (l *linkWeb) Search(w http.ResponseWriter, r *http.Request) {
-	query := r.FormValue("search")
+	search := entity.LinkSearch{Query: r.FormValue("search")}
+	if tag := r.FormValue("tag"); tag != "" {
+		search.Tags = []string{tag}
+	}
+	if campaignID, err := bson.ObjectIDFromHex(r.FormValue("campaign_id")); err == nil {
+		search.CampaignID = &campaignID
+	}
+	search.Deleted, _ = strconv.ParseBool(r.FormValue("deleted"))
+	search.Page, _ = strconv.Atoi(r.FormValue("page"))
 	var component templ.Component
 
-	links, err := l.uc.SearchLinks(r.Context(), "tenant1", query)
+	result, err := l.uc.SearchLinks(r.Context(), "tenant1", search)
 	if err != nil {
 		component = webui.LandingPagesTable([]webui.LandingPage{})
 	} else {
-		res := []webui.LandingPage{}
-		for _, link := range links {
-			res = append(res, webui.LandingPage{
-				ID:              link.ID.Hex(),
-				LandingPageName: link.Name,
-				LandingPageURL:  link.Url,
-				FixedURL:        link.ConstructFixedUrl(l.config.Domain),
-			})
-		}
-		component = webui.LandingPagesTable(res)
+		component = webui.LandingPagesSearchResults(l.landingPages(result.Links), result.Page, result.HasNext())
 	}
 
 	component.Render(context.Background(), w)
@@ -73,10 +95,14 @@ func (l *linkWeb) Search(w http.ResponseWriter, r *http.Request) {
 func (l *linkWeb) Create(w http.ResponseWriter, r *http.Request) {
 	name := r.FormValue("landing_page_name")
 	url := r.FormValue("landing_page_url")
+	slug := r.FormValue("slug")
+	tags := strings.Split(r.FormValue("tags"), ",")
 
 	link := &entity.Link{
 		Name:     name,
 		Url:      url,
+		ShortID:  slug,
+		Tags:     tags,
 		TenantID: "tenant1",
 	}
 	_ = l.uc.CreateLink(r.Context(), link)
@@ -88,16 +114,7 @@ func (l *linkWeb) Create(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		component = webui.LandingPagesTable([]webui.LandingPage{})
 	} else {
-		res := []webui.LandingPage{}
-		for _, link := range links {
-			res = append(res, webui.LandingPage{
-				ID:              link.ID.Hex(),
-				LandingPageName: link.Name,
-				LandingPageURL:  link.Url,
-				FixedURL:        link.ConstructFixedUrl(l.config.Domain),
-			})
-		}
-		component = webui.LandingPagesTable(res)
+		component = webui.LandingPagesTable(l.landingPages(links))
 	}
 
 	component.Render(context.Background(), w)
@@ -114,3 +131,91 @@ func (l *linkWeb) Edit(w http.ResponseWriter, r *http.Request) {
 	component := webui.LandingPagesTable([]webui.LandingPage{})
 	component.Render(context.Background(), w)
 }
+
+// QR downloads the QR code of the link for print
+func (l *linkWeb) QR(w http.ResponseWriter, r *http.Request) {
+	format := entity.QRFormat(r.URL.Query().Get("format"))
+	if !format.IsValid() {
+		format = entity.QRFormatPNG
+	}
+
+	opts := entity.QROptions{Format: format, Size: 1024, Level: "Q"}
+	link, b, err := l.uc.GetLinkQR(r.Context(), r.PathValue("id"), opts)
+	if err != nil {
+		http.Error(w, "failed to get qr code", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", link.ShortID+"."+string(format)))
+	_, _ = w.Write(b)
+}
+
+// Import uploads a csv or json of landing pages, the format is told by the file extension
+func (l *linkWeb) Import(w http.ResponseWriter, r *http.Request) {
+	report := webui.LandingPageImportReport{}
+	pages := []webui.LandingPage{}
+	defer func() {
+		webui.LandingPagesImportReport(report, pages).Render(context.Background(), w)
+	}()
+
+	r.Body = http.MaxBytesReader(w, r.Body, entity.MaxLinkImportSize)
+	file, header, err := r.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		report.Message = fmt.Sprintf("The file must be at most %d MB", entity.MaxLinkImportSize>>20)
+		return
+	} else if err != nil {
+		report.Message = "Choose a file to import"
+		return
+	}
+	defer file.Close()
+
+	format := entity.LinkFileFormatCSV
+	if strings.EqualFold(filepath.Ext(header.Filename), ".json") {
+		format = entity.LinkFileFormatJSON
+	}
+
+	rows, err := entity.ParseLinkImport(format, file)
+	if err != nil {
+		report.Message = err.Error()
+		return
+	}
+
+	atomic, _ := strconv.ParseBool(r.FormValue("atomic"))
+	result, err := l.uc.ImportLinks(r.Context(), "tenant1", rows, atomic)
+	if err != nil {
+		report.Message = "Failed to import the landing pages"
+		return
+	}
+
+	report.Applied = result.Applied
+	report.Created = result.Created
+	report.Updated = result.Updated
+	report.Failed = result.Failed
+	for _, row := range result.Rows {
+		if row.Failed() {
+			report.Errors = append(report.Errors, fmt.Sprintf("Row %d: %s", row.Row, strings.Join(row.Errors, ", ")))
+		}
+	}
+	if len(result.NotRolledBack) > 0 {
+		report.Errors = append(report.Errors, fmt.Sprintf("Not rolled back: %s", strings.Join(result.NotRolledBack, ", ")))
+	}
+
+	if links, err := l.uc.GetAllLinks(r.Context(), "tenant1"); err == nil {
+		pages = l.landingPages(links)
+	}
+}
+
+// Export downloads the landing pages with their stats as a csv
+func (l *linkWeb) Export(w http.ResponseWriter, r *http.Request) {
+	rows, err := l.uc.ExportLinks(r.Context(), "tenant1")
+	if err != nil {
+		http.Error(w, "failed to export landing pages", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", entity.LinkFileFormatCSV.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="landing-pages.csv"`)
+	_ = entity.WriteLinkExportCSV(w, rows)
+}
@@ -0,0 +1,58 @@
+package web
+
+import (
+	"context"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/usecase"
+	webui "github/michaellimmm/turakkingu/web"
+	"net/http"
+	"strings"
+)
+
+type trackingSettingWeb struct {
+	uc     usecase.UseCase
+	config *core.Config
+}
+
+func NewTrackingSettingWeb(config *core.Config, uc usecase.UseCase) *trackingSettingWeb {
+	return &trackingSettingWeb{
+		uc:     uc,
+		config: config,
+	}
+}
+
+// TODO: fix this
+func (t *trackingSettingWeb) Domains(w http.ResponseWriter, r *http.Request) {
+	setting := webui.DomainSetting{}
+
+	trackingSetting, err := t.uc.GetTrackingSettingByTenantID(r.Context(), "tenant1")
+	if err != nil {
+		setting.Error = "failed to get domains"
+	} else {
+		setting.Domains = trackingSetting.Domains
+	}
+
+	component := webui.DomainsContent(setting)
+	component.Render(context.Background(), w)
+}
+
+func (t *trackingSettingWeb) UpdateDomains(w http.ResponseWriter, r *http.Request) {
+	domains := []string{}
+	for _, line := range strings.Split(r.FormValue("domains"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			domains = append(domains, line)
+		}
+	}
+
+	setting := webui.DomainSetting{Domains: domains}
+
+	trackingSetting, err := t.uc.UpdateDomains(r.Context(), "tenant1", domains)
+	if err != nil {
+		setting.Error = err.Error()
+	} else {
+		setting.Domains = trackingSetting.Domains
+	}
+
+	component := webui.DomainsContent(setting)
+	component.Render(context.Background(), w)
+}
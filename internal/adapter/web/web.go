@@ -20,7 +20,16 @@ type web struct {
 func NewWeb(config *core.Config, uc usecase.UseCase) Web {
 	linkWeb := NewLinkWeb(config, uc)
 	thankYouPageWeb := NewThankYouPageWeb(config, uc)
-	router := &router{linkWeb: linkWeb, thankYouPageWeb: thankYouPageWeb}
+	trackingSettingWeb := NewTrackingSettingWeb(config, uc)
+	dashboardWeb := NewDashboardWeb(config, uc)
+	funnelWeb := NewFunnelWeb(config, uc)
+	router := &router{
+		linkWeb:            linkWeb,
+		thankYouPageWeb:    thankYouPageWeb,
+		trackingSettingWeb: trackingSettingWeb,
+		dashboardWeb:       dashboardWeb,
+		funnelWeb:          funnelWeb,
+	}
 	server := &http.Server{
 		Addr:    config.WebPort,
 		Handler: router.Mux(),
@@ -42,8 +51,11 @@ func (w *web) Close(ctx context.Context) error {
 }
 
 type router struct {
-	linkWeb         *linkWeb
-	thankYouPageWeb *thankYouPageWeb
+	linkWeb            *linkWeb
+	thankYouPageWeb    *thankYouPageWeb
+	trackingSettingWeb *trackingSettingWeb
+	dashboardWeb       *dashboardWeb
+	funnelWeb          *funnelWeb
 }
 
 func (r *router) Mux() *http.ServeMux {
@@ -60,6 +72,20 @@ func (r *router) Mux() *http.ServeMux {
 	mux.HandleFunc("POST /landing-pages/search", r.linkWeb.Search)
 	mux.HandleFunc("POST /landing-pages/add", r.linkWeb.Create)
 	mux.HandleFunc("POST /landing-pages/edit/{id}", r.linkWeb.Edit)
+	mux.HandleFunc("GET /landing-pages/{id}/qr", r.linkWeb.QR)
+	mux.HandleFunc("POST /landing-pages/import", r.linkWeb.Import)
+	mux.HandleFunc("GET /landing-pages/export", r.linkWeb.Export)
+
+	// Owned domains routes
+	mux.HandleFunc("GET /domains", r.trackingSettingWeb.Domains)
+	mux.HandleFunc("POST /domains", r.trackingSettingWeb.UpdateDomains)
+
+	// Dashboard routes
+	mux.HandleFunc("GET /dashboard", r.dashboardWeb.Index)
+
+	// Funnels routes
+	mux.HandleFunc("GET /funnels", r.funnelWeb.Index)
+	mux.HandleFunc("POST /funnels", r.funnelWeb.Create)
 
 	return mux
 }
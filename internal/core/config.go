@@ -2,6 +2,9 @@ package core
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,6 +15,49 @@ type Config struct {
 	HttpPort    string
 	WebPort     string
 	Domain      string
+
+	// origin of the web console, the only origin allowed on management routes
+	ConsoleOrigin string
+	// how long the origin checks of ingestion routes are cached
+	CorsCacheTTL time.Duration
+
+	// local MaxMind-format city database, geo enrichment is off when it's missing
+	GeoIPDatabase string
+	// what is stored of the client ip after the lookup, drop or truncate
+	GeoIPIPMode string
+	// proxies whose X-Forwarded-For is trusted for the client ip, e.g. Caddy
+	TrustedProxies []string
+
+	// local list of datacenter ip ranges, one per line
+	BotDatacenterList string
+	// redirects per ip and window above which the clicks are tagged as bot, 0 disables the check
+	BotRateLimit  int
+	BotRateWindow time.Duration
+
+	// local list of words not allowed in custom slugs, one per line
+	SlugBlocklist string
+
+	// entries per cache of the hot lookups and how long they live, 0 disables the caches
+	CacheSize int
+	CacheTTL  time.Duration
+	// invalidate the caches with the changes of the other instances, needs a replica set
+	CacheChangeStream bool
+
+	// max age of the first-party _zt_id cookie set by the identity endpoint
+	VisitorCookieMaxAge time.Duration
+
+	// archive of expiring documents, local directory or S3-compatible store
+	ArchiveDir         string
+	ArchiveS3Endpoint  string
+	ArchiveS3Region    string
+	ArchiveS3Bucket    string
+	ArchiveS3AccessKey string
+	ArchiveS3SecretKey string
+	ArchiveInterval    time.Duration // 0 disables the archive job
+
+	// how often the report rollups catch up with the new clicks, tracks and events, 0 or less disables
+	// the rollups and the reports count the raw data
+	RollupInterval time.Duration
 }
 
 func NewConfig() (*Config, error) {
@@ -26,7 +72,81 @@ func NewConfig() (*Config, error) {
 		HttpPort:    os.Getenv("HTTP_PORT"),
 		WebPort:     os.Getenv("WEB_PORT"),
 		Domain:      os.Getenv("DOMAIN"),
+
+		ConsoleOrigin: getEnv("CONSOLE_ORIGIN", "http://localhost:8083"),
+		CorsCacheTTL:  getEnvDuration("CORS_CACHE_TTL", time.Minute),
+
+		GeoIPDatabase:  os.Getenv("GEOIP_DATABASE"),
+		GeoIPIPMode:    getEnv("GEOIP_IP_MODE", "drop"),
+		TrustedProxies: getEnvList("TRUSTED_PROXIES", []string{"127.0.0.0/8", "::1/128"}),
+
+		BotDatacenterList: os.Getenv("BOT_DATACENTER_LIST"),
+		BotRateLimit:      getEnvInt("BOT_RATE_LIMIT", 120),
+		BotRateWindow:     getEnvDuration("BOT_RATE_WINDOW", time.Minute),
+
+		SlugBlocklist: os.Getenv("SLUG_BLOCKLIST"),
+
+		CacheSize:         getEnvInt("CACHE_SIZE", 10000),
+		CacheTTL:          getEnvDuration("CACHE_TTL", time.Minute),
+		CacheChangeStream: getEnvBool("CACHE_CHANGE_STREAM", false),
+
+		VisitorCookieMaxAge: getEnvDuration("VISITOR_COOKIE_MAX_AGE", 30*24*time.Hour),
+
+		ArchiveDir:         os.Getenv("ARCHIVE_DIR"),
+		ArchiveS3Endpoint:  os.Getenv("ARCHIVE_S3_ENDPOINT"),
+		ArchiveS3Region:    getEnv("ARCHIVE_S3_REGION", "us-east-1"),
+		ArchiveS3Bucket:    os.Getenv("ARCHIVE_S3_BUCKET"),
+		ArchiveS3AccessKey: os.Getenv("ARCHIVE_S3_ACCESS_KEY"),
+		ArchiveS3SecretKey: os.Getenv("ARCHIVE_S3_SECRET_KEY"),
+		ArchiveInterval:    getEnvDuration("ARCHIVE_INTERVAL", time.Hour),
+
+		RollupInterval: getEnvDuration("ROLLUP_INTERVAL", time.Minute),
 	}
 
 	return config, nil
 }
+
+func getEnv(key string, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvList(key string, defaultValue []string) []string {
+	values := []string{}
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,14 @@
+package core
+
+import "time"
+
+// ParseTime accepts a date (UTC) or a RFC 3339 time, empty is the zero time
+func ParseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
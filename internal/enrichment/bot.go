@@ -0,0 +1,180 @@
+package enrichment
+
+import (
+	"bufio"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"log/slog"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BotRequest is what the detection looks at, the ip is the full client ip before it is anonymized
+type BotRequest struct {
+	UserAgent   string
+	Device      entity.Device // parsed from the user agent
+	ClientHints entity.ClientHints
+	IP          string
+	Language    string // Accept-Language
+	Webdriver   bool   // navigator.webdriver reported by the script
+	Browser     bool   // the browser sent the request itself, so browser headers are expected
+	Redirect    bool   // a short link redirect, only redirects count toward the ip rate
+}
+
+var headlessMarkers = []string{"headless", "phantomjs", "puppeteer", "playwright", "selenium", "electron/"}
+
+// BotDetector tags bot traffic from user agent patterns, datacenter ranges, headless heuristics and request rate
+type BotDetector struct {
+	datacenters []netip.Prefix
+	rateLimit   int
+	rateWindow  time.Duration
+
+	mu        sync.Mutex
+	hits      map[netip.Addr]*ipRate
+	lastSweep time.Time
+}
+
+type ipRate struct {
+	start time.Time
+	count int
+}
+
+// NewBotDetector loads the datacenter ranges, a missing list only disables that check
+func NewBotDetector(datacenterList string, rateLimit int, rateWindow time.Duration) *BotDetector {
+	datacenters, err := loadPrefixes(datacenterList)
+	if err != nil {
+		slog.Warn("datacenter list not loaded, datacenter bot detection is disabled",
+			slog.String("path", datacenterList), slog.String("error", err.Error()))
+	}
+
+	return &BotDetector{
+		datacenters: datacenters,
+		rateLimit:   rateLimit,
+		rateWindow:  rateWindow,
+		hits:        map[netip.Addr]*ipRate{},
+		lastSweep:   time.Now(),
+	}
+}
+
+// loadPrefixes reads one ip range per line, blank lines and # comments are skipped
+func loadPrefixes(path string) ([]netip.Prefix, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	prefixes := []netip.Prefix{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(line)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(line)
+			if addrErr != nil {
+				slog.Warn("invalid datacenter range", slog.String("range", line))
+				continue
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, scanner.Err()
+}
+
+func (d *BotDetector) Detect(req BotRequest) entity.Bot {
+	reasons := []entity.BotReason{}
+
+	if req.UserAgent == "" || req.Device.Type == entity.DeviceTypeBot {
+		reasons = append(reasons, entity.BotReasonUserAgent)
+	}
+
+	if d.isHeadless(req) {
+		reasons = append(reasons, entity.BotReasonHeadless)
+	}
+
+	addr, err := netip.ParseAddr(req.IP)
+	if err == nil {
+		addr = addr.Unmap()
+		if d.isDatacenter(addr) {
+			reasons = append(reasons, entity.BotReasonDatacenter)
+		}
+		// the events of a page view follow its redirect from the same ip, they must not count too
+		if req.Redirect && d.exceedsRate(addr, time.Now()) {
+			reasons = append(reasons, entity.BotReasonRate)
+		}
+	}
+
+	if len(reasons) == 0 {
+		return entity.Bot{}
+	}
+	return entity.Bot{IsBot: true, Reasons: reasons}
+}
+
+func (d *BotDetector) isHeadless(req BotRequest) bool {
+	if req.Webdriver {
+		return true
+	}
+
+	ua := strings.ToLower(req.UserAgent)
+	if slices.ContainsFunc(headlessMarkers, func(marker string) bool { return strings.Contains(ua, marker) }) {
+		return true
+	}
+
+	if strings.Contains(req.ClientHints.Brands, "HeadlessChrome") {
+		return true
+	}
+
+	// every real browser sends its languages, automation tools often don't
+	return req.Browser && req.UserAgent != "" && req.Language == ""
+}
+
+func (d *BotDetector) isDatacenter(addr netip.Addr) bool {
+	for _, prefix := range d.datacenters {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// exceedsRate counts the redirects of the ip in fixed windows
+func (d *BotDetector) exceedsRate(addr netip.Addr, now time.Time) bool {
+	if d.rateLimit <= 0 {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if now.Sub(d.lastSweep) > d.rateWindow {
+		for ip, rate := range d.hits {
+			if now.Sub(rate.start) > d.rateWindow {
+				delete(d.hits, ip)
+			}
+		}
+		d.lastSweep = now
+	}
+
+	rate, ok := d.hits[addr]
+	if !ok || now.Sub(rate.start) > d.rateWindow {
+		rate = &ipRate{start: now}
+		d.hits[addr] = rate
+	}
+	rate.count++
+
+	return rate.count > d.rateLimit
+}
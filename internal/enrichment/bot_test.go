@@ -0,0 +1,133 @@
+package enrichment
+
+import (
+	"github/michaellimmm/turakkingu/internal/entity"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBotDetector_Detect(t *testing.T) {
+	list := filepath.Join(t.TempDir(), "datacenters.txt")
+	err := os.WriteFile(list, []byte("# cloud ranges\n203.0.113.0/24\n198.51.100.7 # single ip\nnot a range\n"), 0o644)
+	assert.NoError(t, err)
+	detector := NewBotDetector(list, 0, time.Minute)
+
+	browser := ParseDevice(chromeWindowsUA, entity.ClientHints{})
+	testcases := []struct {
+		name string
+		req  BotRequest
+		want entity.Bot
+	}{
+		{
+			"browser",
+			BotRequest{UserAgent: chromeWindowsUA, Device: browser, IP: "192.0.2.1", Language: "ja", Browser: true},
+			entity.Bot{},
+		},
+		{
+			"no user agent",
+			BotRequest{IP: "192.0.2.1"},
+			entity.Bot{IsBot: true, Reasons: []entity.BotReason{entity.BotReasonUserAgent}},
+		},
+		{
+			"crawler user agent",
+			BotRequest{UserAgent: "curl/8.5.0", Device: entity.Device{Type: entity.DeviceTypeBot}},
+			entity.Bot{IsBot: true, Reasons: []entity.BotReason{entity.BotReasonUserAgent}},
+		},
+		{
+			"webdriver",
+			BotRequest{UserAgent: chromeWindowsUA, Device: browser, Language: "ja", Webdriver: true},
+			entity.Bot{IsBot: true, Reasons: []entity.BotReason{entity.BotReasonHeadless}},
+		},
+		{
+			"headless client hint",
+			BotRequest{UserAgent: chromeWindowsUA, Device: browser, Language: "ja",
+				ClientHints: entity.ClientHints{Brands: `"HeadlessChrome";v="124"`}},
+			entity.Bot{IsBot: true, Reasons: []entity.BotReason{entity.BotReasonHeadless}},
+		},
+		{
+			"browser request without languages",
+			BotRequest{UserAgent: chromeWindowsUA, Device: browser, Browser: true},
+			entity.Bot{IsBot: true, Reasons: []entity.BotReason{entity.BotReasonHeadless}},
+		},
+		{
+			"server request without languages",
+			BotRequest{UserAgent: chromeWindowsUA, Device: browser},
+			entity.Bot{},
+		},
+		{
+			"datacenter range",
+			BotRequest{UserAgent: chromeWindowsUA, Device: browser, IP: "203.0.113.50", Language: "ja"},
+			entity.Bot{IsBot: true, Reasons: []entity.BotReason{entity.BotReasonDatacenter}},
+		},
+		{
+			"datacenter ipv4-mapped ip",
+			BotRequest{UserAgent: chromeWindowsUA, Device: browser, IP: "::ffff:198.51.100.7", Language: "ja"},
+			entity.Bot{IsBot: true, Reasons: []entity.BotReason{entity.BotReasonDatacenter}},
+		},
+		{
+			"every reason",
+			BotRequest{UserAgent: "HeadlessChrome/124.0.0.0", Device: entity.Device{Type: entity.DeviceTypeBot},
+				IP: "203.0.113.50", Browser: true},
+			entity.Bot{IsBot: true, Reasons: []entity.BotReason{
+				entity.BotReasonUserAgent, entity.BotReasonHeadless, entity.BotReasonDatacenter,
+			}},
+		},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.want, detector.Detect(tcase.req))
+		})
+	}
+}
+
+func TestBotDetector_Rate(t *testing.T) {
+	t.Run("should count only the redirects", func(t *testing.T) {
+		detector := NewBotDetector("", 2, time.Minute)
+		req := BotRequest{UserAgent: chromeWindowsUA, Device: ParseDevice(chromeWindowsUA, entity.ClientHints{}), IP: "192.0.2.1"}
+
+		for range 5 {
+			assert.False(t, detector.Detect(req).IsBot)
+		}
+
+		req.Redirect = true
+		assert.False(t, detector.Detect(req).IsBot)
+		assert.False(t, detector.Detect(req).IsBot)
+		assert.Equal(t, entity.Bot{IsBot: true, Reasons: []entity.BotReason{entity.BotReasonRate}}, detector.Detect(req))
+	})
+
+	t.Run("should start a new window", func(t *testing.T) {
+		detector := NewBotDetector("", 1, time.Minute)
+		addr := netip.MustParseAddr("192.0.2.1")
+		now := time.Now()
+
+		assert.False(t, detector.exceedsRate(addr, now))
+		assert.True(t, detector.exceedsRate(addr, now.Add(30*time.Second)))
+		assert.False(t, detector.exceedsRate(addr, now.Add(2*time.Minute)))
+		assert.False(t, detector.exceedsRate(netip.MustParseAddr("192.0.2.2"), now.Add(2*time.Minute)))
+	})
+
+	t.Run("should not limit without a rate", func(t *testing.T) {
+		detector := NewBotDetector("", 0, time.Minute)
+		addr := netip.MustParseAddr("192.0.2.1")
+		for range 10 {
+			assert.False(t, detector.exceedsRate(addr, time.Now()))
+		}
+	})
+}
+
+func TestLoadPrefixes(t *testing.T) {
+	t.Run("should skip a missing list", func(t *testing.T) {
+		prefixes, err := loadPrefixes("")
+		assert.NoError(t, err)
+		assert.Empty(t, prefixes)
+
+		_, err = loadPrefixes(filepath.Join(t.TempDir(), "missing.txt"))
+		assert.Error(t, err)
+	})
+}
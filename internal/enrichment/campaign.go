@@ -0,0 +1,96 @@
+// Package enrichment derives structured data (campaign, channel, ...) from raw tracking input.
+package enrichment
+
+import (
+	"github/michaellimmm/turakkingu/internal/entity"
+	"net/url"
+)
+
+// ClickIDParams are the known click id query params, in priority order when a url has several
+var ClickIDParams = []string{
+	"gclid",    // Google Ads
+	"gbraid",   // Google Ads, iOS app to web
+	"wbraid",   // Google Ads, web to app
+	"dclid",    // Google Display & Video 360
+	"msclkid",  // Microsoft Ads
+	"yclid",    // Yandex Direct
+	"ldtag_cl", // LINE Ads
+	"fbclid",   // Meta, also added to organic links
+	"ttclid",   // TikTok Ads
+	"twclid",   // X Ads
+	"li_fat_id",
+}
+
+var searchSources = []string{"google", "bing", "yahoo*", "yandex", "naver", "duckduckgo", "baidu"}
+
+var socialSources = []string{
+	"facebook", "fb", "instagram", "ig", "line", "twitter", "x", "t.co", "tiktok", "linkedin",
+	"youtube", "pinterest", "threads",
+}
+
+// DefaultChannelRules are applied after the tenant rules, the first match wins.
+// Tracks without utm and click id are direct, unmatched ones are other.
+var DefaultChannelRules = []entity.ChannelRule{
+	{Channel: entity.ChannelPaidSocial, Sources: socialSources, Mediums: []string{"cpc", "ppc", "paid*", "cpm", "ad", "ads"}},
+	{Channel: entity.ChannelPaidSocial, ClickIDs: []string{"ldtag_cl", "ttclid", "twclid", "li_fat_id"}},
+	{Channel: entity.ChannelPaidSearch, ClickIDs: []string{"gclid", "gbraid", "wbraid", "msclkid", "yclid"}},
+	{Channel: entity.ChannelPaidSearch, Mediums: []string{"cpc", "ppc", "paidsearch", "paid_search", "paid-search", "sem"}},
+	{Channel: entity.ChannelDisplay, Mediums: []string{"display", "banner", "cpm", "interstitial"}},
+	{Channel: entity.ChannelDisplay, ClickIDs: []string{"dclid"}},
+	{Channel: entity.ChannelEmail, Mediums: []string{"email", "e-mail", "e_mail", "newsletter"}},
+	{Channel: entity.ChannelEmail, Sources: []string{"email", "e-mail", "newsletter"}},
+	{Channel: entity.ChannelSocial, Mediums: []string{"social*", "sm", "sns"}},
+	{Channel: entity.ChannelSocial, Sources: socialSources},
+	{Channel: entity.ChannelSocial, ClickIDs: []string{"fbclid"}},
+	{Channel: entity.ChannelOrganicSearch, Mediums: []string{"organic"}},
+	{Channel: entity.ChannelOrganicSearch, Sources: searchSources},
+	{Channel: entity.ChannelReferral, Mediums: []string{"referral", "ref"}},
+	{Channel: entity.ChannelReferral, Sources: []string{"*"}},
+}
+
+// ParseCampaign extracts the utm params and the click id of a landing url
+func ParseCampaign(rawURL string) (entity.UTM, entity.ClickID) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return entity.UTM{}, entity.ClickID{}
+	}
+
+	query := u.Query()
+	utm := entity.UTM{
+		Source:   query.Get("utm_source"),
+		Medium:   query.Get("utm_medium"),
+		Campaign: query.Get("utm_campaign"),
+		Term:     query.Get("utm_term"),
+		Content:  query.Get("utm_content"),
+	}
+
+	clickID := entity.ClickID{}
+	for _, param := range ClickIDParams {
+		if value := query.Get(param); value != "" {
+			clickID = entity.ClickID{Type: param, Value: value}
+			break
+		}
+	}
+
+	return utm, clickID
+}
+
+// ClassifyChannel applies the tenant rules, then the default rules
+func ClassifyChannel(utm entity.UTM, clickID entity.ClickID, rules []entity.ChannelRule) entity.Channel {
+	if utm.IsEmpty() && clickID.IsEmpty() {
+		return entity.ChannelDirect
+	}
+
+	for _, rule := range rules {
+		if rule.Match(utm, clickID) {
+			return rule.Channel
+		}
+	}
+	for _, rule := range DefaultChannelRules {
+		if rule.Match(utm, clickID) {
+			return rule.Channel
+		}
+	}
+
+	return entity.ChannelOther
+}
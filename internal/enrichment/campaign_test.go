@@ -0,0 +1,66 @@
+package enrichment
+
+import (
+	"github/michaellimmm/turakkingu/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCampaign(t *testing.T) {
+	t.Run("should read the utm params and the first click id", func(t *testing.T) {
+		utm, clickID := ParseCampaign("https://dealer.com/?utm_source=Google&utm_medium=cpc&utm_campaign=spring&fbclid=b&gclid=a")
+		assert.Equal(t, entity.UTM{Source: "Google", Medium: "cpc", Campaign: "spring"}, utm)
+		assert.Equal(t, entity.ClickID{Type: "gclid", Value: "a"}, clickID)
+	})
+
+	t.Run("should ignore an invalid url", func(t *testing.T) {
+		utm, clickID := ParseCampaign("://dealer.com")
+		assert.True(t, utm.IsEmpty())
+		assert.True(t, clickID.IsEmpty())
+	})
+}
+
+func TestClassifyChannel(t *testing.T) {
+	testcases := []struct {
+		name    string
+		utm     entity.UTM
+		clickID entity.ClickID
+		rules   []entity.ChannelRule
+		want    entity.Channel
+	}{
+		{"no utm nor click id", entity.UTM{}, entity.ClickID{}, nil, entity.ChannelDirect},
+		{"google ads click id", entity.UTM{}, entity.ClickID{Type: "gclid", Value: "a"}, nil, entity.ChannelPaidSearch},
+		{"cpc medium", entity.UTM{Source: "google", Medium: "cpc"}, entity.ClickID{}, nil, entity.ChannelPaidSearch},
+		{"paid social before paid search", entity.UTM{Source: "Instagram", Medium: "CPC"}, entity.ClickID{}, nil, entity.ChannelPaidSocial},
+		{"paid medium prefix", entity.UTM{Source: "facebook", Medium: "paid_social"}, entity.ClickID{}, nil, entity.ChannelPaidSocial},
+		{"line ads click id", entity.UTM{}, entity.ClickID{Type: "ldtag_cl", Value: "a"}, nil, entity.ChannelPaidSocial},
+		{"organic facebook click id", entity.UTM{}, entity.ClickID{Type: "fbclid", Value: "a"}, nil, entity.ChannelSocial},
+		{"display medium", entity.UTM{Source: "gdn", Medium: "banner"}, entity.ClickID{}, nil, entity.ChannelDisplay},
+		{"newsletter", entity.UTM{Source: "newsletter"}, entity.ClickID{}, nil, entity.ChannelEmail},
+		{"social source", entity.UTM{Source: "line"}, entity.ClickID{}, nil, entity.ChannelSocial},
+		{"search source prefix", entity.UTM{Source: "yahoo.co.jp"}, entity.ClickID{}, nil, entity.ChannelOrganicSearch},
+		{"other source", entity.UTM{Source: "partner-blog"}, entity.ClickID{}, nil, entity.ChannelReferral},
+		{"medium only", entity.UTM{Medium: "print"}, entity.ClickID{}, nil, entity.ChannelOther},
+		{
+			"tenant rule first",
+			entity.UTM{Source: "google", Medium: "cpc"},
+			entity.ClickID{},
+			[]entity.ChannelRule{{Channel: entity.ChannelDisplay, Sources: []string{"google"}}},
+			entity.ChannelDisplay,
+		},
+		{
+			"empty tenant rule",
+			entity.UTM{Source: "google", Medium: "cpc"},
+			entity.ClickID{},
+			[]entity.ChannelRule{{Channel: entity.ChannelDisplay}},
+			entity.ChannelPaidSearch,
+		},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.want, ClassifyChannel(tcase.utm, tcase.clickID, tcase.rules))
+		})
+	}
+}
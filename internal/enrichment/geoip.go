@@ -0,0 +1,104 @@
+package enrichment
+
+import (
+	"errors"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/pkg/mmdb"
+	"log/slog"
+	"net/netip"
+)
+
+// GeoIP looks up client ips in a local MaxMind-format city database, a nil reader finds nothing
+type GeoIP struct {
+	reader *mmdb.Reader
+}
+
+// NewGeoIP opens the database, a missing or broken file only disables the lookup
+func NewGeoIP(path string) *GeoIP {
+	if path == "" {
+		return &GeoIP{}
+	}
+
+	reader, err := mmdb.Open(path)
+	if err != nil {
+		slog.Warn("geoip database not loaded, geo enrichment is disabled",
+			slog.String("path", path), slog.String("error", err.Error()))
+		return &GeoIP{}
+	}
+
+	slog.Info("geoip database loaded", slog.String("path", path),
+		slog.String("type", reader.Metadata().DatabaseType))
+	return &GeoIP{reader: reader}
+}
+
+func (g *GeoIP) Lookup(ip string) entity.Geo {
+	if g == nil || g.reader == nil {
+		return entity.Geo{}
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return entity.Geo{}
+	}
+
+	record, err := g.reader.Lookup(addr)
+	if errors.Is(err, mmdb.ErrNotFound) {
+		return entity.Geo{}
+	} else if err != nil {
+		slog.Error("failed to lookup geoip", slog.String("error", err.Error()))
+		return entity.Geo{}
+	}
+
+	geo := entity.Geo{
+		Country:     lookupString(record, "country", "iso_code"),
+		CountryName: lookupString(record, "country", "names", "en"),
+		City:        lookupString(record, "city", "names", "en"),
+	}
+
+	// the first subdivision is the largest one, the prefecture or the state
+	if subdivisions, ok := lookupValue(record, "subdivisions").([]any); ok && len(subdivisions) > 0 {
+		geo.Region = lookupString(subdivisions[0], "iso_code")
+		geo.RegionName = lookupString(subdivisions[0], "names", "en")
+	}
+
+	return geo
+}
+
+// AnonymizeIP keeps the network part of the ip in truncate mode and nothing otherwise
+func AnonymizeIP(ip string, mode entity.IPMode) string {
+	if mode != entity.IPModeTruncate {
+		return ""
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+
+	addr = addr.Unmap()
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+	return prefix.Addr().String()
+}
+
+func lookupValue(record any, path ...string) any {
+	for _, key := range path {
+		m, ok := record.(map[string]any)
+		if !ok {
+			return nil
+		}
+		record = m[key]
+	}
+	return record
+}
+
+func lookupString(record any, path ...string) string {
+	s, _ := lookupValue(record, path...).(string)
+	return s
+}
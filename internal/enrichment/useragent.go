@@ -0,0 +1,222 @@
+package enrichment
+
+import (
+	_ "embed"
+	"encoding/json"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// useragent.json is the rules database, rules of each list are tried in order and the first match wins
+//
+//go:embed useragent.json
+var userAgentRules []byte
+
+type userAgentRule struct {
+	Regex   string            `json:"regex"`
+	Name    string            `json:"name"`
+	Version string            `json:"version"` // fixed version, the first capture group otherwise
+	Type    entity.DeviceType `json:"type"`
+
+	re *regexp.Regexp
+}
+
+type userAgentDatabase struct {
+	Devices  []*userAgentRule `json:"devices"`
+	Browsers []*userAgentRule `json:"browsers"`
+	OS       []*userAgentRule `json:"os"`
+}
+
+var userAgentDB = mustLoadUserAgentDatabase()
+
+func mustLoadUserAgentDatabase() *userAgentDatabase {
+	db := &userAgentDatabase{}
+	if err := json.Unmarshal(userAgentRules, db); err != nil {
+		panic("invalid user agent rules: " + err.Error())
+	}
+
+	for _, rules := range [][]*userAgentRule{db.Devices, db.Browsers, db.OS} {
+		for _, rule := range rules {
+			rule.re = regexp.MustCompile(rule.Regex)
+		}
+	}
+	return db
+}
+
+func (r *userAgentRule) match(ua string) (string, bool) {
+	m := r.re.FindStringSubmatch(ua)
+	if m == nil {
+		return "", false
+	}
+
+	if r.Version != "" {
+		return r.Version, true
+	}
+	if len(m) > 1 {
+		return strings.ReplaceAll(m[1], "_", "."), true
+	}
+	return "", true
+}
+
+// ParseDevice parses the user agent, client hints take precedence since Chromium freezes its user agent
+func ParseDevice(userAgent string, hints entity.ClientHints) entity.Device {
+	device := entity.Device{Type: entity.DeviceTypeUnknown}
+
+	for _, rule := range userAgentDB.Devices {
+		if _, ok := rule.match(userAgent); ok {
+			device.Type = rule.Type
+			break
+		}
+	}
+
+	for _, rule := range userAgentDB.Browsers {
+		if version, ok := rule.match(userAgent); ok {
+			device.Browser = rule.Name
+			device.BrowserVersion = version
+			break
+		}
+	}
+
+	for _, rule := range userAgentDB.OS {
+		if version, ok := rule.match(userAgent); ok {
+			device.OS = rule.Name
+			device.OSVersion = version
+			break
+		}
+	}
+
+	applyClientHints(&device, hints)
+	return device
+}
+
+func applyClientHints(device *entity.Device, hints entity.ClientHints) {
+	if hints.IsEmpty() {
+		return
+	}
+
+	brands := parseBrandList(hints.FullVersionList)
+	if len(brands) == 0 {
+		brands = parseBrandList(hints.Brands)
+	}
+	if name, version, ok := pickBrand(brands); ok && device.Type != entity.DeviceTypeBot {
+		device.Browser = name
+		device.BrowserVersion = version
+	}
+
+	if platform := unquote(hints.Platform); platform != "" {
+		device.OS = platformName(platform)
+		device.OSVersion = platformVersion(device.OS, unquote(hints.PlatformVersion), device.OSVersion)
+	}
+
+	switch hints.Mobile {
+	case "?1":
+		if device.Type != entity.DeviceTypeTablet && device.Type != entity.DeviceTypeBot {
+			device.Type = entity.DeviceTypeMobile
+		}
+	case "?0":
+		if device.Type == entity.DeviceTypeUnknown || device.Type == entity.DeviceTypeMobile {
+			device.Type = entity.DeviceTypeDesktop
+		}
+	}
+
+	if model := unquote(hints.Model); model != "" {
+		device.Model = model
+	}
+}
+
+type brand struct {
+	name    string
+	version string
+}
+
+// parseBrandList parses a structured header list like `"Chromium";v="124", "Google Chrome";v="124"`
+func parseBrandList(header string) []brand {
+	brands := []brand{}
+	for _, item := range strings.Split(header, ",") {
+		parts := strings.Split(item, ";")
+		name := unquote(parts[0])
+		if name == "" {
+			continue
+		}
+
+		b := brand{name: name}
+		for _, param := range parts[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "v="); ok {
+				b.version = unquote(v)
+			}
+		}
+		brands = append(brands, b)
+	}
+	return brands
+}
+
+var brandNames = map[string]string{
+	"Google Chrome":    "Chrome",
+	"Microsoft Edge":   "Edge",
+	"Opera":            "Opera",
+	"Samsung Internet": "Samsung Internet",
+	"YaBrowser":        "Yandex Browser",
+	"Yandex":           "Yandex Browser",
+	"Whale":            "Whale",
+	"Brave":            "Brave",
+	"Vivaldi":          "Vivaldi",
+	"HeadlessChrome":   "Headless Chrome",
+}
+
+// pickBrand skips the GREASE brands ("Not A;Brand") and prefers a known brand over Chromium
+func pickBrand(brands []brand) (string, string, bool) {
+	chromium := brand{}
+	for _, b := range brands {
+		if strings.Contains(b.name, "Brand") {
+			continue
+		}
+		if name, ok := brandNames[b.name]; ok {
+			return name, b.version, true
+		}
+		if b.name == "Chromium" {
+			chromium = b
+		}
+	}
+
+	if chromium.name != "" {
+		return "Chrome", chromium.version, true
+	}
+	return "", "", false
+}
+
+func platformName(platform string) string {
+	switch platform {
+	case "macOS", "Mac OS X":
+		return "macOS"
+	case "Chrome OS", "Chromium OS":
+		return "Chrome OS"
+	default:
+		return platform
+	}
+}
+
+// platformVersion maps Windows platform versions, Windows 11 reports 13 and above
+func platformVersion(os, version, fallback string) string {
+	if version == "" {
+		return fallback
+	}
+
+	if os == "Windows" {
+		major, _ := strconv.Atoi(strings.Split(version, ".")[0])
+		switch {
+		case major >= 13:
+			return "11"
+		case major > 0:
+			return "10"
+		default:
+			return fallback
+		}
+	}
+	return version
+}
+
+func unquote(s string) string {
+	return strings.Trim(strings.TrimSpace(s), `"`)
+}
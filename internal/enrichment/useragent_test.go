@@ -0,0 +1,113 @@
+package enrichment
+
+import (
+	"github/michaellimmm/turakkingu/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	chromeWindowsUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	chromeAndroidUA = "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36"
+	safariIPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+)
+
+func TestParseDevice(t *testing.T) {
+	testcases := []struct {
+		name      string
+		userAgent string
+		hints     entity.ClientHints
+		want      entity.Device
+	}{
+		{
+			"empty user agent",
+			"",
+			entity.ClientHints{},
+			entity.Device{Type: entity.DeviceTypeUnknown},
+		},
+		{
+			"chrome on windows",
+			chromeWindowsUA,
+			entity.ClientHints{},
+			entity.Device{Type: entity.DeviceTypeDesktop, Browser: "Chrome", BrowserVersion: "124.0.0.0", OS: "Windows", OSVersion: "10"},
+		},
+		{
+			"safari on iphone",
+			safariIPhoneUA,
+			entity.ClientHints{},
+			entity.Device{Type: entity.DeviceTypeMobile, Browser: "Safari", BrowserVersion: "17.4", OS: "iOS", OSVersion: "17.4"},
+		},
+		{
+			"edge before chrome",
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.51",
+			entity.ClientHints{},
+			entity.Device{Type: entity.DeviceTypeDesktop, Browser: "Edge", BrowserVersion: "124.0.2478.51", OS: "macOS", OSVersion: "10.15.7"},
+		},
+		{
+			"line in-app browser",
+			safariIPhoneUA + " Line/14.5.0",
+			entity.ClientHints{},
+			entity.Device{Type: entity.DeviceTypeMobile, Browser: "LINE", BrowserVersion: "14.5.0", OS: "iOS", OSVersion: "17.4"},
+		},
+		{
+			"android tablet without mobi",
+			"Mozilla/5.0 (Linux; Android 13; SM-X200) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			entity.ClientHints{},
+			entity.Device{Type: entity.DeviceTypeTablet, Browser: "Chrome", BrowserVersion: "124.0.0.0", OS: "Android", OSVersion: "13"},
+		},
+		{
+			"crawler",
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			entity.ClientHints{},
+			entity.Device{Type: entity.DeviceTypeBot},
+		},
+		{
+			"windows 11 from the platform version",
+			chromeWindowsUA,
+			entity.ClientHints{
+				Brands:          `"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`,
+				FullVersionList: `"Chromium";v="124.0.6367.60", "Google Chrome";v="124.0.6367.60", "Not-A.Brand";v="99.0.0.0"`,
+				Mobile:          "?0",
+				Platform:        `"Windows"`,
+				PlatformVersion: `"15.0.0"`,
+			},
+			entity.Device{Type: entity.DeviceTypeDesktop, Browser: "Chrome", BrowserVersion: "124.0.6367.60", OS: "Windows", OSVersion: "11"},
+		},
+		{
+			"frozen android user agent",
+			chromeAndroidUA,
+			entity.ClientHints{
+				Brands:          `"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`,
+				Mobile:          "?1",
+				Platform:        `"Android"`,
+				PlatformVersion: `"14.0.0"`,
+				Model:           `"Pixel 8"`,
+			},
+			entity.Device{Type: entity.DeviceTypeMobile, Browser: "Chrome", BrowserVersion: "124", OS: "Android", OSVersion: "14.0.0", Model: "Pixel 8"},
+		},
+		{
+			"chromium brand without a known one",
+			chromeWindowsUA,
+			entity.ClientHints{Brands: `"Not A(Brand";v="8", "Chromium";v="132"`},
+			entity.Device{Type: entity.DeviceTypeDesktop, Browser: "Chrome", BrowserVersion: "132", OS: "Windows", OSVersion: "10"},
+		},
+		{
+			"hints don't rename a bot",
+			"HeadlessChrome/124.0.0.0",
+			entity.ClientHints{Brands: `"HeadlessChrome";v="124"`, Mobile: "?1"},
+			entity.Device{Type: entity.DeviceTypeBot, Browser: "Headless Chrome", BrowserVersion: "124.0.0.0"},
+		},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.want, ParseDevice(tcase.userAgent, tcase.hints))
+		})
+	}
+}
+
+func TestParseBrandList(t *testing.T) {
+	brands := parseBrandList(`"Chromium";v="124", "Google Chrome";v="124", ;v="1"`)
+	assert.Equal(t, []brand{{name: "Chromium", version: "124"}, {name: "Google Chrome", version: "124"}}, brands)
+}
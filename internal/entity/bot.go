@@ -0,0 +1,36 @@
+package entity
+
+type BotReason string
+
+const (
+	BotReasonUserAgent  BotReason = "user_agent" // crawler, preview or http client user agent
+	BotReasonDatacenter BotReason = "datacenter" // ip in a known datacenter range
+	BotReasonHeadless   BotReason = "headless"   // automated or headless browser
+	BotReasonRate       BotReason = "rate"       // too many requests from the ip
+)
+
+// Bot is the verdict of the bot detection, kept on clicks, tracks and events
+type Bot struct {
+	IsBot   bool        `bson:"is_bot" json:"is_bot"`
+	Reasons []BotReason `bson:"reasons,omitempty" json:"reasons,omitempty"`
+}
+
+type BotMode string
+
+const (
+	BotModeExclude BotMode = "exclude" // keep bot traffic but leave it out of the reports
+	BotModeDiscard BotMode = "discard" // don't store bot traffic
+)
+
+func (m BotMode) IsValid() bool {
+	return m == BotModeExclude || m == BotModeDiscard
+}
+
+// BotPolicy is how the tenant processes bot traffic, the empty mode keeps it excluded
+type BotPolicy struct {
+	Mode BotMode `bson:"mode" json:"mode"`
+}
+
+func (p BotPolicy) Discards(bot Bot) bool {
+	return bot.IsBot && p.Mode == BotModeDiscard
+}
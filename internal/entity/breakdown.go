@@ -0,0 +1,71 @@
+package entity
+
+type TrackDimension string
+
+const (
+	TrackDimensionChannel     TrackDimension = "channel"
+	TrackDimensionUTMSource   TrackDimension = "utm_source"
+	TrackDimensionUTMMedium   TrackDimension = "utm_medium"
+	TrackDimensionUTMCampaign TrackDimension = "utm_campaign"
+	TrackDimensionUTMTerm     TrackDimension = "utm_term"
+	TrackDimensionUTMContent  TrackDimension = "utm_content"
+	TrackDimensionClickID     TrackDimension = "click_id" // click id type
+	TrackDimensionDeviceType  TrackDimension = "device_type"
+	TrackDimensionBrowser     TrackDimension = "browser"
+	TrackDimensionOS          TrackDimension = "os"
+	TrackDimensionCountry     TrackDimension = "country"
+	TrackDimensionRegion      TrackDimension = "region"
+	TrackDimensionCity        TrackDimension = "city"
+)
+
+// Field returns the track field the dimension groups by
+func (d TrackDimension) Field() (string, bool) {
+	switch d {
+	case TrackDimensionChannel:
+		return "channel", true
+	case TrackDimensionUTMSource:
+		return "utm.source", true
+	case TrackDimensionUTMMedium:
+		return "utm.medium", true
+	case TrackDimensionUTMCampaign:
+		return "utm.campaign", true
+	case TrackDimensionUTMTerm:
+		return "utm.term", true
+	case TrackDimensionUTMContent:
+		return "utm.content", true
+	case TrackDimensionClickID:
+		return "click_id.type", true
+	case TrackDimensionDeviceType:
+		return "device.type", true
+	case TrackDimensionBrowser:
+		return "device.browser", true
+	case TrackDimensionOS:
+		return "device.os", true
+	case TrackDimensionCountry:
+		return "geo.country", true
+	case TrackDimensionRegion:
+		return "geo.region", true
+	case TrackDimensionCity:
+		return "geo.city", true
+	default:
+		return "", false
+	}
+}
+
+func (d TrackDimension) IsValid() bool {
+	_, ok := d.Field()
+	return ok
+}
+
+// BreakdownRow counts the tracks of one dimension value and how far they went
+type BreakdownRow struct {
+	Key         string `bson:"_id" json:"key"`
+	Tracks      int64  `bson:"tracks" json:"tracks"`
+	Landings    int64  `bson:"landings" json:"landings"`
+	Conversions int64  `bson:"conversions" json:"conversions"`
+}
+
+type Breakdown struct {
+	Dimension TrackDimension  `json:"dimension"`
+	Rows      []*BreakdownRow `json:"rows"`
+}
@@ -0,0 +1,10 @@
+package entity
+
+// CacheStats are the counters of an in-memory cache since the start of the instance
+type CacheStats struct {
+	Name      string `json:"name"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Size      int    `json:"size"`
+}
@@ -0,0 +1,92 @@
+package entity
+
+import "strings"
+
+// UTM holds the utm_* parameters of the landing url
+type UTM struct {
+	Source   string `bson:"source,omitempty" json:"source,omitempty"`
+	Medium   string `bson:"medium,omitempty" json:"medium,omitempty"`
+	Campaign string `bson:"campaign,omitempty" json:"campaign,omitempty"`
+	Term     string `bson:"term,omitempty" json:"term,omitempty"`
+	Content  string `bson:"content,omitempty" json:"content,omitempty"`
+}
+
+func (u UTM) IsEmpty() bool {
+	return u == UTM{}
+}
+
+// ClickID is the click identifier added by an ad network, e.g. gclid
+type ClickID struct {
+	Type  string `bson:"type,omitempty" json:"type,omitempty"` // query param name
+	Value string `bson:"value,omitempty" json:"value,omitempty"`
+}
+
+func (c ClickID) IsEmpty() bool {
+	return c.Type == ""
+}
+
+type Channel string
+
+const (
+	ChannelPaidSearch    Channel = "paid_search"
+	ChannelOrganicSearch Channel = "organic_search"
+	ChannelPaidSocial    Channel = "paid_social"
+	ChannelSocial        Channel = "social"
+	ChannelDisplay       Channel = "display"
+	ChannelEmail         Channel = "email"
+	ChannelReferral      Channel = "referral"
+	ChannelDirect        Channel = "direct"
+	ChannelOther         Channel = "other"
+)
+
+func (c Channel) IsValid() bool {
+	switch c {
+	case ChannelPaidSearch, ChannelOrganicSearch, ChannelPaidSocial, ChannelSocial, ChannelDisplay,
+		ChannelEmail, ChannelReferral, ChannelDirect, ChannelOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// ChannelRule classifies a track into a channel. Every non empty list has to match, values are
+// compared case-insensitively and can end with "*" to match a prefix, "*" alone matches any value.
+type ChannelRule struct {
+	Channel  Channel  `bson:"channel" json:"channel"`
+	Sources  []string `bson:"sources,omitempty" json:"sources,omitempty"`     // utm_source
+	Mediums  []string `bson:"mediums,omitempty" json:"mediums,omitempty"`     // utm_medium
+	ClickIDs []string `bson:"click_ids,omitempty" json:"click_ids,omitempty"` // click id types
+}
+
+func (r ChannelRule) IsEmpty() bool {
+	return len(r.Sources) == 0 && len(r.Mediums) == 0 && len(r.ClickIDs) == 0
+}
+
+func (r ChannelRule) Match(utm UTM, clickID ClickID) bool {
+	if r.IsEmpty() {
+		return false
+	}
+	return matchAny(r.Sources, utm.Source) && matchAny(r.Mediums, utm.Medium) && matchAny(r.ClickIDs, clickID.Type)
+}
+
+func matchAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	if value == "" {
+		return false
+	}
+
+	value = strings.ToLower(value)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(value, prefix) {
+				return true
+			}
+		} else if value == pattern {
+			return true
+		}
+	}
+	return false
+}
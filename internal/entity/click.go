@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Click is one redirect of a short link
+type Click struct {
+	ID          bson.ObjectID      `bson:"_id,omitempty" json:"id"`
+	LinkID      bson.ObjectID      `bson:"link_id" json:"link_id"`
+	TenantID    string             `bson:"tenant_id" json:"tenant_id"`
+	Url         string             `bson:"url" json:"url"` // destination
+	Referrer    string             `bson:"referrer" json:"referrer"`
+	UserAgent   string             `bson:"user_agent" json:"user_agent"`
+	ClientHints ClientHints        `bson:"client_hints,omitempty" json:"client_hints,omitempty"`
+	Language    string             `bson:"language" json:"language"` // Accept-Language
+	Device      Device             `bson:"device" json:"device"`
+	IP          string             `bson:"ip,omitempty" json:"ip,omitempty"` // anonymized after the geo lookup
+	Geo         Geo                `bson:"geo" json:"geo"`
+	Bot         Bot                `bson:"bot" json:"bot"`
+	Variant     string             `bson:"variant,omitempty" json:"variant,omitempty"`   // A/B variant id
+	TrackID     string             `bson:"track_id,omitempty" json:"track_id,omitempty"` // auto-created track
+	Outcome     ClickOutcome       `bson:"outcome" json:"outcome"`
+	Reason      LinkInactiveReason `bson:"reason,omitempty" json:"reason,omitempty"` // why the link wasn't followed
+	Source      ClickSource        `bson:"source,omitempty" json:"source,omitempty"` // from the ztsrc param
+	Rule        string             `bson:"rule,omitempty" json:"rule,omitempty"`     // name of the matched redirect rule
+	Query       url.Values         `bson:"-" json:"-"`                               // params of the short link
+	VisitorKey  string             `bson:"-" json:"-"`                               // assigns the variant, see Link.PickVariant
+	Expirable   `bson:",inline"`
+	BaseEntity  `bson:",inline"`
+}
+
+func (c *Click) SetCreatedAt() {
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now().UTC()
+	}
+}
+
+func (c *Click) SetUpdatedAt() {
+	c.UpdatedAt = time.Now().UTC()
+}
+
+type ClickOutcome string
+
+const (
+	ClickOutcomeRedirect    ClickOutcome = "redirect"     // to the link destination
+	ClickOutcomeFallback    ClickOutcome = "fallback"     // to the fallback url of an inactive link
+	ClickOutcomeExpiredPage ClickOutcome = "expired_page" // an inactive link without fallback
+)
+
+// ClickSource tells the printed or shared copies of a short link apart, through the ztsrc param
+type ClickSource string
+
+const ClickSourceQR ClickSource = "qr" // scan of the QR code of the link
+
+// ClickSourceParam is added to the short link by its copies and removed before the redirect
+const ClickSourceParam = "ztsrc"
+
+func (s ClickSource) IsValid() bool {
+	return s == ClickSourceQR
+}
+
+type ClickDimension string
+
+const (
+	ClickDimensionDeviceType ClickDimension = "device_type"
+	ClickDimensionBrowser    ClickDimension = "browser"
+	ClickDimensionOS         ClickDimension = "os"
+	ClickDimensionCountry    ClickDimension = "country"
+	ClickDimensionRegion     ClickDimension = "region"
+	ClickDimensionReferrer   ClickDimension = "referrer"
+	ClickDimensionVariant    ClickDimension = "variant"
+	ClickDimensionOutcome    ClickDimension = "outcome"
+	ClickDimensionReason     ClickDimension = "reason"
+	ClickDimensionSource     ClickDimension = "source"
+	ClickDimensionRule       ClickDimension = "rule"
+)
+
+// Field returns the click field the dimension groups by
+func (d ClickDimension) Field() (string, bool) {
+	switch d {
+	case ClickDimensionDeviceType:
+		return "device.type", true
+	case ClickDimensionBrowser:
+		return "device.browser", true
+	case ClickDimensionOS:
+		return "device.os", true
+	case ClickDimensionCountry:
+		return "geo.country", true
+	case ClickDimensionRegion:
+		return "geo.region", true
+	case ClickDimensionReferrer:
+		return "referrer", true
+	case ClickDimensionVariant:
+		return "variant", true
+	case ClickDimensionOutcome:
+		return "outcome", true
+	case ClickDimensionReason:
+		return "reason", true
+	case ClickDimensionSource:
+		return "source", true
+	case ClickDimensionRule:
+		return "rule", true
+	default:
+		return "", false
+	}
+}
+
+func (d ClickDimension) IsValid() bool {
+	_, ok := d.Field()
+	return ok
+}
+
+type ClickBreakdownRow struct {
+	Key    string `bson:"_id" json:"key"`
+	Clicks int64  `bson:"clicks" json:"clicks"`
+}
+
+// ClickBreakdown counts human clicks, bot clicks are only counted in total
+type ClickBreakdown struct {
+	Dimension ClickDimension       `json:"dimension"`
+	Rows      []*ClickBreakdownRow `json:"rows"`
+	BotClicks int64                `json:"bot_clicks"`
+}
@@ -0,0 +1,69 @@
+package entity
+
+type ConsentState string
+
+const (
+	ConsentStateUnknown ConsentState = "unknown" // no signal, processed like granted
+	ConsentStateGranted ConsentState = "granted"
+	ConsentStateDenied  ConsentState = "denied"
+)
+
+func (s ConsentState) IsValid() bool {
+	switch s {
+	case ConsentStateUnknown, ConsentStateGranted, ConsentStateDenied:
+		return true
+	default:
+		return false
+	}
+}
+
+// Consent is the consent of the visitor when the event was sent, stored on the event for audits
+type Consent struct {
+	State  ConsentState `bson:"state" json:"state"`   // effective state the event was processed with
+	Signal ConsentState `bson:"signal" json:"signal"` // state sent by the script
+	GPC    bool         `bson:"gpc" json:"gpc"`       // Sec-GPC: 1 header
+	DNT    bool         `bson:"dnt" json:"dnt"`       // DNT: 1 header
+}
+
+// NewConsent resolves the effective state, a browser opt-out always wins over the script signal
+func NewConsent(signal ConsentState, gpc, dnt bool) Consent {
+	if !signal.IsValid() || signal == "" {
+		signal = ConsentStateUnknown
+	}
+
+	state := signal
+	if gpc || dnt {
+		state = ConsentStateDenied
+	}
+
+	return Consent{
+		State:  state,
+		Signal: signal,
+		GPC:    gpc,
+		DNT:    dnt,
+	}
+}
+
+func (c Consent) IsDenied() bool {
+	return c.State == ConsentStateDenied
+}
+
+type ConsentMode string
+
+const (
+	ConsentModeCookieless ConsentMode = "cookieless" // keep the event without fingerprint and identity stitching
+	ConsentModeDrop       ConsentMode = "drop"       // don't store the event
+)
+
+func (m ConsentMode) IsValid() bool {
+	return m == ConsentModeCookieless || m == ConsentModeDrop
+}
+
+// ConsentPolicy is how the tenant processes events without consent
+type ConsentPolicy struct {
+	DeniedMode ConsentMode `bson:"denied_mode" json:"denied_mode"`
+}
+
+func (p ConsentPolicy) Drops(consent Consent) bool {
+	return consent.IsDenied() && p.DeniedMode == ConsentModeDrop
+}
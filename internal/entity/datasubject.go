@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type DataSubjectRequestType string
+
+const (
+	DataSubjectRequestTypeExport    DataSubjectRequestType = "export"
+	DataSubjectRequestTypeDelete    DataSubjectRequestType = "delete"
+	DataSubjectRequestTypeAnonymize DataSubjectRequestType = "anonymize"
+)
+
+func (t DataSubjectRequestType) IsValid() bool {
+	switch t {
+	case DataSubjectRequestTypeExport, DataSubjectRequestTypeDelete, DataSubjectRequestTypeAnonymize:
+		return true
+	default:
+		return false
+	}
+}
+
+type DataSubjectRequestStatus string
+
+const (
+	DataSubjectRequestStatusPending   DataSubjectRequestStatus = "pending"
+	DataSubjectRequestStatusCompleted DataSubjectRequestStatus = "completed"
+	DataSubjectRequestStatusFailed    DataSubjectRequestStatus = "failed"
+)
+
+const hashedSubjectPrefix = "sha256:"
+
+// AnonymizedValue replaces personal values when records are anonymized instead of deleted
+const AnonymizedValue = "anonymized"
+
+type DataSubjectRequestResult struct {
+	Tracks     int64 `bson:"tracks" json:"tracks"`
+	Events     int64 `bson:"events" json:"events"`
+	Clicks     int64 `bson:"clicks" json:"clicks"`
+	Identities int64 `bson:"identities" json:"identities"`
+}
+
+// DataSubjectRequest is the audit record of a GDPR/CCPA request
+type DataSubjectRequest struct {
+	ID                bson.ObjectID            `bson:"_id,omitempty" json:"id"`
+	TenantID          string                   `bson:"tenant_id" json:"tenant_id"`
+	TrackingSettingID bson.ObjectID            `bson:"tracking_setting_id" json:"tracking_setting_id"`
+	Type              DataSubjectRequestType   `bson:"type" json:"type"`
+	Subject           Identifier               `bson:"subject" json:"subject"` // value is hashed for erasures
+	RequestedBy       string                   `bson:"requested_by" json:"requested_by"`
+	Channel           string                   `bson:"channel" json:"channel"` // api or cli
+	Status            DataSubjectRequestStatus `bson:"status" json:"status"`
+	Result            DataSubjectRequestResult `bson:"result" json:"result"`
+	Error             string                   `bson:"error,omitempty" json:"error,omitempty"`
+	CompletedAt       *time.Time               `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	BaseEntity        `bson:",inline"`
+}
+
+func (d *DataSubjectRequest) SetCreatedAt() {
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now().UTC()
+	}
+}
+
+func (d *DataSubjectRequest) SetUpdatedAt() {
+	d.UpdatedAt = time.Now().UTC()
+}
+
+func (d *DataSubjectRequest) Complete(result DataSubjectRequestResult) {
+	now := time.Now().UTC()
+	d.Status = DataSubjectRequestStatusCompleted
+	d.Result = result
+	d.CompletedAt = &now
+}
+
+func (d *DataSubjectRequest) Fail(err error) {
+	now := time.Now().UTC()
+	d.Status = DataSubjectRequestStatusFailed
+	d.Error = err.Error()
+	d.CompletedAt = &now
+}
+
+// HashSubject replaces the subject value with its sha256, so the audit record of an erasure
+// does not keep the identifier it erased
+func (d *DataSubjectRequest) HashSubject() {
+	if strings.HasPrefix(d.Subject.Value, hashedSubjectPrefix) {
+		return
+	}
+	sum := sha256.Sum256([]byte(d.Subject.Value))
+	d.Subject.Value = hashedSubjectPrefix + hex.EncodeToString(sum[:])
+}
+
+// SubjectData is every record stored for a data subject
+type SubjectData struct {
+	Subject    Identifier  `json:"subject"`
+	Identities []*Identity `json:"identities"`
+	Tracks     []*Track    `json:"tracks"`
+	Events     []*Event    `json:"events"`
+	Clicks     []*Click    `json:"clicks"`
+	ExportedAt time.Time   `json:"exported_at"`
+}
@@ -0,0 +1,41 @@
+package entity
+
+type DeviceType string
+
+const (
+	DeviceTypeUnknown DeviceType = "unknown"
+	DeviceTypeDesktop DeviceType = "desktop"
+	DeviceTypeMobile  DeviceType = "mobile"
+	DeviceTypeTablet  DeviceType = "tablet"
+	DeviceTypeTV      DeviceType = "tv"
+	DeviceTypeConsole DeviceType = "console"
+	DeviceTypeBot     DeviceType = "bot"
+)
+
+// Device is parsed from the user agent and the client hints
+type Device struct {
+	Type           DeviceType `bson:"type" json:"type"`
+	Browser        string     `bson:"browser" json:"browser"`
+	BrowserVersion string     `bson:"browser_version" json:"browser_version"`
+	OS             string     `bson:"os" json:"os"`
+	OSVersion      string     `bson:"os_version" json:"os_version"`
+	Model          string     `bson:"model,omitempty" json:"model,omitempty"`
+}
+
+func (d Device) IsEmpty() bool {
+	return d == Device{}
+}
+
+// ClientHints are the Sec-CH-UA-* request headers, sent by Chromium browsers instead of a detailed user agent
+type ClientHints struct {
+	Brands          string `bson:"brands,omitempty" json:"brands,omitempty"`                       // Sec-CH-UA
+	FullVersionList string `bson:"full_version_list,omitempty" json:"full_version_list,omitempty"` // Sec-CH-UA-Full-Version-List
+	Mobile          string `bson:"mobile,omitempty" json:"mobile,omitempty"`                       // Sec-CH-UA-Mobile
+	Platform        string `bson:"platform,omitempty" json:"platform,omitempty"`                   // Sec-CH-UA-Platform
+	PlatformVersion string `bson:"platform_version,omitempty" json:"platform_version,omitempty"`   // Sec-CH-UA-Platform-Version
+	Model           string `bson:"model,omitempty" json:"model,omitempty"`                         // Sec-CH-UA-Model
+}
+
+func (c ClientHints) IsEmpty() bool {
+	return c == ClientHints{}
+}
@@ -0,0 +1,111 @@
+package entity
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// NormalizeDomain turns user input like "https://WWW.Example.com:443/path" into "www.example.com"
+func NormalizeDomain(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return "", fmt.Errorf("domain can not be empty")
+	}
+
+	if !strings.Contains(s, "://") {
+		s = "http://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("domain %q is not valid", s)
+	}
+
+	host := strings.TrimPrefix(strings.TrimPrefix(u.Hostname(), "*."), ".")
+	if host == "" || strings.ContainsAny(host, "*_ ") {
+		return "", fmt.Errorf("domain %q is not valid", s)
+	}
+	if net.ParseIP(host) == nil && !strings.Contains(host, ".") && host != "localhost" {
+		return "", fmt.Errorf("domain %q is not valid", s)
+	}
+
+	return host, nil
+}
+
+// NormalizeDomains normalizes and deduplicates a domain list, keeping the order
+func NormalizeDomains(domains []string) ([]string, error) {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, d := range domains {
+		domain, err := NormalizeDomain(d)
+		if err != nil {
+			return nil, err
+		}
+		if seen[domain] {
+			continue
+		}
+		seen[domain] = true
+		result = append(result, domain)
+	}
+	return result, nil
+}
+
+// IsOwnedHost reports whether the host is one of the domains or a subdomain of one of them.
+// An empty list means the tenant hasn't registered its domains yet and every host is accepted.
+func (c TrackingSettingConfig) IsOwnedHost(host string) bool {
+	if len(c.Domains) == 0 {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	for _, domain := range c.Domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParentDomains returns the host and its parent domains, "a.example.com" gives
+// ["a.example.com", "example.com", "com"]
+func ParentDomains(host string) []string {
+	host = strings.ToLower(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	result := []string{host}
+	if net.ParseIP(host) != nil {
+		return result
+	}
+	for i := strings.Index(host, "."); i >= 0; i = strings.Index(host, ".") {
+		host = host[i+1:]
+		if host != "" {
+			result = append(result, host)
+		}
+	}
+	return result
+}
+
+// IsWebURL tells if rawURL is an absolute http or https url, url.ParseRequestURI alone accepts
+// javascript: and data: urls
+func IsWebURL(rawURL string) bool {
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func (c TrackingSettingConfig) IsOwnedURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return c.IsOwnedHost(u.Host)
+}
@@ -0,0 +1,111 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackingSettingConfig_IsOwnedHost(t *testing.T) {
+	config := TrackingSettingConfig{Domains: []string{"dealer.com", "dealer-cars.jp"}}
+
+	testcases := []struct {
+		name string
+		host string
+		want bool
+	}{
+		{"domain", "dealer.com", true},
+		{"subdomain", "www.dealer.com", true},
+		{"ignores case", "WWW.Dealer.COM", true},
+		{"with a port", "shop.dealer-cars.jp:8443", true},
+		{"suffix without dot", "evildealer.com", false},
+		{"domain as subdomain", "dealer.com.evil.com", false},
+		{"parent domain", "com", false},
+		{"empty host", "", false},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.want, config.IsOwnedHost(tcase.host))
+		})
+	}
+
+	t.Run("should accept every host without domains", func(t *testing.T) {
+		assert.True(t, TrackingSettingConfig{}.IsOwnedHost("evil.com"))
+	})
+}
+
+func TestTrackingSettingConfig_IsOwnedURL(t *testing.T) {
+	config := TrackingSettingConfig{Domains: []string{"dealer.com"}}
+
+	testcases := []struct {
+		name   string
+		rawURL string
+		want   bool
+	}{
+		{"owned page", "https://www.dealer.com/cars?id=1", true},
+		{"other site", "https://evil.com/?r=dealer.com", false},
+		{"owned domain in the user info", "https://dealer.com@evil.com/", false},
+		{"invalid url", "https://dealer.com/%zz", false},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.want, config.IsOwnedURL(tcase.rawURL))
+		})
+	}
+}
+
+func TestNormalizeDomain(t *testing.T) {
+	testcases := []struct {
+		name    string
+		input   string
+		want    string
+		isValid bool
+	}{
+		{"url", "https://WWW.Dealer.com:443/path", "www.dealer.com", true},
+		{"wildcard", "*.dealer.com", "dealer.com", true},
+		{"leading dot", ".dealer.com", "dealer.com", true},
+		{"localhost", "localhost:8080", "localhost", true},
+		{"ip address", "192.0.2.1", "192.0.2.1", true},
+		{"empty", " ", "", false},
+		{"single label", "dealer", "", false},
+		{"underscore", "my_dealer.com", "", false},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			domain, err := NormalizeDomain(tcase.input)
+			assert.Equal(t, tcase.isValid, err == nil, err)
+			assert.Equal(t, tcase.want, domain)
+		})
+	}
+}
+
+func TestParentDomains(t *testing.T) {
+	assert.Equal(t, []string{"a.dealer.com", "dealer.com", "com"}, ParentDomains("A.Dealer.com:8080"))
+	assert.Equal(t, []string{"192.0.2.1"}, ParentDomains("192.0.2.1"))
+}
+
+func TestIsWebURL(t *testing.T) {
+	testcases := []struct {
+		name string
+		url  string
+		want bool
+	}{
+		{"https", "https://dealer.com/cars?id=1", true},
+		{"http", "http://dealer.com", true},
+		{"upper case scheme", "HTTPS://dealer.com", true},
+		{"javascript", "javascript:alert(document.cookie)", false},
+		{"data", "data:text/html,<script>alert(1)</script>", false},
+		{"relative", "/cars", false},
+		{"no host", "https:///cars", false},
+		{"empty", "", false},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.want, IsWebURL(tcase.url))
+		})
+	}
+}
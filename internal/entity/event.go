@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -11,17 +12,44 @@ type EventName string
 const (
 	EventNameLandingPage  EventName = "landing_page"
 	EventNameThankYouPage EventName = "thank_you_page"
+	EventNamePageView     EventName = "page_view" // a page between the landing page and the conversion
+	EventNameCustom       EventName = "custom"    // sent by the page, e.g. a form submission
 )
 
+var customEventName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// IsValidCustomEventName tells if the name of a custom event is lowercase letters, digits, "_",
+// "." and "-", at most 64 of them
+func IsValidCustomEventName(name string) bool {
+	return customEventName.MatchString(name)
+}
+
 type Event struct {
-	ID          bson.ObjectID `bson:"_id,omitempty"`
-	TrackID     string        `bson:"track_id"`
-	UserAgent   string        `bson:"user_agent"`
-	Fingerprint string        `bson:"fingerprint"`
-	Url         string        `bson:"url"`
-	EventName   EventName     `bson:"event_name"`
-	PublishedAt time.Time     `bson:"published_at"`
-	BaseEntity  `bson:",inline"`
+	ID          bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	TrackID     string        `bson:"track_id" json:"track_id"`
+	UserAgent   string        `bson:"user_agent" json:"user_agent"`
+	Fingerprint string        `bson:"fingerprint" json:"fingerprint"`
+	Url         string        `bson:"url" json:"url"`
+	EventName   EventName     `bson:"event_name" json:"event_name"`
+	Name        string        `bson:"name,omitempty" json:"name,omitempty"` // of a custom event
+	PublishedAt time.Time     `bson:"published_at" json:"published_at"`
+	Consent     Consent       `bson:"consent" json:"consent"`
+	ClientHints ClientHints   `bson:"client_hints,omitempty" json:"client_hints,omitempty"`
+	Device      Device        `bson:"device" json:"device"`
+	IP          string        `bson:"ip,omitempty" json:"ip,omitempty"` // anonymized after the geo lookup
+	Geo         Geo           `bson:"geo" json:"geo"`
+	Language    string        `bson:"language" json:"language"`                       // Accept-Language
+	Webdriver   bool          `bson:"webdriver,omitempty" json:"webdriver,omitempty"` // navigator.webdriver
+	Bot         Bot           `bson:"bot" json:"bot"`
+	TenantID    string        `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	// tracking setting of the script, only used to attribute the events without track
+	TrackingSettingID bson.ObjectID `bson:"-" json:"-"`
+	// set on conversions
+	ThankYouPageID bson.ObjectID `bson:"thank_you_page_id,omitempty" json:"thank_you_page_id,omitempty"`
+	Points         int           `bson:"points,omitempty" json:"points,omitempty"`
+	Revenue        float64       `bson:"revenue,omitempty" json:"revenue,omitempty"`
+	Expirable      `bson:",inline"`
+	BaseEntity     `bson:",inline"`
 }
 
 func (t *Event) GetTrackID() (bson.ObjectID, error) {
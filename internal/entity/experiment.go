@@ -0,0 +1,47 @@
+package entity
+
+import "math"
+
+// SignificanceLevel is the p-value under which a variant differs from the control
+const SignificanceLevel = 0.05
+
+// VariantResult is the conversion rate of one variant, compared with the first variant as control
+type VariantResult struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	Url            string  `json:"url"`
+	Weight         int     `json:"weight"`
+	Clicks         int64   `json:"clicks"`
+	Tracks         int64   `json:"tracks"`
+	Conversions    int64   `json:"conversions"`
+	ConversionRate float64 `json:"conversion_rate"` // conversions per track
+	Uplift         float64 `json:"uplift"`          // relative to the control rate
+	ZScore         float64 `json:"z_score"`
+	PValue         float64 `json:"p_value"`
+	Significant    bool    `json:"significant"`
+}
+
+type ExperimentReport struct {
+	LinkID   string           `json:"link_id"`
+	Control  string           `json:"control"`
+	Variants []*VariantResult `json:"variants"`
+}
+
+// TwoProportionZTest compares the conversion rates x1/n1 and x2/n2 with a pooled two-sided z-test
+func TwoProportionZTest(x1, n1, x2, n2 int64) (z float64, p float64) {
+	if n1 == 0 || n2 == 0 {
+		return 0, 1
+	}
+
+	p1 := float64(x1) / float64(n1)
+	p2 := float64(x2) / float64(n2)
+	pooled := float64(x1+x2) / float64(n1+n2)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(n1) + 1/float64(n2)))
+	if se == 0 {
+		return 0, 1
+	}
+
+	z = (p2 - p1) / se
+	p = math.Erfc(math.Abs(z) / math.Sqrt2)
+	return z, p
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoProportionZTest(t *testing.T) {
+	testcases := []struct {
+		name           string
+		x1, n1, x2, n2 int64
+		z, p           float64
+	}{
+		{"better variant", 100, 1000, 130, 1000, 2.1027, 0.0355},
+		{"worse variant", 130, 1000, 100, 1000, -2.1027, 0.0355},
+		{"no difference to speak of", 50, 500, 52, 500, 0.2090, 0.8345},
+		{"no tracks", 0, 0, 10, 100, 0, 1},
+		{"no conversion at all", 0, 100, 0, 100, 0, 1},
+		{"every track converted", 100, 100, 50, 50, 0, 1},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			z, p := TwoProportionZTest(tcase.x1, tcase.n1, tcase.x2, tcase.n2)
+			assert.InDelta(t, tcase.z, z, 0.0001)
+			assert.InDelta(t, tcase.p, p, 0.0001)
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package entity
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	maxFunnelSteps = 10
+	// MaxFunnelWindow keeps the journeys of a report to a month after their first step
+	MaxFunnelWindow = 30 * 24 * time.Hour
+)
+
+type FunnelStepType string
+
+const (
+	FunnelStepLanding    FunnelStepType = "landing"    // the landing page of the track, only as first step
+	FunnelStepURL        FunnelStepType = "url"        // a page view matching the url rule
+	FunnelStepEvent      FunnelStepType = "event"      // a custom event of the name
+	FunnelStepConversion FunnelStepType = "conversion" // a thank you page, any one without value
+)
+
+func (t FunnelStepType) IsValid() bool {
+	switch t {
+	case FunnelStepLanding, FunnelStepURL, FunnelStepEvent, FunnelStepConversion:
+		return true
+	default:
+		return false
+	}
+}
+
+// URLMatch is how the url rule of a step is compared to the path of a page, e.g. /cars/123.
+// Contains also looks into the query string.
+type URLMatch string
+
+const (
+	URLMatchExact    URLMatch = "exact"
+	URLMatchPrefix   URLMatch = "prefix"
+	URLMatchContains URLMatch = "contains"
+)
+
+func (m URLMatch) IsValid() bool {
+	return m == URLMatchExact || m == URLMatchPrefix || m == URLMatchContains
+}
+
+type FunnelStep struct {
+	Name  string         `bson:"name" json:"name"`
+	Type  FunnelStepType `bson:"type" json:"type"`
+	Match URLMatch       `bson:"match,omitempty" json:"match,omitempty"` // url steps
+	Value string         `bson:"value,omitempty" json:"value,omitempty"` // url rule, event name or thank you page id
+}
+
+func (s *FunnelStep) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("step name can not be empty")
+	}
+
+	switch s.Type {
+	case FunnelStepURL:
+		if !s.Match.IsValid() {
+			return fmt.Errorf("step %s: match must be exact, prefix or contains", s.Name)
+		}
+		if s.Value == "" {
+			return fmt.Errorf("step %s: url rule can not be empty", s.Name)
+		}
+	case FunnelStepEvent:
+		if !IsValidCustomEventName(s.Value) {
+			return fmt.Errorf("step %s: event name is not valid", s.Name)
+		}
+	case FunnelStepConversion:
+		if _, err := bson.ObjectIDFromHex(s.Value); s.Value != "" && err != nil {
+			return fmt.Errorf("step %s: thank you page id is not valid", s.Name)
+		}
+	case FunnelStepLanding:
+	default:
+		return fmt.Errorf("step %s: type must be landing, url, event or conversion", s.Name)
+	}
+
+	return nil
+}
+
+// Matches tells if the event reaches the step
+func (s *FunnelStep) Matches(event *FunnelEvent) bool {
+	switch s.Type {
+	case FunnelStepLanding:
+		return event.EventName == EventNameLandingPage
+	case FunnelStepEvent:
+		return event.EventName == EventNameCustom && event.Name == s.Value
+	case FunnelStepConversion:
+		return event.EventName == EventNameThankYouPage &&
+			(s.Value == "" || event.ThankYouPageID.Hex() == s.Value)
+	case FunnelStepURL:
+		// the landing page and the thank you pages are pages too
+		if event.EventName == EventNameCustom {
+			return false
+		}
+		return s.matchURL(event.Url)
+	}
+	return false
+}
+
+func (s *FunnelStep) matchURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	path := parsed.Path
+	if path == "" {
+		path = "/"
+	}
+
+	switch s.Match {
+	case URLMatchExact:
+		return strings.TrimSuffix(path, "/") == strings.TrimSuffix(s.Value, "/")
+	case URLMatchPrefix:
+		return strings.HasPrefix(path, s.Value)
+	case URLMatchContains:
+		if parsed.RawQuery != "" {
+			path += "?" + parsed.RawQuery
+		}
+		return strings.Contains(path, s.Value)
+	}
+	return false
+}
+
+// Funnel is an ordered list of steps of a journey from a landing page to a thank you page. A
+// track reaches a step after the previous one, within the window from the first step.
+type Funnel struct {
+	ID          bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID    string        `bson:"tenant_id" json:"tenant_id"`
+	Name        string        `bson:"name" json:"name"`
+	Steps       []*FunnelStep `bson:"steps" json:"steps"`
+	WindowHours int           `bson:"window_hours" json:"window_hours"` // conversion window
+	BaseEntity  `bson:",inline"`
+}
+
+func (f *Funnel) Validate() error {
+	if f.Name == "" {
+		return fmt.Errorf("name can not be empty")
+	}
+
+	if len(f.Steps) < 2 || len(f.Steps) > maxFunnelSteps {
+		return fmt.Errorf("a funnel has 2 to %d steps", maxFunnelSteps)
+	}
+
+	for i, step := range f.Steps {
+		if step == nil {
+			return fmt.Errorf("step can not be empty")
+		}
+		if err := step.Validate(); err != nil {
+			return err
+		}
+		if step.Type == FunnelStepLanding && i > 0 {
+			return fmt.Errorf("step %s: landing can only be the first step", step.Name)
+		}
+	}
+
+	if f.WindowHours <= 0 || f.Window() > MaxFunnelWindow {
+		return fmt.Errorf("window_hours must be between 1 and %d", int(MaxFunnelWindow.Hours()))
+	}
+
+	return nil
+}
+
+func (f *Funnel) Window() time.Duration {
+	return time.Duration(f.WindowHours) * time.Hour
+}
+
+// Progress returns how many steps the journey reached in order and when it reached the first
+// one. The journey starts at the first event matching the first step.
+func (f *Funnel) Progress(events []*FunnelEvent) (int, time.Time) {
+	reached := 0
+	var start time.Time
+	for _, event := range events {
+		if reached == len(f.Steps) {
+			break
+		}
+		if reached > 0 && event.CreatedAt.Sub(start) > f.Window() {
+			break
+		}
+		if f.Steps[reached].Matches(event) {
+			if reached == 0 {
+				start = event.CreatedAt
+			}
+			reached++
+		}
+	}
+	return reached, start
+}
+
+func (f *Funnel) SetCreatedAt() {
+	if f.CreatedAt.IsZero() {
+		f.CreatedAt = time.Now().UTC()
+	}
+}
+
+func (f *Funnel) SetUpdatedAt() {
+	f.UpdatedAt = time.Now().UTC()
+}
+
+// FunnelEvent is an event of a journey, only what the steps match
+type FunnelEvent struct {
+	EventName      EventName     `bson:"event_name"`
+	Name           string        `bson:"name,omitempty"`
+	Url            string        `bson:"url"`
+	ThankYouPageID bson.ObjectID `bson:"thank_you_page_id,omitempty"`
+	CreatedAt      time.Time     `bson:"created_at"`
+}
+
+// FunnelJourney is the events of a track in the order they happened
+type FunnelJourney struct {
+	TrackID string         `bson:"_id"`
+	Link    string         `bson:"link"`
+	Channel string         `bson:"channel"`
+	Events  []*FunnelEvent `bson:"events"`
+}
+
+// FunnelDimension splits a funnel report
+type FunnelDimension string
+
+const (
+	FunnelDimensionLink    FunnelDimension = "link"
+	FunnelDimensionChannel FunnelDimension = "channel"
+)
+
+func (d FunnelDimension) IsValid() bool {
+	return d == FunnelDimensionLink || d == FunnelDimensionChannel
+}
+
+type FunnelStepCount struct {
+	Name        string  `json:"name"`
+	Tracks      int64   `json:"tracks"`        // reached the step
+	Rate        float64 `json:"rate"`          // of the tracks of the first step
+	DropOff     int64   `json:"drop_off"`      // reached the previous step but not this one
+	DropOffRate float64 `json:"drop_off_rate"` // of the tracks of the previous step
+}
+
+type FunnelRow struct {
+	Value string             `json:"value,omitempty"` // link id or channel
+	Steps []*FunnelStepCount `json:"steps"`
+}
+
+// Add counts a journey that reached the first steps
+func (r *FunnelRow) Add(reached int) {
+	for i := 0; i < reached && i < len(r.Steps); i++ {
+		r.Steps[i].Tracks++
+	}
+}
+
+// SetRates computes the drop-offs once every journey is counted
+func (r *FunnelRow) SetRates() {
+	for i, step := range r.Steps {
+		if r.Steps[0].Tracks > 0 {
+			step.Rate = float64(step.Tracks) / float64(r.Steps[0].Tracks)
+		}
+		if i == 0 {
+			continue
+		}
+		previous := r.Steps[i-1].Tracks
+		step.DropOff = previous - step.Tracks
+		if previous > 0 {
+			step.DropOffRate = float64(step.DropOff) / float64(previous)
+		}
+	}
+}
+
+func NewFunnelRow(funnel *Funnel, value string) *FunnelRow {
+	row := &FunnelRow{Value: value, Steps: make([]*FunnelStepCount, len(funnel.Steps))}
+	for i, step := range funnel.Steps {
+		row.Steps[i] = &FunnelStepCount{Name: step.Name}
+	}
+	return row
+}
+
+// FunnelReport counts the tracks whose journey started in [From, To) at each step
+type FunnelReport struct {
+	FunnelID  bson.ObjectID     `json:"funnel_id"`
+	Name      string            `json:"name"`
+	From      time.Time         `json:"from"`
+	To        time.Time         `json:"to"`
+	Dimension FunnelDimension   `json:"dimension,omitempty"`
+	Total     *FunnelRow        `json:"total"`
+	Rows      []*FunnelRow      `json:"rows"`
+	Labels    map[string]string `json:"labels"` // names of the links
+}
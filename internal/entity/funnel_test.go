@@ -0,0 +1,147 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestFunnelStep_Matches(t *testing.T) {
+	pageID := bson.NewObjectID()
+	testcases := []struct {
+		name  string
+		step  FunnelStep
+		event FunnelEvent
+		want  bool
+	}{
+		{"landing", FunnelStep{Type: FunnelStepLanding}, FunnelEvent{EventName: EventNameLandingPage}, true},
+		{"page view is not a landing", FunnelStep{Type: FunnelStepLanding}, FunnelEvent{EventName: EventNamePageView}, false},
+		{
+			"exact url ignores the trailing slash",
+			FunnelStep{Type: FunnelStepURL, Match: URLMatchExact, Value: "/cars"},
+			FunnelEvent{EventName: EventNamePageView, Url: "https://dealer.com/cars/?id=1"},
+			true,
+		},
+		{
+			"exact url of the root",
+			FunnelStep{Type: FunnelStepURL, Match: URLMatchExact, Value: "/"},
+			FunnelEvent{EventName: EventNameLandingPage, Url: "https://dealer.com"},
+			true,
+		},
+		{
+			"prefix url",
+			FunnelStep{Type: FunnelStepURL, Match: URLMatchPrefix, Value: "/cars/"},
+			FunnelEvent{EventName: EventNamePageView, Url: "https://dealer.com/cars/123"},
+			true,
+		},
+		{
+			"contains looks into the query",
+			FunnelStep{Type: FunnelStepURL, Match: URLMatchContains, Value: "step=quote"},
+			FunnelEvent{EventName: EventNamePageView, Url: "https://dealer.com/form?step=quote"},
+			true,
+		},
+		{
+			"url of a custom event",
+			FunnelStep{Type: FunnelStepURL, Match: URLMatchPrefix, Value: "/cars/"},
+			FunnelEvent{EventName: EventNameCustom, Name: "quote_started", Url: "https://dealer.com/cars/123"},
+			false,
+		},
+		{
+			"event name",
+			FunnelStep{Type: FunnelStepEvent, Value: "quote_started"},
+			FunnelEvent{EventName: EventNameCustom, Name: "quote_started"},
+			true,
+		},
+		{
+			"other event name",
+			FunnelStep{Type: FunnelStepEvent, Value: "quote_started"},
+			FunnelEvent{EventName: EventNameCustom, Name: "quote_sent"},
+			false,
+		},
+		{
+			"any conversion",
+			FunnelStep{Type: FunnelStepConversion},
+			FunnelEvent{EventName: EventNameThankYouPage, ThankYouPageID: pageID},
+			true,
+		},
+		{
+			"conversion of the page",
+			FunnelStep{Type: FunnelStepConversion, Value: pageID.Hex()},
+			FunnelEvent{EventName: EventNameThankYouPage, ThankYouPageID: pageID},
+			true,
+		},
+		{
+			"conversion of another page",
+			FunnelStep{Type: FunnelStepConversion, Value: bson.NewObjectID().Hex()},
+			FunnelEvent{EventName: EventNameThankYouPage, ThankYouPageID: pageID},
+			false,
+		},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.want, tcase.step.Matches(&tcase.event))
+		})
+	}
+}
+
+func TestFunnel_Progress(t *testing.T) {
+	funnel := &Funnel{
+		Steps: []*FunnelStep{
+			{Name: "landing", Type: FunnelStepLanding},
+			{Name: "car page", Type: FunnelStepURL, Match: URLMatchPrefix, Value: "/cars/"},
+			{Name: "thank you", Type: FunnelStepConversion},
+		},
+		WindowHours: 24,
+	}
+
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	landing := func(at time.Duration) *FunnelEvent {
+		return &FunnelEvent{EventName: EventNameLandingPage, Url: "https://dealer.com/", CreatedAt: start.Add(at)}
+	}
+	carPage := func(at time.Duration) *FunnelEvent {
+		return &FunnelEvent{EventName: EventNamePageView, Url: "https://dealer.com/cars/1", CreatedAt: start.Add(at)}
+	}
+	conversion := func(at time.Duration) *FunnelEvent {
+		return &FunnelEvent{EventName: EventNameThankYouPage, Url: "https://dealer.com/thanks", CreatedAt: start.Add(at)}
+	}
+
+	testcases := []struct {
+		name    string
+		events  []*FunnelEvent
+		reached int
+		start   time.Time
+	}{
+		{"no event", nil, 0, time.Time{}},
+		{"no first step", []*FunnelEvent{carPage(0), conversion(time.Hour)}, 0, time.Time{}},
+		{"every step", []*FunnelEvent{landing(0), carPage(time.Hour), conversion(2 * time.Hour)}, 3, start},
+		{"steps out of order", []*FunnelEvent{landing(0), conversion(time.Hour), carPage(2 * time.Hour)}, 2, start},
+		{"other pages in between", []*FunnelEvent{landing(0), landing(time.Hour), carPage(2 * time.Hour)}, 2, start},
+		{"journey starts at the first step", []*FunnelEvent{carPage(0), landing(time.Hour), carPage(2 * time.Hour)}, 2, start.Add(time.Hour)},
+		{"last step at the end of the window", []*FunnelEvent{landing(0), carPage(time.Hour), conversion(24 * time.Hour)}, 3, start},
+		{"last step after the window", []*FunnelEvent{landing(0), carPage(time.Hour), conversion(25 * time.Hour)}, 2, start},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			reached, journeyStart := funnel.Progress(tcase.events)
+			assert.Equal(t, tcase.reached, reached)
+			assert.Equal(t, tcase.start, journeyStart)
+		})
+	}
+}
+
+func TestFunnelRow_SetRates(t *testing.T) {
+	funnel := &Funnel{Steps: []*FunnelStep{{Name: "landing"}, {Name: "car page"}, {Name: "thank you"}}}
+	row := NewFunnelRow(funnel, "")
+	for _, reached := range []int{3, 2, 2, 1} {
+		row.Add(reached)
+	}
+	row.SetRates()
+
+	assert.Equal(t, &FunnelStepCount{Name: "landing", Tracks: 4, Rate: 1}, row.Steps[0])
+	assert.Equal(t, &FunnelStepCount{Name: "car page", Tracks: 3, Rate: 0.75, DropOff: 1, DropOffRate: 0.25}, row.Steps[1])
+	assert.Equal(t, &FunnelStepCount{Name: "thank you", Tracks: 1, Rate: 0.25, DropOff: 2, DropOffRate: 2.0 / 3}, row.Steps[2])
+}
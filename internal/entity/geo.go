@@ -0,0 +1,22 @@
+package entity
+
+// Geo is looked up from the client ip, which isn't kept as is
+type Geo struct {
+	Country     string `bson:"country,omitempty" json:"country,omitempty"` // ISO 3166-1 alpha-2
+	CountryName string `bson:"country_name,omitempty" json:"country_name,omitempty"`
+	Region      string `bson:"region,omitempty" json:"region,omitempty"` // ISO 3166-2 subdivision code, e.g. 13 for Tokyo
+	RegionName  string `bson:"region_name,omitempty" json:"region_name,omitempty"`
+	City        string `bson:"city,omitempty" json:"city,omitempty"`
+}
+
+func (g Geo) IsEmpty() bool {
+	return g == Geo{}
+}
+
+// IPMode is what is kept of the client ip after the lookup
+type IPMode string
+
+const (
+	IPModeDrop     IPMode = "drop"
+	IPModeTruncate IPMode = "truncate" // ipv4 /24, ipv6 /48
+)
@@ -0,0 +1,229 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type IdentifierType string
+
+const (
+	IdentifierTypeEndUserID   IdentifierType = "end_user_id"
+	IdentifierTypeFingerprint IdentifierType = "fingerprint"
+	IdentifierTypeZTID        IdentifierType = "ztid"
+)
+
+func (t IdentifierType) IsValid() bool {
+	switch t {
+	case IdentifierTypeEndUserID, IdentifierTypeFingerprint, IdentifierTypeZTID:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsProbabilistic reports whether the identifier can be shared by different people
+// (e.g. two users on the same device profile produce the same fingerprint).
+func (t IdentifierType) IsProbabilistic() bool {
+	return t == IdentifierTypeFingerprint
+}
+
+type Identifier struct {
+	Type  IdentifierType `bson:"type" json:"type"`
+	Value string         `bson:"value" json:"value"`
+}
+
+func (i Identifier) String() string {
+	return string(i.Type) + ":" + i.Value
+}
+
+type IdentityConfidence int
+
+const (
+	IdentityConfidenceUnknown IdentityConfidence = iota
+	IdentityConfidenceLow                        // inferred
+	IdentityConfidenceMedium                     // observed together on the same event
+	IdentityConfidenceHigh                       // issued by the server (track creation)
+)
+
+func (c IdentityConfidence) String() string {
+	switch c {
+	case IdentityConfidenceLow:
+		return "Low"
+	case IdentityConfidenceMedium:
+		return "Medium"
+	case IdentityConfidenceHigh:
+		return "High"
+	default:
+		return "Unknown"
+	}
+}
+
+// IdentityEdge is a merge edge between two identifiers of the same cluster
+type IdentityEdge struct {
+	From       Identifier         `bson:"from" json:"from"`
+	To         Identifier         `bson:"to" json:"to"`
+	Confidence IdentityConfidence `bson:"confidence" json:"confidence"`
+	Source     string             `bson:"source" json:"source"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+func (e IdentityEdge) Touches(identifier Identifier) bool {
+	return e.From == identifier || e.To == identifier
+}
+
+func (e IdentityEdge) Connects(a, b Identifier) bool {
+	return (e.From == a && e.To == b) || (e.From == b && e.To == a)
+}
+
+// Identity is a cluster of identifiers that belong to the same end user
+type Identity struct {
+	ID                bson.ObjectID  `bson:"_id,omitempty" json:"id"`
+	TrackingSettingID bson.ObjectID  `bson:"tracking_setting_id" json:"tracking_setting_id"`
+	Identifiers       []Identifier   `bson:"identifiers" json:"identifiers"`
+	Edges             []IdentityEdge `bson:"edges" json:"edges"`
+	BaseEntity        `bson:",inline"`
+}
+
+func (i *Identity) SetCreatedAt() {
+	if i.CreatedAt.IsZero() {
+		i.CreatedAt = time.Now().UTC()
+	}
+}
+
+func (i *Identity) SetUpdatedAt() {
+	i.UpdatedAt = time.Now().UTC()
+}
+
+func (i *Identity) Has(identifier Identifier) bool {
+	for _, id := range i.Identifiers {
+		if id == identifier {
+			return true
+		}
+	}
+	return false
+}
+
+// Values returns every identifier value of the given type inside the cluster
+func (i *Identity) Values(identifierType IdentifierType) []string {
+	res := []string{}
+	for _, id := range i.Identifiers {
+		if id.Type == identifierType {
+			res = append(res, id.Value)
+		}
+	}
+	return res
+}
+
+// ConfidenceOf returns the strongest edge confidence attached to the identifier
+func (i *Identity) ConfidenceOf(identifier Identifier) IdentityConfidence {
+	confidence := IdentityConfidenceUnknown
+	for _, edge := range i.Edges {
+		if edge.Touches(identifier) && edge.Confidence > confidence {
+			confidence = edge.Confidence
+		}
+	}
+	return confidence
+}
+
+func (i *Identity) addIdentifier(identifier Identifier) {
+	if !i.Has(identifier) {
+		i.Identifiers = append(i.Identifiers, identifier)
+	}
+}
+
+// AddEdge adds both ends of the edge to the cluster. When the pair is already linked,
+// only the confidence is upgraded.
+func (i *Identity) AddEdge(edge IdentityEdge) {
+	i.addIdentifier(edge.From)
+	i.addIdentifier(edge.To)
+
+	for idx, existing := range i.Edges {
+		if existing.Connects(edge.From, edge.To) {
+			if edge.Confidence > existing.Confidence {
+				i.Edges[idx].Confidence = edge.Confidence
+				i.Edges[idx].Source = edge.Source
+			}
+			return
+		}
+	}
+
+	if edge.CreatedAt.IsZero() {
+		edge.CreatedAt = time.Now().UTC()
+	}
+	i.Edges = append(i.Edges, edge)
+}
+
+// Merge moves all identifiers and edges of other into this cluster
+func (i *Identity) Merge(other *Identity) {
+	for _, identifier := range other.Identifiers {
+		i.addIdentifier(identifier)
+	}
+	for _, edge := range other.Edges {
+		i.AddEdge(edge)
+	}
+}
+
+// Remove detaches the identifier from the cluster and splits what is left into
+// connected components. The first returned cluster keeps the current ID, the others
+// are new clusters; the detached identifier is returned as its own cluster.
+func (i *Identity) Remove(identifier Identifier) []*Identity {
+	remainingEdges := []IdentityEdge{}
+	for _, edge := range i.Edges {
+		if !edge.Touches(identifier) {
+			remainingEdges = append(remainingEdges, edge)
+		}
+	}
+
+	visited := map[Identifier]bool{identifier: true}
+	components := []*Identity{}
+	for _, start := range i.Identifiers {
+		if visited[start] {
+			continue
+		}
+
+		component := &Identity{TrackingSettingID: i.TrackingSettingID}
+		queue := []Identifier{start}
+		visited[start] = true
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			component.addIdentifier(current)
+
+			for _, edge := range remainingEdges {
+				if !edge.Touches(current) {
+					continue
+				}
+				next := edge.From
+				if next == current {
+					next = edge.To
+				}
+				if !visited[next] {
+					visited[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+
+		for _, edge := range remainingEdges {
+			if component.Has(edge.From) {
+				component.Edges = append(component.Edges, edge)
+			}
+		}
+		components = append(components, component)
+	}
+
+	if len(components) > 0 {
+		components[0].ID = i.ID
+		components[0].BaseEntity = i.BaseEntity
+	}
+
+	detached := &Identity{
+		TrackingSettingID: i.TrackingSettingID,
+		Identifiers:       []Identifier{identifier},
+		Edges:             []IdentityEdge{},
+	}
+
+	return append(components, detached)
+}
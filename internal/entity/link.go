@@ -1,7 +1,8 @@
 package entity
 
 import (
-	"fmt"
+	"hash/fnv"
+	"strings"
 	"time"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
@@ -21,12 +22,65 @@ Best Practices
 - Always use omitempty with _id field to allow MongoDB auto-generation
 */
 type Link struct {
-	ID         bson.ObjectID `bson:"_id,omitempty"` // id
-	Name       string        `bson:"name"`          // name
-	TenantID   string        `bson:"tenant_id"`     // tenant id
-	Url        string        `bson:"url"`           // original url
-	ShortID    string        `bson:"short_id"`      // short id
-	BaseEntity `bson:",inline"`
+	ID            bson.ObjectID  `bson:"_id,omitempty"`      // id
+	Name          string         `bson:"name"`               // name
+	TenantID      string         `bson:"tenant_id"`          // tenant id
+	Url           string         `bson:"url"`                // original url
+	ShortID       string         `bson:"short_id"`           // short id, random or a custom slug
+	Domain        string         `bson:"domain"`             // branded domain of the tenant, empty for config.Domain
+	Tags          []string       `bson:"tags,omitempty"`     // free-form, lowercase
+	Variants      []LinkVariant  `bson:"variants,omitempty"` // weighted A/B destinations, Url is used without them
+	Rules         []RedirectRule `bson:"rules,omitempty"`    // ordered, tried before the variants
+	AutoTrack     bool           `bson:"auto_track"`         // create a track on every click
+	Clicks        int64          `bson:"clicks"`             // human clicks, only counted while MaxClicks is set
+	CampaignID    bson.ObjectID  `bson:"campaign_id,omitempty"`
+	SearchName    string         `bson:"search_name"`     // lowercase name, matched by prefix in the search
+	SearchShortID string         `bson:"search_short_id"` // lowercase short id, matched by prefix in the search
+	LinkLifecycle `bson:",inline"`
+	LinkRedirect  `bson:",inline"`
+	BaseEntity    `bson:",inline"`
+}
+
+// LinkLifecycle limits when and how often a link redirects to its destination
+type LinkLifecycle struct {
+	StartsAt       *time.Time `bson:"starts_at,omitempty" json:"starts_at,omitempty"`
+	EndsAt         *time.Time `bson:"ends_at,omitempty" json:"ends_at,omitempty"`
+	MaxClicks      int64      `bson:"max_clicks,omitempty" json:"max_clicks,omitempty"`           // 0 is unlimited
+	FallbackUrl    string     `bson:"fallback_url,omitempty" json:"fallback_url,omitempty"`       // instead of the expired page
+	ExpiredMessage string     `bson:"expired_message,omitempty" json:"expired_message,omitempty"` // shown on the expired page
+}
+
+type LinkInactiveReason string
+
+const (
+	LinkInactiveReasonNotStarted LinkInactiveReason = "not_started"
+	LinkInactiveReasonEnded      LinkInactiveReason = "ended"
+	LinkInactiveReasonClickLimit LinkInactiveReason = "click_limit"
+)
+
+// InactiveReason tells why the link doesn't redirect to its destination at now, empty when it does
+func (l *Link) InactiveReason(now time.Time) LinkInactiveReason {
+	if l.StartsAt != nil && now.Before(*l.StartsAt) {
+		return LinkInactiveReasonNotStarted
+	}
+
+	if l.EndsAt != nil && !now.Before(*l.EndsAt) {
+		return LinkInactiveReasonEnded
+	}
+
+	if l.MaxClicks > 0 && l.Clicks >= l.MaxClicks {
+		return LinkInactiveReasonClickLimit
+	}
+
+	return ""
+}
+
+// LinkVariant is one destination of an A/B split
+type LinkVariant struct {
+	ID     string `bson:"id" json:"id"`
+	Name   string `bson:"name" json:"name"`
+	Url    string `bson:"url" json:"url"`
+	Weight int    `bson:"weight" json:"weight"`
 }
 
 func (l *Link) SetShortID() error {
@@ -42,6 +96,13 @@ func (l *Link) SetShortID() error {
 	return nil
 }
 
+// SetSearchFields lowercases the name and the short id for the prefix search, which matches
+// them with a case-sensitive pattern so their index is used
+func (l *Link) SetSearchFields() {
+	l.SearchName = strings.ToLower(l.Name)
+	l.SearchShortID = strings.ToLower(l.ShortID)
+}
+
 func (l *Link) SetCreatedAt() {
 	if l.CreatedAt.IsZero() {
 		l.CreatedAt = time.Now().UTC()
@@ -58,6 +119,60 @@ func (l *Link) SoftDelete() {
 	l.SetUpdatedAt()
 }
 
+// CreatesTrack tells whether clicks get a track, A/B links need one to count conversions per variant
+func (l *Link) CreatesTrack() bool {
+	return l.AutoTrack || len(l.Variants) > 0
+}
+
+func (l *Link) FindVariant(id string) *LinkVariant {
+	for i := range l.Variants {
+		if l.Variants[i].ID == id && l.Variants[i].Weight > 0 {
+			return &l.Variants[i]
+		}
+	}
+	return nil
+}
+
+// PickVariant assigns the visitor to a variant by weight, the same key always gets the same variant
+// as long as the variants don't change
+func (l *Link) PickVariant(visitorKey string) *LinkVariant {
+	total := 0
+	for _, variant := range l.Variants {
+		total += max(variant.Weight, 0)
+	}
+	if total == 0 {
+		return nil
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(l.ShortID + ":" + visitorKey))
+	point := int(h.Sum64() % uint64(total))
+	for i := range l.Variants {
+		point -= max(l.Variants[i].Weight, 0)
+		if point < 0 {
+			return &l.Variants[i]
+		}
+	}
+	return nil
+}
+
+// Path is the path of the short link, branded domains serve the slug from the root
+func (f *Link) Path() string {
+	if f.Domain != "" {
+		return "/" + f.ShortID
+	}
+	return "/r/" + f.ShortID
+}
+
+// ConstructQRUrl is the short link encoded in its QR code, scans are clicks of source qr
+func (f *Link) ConstructQRUrl(baseUrl string) string {
+	return f.ConstructFixedUrl(baseUrl) + "?" + ClickSourceParam + "=" + string(ClickSourceQR)
+}
+
+// ConstructFixedUrl is the short link, on the branded domain of the link or under baseUrl
 func (f *Link) ConstructFixedUrl(baseUrl string) string {
-	return fmt.Sprintf("%s/r/%s", baseUrl, f.ShortID)
+	if f.Domain != "" {
+		return "https://" + f.Domain + f.Path()
+	}
+	return baseUrl + f.Path()
 }
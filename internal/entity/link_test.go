@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLink_PickVariant(t *testing.T) {
+	link := &Link{
+		ShortID: "spring",
+		Variants: []LinkVariant{
+			{ID: "a", Weight: 70},
+			{ID: "b", Weight: 30},
+			{ID: "c", Weight: 0},
+		},
+	}
+
+	t.Run("should split the visitors by weight", func(t *testing.T) {
+		counts := map[string]int{}
+		for i := range 10000 {
+			counts[link.PickVariant(fmt.Sprintf("visitor-%d", i)).ID]++
+		}
+
+		assert.InDelta(t, 7000, counts["a"], 300)
+		assert.InDelta(t, 3000, counts["b"], 300)
+		assert.Zero(t, counts["c"])
+	})
+
+	t.Run("should keep the variant of a visitor", func(t *testing.T) {
+		for i := range 100 {
+			key := fmt.Sprintf("visitor-%d", i)
+			assert.Equal(t, link.PickVariant(key), link.PickVariant(key))
+		}
+	})
+
+	t.Run("should pick nothing without weight", func(t *testing.T) {
+		assert.Nil(t, (&Link{}).PickVariant("visitor"))
+		assert.Nil(t, (&Link{Variants: []LinkVariant{{ID: "a", Weight: 0}}}).PickVariant("visitor"))
+	})
+}
+
+func TestLink_FindVariant(t *testing.T) {
+	link := &Link{Variants: []LinkVariant{{ID: "a", Weight: 1}, {ID: "b", Weight: 0}}}
+
+	testcases := []struct {
+		name  string
+		id    string
+		found bool
+	}{
+		{"served variant", "a", true},
+		{"variant without weight", "b", false},
+		{"removed variant", "c", false},
+		{"no variant", "", false},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.found, link.FindVariant(tcase.id) != nil)
+		})
+	}
+}
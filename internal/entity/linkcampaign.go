@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// LinkCampaign groups links of a tenant, e.g. the landing pages of a dealer's spring sale.
+// Not to be mixed up with the utm_campaign of the tracks.
+type LinkCampaign struct {
+	ID         bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID   string        `bson:"tenant_id" json:"tenant_id"`
+	Name       string        `bson:"name" json:"name"`
+	StartsAt   *time.Time    `bson:"starts_at,omitempty" json:"starts_at,omitempty"`
+	EndsAt     *time.Time    `bson:"ends_at,omitempty" json:"ends_at,omitempty"`
+	Budget     float64       `bson:"budget" json:"budget"`                         // in Currency
+	Currency   string        `bson:"currency,omitempty" json:"currency,omitempty"` // ISO 4217
+	Owner      string        `bson:"owner" json:"owner"`                           // who manages it, e.g. an account manager
+	BaseEntity `bson:",inline"`
+}
+
+func (c *LinkCampaign) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("name can not be empty")
+	}
+
+	if c.StartsAt != nil && c.EndsAt != nil && !c.EndsAt.After(*c.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+
+	if c.Budget < 0 {
+		return fmt.Errorf("budget can not be negative")
+	}
+
+	if c.Currency != "" && len(c.Currency) != 3 {
+		return fmt.Errorf("currency must be an ISO 4217 code")
+	}
+
+	return nil
+}
+
+func (c *LinkCampaign) SetCreatedAt() {
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now().UTC()
+	}
+}
+
+func (c *LinkCampaign) SetUpdatedAt() {
+	c.UpdatedAt = time.Now().UTC()
+}
+
+// LinkGroup is how the rows of a link group report are grouped
+type LinkGroup string
+
+const (
+	LinkGroupCampaign LinkGroup = "campaign"
+	LinkGroupTag      LinkGroup = "tag"
+)
+
+func (g LinkGroup) IsValid() bool {
+	return g == LinkGroupCampaign || g == LinkGroupTag
+}
+
+// LinkGroupRow sums the clicks and the outcomes of the auto-created tracks of the links of a
+// campaign or a tag. A link with several tags counts in each of them.
+type LinkGroupRow struct {
+	Key               string  `json:"key"`  // campaign id or tag, empty for the links without
+	Name              string  `json:"name"` // of the campaign
+	Links             int     `json:"links"`
+	Clicks            int64   `json:"clicks"`
+	Tracks            int64   `json:"tracks"`
+	Landings          int64   `json:"landings"`
+	Conversions       int64   `json:"conversions"`
+	ConversionRate    float64 `json:"conversion_rate"`               // conversions per click
+	Budget            float64 `json:"budget,omitempty"`              // of the campaign
+	CostPerConversion float64 `json:"cost_per_conversion,omitempty"` // budget per conversion
+}
+
+type LinkGroupReport struct {
+	Group LinkGroup       `json:"group"`
+	Rows  []*LinkGroupRow `json:"rows"`
+}
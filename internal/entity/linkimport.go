@@ -0,0 +1,236 @@
+package entity
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxLinkImportRows caps the rows of a bulk import
+const MaxLinkImportRows = 5000
+
+// MaxLinkImportSize caps the bytes of a bulk import
+const MaxLinkImportSize = 10 << 20
+
+// linkTagSeparator joins the tags in a csv cell
+const linkTagSeparator = "|"
+
+type LinkFileFormat string
+
+const (
+	LinkFileFormatCSV  LinkFileFormat = "csv"
+	LinkFileFormatJSON LinkFileFormat = "json"
+)
+
+func (f LinkFileFormat) IsValid() bool {
+	return f == LinkFileFormatCSV || f == LinkFileFormatJSON
+}
+
+func (f LinkFileFormat) ContentType() string {
+	if f == LinkFileFormatCSV {
+		return "text/csv"
+	}
+	return "application/json"
+}
+
+// LinkImportRow is one link of a bulk import, a row with an id updates the name, url, tags
+// and campaign of that link, the others create a link
+type LinkImportRow struct {
+	Row        int      `json:"-"` // 1-based, without the csv header
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Url        string   `json:"url"`
+	Slug       string   `json:"slug"`
+	Domain     string   `json:"domain"`
+	Tags       []string `json:"tags"`
+	CampaignID string   `json:"campaign_id"`
+}
+
+// ParseLinkImport reads the rows of a csv with a header or of a json {"links": [...]}. The csv
+// columns are matched by name, the unknown ones are ignored so an export can be imported back.
+func ParseLinkImport(format LinkFileFormat, r io.Reader) ([]*LinkImportRow, error) {
+	var rows []*LinkImportRow
+	var err error
+	if format == LinkFileFormatCSV {
+		rows, err = parseLinkImportCSV(r)
+	} else {
+		rows, err = parseLinkImportJSON(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the file has no links")
+	}
+
+	if len(rows) > MaxLinkImportRows {
+		return nil, fmt.Errorf("at most %d links can be imported at once", MaxLinkImportRows)
+	}
+
+	for i, row := range rows {
+		row.Row = i + 1
+	}
+	return rows, nil
+}
+
+func parseLinkImportJSON(r io.Reader) ([]*LinkImportRow, error) {
+	var body struct {
+		Links []*LinkImportRow `json:"links"`
+	}
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return nil, fmt.Errorf("json is not valid: %w", err)
+	}
+	return body.Links, nil
+}
+
+func parseLinkImportCSV(r io.Reader) ([]*LinkImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("the file has no links")
+	} else if err != nil {
+		return nil, fmt.Errorf("csv is not valid: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		// excel prefixes utf-8 files with a byte order mark
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, name := range []string{"name", "url"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv has no %s column", name)
+		}
+	}
+
+	rows := []*LinkImportRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("csv is not valid: %w", err)
+		}
+
+		if len(rows) == MaxLinkImportRows {
+			return nil, fmt.Errorf("at most %d links can be imported at once", MaxLinkImportRows)
+		}
+
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := &LinkImportRow{
+			ID:         cell("id"),
+			Name:       cell("name"),
+			Url:        cell("url"),
+			Slug:       cell("slug"),
+			Domain:     cell("domain"),
+			CampaignID: cell("campaign_id"),
+		}
+		if tags := cell("tags"); tags != "" {
+			row.Tags = strings.Split(tags, linkTagSeparator)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+type LinkImportAction string
+
+const (
+	LinkImportActionCreate LinkImportAction = "create"
+	LinkImportActionUpdate LinkImportAction = "update"
+)
+
+type LinkImportRowResult struct {
+	Row    int              `json:"row"`
+	Action LinkImportAction `json:"action"`
+	LinkID string           `json:"link_id,omitempty"`
+	Errors []string         `json:"errors,omitempty"`
+}
+
+func (r *LinkImportRowResult) Failed() bool {
+	return len(r.Errors) > 0
+}
+
+// LinkImportReport has a result per row. An atomic import writes nothing unless every row is
+// valid, the other imports skip the failing rows.
+// An atomic import is not a transaction, a failed write undoes the earlier ones one by one and
+// NotRolledBack lists the links that were left written.
+type LinkImportReport struct {
+	Atomic        bool                   `json:"atomic"`
+	Applied       bool                   `json:"applied"`
+	Created       int                    `json:"created"`
+	Updated       int                    `json:"updated"`
+	Failed        int                    `json:"failed"`
+	Rows          []*LinkImportRowResult `json:"rows"`
+	NotRolledBack []string               `json:"not_rolled_back,omitempty"` // ids of the created or updated links
+}
+
+// LinkExportRow is a link with its short link and its stats since its creation
+type LinkExportRow struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Url         string    `json:"url"`
+	Slug        string    `json:"slug"`
+	Domain      string    `json:"domain"`
+	FixedUrl    string    `json:"fixed_url"`
+	Tags        []string  `json:"tags"`
+	CampaignID  string    `json:"campaign_id"`
+	Clicks      int64     `json:"clicks"` // human clicks
+	Tracks      int64     `json:"tracks"`
+	Landings    int64     `json:"landings"`
+	Conversions int64     `json:"conversions"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+var linkExportColumns = []string{
+	"id", "name", "url", "slug", "domain", "fixed_url", "tags", "campaign_id",
+	"clicks", "tracks", "landings", "conversions", "created_at",
+}
+
+// WriteLinkExportCSV writes the rows with a header, the file can be imported back
+func WriteLinkExportCSV(w io.Writer, rows []*LinkExportRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(linkExportColumns); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := []string{
+			row.ID,
+			row.Name,
+			row.Url,
+			row.Slug,
+			row.Domain,
+			row.FixedUrl,
+			strings.Join(row.Tags, linkTagSeparator),
+			row.CampaignID,
+			strconv.FormatInt(row.Clicks, 10),
+			strconv.FormatInt(row.Tracks, 10),
+			strconv.FormatInt(row.Landings, 10),
+			strconv.FormatInt(row.Conversions, 10),
+			row.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,135 @@
+package entity
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ParamPolicy is how the params of the short link are merged into the destination
+type ParamPolicy string
+
+const (
+	ParamPolicyAppend       ParamPolicy = "append"        // added next to the destination values
+	ParamPolicyOverride     ParamPolicy = "override"      // replace the destination values
+	ParamPolicyKeepOriginal ParamPolicy = "keep_original" // only the params the destination doesn't have
+	ParamPolicyDropAll      ParamPolicy = "drop_all"      // ignored
+	ParamPolicyAllowlist    ParamPolicy = "allowlist"     // only AllowedParams, appended
+)
+
+func (p ParamPolicy) IsValid() bool {
+	switch p {
+	case "", ParamPolicyAppend, ParamPolicyOverride, ParamPolicyKeepOriginal, ParamPolicyDropAll, ParamPolicyAllowlist:
+		return true
+	default:
+		return false
+	}
+}
+
+// MaxInterstitialDelay in seconds, visitors don't wait longer
+const MaxInterstitialDelay = 10
+
+// LinkRedirect is how a link forwards the visitor to the destination
+type LinkRedirect struct {
+	ParamPolicy    ParamPolicy   `bson:"param_policy,omitempty" json:"param_policy,omitempty"`       // append when empty
+	AllowedParams  []string      `bson:"allowed_params,omitempty" json:"allowed_params,omitempty"`   // of the allowlist policy
+	StripParams    []string      `bson:"strip_params,omitempty" json:"strip_params,omitempty"`       // removed from the destination, "utm_*" is a prefix
+	RedirectStatus int           `bson:"redirect_status,omitempty" json:"redirect_status,omitempty"` // 301, 302, 307 or 308, 302 when empty
+	Interstitial   *Interstitial `bson:"interstitial,omitempty" json:"interstitial,omitempty"`       // a page forwarding with a meta refresh instead
+}
+
+// Interstitial is a page loading the scripts before the meta refresh to the destination
+type Interstitial struct {
+	Delay   int      `bson:"delay" json:"delay"`     // seconds
+	Scripts []string `bson:"scripts" json:"scripts"` // https urls, e.g. the analytics of the tenant
+}
+
+func (r *LinkRedirect) Validate() error {
+	if !r.ParamPolicy.IsValid() {
+		return fmt.Errorf("param_policy is not valid")
+	}
+
+	if r.ParamPolicy == ParamPolicyAllowlist && len(r.AllowedParams) == 0 {
+		return fmt.Errorf("allowed_params can not be empty with the allowlist policy")
+	}
+
+	switch r.RedirectStatus {
+	case 0, http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return fmt.Errorf("redirect_status must be 301, 302, 307 or 308")
+	}
+
+	if i := r.Interstitial; i != nil {
+		if i.Delay < 0 || i.Delay > MaxInterstitialDelay {
+			return fmt.Errorf("interstitial.delay must be between 0 and %d", MaxInterstitialDelay)
+		}
+		for _, script := range i.Scripts {
+			u, err := url.ParseRequestURI(script)
+			if err != nil || u.Scheme != "https" {
+				return fmt.Errorf("interstitial.scripts must be https urls")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Status is the status of the redirect to the destination
+func (r *LinkRedirect) Status() int {
+	if r.RedirectStatus == 0 {
+		return http.StatusFound
+	}
+	return r.RedirectStatus
+}
+
+// Destination merges the params of the short link into the destination with the param policy,
+// then strips the params of StripParams
+func (r *LinkRedirect) Destination(destination string, query url.Values) string {
+	u, err := url.Parse(destination)
+	if err != nil {
+		return destination
+	}
+
+	params := u.Query()
+	for key, values := range query {
+		switch r.ParamPolicy {
+		case ParamPolicyOverride:
+			params[key] = slices.Clone(values)
+		case ParamPolicyKeepOriginal:
+			if !params.Has(key) {
+				params[key] = slices.Clone(values)
+			}
+		case ParamPolicyDropAll:
+		case ParamPolicyAllowlist:
+			if slices.Contains(r.AllowedParams, key) {
+				params[key] = append(params[key], values...)
+			}
+		default:
+			params[key] = append(params[key], values...)
+		}
+	}
+
+	for key := range params {
+		if r.strips(key) {
+			params.Del(key)
+		}
+	}
+
+	u.RawQuery = params.Encode()
+	return u.String()
+}
+
+func (r *LinkRedirect) strips(key string) bool {
+	for _, pattern := range r.StripParams {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	LinkSearchDefaultPageSize = 20
+	LinkSearchMaxPageSize     = 100
+	LinkSearchMaxQueryLength  = 200
+)
+
+// LinkSearch finds the links of a tenant, an empty query lists them by last update
+type LinkSearch struct {
+	Query       string   // words of the name, url, short id or tags, the last one may be a prefix
+	Tags        []string // links having all of them
+	CampaignID  *bson.ObjectID
+	CreatedFrom *time.Time // inclusive
+	CreatedTo   *time.Time // exclusive
+	Deleted     bool       // the soft deleted links instead of the others
+	Page        int        // from 1
+	PageSize    int
+}
+
+// LinkSearchResult is a page of links, ranked by relevance to the query
+type LinkSearchResult struct {
+	Links    []*Link `json:"links"`
+	Total    int64   `json:"total"`
+	Page     int     `json:"page"`
+	PageSize int     `json:"page_size"`
+}
+
+func (r *LinkSearchResult) HasNext() bool {
+	return int64(r.Page*r.PageSize) < r.Total
+}
+
+// Normalize fills the defaults of the paging and trims the query and the tags
+func (s *LinkSearch) Normalize() {
+	s.Query = strings.TrimSpace(s.Query)
+	if runes := []rune(s.Query); len(runes) > LinkSearchMaxQueryLength {
+		s.Query = string(runes[:LinkSearchMaxQueryLength])
+	}
+
+	s.Tags = NormalizeTags(s.Tags)
+
+	if s.Page < 1 {
+		s.Page = 1
+	}
+	if s.PageSize < 1 {
+		s.PageSize = LinkSearchDefaultPageSize
+	}
+	s.PageSize = min(s.PageSize, LinkSearchMaxPageSize)
+}
+
+// TextSearch is the query for the text index, made of its words only so the operators of
+// $search (quotes and negation) can't be injected
+func (s *LinkSearch) TextSearch() string {
+	words := strings.FieldsFunc(s.Query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// PrefixPattern is an anchored pattern matching the search fields of Link starting with the
+// query, the query is lowercased like them and escaped so it's matched literally
+func (s *LinkSearch) PrefixPattern() string {
+	return "^" + regexp.QuoteMeta(strings.ToLower(s.Query))
+}
+
+// NormalizeTags lowercases and trims the tags, dropping the empty and duplicated ones
+func NormalizeTags(tags []string) []string {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
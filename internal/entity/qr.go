@@ -0,0 +1,33 @@
+package entity
+
+type QRFormat string
+
+const (
+	QRFormatPNG QRFormat = "png"
+	QRFormatSVG QRFormat = "svg"
+)
+
+func (f QRFormat) IsValid() bool {
+	return f == QRFormatPNG || f == QRFormatSVG
+}
+
+func (f QRFormat) ContentType() string {
+	if f == QRFormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+const (
+	QRMinSize     = 64
+	QRMaxSize     = 2048
+	QRDefaultSize = 512
+)
+
+// QROptions of the QR code of a link
+type QROptions struct {
+	Format QRFormat
+	Size   int    // pixels
+	Level  string // error correction, L, M, Q or H
+	Logo   string // url of a png, jpeg or gif on an owned domain of the tenant, drawn in the center
+}
@@ -0,0 +1,225 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// RedirectRule sends the clicks matching all of its conditions to its own destination,
+// the rules of a link are tried in order and the first match wins over the variants
+type RedirectRule struct {
+	Name       string         `bson:"name" json:"name"`
+	Conditions RuleConditions `bson:"conditions" json:"conditions"`
+	Url        string         `bson:"url" json:"url"`
+}
+
+// RuleConditions are and-ed, an empty condition matches every click.
+// Values of a list are or-ed and compared ignoring case.
+type RuleConditions struct {
+	DeviceTypes []DeviceType     `bson:"device_types,omitempty" json:"device_types,omitempty"`
+	OS          []string         `bson:"os,omitempty" json:"os,omitempty"`               // e.g. iOS, Android
+	Countries   []string         `bson:"countries,omitempty" json:"countries,omitempty"` // ISO 3166-1 alpha-2
+	Languages   []string         `bson:"languages,omitempty" json:"languages,omitempty"` // "ja" matches ja-JP, "en-US" only en-US
+	TimeOfDay   *TimeOfDay       `bson:"time_of_day,omitempty" json:"time_of_day,omitempty"`
+	QueryParams []QueryCondition `bson:"query_params,omitempty" json:"query_params,omitempty"`
+}
+
+// TimeOfDay is the hours [From, To) in the time zone, To below From wraps around midnight
+type TimeOfDay struct {
+	From     int    `bson:"from" json:"from"`
+	To       int    `bson:"to" json:"to"`
+	TimeZone string `bson:"time_zone" json:"time_zone"` // IANA name, UTC when empty
+
+	location *time.Location // of TimeZone, resolved when the rule is decoded or validated
+}
+
+// timeOfDayFields decodes the fields of a TimeOfDay without its unmarshalers
+type timeOfDayFields TimeOfDay
+
+// UnmarshalBSON resolves the time zone once, when the link is loaded, not at every click
+func (t *TimeOfDay) UnmarshalBSON(data []byte) error {
+	if err := bson.Unmarshal(data, (*timeOfDayFields)(t)); err != nil {
+		return err
+	}
+	t.location, _ = time.LoadLocation(t.TimeZone)
+	return nil
+}
+
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*timeOfDayFields)(t)); err != nil {
+		return err
+	}
+	t.location, _ = time.LoadLocation(t.TimeZone)
+	return nil
+}
+
+// QueryCondition matches a param of the short link, any value when Value is empty
+type QueryCondition struct {
+	Key   string `bson:"key" json:"key"`
+	Value string `bson:"value,omitempty" json:"value,omitempty"`
+}
+
+// RuleRequest holds the attributes of a click the rules match on
+type RuleRequest struct {
+	DeviceType DeviceType `json:"device_type"`
+	OS         string     `json:"os"`
+	Country    string     `json:"country"`
+	Language   string     `json:"language"`   // Accept-Language
+	UserAgent  string     `json:"user_agent"` // parsed into DeviceType and OS when they are empty
+	Time       time.Time  `json:"time"`
+	Query      url.Values `json:"query"`
+}
+
+// RuleMatch is the rule a click matched, Index is -1 without match
+type RuleMatch struct {
+	Index       int           `json:"index"`
+	Rule        *RedirectRule `json:"rule"`
+	Destination string        `json:"destination"`
+}
+
+func (r *RedirectRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name can not be empty")
+	}
+
+	if !IsWebURL(r.Url) {
+		return fmt.Errorf("url is not valid")
+	}
+
+	if t := r.Conditions.TimeOfDay; t != nil {
+		if t.From < 0 || t.From > 23 || t.To < 0 || t.To > 24 || t.From == t.To {
+			return fmt.Errorf("time_of_day must be hours from 0 to 24")
+		}
+		location, err := time.LoadLocation(t.TimeZone)
+		if err != nil {
+			return fmt.Errorf("time_of_day.time_zone %q is not valid", t.TimeZone)
+		}
+		t.location = location
+	}
+
+	for _, q := range r.Conditions.QueryParams {
+		if q.Key == "" {
+			return fmt.Errorf("query_params.key can not be empty")
+		}
+	}
+
+	return nil
+}
+
+func (r *RedirectRule) Matches(req RuleRequest) bool {
+	c := r.Conditions
+
+	if len(c.DeviceTypes) > 0 && !slices.Contains(c.DeviceTypes, req.DeviceType) {
+		return false
+	}
+
+	if len(c.OS) > 0 && !containsFold(c.OS, req.OS) {
+		return false
+	}
+
+	if len(c.Countries) > 0 && !containsFold(c.Countries, req.Country) {
+		return false
+	}
+
+	if len(c.Languages) > 0 && !matchesLanguage(c.Languages, PreferredLanguage(req.Language)) {
+		return false
+	}
+
+	if c.TimeOfDay != nil && !c.TimeOfDay.Contains(req.Time) {
+		return false
+	}
+
+	for _, q := range c.QueryParams {
+		values, ok := req.Query[q.Key]
+		if !ok || (q.Value != "" && !slices.Contains(values, q.Value)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t *TimeOfDay) Contains(at time.Time) bool {
+	location := t.location
+	if location == nil {
+		// built in code, not decoded nor validated
+		var err error
+		if location, err = time.LoadLocation(t.TimeZone); err != nil {
+			return false
+		}
+	}
+
+	hour := at.In(location).Hour()
+	if t.From < t.To {
+		return hour >= t.From && hour < t.To
+	}
+	return hour >= t.From || hour < t.To
+}
+
+// MatchRule returns the first rule matching the request
+func (l *Link) MatchRule(req RuleRequest) *RuleMatch {
+	for i := range l.Rules {
+		if l.Rules[i].Matches(req) {
+			return &RuleMatch{Index: i, Rule: &l.Rules[i], Destination: l.Rules[i].Url}
+		}
+	}
+	return nil
+}
+
+// PreferredLanguage is the language of the Accept-Language header with the highest quality
+func PreferredLanguage(acceptLanguage string) string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+
+	languages := []language{}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = v
+			}
+		}
+		languages = append(languages, language{tag: tag, quality: quality})
+	}
+
+	if len(languages) == 0 {
+		return ""
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].quality > languages[j].quality })
+	return languages[0].tag
+}
+
+func matchesLanguage(conditions []string, tag string) bool {
+	primary, _, _ := strings.Cut(tag, "-")
+	for _, c := range conditions {
+		if strings.EqualFold(c, tag) || (!strings.Contains(c, "-") && strings.EqualFold(c, primary)) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package entity
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+	_ "time/tzdata" // time zones of the rules, without relying on the host
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestPreferredLanguage(t *testing.T) {
+	testcases := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{"empty header", "", ""},
+		{"single language", "ja-JP", "ja-JP"},
+		{"first of equal qualities", "en-US,en;q=0.9,ja;q=0.9", "en-US"},
+		{"highest quality", "en;q=0.5, ja;q=0.8, fr;q=0.1", "ja"},
+		{"invalid quality is the default", "en;q=0.5,ja;q=x", "ja"},
+		{"wildcard is skipped", "*, de;q=0.2", "de"},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.want, PreferredLanguage(tcase.acceptLanguage))
+		})
+	}
+}
+
+func TestMatchesLanguage(t *testing.T) {
+	testcases := []struct {
+		name       string
+		conditions []string
+		tag        string
+		want       bool
+	}{
+		{"primary language matches a region", []string{"ja"}, "ja-JP", true},
+		{"ignores case", []string{"EN-us"}, "en-US", true},
+		{"region only matches itself", []string{"en-US"}, "en-GB", false},
+		{"region doesn't match the primary language", []string{"en-US"}, "en", false},
+		{"other language", []string{"ja", "ko"}, "en", false},
+		{"no language", []string{"ja"}, "", false},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.want, matchesLanguage(tcase.conditions, tcase.tag))
+		})
+	}
+}
+
+func TestTimeOfDay_Contains(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, 3, 1, hour, 30, 0, 0, time.UTC) }
+
+	testcases := []struct {
+		name      string
+		timeOfDay TimeOfDay
+		at        time.Time
+		want      bool
+	}{
+		{"inside the hours", TimeOfDay{From: 9, To: 17}, at(9), true},
+		{"end is excluded", TimeOfDay{From: 9, To: 17}, at(17), false},
+		{"before the hours", TimeOfDay{From: 9, To: 17}, at(8), false},
+		{"until midnight", TimeOfDay{From: 18, To: 24}, at(23), true},
+		{"around midnight, late", TimeOfDay{From: 22, To: 6}, at(23), true},
+		{"around midnight, early", TimeOfDay{From: 22, To: 6}, at(5), true},
+		{"around midnight, day", TimeOfDay{From: 22, To: 6}, at(12), false},
+		{"in the time zone", TimeOfDay{From: 9, To: 17, TimeZone: "Asia/Tokyo"}, at(1), true},
+		{"out of the time zone", TimeOfDay{From: 9, To: 17, TimeZone: "Asia/Tokyo"}, at(9), false},
+		{"unknown time zone", TimeOfDay{From: 0, To: 24, TimeZone: "Mars/Olympus"}, at(9), false},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.want, tcase.timeOfDay.Contains(tcase.at))
+		})
+	}
+}
+
+func TestTimeOfDay_Unmarshal(t *testing.T) {
+	t.Run("should resolve the time zone from json", func(t *testing.T) {
+		timeOfDay := &TimeOfDay{}
+		err := json.Unmarshal([]byte(`{"from": 9, "to": 17, "time_zone": "Asia/Tokyo"}`), timeOfDay)
+		assert.NoError(t, err)
+		assert.Equal(t, 9, timeOfDay.From)
+		assert.Equal(t, "Asia/Tokyo", timeOfDay.location.String())
+	})
+
+	t.Run("should resolve the time zone from bson", func(t *testing.T) {
+		data, err := bson.Marshal(RuleConditions{TimeOfDay: &TimeOfDay{From: 9, To: 17, TimeZone: "Asia/Tokyo"}})
+		assert.NoError(t, err)
+
+		conditions := RuleConditions{}
+		assert.NoError(t, bson.Unmarshal(data, &conditions))
+		assert.Equal(t, 17, conditions.TimeOfDay.To)
+		assert.Equal(t, "Asia/Tokyo", conditions.TimeOfDay.location.String())
+	})
+}
+
+func TestRedirectRule_Validate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		rule    RedirectRule
+		isValid bool
+	}{
+		{"valid rule", RedirectRule{Name: "ios", Url: "https://dealer.com/app"}, true},
+		{"no name", RedirectRule{Url: "https://dealer.com/app"}, false},
+		{"invalid url", RedirectRule{Name: "ios", Url: "dealer"}, false},
+		{"javascript url", RedirectRule{Name: "ios", Url: "javascript:alert(1)"}, false},
+		{
+			"hours over the day",
+			RedirectRule{Name: "night", Url: "https://dealer.com", Conditions: RuleConditions{TimeOfDay: &TimeOfDay{From: 22, To: 25}}},
+			false,
+		},
+		{
+			"same hours",
+			RedirectRule{Name: "night", Url: "https://dealer.com", Conditions: RuleConditions{TimeOfDay: &TimeOfDay{From: 6, To: 6}}},
+			false,
+		},
+		{
+			"unknown time zone",
+			RedirectRule{Name: "night", Url: "https://dealer.com",
+				Conditions: RuleConditions{TimeOfDay: &TimeOfDay{From: 22, To: 6, TimeZone: "Mars/Olympus"}}},
+			false,
+		},
+		{
+			"query param without key",
+			RedirectRule{Name: "qr", Url: "https://dealer.com", Conditions: RuleConditions{QueryParams: []QueryCondition{{Value: "qr"}}}},
+			false,
+		},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			err := tcase.rule.Validate()
+			assert.Equal(t, tcase.isValid, err == nil, err)
+		})
+	}
+}
+
+func TestRedirectRule_Matches(t *testing.T) {
+	req := RuleRequest{
+		DeviceType: DeviceTypeMobile,
+		OS:         "iOS",
+		Country:    "JP",
+		Language:   "ja-JP,en;q=0.8",
+		Time:       time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Query:      url.Values{"ztsrc": {"qr"}},
+	}
+
+	testcases := []struct {
+		name       string
+		conditions RuleConditions
+		want       bool
+	}{
+		{"no condition", RuleConditions{}, true},
+		{"device type", RuleConditions{DeviceTypes: []DeviceType{DeviceTypeTablet, DeviceTypeMobile}}, true},
+		{"other device type", RuleConditions{DeviceTypes: []DeviceType{DeviceTypeDesktop}}, false},
+		{"os ignoring case", RuleConditions{OS: []string{"ios"}}, true},
+		{"country ignoring case", RuleConditions{Countries: []string{"jp"}}, true},
+		{"other country", RuleConditions{Countries: []string{"KR"}}, false},
+		{"preferred language", RuleConditions{Languages: []string{"ja"}}, true},
+		{"second language", RuleConditions{Languages: []string{"en"}}, false},
+		{"time of day", RuleConditions{TimeOfDay: &TimeOfDay{From: 9, To: 18}}, true},
+		{"query param", RuleConditions{QueryParams: []QueryCondition{{Key: "ztsrc", Value: "qr"}}}, true},
+		{"query param with any value", RuleConditions{QueryParams: []QueryCondition{{Key: "ztsrc"}}}, true},
+		{"query param with other value", RuleConditions{QueryParams: []QueryCondition{{Key: "ztsrc", Value: "mail"}}}, false},
+		{"missing query param", RuleConditions{QueryParams: []QueryCondition{{Key: "utm_source"}}}, false},
+		{"every condition", RuleConditions{OS: []string{"iOS"}, Countries: []string{"JP"}, Languages: []string{"ja-JP"}}, true},
+		{"one failing condition", RuleConditions{OS: []string{"iOS"}, Countries: []string{"US"}}, false},
+	}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			rule := RedirectRule{Name: tcase.name, Conditions: tcase.conditions, Url: "https://dealer.com"}
+			assert.Equal(t, tcase.want, rule.Matches(req))
+		})
+	}
+}
+
+func TestLink_MatchRule(t *testing.T) {
+	link := &Link{Rules: []RedirectRule{
+		{Name: "korea", Conditions: RuleConditions{Countries: []string{"KR"}}, Url: "https://dealer.com/kr"},
+		{Name: "mobile", Conditions: RuleConditions{DeviceTypes: []DeviceType{DeviceTypeMobile}}, Url: "https://dealer.com/m"},
+		{Name: "japan", Conditions: RuleConditions{Countries: []string{"JP"}}, Url: "https://dealer.com/jp"},
+	}}
+
+	t.Run("should return the first matching rule", func(t *testing.T) {
+		match := link.MatchRule(RuleRequest{DeviceType: DeviceTypeMobile, Country: "JP"})
+		assert.Equal(t, 1, match.Index)
+		assert.Equal(t, "mobile", match.Rule.Name)
+		assert.Equal(t, "https://dealer.com/m", match.Destination)
+	})
+
+	t.Run("should return nothing without match", func(t *testing.T) {
+		assert.Nil(t, link.MatchRule(RuleRequest{DeviceType: DeviceTypeDesktop, Country: "US"}))
+	})
+}
@@ -0,0 +1,258 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+type ReportMetric string
+
+const (
+	ReportMetricClicks         ReportMetric = "clicks"      // human clicks of the links
+	ReportMetricTracks         ReportMetric = "tracks"      // human tracks
+	ReportMetricLandings       ReportMetric = "landings"    // landing page events
+	ReportMetricConversions    ReportMetric = "conversions" // thank you page events
+	ReportMetricConversionRate ReportMetric = "conversion_rate"
+	ReportMetricPoints         ReportMetric = "points"
+	ReportMetricRevenue        ReportMetric = "revenue"
+)
+
+// ReportMetrics are the metrics of a report that doesn't ask for some
+var ReportMetrics = []ReportMetric{
+	ReportMetricClicks,
+	ReportMetricTracks,
+	ReportMetricLandings,
+	ReportMetricConversions,
+	ReportMetricConversionRate,
+	ReportMetricPoints,
+	ReportMetricRevenue,
+}
+
+func (m ReportMetric) IsValid() bool {
+	for _, metric := range ReportMetrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+type ReportDimension string
+
+const (
+	ReportDimensionLink         ReportDimension = "link"
+	ReportDimensionThankYouPage ReportDimension = "thank_you_page" // only splits conversions, points and revenue
+	ReportDimensionChannel      ReportDimension = "channel"        // clicks have no channel
+	ReportDimensionDevice       ReportDimension = "device"         // device type
+	ReportDimensionCampaign     ReportDimension = "campaign"       // link campaign
+)
+
+func (d ReportDimension) IsValid() bool {
+	switch d {
+	case ReportDimensionLink, ReportDimensionThankYouPage, ReportDimensionChannel, ReportDimensionDevice,
+		ReportDimensionCampaign:
+		return true
+	default:
+		return false
+	}
+}
+
+type ReportGranularity string
+
+const (
+	ReportGranularityHour  ReportGranularity = "hour"
+	ReportGranularityDay   ReportGranularity = "day"
+	ReportGranularityWeek  ReportGranularity = "week" // from monday
+	ReportGranularityMonth ReportGranularity = "month"
+	ReportGranularityTotal ReportGranularity = "total" // one bucket for the whole range
+)
+
+func (g ReportGranularity) IsValid() bool {
+	switch g {
+	case ReportGranularityHour, ReportGranularityDay, ReportGranularityWeek, ReportGranularityMonth,
+		ReportGranularityTotal:
+		return true
+	default:
+		return false
+	}
+}
+
+// maxHourlyReportRange keeps hourly reports to about a month of buckets
+const maxHourlyReportRange = 31 * 24 * time.Hour
+
+// defaultReportRange is the range of a report without from
+const defaultReportRange = 30 * 24 * time.Hour
+
+// ReportQuery asks for metrics by time bucket and dimensions. From (inclusive) and To (exclusive)
+// are dates or RFC 3339 times, dates are days of the tenant time zone.
+type ReportQuery struct {
+	Metrics     []ReportMetric
+	Dimensions  []ReportDimension
+	Granularity ReportGranularity
+	From        string
+	To          string
+}
+
+func (q *ReportQuery) Validate() error {
+	for _, metric := range q.Metrics {
+		if !metric.IsValid() {
+			return fmt.Errorf("metric %q is not valid", metric)
+		}
+	}
+
+	seen := map[ReportDimension]bool{}
+	for _, dimension := range q.Dimensions {
+		if !dimension.IsValid() {
+			return fmt.Errorf("dimension %q is not valid", dimension)
+		}
+		if seen[dimension] {
+			return fmt.Errorf("dimension %q is duplicated", dimension)
+		}
+		seen[dimension] = true
+	}
+
+	if q.Granularity != "" && !q.Granularity.IsValid() {
+		return fmt.Errorf("granularity must be hour, day, week, month or total")
+	}
+
+	if _, err := parseReportTime(q.From, time.UTC); err != nil {
+		return fmt.Errorf("from is not valid")
+	}
+
+	if _, err := parseReportTime(q.To, time.UTC); err != nil {
+		return fmt.Errorf("to is not valid")
+	}
+
+	return nil
+}
+
+// Normalize sets the defaults, every metric by day
+func (q *ReportQuery) Normalize() {
+	if len(q.Metrics) == 0 {
+		q.Metrics = ReportMetrics
+	}
+
+	if q.Granularity == "" {
+		q.Granularity = ReportGranularityDay
+	}
+}
+
+// Range is [from, to) in loc, to is now and from the midnight 30 days before to without them
+func (q *ReportQuery) Range(loc *time.Location, now time.Time) (time.Time, time.Time, error) {
+	from, _ := parseReportTime(q.From, loc)
+	to, _ := parseReportTime(q.To, loc)
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultReportRange).In(loc)
+		from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	}
+
+	if !to.After(from) {
+		return from, to, fmt.Errorf("to must be after from")
+	}
+
+	if q.Granularity == ReportGranularityHour && to.Sub(from) > maxHourlyReportRange {
+		return from, to, fmt.Errorf("hourly reports cover at most 31 days")
+	}
+
+	return from, to, nil
+}
+
+// HasDimension tells if the rows are split by the dimension
+func (q *ReportQuery) HasDimension(dimension ReportDimension) bool {
+	for _, d := range q.Dimensions {
+		if d == dimension {
+			return true
+		}
+	}
+	return false
+}
+
+// parseReportTime accepts a date in loc or a RFC 3339 time, empty is the zero time
+func parseReportTime(s string, loc *time.Location) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, loc); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// ReportKey is a time bucket and the dimension values the counts are grouped by, the values of
+// the dimensions that are not asked for are empty
+type ReportKey struct {
+	Time         time.Time `bson:"time"`
+	Link         string    `bson:"link"`
+	ThankYouPage string    `bson:"thank_you_page"`
+	Channel      string    `bson:"channel"`
+	Device       string    `bson:"device"`
+}
+
+// ReportCount is what one collection counts for a key
+type ReportCount struct {
+	Key         ReportKey `bson:"_id"`
+	Clicks      int64     `bson:"clicks"`
+	Tracks      int64     `bson:"tracks"`
+	Landings    int64     `bson:"landings"`
+	Conversions int64     `bson:"conversions"`
+	Points      int64     `bson:"points"`
+	Revenue     float64   `bson:"revenue"`
+}
+
+// ReportRow holds every metric, the report only shows the asked ones
+type ReportRow struct {
+	Time           *time.Time                 `json:"time,omitempty"` // start of the bucket in the tenant time zone
+	Dimensions     map[ReportDimension]string `json:"dimensions"`
+	Clicks         int64                      `json:"-"`
+	Tracks         int64                      `json:"-"`
+	Landings       int64                      `json:"-"`
+	Conversions    int64                      `json:"-"`
+	ConversionRate float64                    `json:"-"` // conversions per track
+	Points         int64                      `json:"-"`
+	Revenue        float64                    `json:"-"`
+}
+
+func (r *ReportRow) Add(count *ReportCount) {
+	r.Clicks += count.Clicks
+	r.Tracks += count.Tracks
+	r.Landings += count.Landings
+	r.Conversions += count.Conversions
+	r.Points += count.Points
+	r.Revenue += count.Revenue
+}
+
+func (r *ReportRow) Value(metric ReportMetric) float64 {
+	switch metric {
+	case ReportMetricClicks:
+		return float64(r.Clicks)
+	case ReportMetricTracks:
+		return float64(r.Tracks)
+	case ReportMetricLandings:
+		return float64(r.Landings)
+	case ReportMetricConversions:
+		return float64(r.Conversions)
+	case ReportMetricConversionRate:
+		return r.ConversionRate
+	case ReportMetricPoints:
+		return float64(r.Points)
+	case ReportMetricRevenue:
+		return r.Revenue
+	default:
+		return 0
+	}
+}
+
+type Report struct {
+	TimeZone    string            `json:"time_zone"`
+	Granularity ReportGranularity `json:"granularity"`
+	From        time.Time         `json:"from"`
+	To          time.Time         `json:"to"`
+	Metrics     []ReportMetric    `json:"metrics"`
+	Dimensions  []ReportDimension `json:"dimensions"`
+	Rows        []*ReportRow      `json:"rows"`
+	Labels      map[string]string `json:"labels"` // names of the links, thank you pages and campaigns by id
+	Totals      *ReportRow        `json:"totals"`
+}
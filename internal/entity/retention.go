@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// RetentionPolicy is the per-tenant retention of raw data. A policy change only applies to
+// documents written after the change.
+type RetentionPolicy struct {
+	EventDays int  `bson:"event_days" json:"event_days"` // 0 keeps events forever
+	TrackDays int  `bson:"track_days" json:"track_days"` // 0 keeps tracks forever
+	ClickDays int  `bson:"click_days" json:"click_days"` // 0 keeps clicks forever
+	Archive   bool `bson:"archive" json:"archive"`       // archive documents before they are deleted
+}
+
+// Expirable is embedded in documents that are removed by the expire_at TTL index.
+// When the tenant archives its data, expire_at is only set by the archival job
+// once the document has been written to the archive store.
+type Expirable struct {
+	RetainUntil *time.Time `bson:"retain_until,omitempty" json:"retain_until,omitempty"`
+	ExpireAt    *time.Time `bson:"expire_at,omitempty" json:"expire_at,omitempty"`
+	ArchivedAt  *time.Time `bson:"archived_at,omitempty" json:"archived_at,omitempty"`
+}
+
+func (e *Expirable) ApplyRetention(from time.Time, days int, archive bool) {
+	if days <= 0 {
+		return
+	}
+
+	until := from.AddDate(0, 0, days)
+	e.RetainUntil = &until
+	if !archive {
+		e.ExpireAt = &until
+	}
+}
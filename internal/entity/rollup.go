@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// ReportRollup is a bucket of pre-aggregated report counts. Hourly rollups start at the UTC
+// hours, daily rollups at the midnights of TimeZone, the time zone of the tenant.
+type ReportRollup struct {
+	TenantID     string    `bson:"tenant_id" json:"tenant_id"`
+	TimeZone     string    `bson:"time_zone,omitempty" json:"time_zone,omitempty"` // only daily rollups
+	Time         time.Time `bson:"time" json:"time"`
+	Link         string    `bson:"link" json:"link"`
+	ThankYouPage string    `bson:"thank_you_page" json:"thank_you_page"`
+	Channel      string    `bson:"channel" json:"channel"`
+	Device       string    `bson:"device" json:"device"`
+	Clicks       int64     `bson:"clicks" json:"clicks"`
+	Tracks       int64     `bson:"tracks" json:"tracks"`
+	Landings     int64     `bson:"landings" json:"landings"`
+	Conversions  int64     `bson:"conversions" json:"conversions"`
+	Points       int64     `bson:"points" json:"points"`
+	Revenue      float64   `bson:"revenue" json:"revenue"`
+}
+
+// RollupWatermark is where the next rollup run starts, the hours before it are rolled up
+type RollupWatermark struct {
+	ID        string    `bson:"_id"`
+	Until     time.Time `bson:"until"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// RollupRun is the range a rollup run counted again
+type RollupRun struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Watermark time.Time `json:"watermark"`
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var slugPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{2,63}$`)
+
+// ReservedSlugs can't be slugs, they are paths of the tracker or easily mistaken for them
+var ReservedSlugs = []string{
+	"r", "v1", "api", "static", "admin", "login", "logout", "signup", "account", "settings",
+	"dashboard", "www", "app", "help", "support", "health", "metrics", "qr", "robots.txt", "favicon.ico",
+}
+
+// ValidateSlug checks a custom slug, blockedWords are matched anywhere in it, ignoring case and separators
+func ValidateSlug(slug string, blockedWords []string) error {
+	if !slugPattern.MatchString(slug) {
+		return fmt.Errorf("slug must be 3 to 64 letters, digits, - or _")
+	}
+
+	lower := strings.ToLower(slug)
+	if slices.Contains(ReservedSlugs, lower) {
+		return fmt.Errorf("slug %q is reserved", slug)
+	}
+
+	compact := strings.NewReplacer("-", "", "_", "").Replace(lower)
+	for _, word := range blockedWords {
+		if word != "" && strings.Contains(compact, word) {
+			return fmt.Errorf("slug %q is not allowed", slug)
+		}
+	}
+
+	return nil
+}
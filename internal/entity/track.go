@@ -15,6 +15,17 @@ type Track struct {
 	Platform          string            `bson:"platform" json:"platform"`
 	GeneratedFrom     string            `bson:"generated_from" json:"generated_from"` // source
 	Metadata          map[string]string `bson:"metadata" json:"metadata"`
+	UTM               UTM               `bson:"utm" json:"utm"`
+	ClickID           ClickID           `bson:"click_id" json:"click_id"`
+	Channel           Channel           `bson:"channel" json:"channel"`
+	UserAgent         string            `bson:"user_agent,omitempty" json:"user_agent,omitempty"` // user agent of the click, when known
+	Device            Device            `bson:"device" json:"device"`
+	IP                string            `bson:"ip,omitempty" json:"ip,omitempty"` // anonymized after the geo lookup
+	Geo               Geo               `bson:"geo" json:"geo"`
+	Bot               Bot               `bson:"bot" json:"bot"`
+	LinkID            bson.ObjectID     `bson:"link_id,omitempty" json:"link_id,omitempty"` // link of an auto-created track
+	Variant           string            `bson:"variant,omitempty" json:"variant,omitempty"` // A/B variant of the link
+	Expirable         `bson:",inline"`
 	BaseEntity        `bson:",inline"`
 }
 
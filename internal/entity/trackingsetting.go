@@ -7,9 +7,21 @@ import "go.mongodb.org/mongo-driver/v2/bson"
 // db.thankyoupages.createIndex({"tenant_id": 1, "status": 1})
 
 type TrackingSetting struct {
-	ID         bson.ObjectID `bson:"_id,omitempty"`
-	TenantID   string        `bson:"tenant_id"`
-	BaseEntity `bson:",inline"`
+	ID                    bson.ObjectID `bson:"_id,omitempty"`
+	TenantID              string        `bson:"tenant_id"`
+	TrackingSettingConfig `bson:",inline"`
+	BaseEntity            `bson:",inline"`
+}
+
+// TrackingSettingConfig holds the tenant configurable fields, shared by TrackingSetting and TrackingSettingWithPages
+type TrackingSettingConfig struct {
+	Retention    RetentionPolicy `bson:"retention" json:"retention"`
+	Consent      ConsentPolicy   `bson:"consent" json:"consent"`
+	Domains      []string        `bson:"domains" json:"domains"`             // owned domains, see IsOwnedHost
+	ChannelRules []ChannelRule   `bson:"channel_rules" json:"channel_rules"` // applied before the default rules
+	Bots         BotPolicy       `bson:"bots" json:"bots"`
+	LinkDomains  []string        `bson:"link_domains" json:"link_domains"` // branded short link domains
+	TimeZone     string          `bson:"time_zone" json:"time_zone"`       // IANA name of the reports, UTC when empty
 }
 
 type ThankYouPage struct {
@@ -17,11 +29,22 @@ type ThankYouPage struct {
 	TrackingSettingID bson.ObjectID  `bson:"tracking_setting_id" json:"tracking_setting_id"`
 	URL               string         `bson:"url" json:"url"`
 	Point             int            `bson:"point" json:"point"`
+	Revenue           float64        `bson:"revenue" json:"revenue"`                             // of a conversion, unless the page sends its own
+	MaxRevenue        float64        `bson:"max_revenue,omitempty" json:"max_revenue,omitempty"` // cap of a revenue sent by the page, 0 ignores it
 	Name              string         `bson:"name" json:"name"`
 	Status            TrackingStatus `bson:"tracking_status" json:"tracking_status"`
 	BaseEntity        `bson:",inline"`
 }
 
+// ConversionRevenue is the revenue sent by the page when the page accepts one, capped at MaxRevenue,
+// or else the revenue of the page
+func (p *ThankYouPage) ConversionRevenue(sent float64) float64 {
+	if p.MaxRevenue <= 0 || sent <= 0 {
+		return p.Revenue
+	}
+	return min(sent, p.MaxRevenue)
+}
+
 type TrackingStatus int
 
 const (
@@ -42,8 +65,9 @@ func (ts TrackingStatus) String() string {
 }
 
 type TrackingSettingWithPages struct {
-	ID            bson.ObjectID  `bson:"_id,omitempty" json:"id"`
-	TenantID      string         `bson:"tenant_id"`
-	ThankYouPages []ThankYouPage `bson:"thank_you_pages"`
-	BaseEntity    `bson:",inline"`
+	ID                    bson.ObjectID  `bson:"_id,omitempty" json:"id"`
+	TenantID              string         `bson:"tenant_id"`
+	ThankYouPages         []ThankYouPage `bson:"thank_you_pages"`
+	TrackingSettingConfig `bson:",inline"`
+	BaseEntity            `bson:",inline"`
 }
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// VisitorIdentityOriginal is the ztid of visitors that didn't come from a tracked link
+const VisitorIdentityOriginal = "original"
+
+// VisitorIdentity is the content of the _zt_id cookie, shared by the script and the identity endpoint
+type VisitorIdentity struct {
+	ZTID      string `json:"ztid"`
+	TS        int64  `json:"ts"`      // ztts of the tracked link, unix milliseconds
+	Created   int64  `json:"created"` // unix milliseconds
+	Refreshed int64  `json:"refreshed,omitempty"`
+}
+
+func (v *VisitorIdentity) IsValid() bool {
+	if v.ZTID == VisitorIdentityOriginal {
+		return true
+	}
+	_, err := bson.ObjectIDFromHex(v.ZTID)
+	return err == nil
+}
+
+// Encode returns the cookie value, base64 without padding like conversion.js
+func (v *VisitorIdentity) Encode() string {
+	b, _ := json.Marshal(v)
+	return base64.RawStdEncoding.EncodeToString(b)
+}
+
+func DecodeVisitorIdentity(value string) (*VisitorIdentity, bool) {
+	b, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, false
+	}
+
+	identity := &VisitorIdentity{}
+	if err := json.Unmarshal(b, identity); err != nil {
+		return nil, false
+	}
+
+	return identity, identity.IsValid()
+}
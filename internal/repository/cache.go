@@ -0,0 +1,445 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/pkg/lru"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// CacheRepo reports the caches of the hot lookups
+type CacheRepo interface {
+	CacheStats() []entity.CacheStats
+}
+
+// repoCache holds the cached lookups of the redirects and the events. A nil value caches a
+// lookup without document, e.g. an unknown short id. Values are copied on the way out so the
+// callers can't change the cached ones.
+type repoCache struct {
+	links              *lru.Cache[string, *entity.Link] // by domain and short id
+	linkDomains        *lru.Cache[string, string]       // tenant id by link domain, empty when not registered
+	settingsByTenantID *lru.Cache[string, *entity.TrackingSettingWithPages]
+	settingsWithPages  *lru.Cache[bson.ObjectID, *entity.TrackingSettingWithPages]
+	settings           *lru.Cache[bson.ObjectID, *entity.TrackingSetting]
+	tracks             *lru.Cache[bson.ObjectID, *entity.Track]
+	tracksWithSettings *lru.Cache[bson.ObjectID, *entity.TrackWithThankYouPages]
+}
+
+func newRepoCache(size int, ttl time.Duration) *repoCache {
+	return &repoCache{
+		links:              lru.New[string, *entity.Link](size, ttl),
+		linkDomains:        lru.New[string, string](size, ttl),
+		settingsByTenantID: lru.New[string, *entity.TrackingSettingWithPages](size, ttl),
+		settingsWithPages:  lru.New[bson.ObjectID, *entity.TrackingSettingWithPages](size, ttl),
+		settings:           lru.New[bson.ObjectID, *entity.TrackingSetting](size, ttl),
+		tracks:             lru.New[bson.ObjectID, *entity.Track](size, ttl),
+		tracksWithSettings: lru.New[bson.ObjectID, *entity.TrackWithThankYouPages](size, ttl),
+	}
+}
+
+func linkCacheKey(domain, shortID string) string {
+	return domain + "/" + shortID
+}
+
+func (c *repoCache) stats() []entity.CacheStats {
+	result := []entity.CacheStats{}
+	add := func(name string, s lru.Stats) {
+		result = append(result, entity.CacheStats{Name: name, Hits: s.Hits, Misses: s.Misses, Evictions: s.Evictions, Size: s.Size})
+	}
+	add("link", c.links.Stats())
+	add("link_domain", c.linkDomains.Stats())
+	add("tracking_setting_by_tenant", c.settingsByTenantID.Stats())
+	add("tracking_setting_with_pages", c.settingsWithPages.Stats())
+	add("tracking_setting", c.settings.Stats())
+	add("track", c.tracks.Stats())
+	add("track_with_thank_you_pages", c.tracksWithSettings.Stats())
+	return result
+}
+
+func (c *repoCache) invalidateLink(id bson.ObjectID) {
+	c.links.RemoveFunc(func(_ string, link *entity.Link) bool {
+		return link != nil && link.ID == id
+	})
+}
+
+func (c *repoCache) invalidateLinks(ids []bson.ObjectID) {
+	set := map[bson.ObjectID]bool{}
+	for _, id := range ids {
+		set[id] = true
+	}
+	c.links.RemoveFunc(func(_ string, link *entity.Link) bool {
+		return link != nil && set[link.ID]
+	})
+}
+
+// invalidateTrackingSettings drops every cached setting, they change rarely. The tracks carry
+// the setting and the pages of their tenant.
+func (c *repoCache) invalidateTrackingSettings() {
+	c.linkDomains.Purge()
+	c.settingsByTenantID.Purge()
+	c.settingsWithPages.Purge()
+	c.settings.Purge()
+	c.tracksWithSettings.Purge()
+}
+
+func (c *repoCache) purge() {
+	c.links.Purge()
+	c.tracks.Purge()
+	c.invalidateTrackingSettings()
+}
+
+// changeEvent is the part of a change stream event the invalidation needs
+type changeEvent struct {
+	OperationType string `bson:"operationType"`
+	NS            struct {
+		Coll string `bson:"coll"`
+	} `bson:"ns"`
+	DocumentKey struct {
+		ID bson.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument *entity.Link `bson:"fullDocument"`
+}
+
+// watch applies the changes of the other instances, it needs a replica set. The caches are
+// purged whenever the stream breaks since changes may have been missed.
+func (c *repoCache) watch(ctx context.Context, db *mongo.Database) {
+	backoff := time.Second
+	for {
+		err := c.watchOnce(ctx, db)
+		if ctx.Err() != nil {
+			return
+		}
+
+		c.purge()
+		slog.Warn("cache change stream stopped", slog.String("error", err.Error()), slog.Duration("retry_in", backoff))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, time.Minute)
+	}
+}
+
+func (c *repoCache) watchOnce(ctx context.Context, db *mongo.Database) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"ns.coll":       bson.M{"$in": bson.A{"link", "tracking_setting", "thank_you_page", "track"}},
+			"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}},
+		}}},
+	}
+
+	stream, err := db.Watch(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		var event changeEvent
+		if err := stream.Decode(&event); err != nil {
+			slog.Warn("failed to decode change event", slog.String("error", err.Error()))
+			continue
+		}
+		c.apply(event)
+	}
+
+	if err := stream.Err(); err != nil {
+		return err
+	}
+	return errors.New("change stream closed")
+}
+
+func (c *repoCache) apply(event changeEvent) {
+	switch event.NS.Coll {
+	case "link":
+		if event.OperationType == "insert" && event.FullDocument != nil {
+			c.links.Remove(linkCacheKey(event.FullDocument.Domain, event.FullDocument.ShortID))
+			return
+		}
+		c.invalidateLink(event.DocumentKey.ID)
+	case "tracking_setting", "thank_you_page":
+		c.invalidateTrackingSettings()
+	case "track":
+		c.invalidateTrack(event.DocumentKey.ID)
+	}
+}
+
+func (c *repoCache) invalidateTrack(id bson.ObjectID) {
+	c.tracks.Remove(id)
+	c.tracksWithSettings.Remove(id)
+}
+
+// copyOf returns a shallow copy, the callers replace fields and slices but don't change their elements
+func copyOf[T any](v *T) *T {
+	cp := *v
+	return &cp
+}
+
+type cachedLinkRepo struct {
+	LinkRepo
+	cache *repoCache
+}
+
+func (r *cachedLinkRepo) CreateLink(ctx context.Context, link *entity.Link) error {
+	if err := r.LinkRepo.CreateLink(ctx, link); err != nil {
+		return err
+	}
+	r.cache.links.Remove(linkCacheKey(link.Domain, link.ShortID))
+	return nil
+}
+
+func (r *cachedLinkRepo) FindLinkByShortID(ctx context.Context, id string) (*entity.Link, error) {
+	return r.FindLinkByDomainAndShortID(ctx, "", id)
+}
+
+func (r *cachedLinkRepo) FindLinkByDomainAndShortID(ctx context.Context, domain string, shortID string) (*entity.Link, error) {
+	key := linkCacheKey(domain, shortID)
+	if link, ok := r.cache.links.Get(key); ok {
+		if link == nil {
+			return nil, mongo.ErrNoDocuments
+		}
+		return copyOf(link), nil
+	}
+
+	link, err := r.LinkRepo.FindLinkByDomainAndShortID(ctx, domain, shortID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		r.cache.links.Add(key, nil)
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	r.cache.links.Add(key, copyOf(link))
+	return link, nil
+}
+
+func (r *cachedLinkRepo) UpdateLinkLifecycle(ctx context.Context, id bson.ObjectID, lifecycle entity.LinkLifecycle) error {
+	defer r.cache.invalidateLink(id)
+	return r.LinkRepo.UpdateLinkLifecycle(ctx, id, lifecycle)
+}
+
+func (r *cachedLinkRepo) UpdateLinkRules(ctx context.Context, id bson.ObjectID, rules []entity.RedirectRule) error {
+	defer r.cache.invalidateLink(id)
+	return r.LinkRepo.UpdateLinkRules(ctx, id, rules)
+}
+
+func (r *cachedLinkRepo) UpdateLinkRedirect(ctx context.Context, id bson.ObjectID, redirect entity.LinkRedirect) error {
+	defer r.cache.invalidateLink(id)
+	return r.LinkRepo.UpdateLinkRedirect(ctx, id, redirect)
+}
+
+func (r *cachedLinkRepo) UpdateLinkTags(ctx context.Context, id bson.ObjectID, tags []string) error {
+	defer r.cache.invalidateLink(id)
+	return r.LinkRepo.UpdateLinkTags(ctx, id, tags)
+}
+
+func (r *cachedLinkRepo) AssignLinksToCampaign(ctx context.Context, tenantID string, ids []bson.ObjectID,
+	campaignID bson.ObjectID) (int64, error) {
+	defer r.cache.invalidateLinks(ids)
+	return r.LinkRepo.AssignLinksToCampaign(ctx, tenantID, ids, campaignID)
+}
+
+func (r *cachedLinkRepo) ClearLinksCampaign(ctx context.Context, campaignID bson.ObjectID) (int64, error) {
+	defer r.cache.links.RemoveFunc(func(_ string, link *entity.Link) bool {
+		return link != nil && link.CampaignID == campaignID
+	})
+	return r.LinkRepo.ClearLinksCampaign(ctx, campaignID)
+}
+
+func (r *cachedLinkRepo) UpdateLinkDetails(ctx context.Context, link *entity.Link) error {
+	defer r.cache.invalidateLink(link.ID)
+	return r.LinkRepo.UpdateLinkDetails(ctx, link)
+}
+
+func (r *cachedLinkRepo) RemoveLinks(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	defer r.cache.invalidateLinks(ids)
+	return r.LinkRepo.RemoveLinks(ctx, ids)
+}
+
+type cachedTrackingSettingRepo struct {
+	TrackingSettingRepo
+	cache *repoCache
+}
+
+func (r *cachedTrackingSettingRepo) FindOrCreateWithPagesByTenantID(ctx context.Context,
+	tenantID string) (*entity.TrackingSettingWithPages, error) {
+	if setting, ok := r.cache.settingsByTenantID.Get(tenantID); ok {
+		return copyOf(setting), nil
+	}
+
+	setting, err := r.TrackingSettingRepo.FindOrCreateWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.settingsByTenantID.Add(tenantID, copyOf(setting))
+	return setting, nil
+}
+
+func (r *cachedTrackingSettingRepo) FindTrackingSettingWithPagesByTenantID(ctx context.Context,
+	tenantID string) (*entity.TrackingSettingWithPages, error) {
+	if setting, ok := r.cache.settingsByTenantID.Get(tenantID); ok {
+		return copyOf(setting), nil
+	}
+
+	setting, err := r.TrackingSettingRepo.FindTrackingSettingWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.settingsByTenantID.Add(tenantID, copyOf(setting))
+	return setting, nil
+}
+
+func (r *cachedTrackingSettingRepo) FindTrackingSettingByID(ctx context.Context, id bson.ObjectID) (*entity.TrackingSetting, error) {
+	if setting, ok := r.cache.settings.Get(id); ok {
+		if setting == nil {
+			return nil, mongo.ErrNoDocuments
+		}
+		return copyOf(setting), nil
+	}
+
+	setting, err := r.TrackingSettingRepo.FindTrackingSettingByID(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		r.cache.settings.Add(id, nil)
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	r.cache.settings.Add(id, copyOf(setting))
+	return setting, nil
+}
+
+func (r *cachedTrackingSettingRepo) FindTrackingSettingWithPagesByID(ctx context.Context,
+	trackingSettingID bson.ObjectID) (*entity.TrackingSettingWithPages, error) {
+	if setting, ok := r.cache.settingsWithPages.Get(trackingSettingID); ok {
+		if setting == nil {
+			return nil, mongo.ErrNoDocuments
+		}
+		return copyOf(setting), nil
+	}
+
+	setting, err := r.TrackingSettingRepo.FindTrackingSettingWithPagesByID(ctx, trackingSettingID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		r.cache.settingsWithPages.Add(trackingSettingID, nil)
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	r.cache.settingsWithPages.Add(trackingSettingID, copyOf(setting))
+	return setting, nil
+}
+
+func (r *cachedTrackingSettingRepo) FindTenantIDByLinkDomain(ctx context.Context, domain string) (string, error) {
+	if tenantID, ok := r.cache.linkDomains.Get(domain); ok {
+		if tenantID == "" {
+			return "", mongo.ErrNoDocuments
+		}
+		return tenantID, nil
+	}
+
+	tenantID, err := r.TrackingSettingRepo.FindTenantIDByLinkDomain(ctx, domain)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		r.cache.linkDomains.Add(domain, "")
+		return "", err
+	} else if err != nil {
+		return "", err
+	}
+
+	r.cache.linkDomains.Add(domain, tenantID)
+	return tenantID, nil
+}
+
+func (r *cachedTrackingSettingRepo) UpdateTrackingSettingConfig(ctx context.Context, trackingSettingID bson.ObjectID,
+	config entity.TrackingSettingConfig) error {
+	defer r.cache.invalidateTrackingSettings()
+	return r.TrackingSettingRepo.UpdateTrackingSettingConfig(ctx, trackingSettingID, config)
+}
+
+type cachedThankYouPageRepo struct {
+	ThankYouPageRepo
+	cache *repoCache
+}
+
+func (r *cachedThankYouPageRepo) CreatePage(ctx context.Context, page *entity.ThankYouPage) error {
+	defer r.cache.invalidateTrackingSettings()
+	return r.ThankYouPageRepo.CreatePage(ctx, page)
+}
+
+func (r *cachedThankYouPageRepo) UpdatePageFieldsAndReturn(ctx context.Context, id bson.ObjectID,
+	page *entity.ThankYouPage) (*entity.ThankYouPage, error) {
+	defer r.cache.invalidateTrackingSettings()
+	return r.ThankYouPageRepo.UpdatePageFieldsAndReturn(ctx, id, page)
+}
+
+type cachedTrackRepo struct {
+	TrackRepo
+	cache *repoCache
+}
+
+func (r *cachedTrackRepo) FindTrackByID(ctx context.Context, id bson.ObjectID) (*entity.Track, error) {
+	if track, ok := r.cache.tracks.Get(id); ok {
+		return copyOf(track), nil
+	}
+
+	track, err := r.TrackRepo.FindTrackByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.tracks.Add(id, copyOf(track))
+	return track, nil
+}
+
+func (r *cachedTrackRepo) FindTrackByIDWithThankYouPages(ctx context.Context, id bson.ObjectID) (*entity.TrackWithThankYouPages, error) {
+	if track, ok := r.cache.tracksWithSettings.Get(id); ok {
+		return copyOf(track), nil
+	}
+
+	track, err := r.TrackRepo.FindTrackByIDWithThankYouPages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.tracksWithSettings.Add(id, copyOf(track))
+	return track, nil
+}
+
+func (r *cachedTrackRepo) DeleteTracksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	defer r.invalidate(ids...)
+	return r.TrackRepo.DeleteTracksByIDs(ctx, ids)
+}
+
+func (r *cachedTrackRepo) AnonymizeTracksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	defer r.invalidate(ids...)
+	return r.TrackRepo.AnonymizeTracksByIDs(ctx, ids)
+}
+
+func (r *cachedTrackRepo) UpdateTrackDevice(ctx context.Context, id bson.ObjectID, device entity.Device) error {
+	defer r.invalidate(id)
+	return r.TrackRepo.UpdateTrackDevice(ctx, id, device)
+}
+
+func (r *cachedTrackRepo) UpdateTrackGeo(ctx context.Context, id bson.ObjectID, geo entity.Geo) error {
+	defer r.invalidate(id)
+	return r.TrackRepo.UpdateTrackGeo(ctx, id, geo)
+}
+
+func (r *cachedTrackRepo) UpdateTrackBot(ctx context.Context, id bson.ObjectID, bot entity.Bot) error {
+	defer r.invalidate(id)
+	return r.TrackRepo.UpdateTrackBot(ctx, id, bot)
+}
+
+func (r *cachedTrackRepo) invalidate(ids ...bson.ObjectID) {
+	for _, id := range ids {
+		r.cache.invalidateTrack(id)
+	}
+}
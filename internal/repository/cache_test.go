@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCachedLinkRepo(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should serve the link from the cache until it's updated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := NewMockRepo(ctrl)
+		cache := newRepoCache(10, time.Minute)
+		repo := &cachedLinkRepo{LinkRepo: inner, cache: cache}
+
+		link := &entity.Link{ID: bson.NewObjectID(), ShortID: "abc", Url: "https://example.com"}
+		inner.EXPECT().FindLinkByDomainAndShortID(ctx, "", "abc").Return(link, nil).Times(2)
+		inner.EXPECT().UpdateLinkRules(ctx, link.ID, gomock.Any()).Return(nil)
+
+		got, err := repo.FindLinkByShortID(ctx, "abc")
+		assert.NoError(t, err)
+		got.Url = "https://changed.example.com"
+
+		got, err = repo.FindLinkByShortID(ctx, "abc")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com", got.Url)
+
+		assert.NoError(t, repo.UpdateLinkRules(ctx, link.ID, nil))
+		_, err = repo.FindLinkByShortID(ctx, "abc")
+		assert.NoError(t, err)
+
+		stats := cache.stats()[0]
+		assert.Equal(t, "link", stats.Name)
+		assert.Equal(t, uint64(1), stats.Hits)
+		assert.Equal(t, uint64(2), stats.Misses)
+	})
+
+	t.Run("should cache unknown short ids until the link is created", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := NewMockRepo(ctrl)
+		repo := &cachedLinkRepo{LinkRepo: inner, cache: newRepoCache(10, time.Minute)}
+
+		link := &entity.Link{ID: bson.NewObjectID(), ShortID: "abc", Domain: "go.example.com"}
+		gomock.InOrder(
+			inner.EXPECT().FindLinkByDomainAndShortID(ctx, "go.example.com", "abc").Return(nil, mongo.ErrNoDocuments),
+			inner.EXPECT().CreateLink(ctx, link).Return(nil),
+			inner.EXPECT().FindLinkByDomainAndShortID(ctx, "go.example.com", "abc").Return(link, nil),
+		)
+
+		for range 2 {
+			_, err := repo.FindLinkByDomainAndShortID(ctx, "go.example.com", "abc")
+			assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+		}
+
+		assert.NoError(t, repo.CreateLink(ctx, link))
+		got, err := repo.FindLinkByDomainAndShortID(ctx, "go.example.com", "abc")
+		assert.NoError(t, err)
+		assert.Equal(t, link.ID, got.ID)
+	})
+}
+
+func TestCachedTrackRepo(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should serve the track from the cache until it's updated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := NewMockRepo(ctrl)
+		repo := &cachedTrackRepo{TrackRepo: inner, cache: newRepoCache(10, time.Minute)}
+
+		track := &entity.Track{ID: bson.NewObjectID(), Url: "https://example.com"}
+		inner.EXPECT().FindTrackByID(ctx, track.ID).Return(track, nil).Times(2)
+		inner.EXPECT().UpdateTrackBot(ctx, track.ID, gomock.Any()).Return(nil)
+
+		got, err := repo.FindTrackByID(ctx, track.ID)
+		assert.NoError(t, err)
+		got.Url = "https://changed.example.com"
+
+		got, err = repo.FindTrackByID(ctx, track.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com", got.Url)
+
+		assert.NoError(t, repo.UpdateTrackBot(ctx, track.ID, entity.Bot{}))
+		_, err = repo.FindTrackByID(ctx, track.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should not cache unknown tracks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := NewMockRepo(ctrl)
+		repo := &cachedTrackRepo{TrackRepo: inner, cache: newRepoCache(10, time.Minute)}
+
+		id := bson.NewObjectID()
+		inner.EXPECT().FindTrackByID(ctx, id).Return(nil, mongo.ErrNoDocuments).Times(2)
+
+		for range 2 {
+			_, err := repo.FindTrackByID(ctx, id)
+			assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type ClickRepo interface {
+	CreateClick(ctx context.Context, click *entity.Click) error
+	CountClicksByDimension(ctx context.Context, linkID bson.ObjectID, dimension entity.ClickDimension,
+		from, to time.Time) ([]*entity.ClickBreakdownRow, error)
+	CountBotClicks(ctx context.Context, linkID bson.ObjectID, from, to time.Time) (int64, error)
+	CountClicksByLinks(ctx context.Context, linkIDs []bson.ObjectID, from, to time.Time) ([]*entity.ClickBreakdownRow, error)
+	FindClicksByTrackIDs(ctx context.Context, tenantID string, trackIDs []string) ([]*entity.Click, error)
+	DeleteClicksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error)
+	AnonymizeClicksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error)
+}
+
+type clickRepo struct {
+	collection *mongo.Collection
+}
+
+func NewClickRepo(db *mongo.Database) ClickRepo {
+	return &clickRepo{
+		collection: db.Collection("click"),
+	}
+}
+
+func (r *clickRepo) CreateClick(ctx context.Context, click *entity.Click) error {
+	click.SetCreatedAt()
+	click.SetUpdatedAt()
+
+	res, err := r.collection.InsertOne(ctx, click)
+	if err != nil {
+		return fmt.Errorf("failed to create click: %w", err)
+	}
+	click.ID = res.InsertedID.(bson.ObjectID)
+	return nil
+}
+
+// clickFilter adds the clicks created in [from, to) to the filter, zero times are open bounds
+func clickFilter(filter bson.M, from, to time.Time) bson.M {
+	createdAt := bson.M{}
+	if !from.IsZero() {
+		createdAt["$gte"] = from
+	}
+	if !to.IsZero() {
+		createdAt["$lt"] = to
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+	return filter
+}
+
+// CountClicksByDimension groups the human clicks of the link by the dimension
+func (r *clickRepo) CountClicksByDimension(ctx context.Context, linkID bson.ObjectID,
+	dimension entity.ClickDimension, from, to time.Time) ([]*entity.ClickBreakdownRow, error) {
+	field, ok := dimension.Field()
+	if !ok {
+		return nil, fmt.Errorf("unknown dimension %q", dimension)
+	}
+
+	match := clickFilter(bson.M{"link_id": linkID}, from, to)
+	match["bot.is_bot"] = bson.M{"$ne": true}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id":    bson.M{"$ifNull": bson.A{"$" + field, ""}},
+			"clicks": bson.M{"$sum": 1},
+		}},
+		{"$sort": bson.D{{Key: "clicks", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate clicks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := []*entity.ClickBreakdownRow{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
+
+func (r *clickRepo) CountBotClicks(ctx context.Context, linkID bson.ObjectID, from, to time.Time) (int64, error) {
+	filter := clickFilter(bson.M{"link_id": linkID}, from, to)
+	filter["bot.is_bot"] = true
+
+	count, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count bot clicks: %w", err)
+	}
+	return count, nil
+}
+
+// CountClicksByLinks counts the human clicks of each link, keyed by the hex link id
+func (r *clickRepo) CountClicksByLinks(ctx context.Context, linkIDs []bson.ObjectID,
+	from, to time.Time) ([]*entity.ClickBreakdownRow, error) {
+	match := clickFilter(bson.M{"link_id": bson.M{"$in": linkIDs}}, from, to)
+	match["bot.is_bot"] = bson.M{"$ne": true}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id":    bson.M{"$toString": "$link_id"},
+			"clicks": bson.M{"$sum": 1},
+		}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate clicks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := []*entity.ClickBreakdownRow{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
+
+// FindClicksByTrackIDs returns the clicks of the tenant that created one of the tracks
+func (r *clickRepo) FindClicksByTrackIDs(ctx context.Context, tenantID string, trackIDs []string) ([]*entity.Click, error) {
+	if len(trackIDs) == 0 {
+		return []*entity.Click{}, nil
+	}
+
+	filter := bson.M{
+		"tenant_id": tenantID,
+		"track_id":  bson.M{"$in": trackIDs},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*entity.Click{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
+
+func (r *clickRepo) DeleteClicksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	res, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete clicks: %w", err)
+	}
+	return res.DeletedCount, nil
+}
+
+// AnonymizeClicksByIDs keeps the clicks in the link counts but removes what tells the visitor apart
+func (r *clickRepo) AnonymizeClicksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"user_agent": "",
+			"ip":         "",
+			"updated_at": time.Now().UTC(),
+		},
+		"$unset": bson.M{
+			"client_hints": "",
+			"track_id":     "",
+			"variant":      "",
+		},
+	}
+	res, err := r.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize clicks: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
@@ -0,0 +1,147 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type TestSuiteClickRepo struct {
+	mongoContainer testcontainers.Container
+	client         *mongo.Client
+	repo           repository.ClickRepo
+}
+
+func setupTestSuiteClickRepo() (*TestSuiteClickRepo, error) {
+	ctx := context.Background()
+
+	mongodbContainer, err := mongodb.Run(ctx, "mongo:8")
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := mongodbContainer.Endpoint(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(fmt.Sprintf("mongodb://%s", endpoint)))
+	if err != nil {
+		return nil, err
+	}
+
+	database := client.Database("test")
+	return &TestSuiteClickRepo{
+		mongoContainer: mongodbContainer,
+		client:         client,
+		repo:           repository.NewClickRepo(database),
+	}, nil
+}
+
+func (ts *TestSuiteClickRepo) Cleanup() {
+	ctx := context.Background()
+	if ts.client != nil {
+		ts.client.Disconnect(ctx)
+	}
+	if ts.mongoContainer != nil {
+		ts.mongoContainer.Terminate(ctx)
+	}
+}
+
+func TestClickRepo_CountClicksByDimension(t *testing.T) {
+	suite, err := setupTestSuiteClickRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	linkID := bson.NewObjectID()
+	clicks := []*entity.Click{
+		{LinkID: linkID, Device: entity.Device{Type: entity.DeviceTypeMobile}},
+		{LinkID: linkID, Device: entity.Device{Type: entity.DeviceTypeMobile}},
+		{LinkID: linkID, Device: entity.Device{Type: entity.DeviceTypeDesktop}},
+		{LinkID: linkID, Device: entity.Device{Type: entity.DeviceTypeBot}, Bot: entity.Bot{IsBot: true, Reasons: []entity.BotReason{entity.BotReasonUserAgent}}},
+		{LinkID: bson.NewObjectID(), Device: entity.Device{Type: entity.DeviceTypeMobile}},
+	}
+	for _, click := range clicks {
+		assert.NoError(t, suite.repo.CreateClick(ctx, click))
+		assert.False(t, click.ID.IsZero(), "ID should be generated")
+	}
+
+	t.Run("should group human clicks of the link", func(t *testing.T) {
+		rows, err := suite.repo.CountClicksByDimension(ctx, linkID, entity.ClickDimensionDeviceType, time.Time{}, time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.ClickBreakdownRow{
+			{Key: "mobile", Clicks: 2},
+			{Key: "desktop", Clicks: 1},
+		}, rows)
+	})
+
+	t.Run("should count bot clicks of the link", func(t *testing.T) {
+		count, err := suite.repo.CountBotClicks(ctx, linkID, time.Time{}, time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("should return error on unknown dimension", func(t *testing.T) {
+		_, err := suite.repo.CountClicksByDimension(ctx, linkID, entity.ClickDimension("unknown"), time.Time{}, time.Time{})
+		assert.Error(t, err)
+	})
+}
+
+func TestClickRepo_EraseClicksByTrackIDs(t *testing.T) {
+	suite, err := setupTestSuiteClickRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	trackID := bson.NewObjectID().Hex()
+	click := &entity.Click{
+		LinkID:    bson.NewObjectID(),
+		TenantID:  "tenant1",
+		UserAgent: "Mozilla/5.0",
+		IP:        "203.0.113.0",
+		Variant:   "b",
+		TrackID:   trackID,
+	}
+	assert.NoError(t, suite.repo.CreateClick(ctx, click))
+	other := &entity.Click{LinkID: bson.NewObjectID(), TenantID: "tenant2", TrackID: trackID}
+	assert.NoError(t, suite.repo.CreateClick(ctx, other))
+
+	t.Run("should find the clicks of the tenant by track id", func(t *testing.T) {
+		clicks, err := suite.repo.FindClicksByTrackIDs(ctx, "tenant1", []string{trackID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(clicks))
+		assert.Equal(t, click.ID, clicks[0].ID)
+	})
+
+	t.Run("should anonymize clicks", func(t *testing.T) {
+		count, err := suite.repo.AnonymizeClicksByIDs(ctx, []bson.ObjectID{click.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		clicks, err := suite.repo.FindClicksByTrackIDs(ctx, "tenant1", []string{trackID})
+		assert.NoError(t, err)
+		assert.Empty(t, clicks, "anonymized click should not be linked to the track")
+	})
+
+	t.Run("should delete clicks", func(t *testing.T) {
+		count, err := suite.repo.DeleteClicksByIDs(ctx, []bson.ObjectID{click.ID, other.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		clicks, err := suite.repo.FindClicksByTrackIDs(ctx, "tenant2", []string{trackID})
+		assert.NoError(t, err)
+		assert.Empty(t, clicks)
+	})
+}
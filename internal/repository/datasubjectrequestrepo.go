@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type DataSubjectRequestRepo interface {
+	CreateDataSubjectRequest(ctx context.Context, request *entity.DataSubjectRequest) error
+	UpdateDataSubjectRequest(ctx context.Context, request *entity.DataSubjectRequest) error
+	FindDataSubjectRequestsByTrackingSettingID(ctx context.Context,
+		trackingSettingID bson.ObjectID) ([]*entity.DataSubjectRequest, error)
+}
+
+type dataSubjectRequestRepo struct {
+	collection *mongo.Collection
+}
+
+func NewDataSubjectRequestRepo(db *mongo.Database) DataSubjectRequestRepo {
+	collection := db.Collection("data_subject_request")
+
+	return &dataSubjectRequestRepo{
+		collection: collection,
+	}
+}
+
+func (r *dataSubjectRequestRepo) CreateDataSubjectRequest(ctx context.Context, request *entity.DataSubjectRequest) error {
+	request.SetCreatedAt()
+	request.SetUpdatedAt()
+
+	res, err := r.collection.InsertOne(ctx, request)
+	if err != nil {
+		return err
+	}
+	request.ID = res.InsertedID.(bson.ObjectID)
+	return nil
+}
+
+func (r *dataSubjectRequestRepo) UpdateDataSubjectRequest(ctx context.Context, request *entity.DataSubjectRequest) error {
+	request.SetUpdatedAt()
+
+	update := bson.M{
+		"$set": bson.M{
+			"subject":      request.Subject,
+			"status":       request.Status,
+			"result":       request.Result,
+			"error":        request.Error,
+			"completed_at": request.CompletedAt,
+			"updated_at":   request.UpdatedAt,
+		},
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": request.ID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update data subject request: %w", err)
+	}
+	return nil
+}
+
+func (r *dataSubjectRequestRepo) FindDataSubjectRequestsByTrackingSettingID(ctx context.Context,
+	trackingSettingID bson.ObjectID) ([]*entity.DataSubjectRequest, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"tracking_setting_id": trackingSettingID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*entity.DataSubjectRequest{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
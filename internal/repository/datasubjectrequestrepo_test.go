@@ -0,0 +1,125 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type TestSuiteDataSubjectRequestRepo struct {
+	mongoContainer testcontainers.Container
+	client         *mongo.Client
+	repo           repository.DataSubjectRequestRepo
+}
+
+func setupTestSuiteDataSubjectRequestRepo() (*TestSuiteDataSubjectRequestRepo, error) {
+	ctx := context.Background()
+
+	mongodbContainer, err := mongodb.Run(ctx, "mongo:8")
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := mongodbContainer.Endpoint(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(fmt.Sprintf("mongodb://%s", endpoint)))
+	if err != nil {
+		return nil, err
+	}
+
+	database := client.Database("test")
+	repo := repository.NewDataSubjectRequestRepo(database)
+
+	return &TestSuiteDataSubjectRequestRepo{
+		mongoContainer: mongodbContainer,
+		client:         client,
+		repo:           repo,
+	}, nil
+}
+
+func (ts *TestSuiteDataSubjectRequestRepo) Cleanup() {
+	ctx := context.Background()
+	if ts.client != nil {
+		ts.client.Disconnect(ctx)
+	}
+	if ts.mongoContainer != nil {
+		ts.mongoContainer.Terminate(ctx)
+	}
+}
+
+func TestDataSubjectRequestRepo_CreateAndUpdate(t *testing.T) {
+	suite, err := setupTestSuiteDataSubjectRequestRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	trackingSettingID := bson.NewObjectID()
+	t.Run("should keep the audit record of a request", func(t *testing.T) {
+		request := &entity.DataSubjectRequest{
+			TenantID:          "tenant1",
+			TrackingSettingID: trackingSettingID,
+			Type:              entity.DataSubjectRequestTypeDelete,
+			Subject:           entity.Identifier{Type: entity.IdentifierTypeEndUserID, Value: "EndUserID12345"},
+			RequestedBy:       "dpo@example.com",
+			Channel:           "api",
+			Status:            entity.DataSubjectRequestStatusPending,
+		}
+		err := suite.repo.CreateDataSubjectRequest(ctx, request)
+		assert.NoError(t, err)
+		assert.False(t, request.ID.IsZero(), "ID should be generated")
+
+		request.HashSubject()
+		request.Complete(entity.DataSubjectRequestResult{Tracks: 1, Events: 2, Identities: 1})
+		err = suite.repo.UpdateDataSubjectRequest(ctx, request)
+		assert.NoError(t, err)
+
+		failed := &entity.DataSubjectRequest{
+			TenantID:          "tenant1",
+			TrackingSettingID: trackingSettingID,
+			Type:              entity.DataSubjectRequestTypeExport,
+			Subject:           entity.Identifier{Type: entity.IdentifierTypeZTID, Value: "685cbfb8085b1462689b2447"},
+			RequestedBy:       "dpo@example.com",
+			Channel:           "cli",
+		}
+		err = suite.repo.CreateDataSubjectRequest(ctx, failed)
+		assert.NoError(t, err)
+		failed.Fail(errors.New("connection refused"))
+		err = suite.repo.UpdateDataSubjectRequest(ctx, failed)
+		assert.NoError(t, err)
+
+		other := &entity.DataSubjectRequest{
+			TenantID:          "tenant2",
+			TrackingSettingID: bson.NewObjectID(),
+			Type:              entity.DataSubjectRequestTypeExport,
+			Subject:           entity.Identifier{Type: entity.IdentifierTypeEndUserID, Value: "EndUserID12345"},
+			RequestedBy:       "dpo@example.com",
+			Channel:           "api",
+		}
+		err = suite.repo.CreateDataSubjectRequest(ctx, other)
+		assert.NoError(t, err)
+
+		requests, err := suite.repo.FindDataSubjectRequestsByTrackingSettingID(ctx, trackingSettingID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(requests))
+		assert.Equal(t, failed.ID, requests[0].ID, "latest request should be first")
+		assert.Equal(t, entity.DataSubjectRequestStatusFailed, requests[0].Status)
+		assert.Equal(t, entity.DataSubjectRequestStatusCompleted, requests[1].Status)
+		assert.Equal(t, int64(2), requests[1].Result.Events)
+		assert.NotNil(t, requests[1].CompletedAt)
+		assert.NotContains(t, requests[1].Subject.Value, "EndUserID12345", "erased subject should be hashed")
+	})
+}
@@ -21,6 +21,11 @@ type EventRepo interface {
 	FindAllEventByTenantID(ctx context.Context, tenantID string) ([]*entity.Event, error)
 	FindLastEventByFingerprint(ctx context.Context, fingerprint string) (*entity.Event, error)
 	FindAllEventByTrackID(ctx context.Context, trackID bson.ObjectID) ([]*entity.Event, error)
+	FindLastEventByTrackIDs(ctx context.Context, trackIDs []string) (*entity.Event, error)
+	FindEventsBySubject(ctx context.Context, tenantID string, trackIDs []string,
+		fingerprints []string) ([]*entity.Event, error)
+	DeleteEventsByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error)
+	AnonymizeEventsByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error)
 }
 
 type eventRepo struct {
@@ -102,7 +107,8 @@ func (r *eventRepo) FindLastEventByFingerprint(ctx context.Context, fingerprint
 }
 
 func (r *eventRepo) FindAllEventByTrackID(ctx context.Context, trackID bson.ObjectID) ([]*entity.Event, error) {
-	filter := bson.M{"track_id": trackID}
+	// track_id is stored as hex string (see entity.Event)
+	filter := bson.M{"track_id": trackID.Hex()}
 	opts := options.Find().
 		SetSort(bson.D{{Key: "published_at", Value: -1}})
 	cursor, err := r.collection.Find(ctx, filter, opts)
@@ -119,3 +125,83 @@ func (r *eventRepo) FindAllEventByTrackID(ctx context.Context, trackID bson.Obje
 
 	return results, nil
 }
+
+func (r *eventRepo) FindLastEventByTrackIDs(ctx context.Context, trackIDs []string) (*entity.Event, error) {
+	if len(trackIDs) == 0 {
+		return nil, ErrNoEvents
+	}
+
+	filter := bson.M{"track_id": bson.M{"$in": trackIDs}}
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "published_at", Value: -1}})
+
+	var event entity.Event
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&event)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNoEvents
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// FindEventsBySubject matches the events of the tenant's tracks, and the events of the fingerprints
+// that were seen at the tenant
+func (r *eventRepo) FindEventsBySubject(ctx context.Context, tenantID string, trackIDs []string,
+	fingerprints []string) ([]*entity.Event, error) {
+	if len(trackIDs) == 0 && len(fingerprints) == 0 {
+		return []*entity.Event{}, nil
+	}
+
+	filter := bson.M{
+		"$or": []bson.M{
+			{"track_id": bson.M{"$in": trackIDs}},
+			{"fingerprint": bson.M{"$in": fingerprints}, "tenant_id": tenantID},
+		},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "published_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*entity.Event{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
+
+func (r *eventRepo) DeleteEventsByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	res, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete events: %w", err)
+	}
+	return res.DeletedCount, nil
+}
+
+func (r *eventRepo) AnonymizeEventsByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"fingerprint": "",
+			"user_agent":  "",
+			"ip":          "",
+			"updated_at":  time.Now().UTC(),
+		},
+	}
+	res, err := r.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize events: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
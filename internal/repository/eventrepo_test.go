@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
@@ -185,3 +186,55 @@ func TestEventRepo_FindLastEventByFingerprint(t *testing.T) {
 		assert.Equal(t, event.ID, actual.ID)
 	})
 }
+
+func TestEventRepo_EraseEventsBySubject(t *testing.T) {
+	suite, err := setupTestSuiteEventRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	t.Run("should find and anonymize events by track id and fingerprint", func(t *testing.T) {
+		event := &entity.Event{
+			TrackID:     "685cbfb8085b1462689b2447",
+			UserAgent:   "Mozilla/5.0",
+			Fingerprint: "fingerprint123456",
+			Url:         "http://www.example.com",
+			EventName:   entity.EventNameLandingPage,
+			PublishedAt: time.Now(),
+		}
+		err := suite.eventRepo.CreateEvent(ctx, event)
+		assert.NoError(t, err)
+		event2 := &entity.Event{
+			TrackID:     "original",
+			UserAgent:   "Mozilla/5.0",
+			Fingerprint: "fingerprint654321",
+			Url:         "http://www.example.com/thank-you",
+			EventName:   entity.EventNameThankYouPage,
+			PublishedAt: time.Now(),
+			TenantID:    "tenant1",
+		}
+		err = suite.eventRepo.CreateEvent(ctx, event2)
+		assert.NoError(t, err)
+
+		events, err := suite.eventRepo.FindEventsBySubject(ctx, "tenant1",
+			[]string{"685cbfb8085b1462689b2447"}, []string{"fingerprint654321"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(events))
+
+		events, err = suite.eventRepo.FindEventsBySubject(ctx, "tenant2", nil, []string{"fingerprint654321"})
+		assert.NoError(t, err)
+		assert.Empty(t, events, "fingerprint should only match the events of the tenant")
+
+		count, err := suite.eventRepo.AnonymizeEventsByIDs(ctx, []bson.ObjectID{event.ID, event2.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		events, err = suite.eventRepo.FindEventsBySubject(ctx, "tenant1", nil, []string{"fingerprint654321"})
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+
+		count, err = suite.eventRepo.DeleteEventsByIDs(ctx, []bson.ObjectID{event.ID, event2.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
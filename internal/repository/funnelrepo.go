@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type FunnelRepo interface {
+	CreateFunnel(ctx context.Context, funnel *entity.Funnel) error
+	FindFunnelByID(ctx context.Context, id bson.ObjectID) (*entity.Funnel, error)
+	FindFunnelsByTenantID(ctx context.Context, tenantID string) ([]*entity.Funnel, error)
+	UpdateFunnel(ctx context.Context, funnel *entity.Funnel) error
+	DeleteFunnel(ctx context.Context, id bson.ObjectID) error
+	// FindFunnelJourneys returns the events of [from, to) of the tenant by track, with the link and
+	// the channel of the track
+	FindFunnelJourneys(ctx context.Context, tenantID string, from, to time.Time) ([]*entity.FunnelJourney, error)
+}
+
+type funnelRepo struct {
+	collection *mongo.Collection
+	events     *mongo.Collection
+}
+
+func NewFunnelRepo(db *mongo.Database) FunnelRepo {
+	return &funnelRepo{
+		collection: db.Collection("funnel"),
+		events:     db.Collection("event"),
+	}
+}
+
+func (r *funnelRepo) CreateFunnel(ctx context.Context, funnel *entity.Funnel) error {
+	funnel.SetCreatedAt()
+	funnel.SetUpdatedAt()
+
+	res, err := r.collection.InsertOne(ctx, funnel)
+	if err != nil {
+		return fmt.Errorf("failed to create funnel: %w", err)
+	}
+	funnel.ID = res.InsertedID.(bson.ObjectID)
+	return nil
+}
+
+func (r *funnelRepo) FindFunnelByID(ctx context.Context, id bson.ObjectID) (*entity.Funnel, error) {
+	var funnel entity.Funnel
+	filter := bson.M{"_id": id, "deleted_at": bson.M{"$exists": false}}
+	if err := r.collection.FindOne(ctx, filter).Decode(&funnel); err != nil {
+		return nil, err
+	}
+	return &funnel, nil
+}
+
+func (r *funnelRepo) FindFunnelsByTenantID(ctx context.Context, tenantID string) ([]*entity.Funnel, error) {
+	filter := bson.M{"tenant_id": tenantID, "deleted_at": bson.M{"$exists": false}}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*entity.Funnel{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
+
+func (r *funnelRepo) UpdateFunnel(ctx context.Context, funnel *entity.Funnel) error {
+	funnel.SetUpdatedAt()
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":         funnel.Name,
+			"steps":        funnel.Steps,
+			"window_hours": funnel.WindowHours,
+			"updated_at":   funnel.UpdatedAt,
+		},
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": funnel.ID, "deleted_at": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update funnel: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeleteFunnel soft deletes the funnel
+func (r *funnelRepo) DeleteFunnel(ctx context.Context, id bson.ObjectID) error {
+	now := time.Now().UTC()
+	update := bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return fmt.Errorf("failed to delete funnel: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *funnelRepo) FindFunnelJourneys(ctx context.Context, tenantID string,
+	from, to time.Time) ([]*entity.FunnelJourney, error) {
+	match := bson.M{
+		"tenant_id":  tenantID,
+		"created_at": bson.M{"$gte": from, "$lt": to},
+		"event_name": bson.M{"$in": bson.A{entity.EventNameLandingPage, entity.EventNamePageView,
+			entity.EventNameCustom, entity.EventNameThankYouPage}},
+		"bot.is_bot": bson.M{"$ne": true},
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$sort": bson.D{{Key: "track_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{"$group": bson.M{
+			"_id": "$track_id",
+			"events": bson.M{"$push": bson.M{
+				"event_name":        "$event_name",
+				"name":              "$name",
+				"url":               "$url",
+				"thank_you_page_id": "$thank_you_page_id",
+				"created_at":        "$created_at",
+			}},
+		}},
+		// event.track_id is the hex string of the track id
+		{"$addFields": bson.M{"track_oid": bson.M{
+			"$convert": bson.M{"input": "$_id", "to": "objectId", "onError": nil, "onNull": nil},
+		}}},
+		{"$lookup": bson.M{
+			"from":         "track",
+			"localField":   "track_oid",
+			"foreignField": "_id",
+			"pipeline":     []bson.M{{"$project": bson.M{"link_id": 1, "channel": 1}}},
+			"as":           "track",
+		}},
+		{"$unwind": bson.M{"path": "$track", "preserveNullAndEmptyArrays": true}},
+		{"$project": bson.M{
+			"events":  1,
+			"link":    bson.M{"$ifNull": bson.A{bson.M{"$toString": "$track.link_id"}, ""}},
+			"channel": bson.M{"$ifNull": bson.A{"$track.channel", ""}},
+		}},
+	}
+
+	cursor, err := r.events.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*entity.FunnelJourney{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
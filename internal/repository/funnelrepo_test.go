@@ -0,0 +1,168 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type TestSuiteFunnelRepo struct {
+	mongoContainer testcontainers.Container
+	client         *mongo.Client
+	database       *mongo.Database
+	repo           repository.FunnelRepo
+}
+
+func setupTestSuiteFunnelRepo() (*TestSuiteFunnelRepo, error) {
+	ctx := context.Background()
+
+	mongodbContainer, err := mongodb.Run(ctx, "mongo:8")
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := mongodbContainer.Endpoint(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(fmt.Sprintf("mongodb://%s", endpoint)))
+	if err != nil {
+		return nil, err
+	}
+
+	database := client.Database("test")
+	return &TestSuiteFunnelRepo{
+		mongoContainer: mongodbContainer,
+		client:         client,
+		database:       database,
+		repo:           repository.NewFunnelRepo(database),
+	}, nil
+}
+
+func (ts *TestSuiteFunnelRepo) Cleanup() {
+	ctx := context.Background()
+	if ts.client != nil {
+		ts.client.Disconnect(ctx)
+	}
+	if ts.mongoContainer != nil {
+		ts.mongoContainer.Terminate(ctx)
+	}
+}
+
+func TestFunnelRepo_CRUD(t *testing.T) {
+	suite, err := setupTestSuiteFunnelRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	funnel := &entity.Funnel{
+		TenantID: "tenant1",
+		Name:     "Test drive",
+		Steps: []*entity.FunnelStep{
+			{Name: "Landing", Type: entity.FunnelStepLanding},
+			{Name: "Booked", Type: entity.FunnelStepConversion},
+		},
+		WindowHours: 24,
+	}
+
+	t.Run("should create and find the funnel", func(t *testing.T) {
+		assert.NoError(t, suite.repo.CreateFunnel(ctx, funnel))
+		assert.False(t, funnel.ID.IsZero())
+
+		found, err := suite.repo.FindFunnelByID(ctx, funnel.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Test drive", found.Name)
+		assert.Len(t, found.Steps, 2)
+	})
+
+	t.Run("should update the funnel", func(t *testing.T) {
+		funnel.Name = "Test drive booking"
+		funnel.WindowHours = 48
+		assert.NoError(t, suite.repo.UpdateFunnel(ctx, funnel))
+
+		funnels, err := suite.repo.FindFunnelsByTenantID(ctx, "tenant1")
+		assert.NoError(t, err)
+		assert.Len(t, funnels, 1)
+		assert.Equal(t, "Test drive booking", funnels[0].Name)
+		assert.Equal(t, 48, funnels[0].WindowHours)
+	})
+
+	t.Run("should soft delete the funnel", func(t *testing.T) {
+		assert.NoError(t, suite.repo.DeleteFunnel(ctx, funnel.ID))
+
+		_, err := suite.repo.FindFunnelByID(ctx, funnel.ID)
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+		assert.ErrorIs(t, suite.repo.DeleteFunnel(ctx, funnel.ID), mongo.ErrNoDocuments)
+	})
+}
+
+func TestFunnelRepo_FindFunnelJourneys(t *testing.T) {
+	suite, err := setupTestSuiteFunnelRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	tenantID := "tenant1"
+	linkID := bson.NewObjectID()
+	track := &entity.Track{ID: bson.NewObjectID(), LinkID: linkID, Channel: entity.ChannelPaidSearch}
+	_, err = suite.database.Collection("track").InsertOne(ctx, track)
+	assert.NoError(t, err)
+
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	events := []any{
+		&entity.Event{TrackID: track.ID.Hex(), TenantID: tenantID, EventName: entity.EventNamePageView,
+			Url: "https://dealer.example.com/cars/1", BaseEntity: entity.BaseEntity{CreatedAt: start.Add(time.Minute)}},
+		&entity.Event{TrackID: track.ID.Hex(), TenantID: tenantID, EventName: entity.EventNameLandingPage,
+			Url: "https://dealer.example.com/", BaseEntity: entity.BaseEntity{CreatedAt: start}},
+		&entity.Event{TrackID: track.ID.Hex(), TenantID: tenantID, EventName: entity.EventNameCustom,
+			Name: "test_drive_form", BaseEntity: entity.BaseEntity{CreatedAt: start.Add(2 * time.Minute)}},
+		// a bot, another tenant and out of range
+		&entity.Event{TrackID: track.ID.Hex(), TenantID: tenantID, EventName: entity.EventNamePageView,
+			Bot: entity.Bot{IsBot: true}, BaseEntity: entity.BaseEntity{CreatedAt: start.Add(time.Minute)}},
+		&entity.Event{TrackID: track.ID.Hex(), TenantID: "tenant2", EventName: entity.EventNameLandingPage,
+			BaseEntity: entity.BaseEntity{CreatedAt: start}},
+		&entity.Event{TrackID: bson.NewObjectID().Hex(), TenantID: tenantID, EventName: entity.EventNameLandingPage,
+			BaseEntity: entity.BaseEntity{CreatedAt: start.AddDate(0, 0, 2)}},
+	}
+	_, err = suite.database.Collection("event").InsertMany(ctx, events)
+	assert.NoError(t, err)
+
+	t.Run("should group the events of the range by track in order", func(t *testing.T) {
+		journeys, err := suite.repo.FindFunnelJourneys(ctx, tenantID, start, start.AddDate(0, 0, 1))
+		assert.NoError(t, err)
+		assert.Len(t, journeys, 1)
+
+		journey := journeys[0]
+		assert.Equal(t, track.ID.Hex(), journey.TrackID)
+		assert.Equal(t, linkID.Hex(), journey.Link)
+		assert.Equal(t, string(entity.ChannelPaidSearch), journey.Channel)
+		assert.Len(t, journey.Events, 3)
+		assert.Equal(t, entity.EventNameLandingPage, journey.Events[0].EventName)
+		assert.Equal(t, entity.EventNamePageView, journey.Events[1].EventName)
+		assert.Equal(t, "test_drive_form", journey.Events[2].Name)
+
+		funnel := &entity.Funnel{
+			Steps: []*entity.FunnelStep{
+				{Name: "Landing", Type: entity.FunnelStepLanding},
+				{Name: "Car page", Type: entity.FunnelStepURL, Match: entity.URLMatchPrefix, Value: "/cars/"},
+				{Name: "Form", Type: entity.FunnelStepEvent, Value: "test_drive_form"},
+				{Name: "Booked", Type: entity.FunnelStepConversion},
+			},
+			WindowHours: 1,
+		}
+		reached, first := funnel.Progress(journey.Events)
+		assert.Equal(t, 3, reached)
+		assert.Equal(t, start, first.UTC())
+	})
+}
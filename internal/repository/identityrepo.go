@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type IdentityRepo interface {
+	CreateIdentity(ctx context.Context, identity *entity.Identity) error
+	UpdateIdentity(ctx context.Context, identity *entity.Identity) error
+	DeleteIdentity(ctx context.Context, id bson.ObjectID) error
+	FindIdentityByID(ctx context.Context, id bson.ObjectID) (*entity.Identity, error)
+	FindIdentitiesByIdentifier(ctx context.Context, trackingSettingID bson.ObjectID,
+		identifier entity.Identifier) ([]*entity.Identity, error)
+	FindIdentitiesByIdentifiers(ctx context.Context, trackingSettingID bson.ObjectID,
+		identifiers []entity.Identifier) ([]*entity.Identity, error)
+}
+
+type identityRepo struct {
+	collection *mongo.Collection
+}
+
+func NewIdentityRepo(db *mongo.Database) IdentityRepo {
+	collection := db.Collection("identity")
+
+	return &identityRepo{
+		collection: collection,
+	}
+}
+
+func (r *identityRepo) CreateIdentity(ctx context.Context, identity *entity.Identity) error {
+	identity.SetCreatedAt()
+	identity.SetUpdatedAt()
+
+	res, err := r.collection.InsertOne(ctx, identity)
+	if err != nil {
+		return err
+	}
+	identity.ID = res.InsertedID.(bson.ObjectID)
+	return nil
+}
+
+func (r *identityRepo) UpdateIdentity(ctx context.Context, identity *entity.Identity) error {
+	identity.SetUpdatedAt()
+
+	filter := bson.M{"_id": identity.ID}
+	update := bson.M{
+		"$set": bson.M{
+			"identifiers": identity.Identifiers,
+			"edges":       identity.Edges,
+			"updated_at":  identity.UpdatedAt,
+		},
+	}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update identity: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *identityRepo) DeleteIdentity(ctx context.Context, id bson.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *identityRepo) FindIdentityByID(ctx context.Context, id bson.ObjectID) (*entity.Identity, error) {
+	var identity entity.Identity
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&identity)
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *identityRepo) FindIdentitiesByIdentifier(ctx context.Context, trackingSettingID bson.ObjectID,
+	identifier entity.Identifier) ([]*entity.Identity, error) {
+	filter := bson.M{
+		"tracking_setting_id": trackingSettingID,
+		"identifiers":         bson.M{"$elemMatch": bson.M{"type": identifier.Type, "value": identifier.Value}},
+	}
+
+	return r.find(ctx, filter)
+}
+
+func (r *identityRepo) FindIdentitiesByIdentifiers(ctx context.Context, trackingSettingID bson.ObjectID,
+	identifiers []entity.Identifier) ([]*entity.Identity, error) {
+	if len(identifiers) == 0 {
+		return []*entity.Identity{}, nil
+	}
+
+	or := []bson.M{}
+	for _, identifier := range identifiers {
+		or = append(or, bson.M{
+			"identifiers": bson.M{"$elemMatch": bson.M{"type": identifier.Type, "value": identifier.Value}},
+		})
+	}
+	filter := bson.M{
+		"tracking_setting_id": trackingSettingID,
+		"$or":                 or,
+	}
+
+	return r.find(ctx, filter)
+}
+
+func (r *identityRepo) find(ctx context.Context, filter bson.M) ([]*entity.Identity, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return []*entity.Identity{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	results := []*entity.Identity{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
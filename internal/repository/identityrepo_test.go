@@ -0,0 +1,156 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type TestSuiteIdentityRepo struct {
+	mongoContainer testcontainers.Container
+	client         *mongo.Client
+	repo           repository.IdentityRepo
+}
+
+func setupTestSuiteIdentityRepo() (*TestSuiteIdentityRepo, error) {
+	ctx := context.Background()
+
+	mongodbContainer, err := mongodb.Run(ctx, "mongo:8")
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := mongodbContainer.Endpoint(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(fmt.Sprintf("mongodb://%s", endpoint)))
+	if err != nil {
+		return nil, err
+	}
+
+	database := client.Database("test")
+	repo := repository.NewIdentityRepo(database)
+
+	return &TestSuiteIdentityRepo{
+		mongoContainer: mongodbContainer,
+		client:         client,
+		repo:           repo,
+	}, nil
+}
+
+func (ts *TestSuiteIdentityRepo) Cleanup() {
+	ctx := context.Background()
+	if ts.client != nil {
+		ts.client.Disconnect(ctx)
+	}
+	if ts.mongoContainer != nil {
+		ts.mongoContainer.Terminate(ctx)
+	}
+}
+
+func TestIdentityRepo_FindIdentitiesByIdentifier(t *testing.T) {
+	suite, err := setupTestSuiteIdentityRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	trackingSettingID := bson.NewObjectID()
+	endUser := entity.Identifier{Type: entity.IdentifierTypeEndUserID, Value: "EndUserID12345"}
+	ztid := entity.Identifier{Type: entity.IdentifierTypeZTID, Value: bson.NewObjectID().Hex()}
+
+	t.Run("should create and find identity by identifier", func(t *testing.T) {
+		identity := &entity.Identity{TrackingSettingID: trackingSettingID}
+		identity.AddEdge(entity.IdentityEdge{
+			From:       endUser,
+			To:         ztid,
+			Confidence: entity.IdentityConfidenceHigh,
+			Source:     "track",
+		})
+		err := suite.repo.CreateIdentity(ctx, identity)
+		assert.NoError(t, err)
+		assert.False(t, identity.ID.IsZero(), "ID should be generated")
+
+		found, err := suite.repo.FindIdentitiesByIdentifier(ctx, trackingSettingID, ztid)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(found))
+		assert.Equal(t, identity.ID, found[0].ID)
+		assert.Equal(t, 2, len(found[0].Identifiers))
+	})
+
+	t.Run("should scope identifiers lookup by tracking setting", func(t *testing.T) {
+		found, err := suite.repo.FindIdentitiesByIdentifiers(ctx, bson.NewObjectID(), []entity.Identifier{endUser})
+
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+
+		found, err = suite.repo.FindIdentitiesByIdentifier(ctx, bson.NewObjectID(), ztid)
+
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+	})
+
+	t.Run("should return empty list for unknown identifier", func(t *testing.T) {
+		found, err := suite.repo.FindIdentitiesByIdentifier(ctx, trackingSettingID,
+			entity.Identifier{Type: entity.IdentifierTypeFingerprint, Value: "unknown"})
+
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+	})
+}
+
+func TestIdentityRepo_UpdateIdentity(t *testing.T) {
+	suite, err := setupTestSuiteIdentityRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	t.Run("should update identifiers and edges", func(t *testing.T) {
+		ztid := entity.Identifier{Type: entity.IdentifierTypeZTID, Value: bson.NewObjectID().Hex()}
+		identity := &entity.Identity{TrackingSettingID: bson.NewObjectID()}
+		identity.AddEdge(entity.IdentityEdge{
+			From:       entity.Identifier{Type: entity.IdentifierTypeEndUserID, Value: "EndUserID12345"},
+			To:         ztid,
+			Confidence: entity.IdentityConfidenceHigh,
+		})
+		err := suite.repo.CreateIdentity(ctx, identity)
+		assert.NoError(t, err)
+
+		identity.AddEdge(entity.IdentityEdge{
+			From:       ztid,
+			To:         entity.Identifier{Type: entity.IdentifierTypeFingerprint, Value: "fingerprint123456"},
+			Confidence: entity.IdentityConfidenceMedium,
+		})
+		err = suite.repo.UpdateIdentity(ctx, identity)
+		assert.NoError(t, err)
+
+		found, err := suite.repo.FindIdentityByID(ctx, identity.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(found.Identifiers))
+		assert.Equal(t, 2, len(found.Edges))
+	})
+
+	t.Run("should delete identity", func(t *testing.T) {
+		identity := &entity.Identity{TrackingSettingID: bson.NewObjectID()}
+		err := suite.repo.CreateIdentity(ctx, identity)
+		assert.NoError(t, err)
+
+		err = suite.repo.DeleteIdentity(ctx, identity.ID)
+		assert.NoError(t, err)
+
+		found, err := suite.repo.FindIdentityByID(ctx, identity.ID)
+		assert.Error(t, err)
+		assert.Nil(t, found)
+	})
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type LinkCampaignRepo interface {
+	CreateLinkCampaign(ctx context.Context, campaign *entity.LinkCampaign) error
+	FindLinkCampaignByID(ctx context.Context, id bson.ObjectID) (*entity.LinkCampaign, error)
+	FindLinkCampaignsByTenantID(ctx context.Context, tenantID string) ([]*entity.LinkCampaign, error)
+	UpdateLinkCampaign(ctx context.Context, campaign *entity.LinkCampaign) error
+	DeleteLinkCampaign(ctx context.Context, id bson.ObjectID) error
+}
+
+type linkCampaignRepo struct {
+	collection *mongo.Collection
+}
+
+func NewLinkCampaignRepo(db *mongo.Database) LinkCampaignRepo {
+	collection := db.Collection("link_campaign")
+
+	return &linkCampaignRepo{
+		collection: collection,
+	}
+}
+
+func (r *linkCampaignRepo) CreateLinkCampaign(ctx context.Context, campaign *entity.LinkCampaign) error {
+	campaign.SetCreatedAt()
+	campaign.SetUpdatedAt()
+
+	res, err := r.collection.InsertOne(ctx, campaign)
+	if err != nil {
+		return fmt.Errorf("failed to create link campaign: %w", err)
+	}
+	campaign.ID = res.InsertedID.(bson.ObjectID)
+	return nil
+}
+
+func (r *linkCampaignRepo) FindLinkCampaignByID(ctx context.Context, id bson.ObjectID) (*entity.LinkCampaign, error) {
+	var campaign entity.LinkCampaign
+	filter := bson.M{"_id": id, "deleted_at": bson.M{"$exists": false}}
+	if err := r.collection.FindOne(ctx, filter).Decode(&campaign); err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+func (r *linkCampaignRepo) FindLinkCampaignsByTenantID(ctx context.Context, tenantID string) ([]*entity.LinkCampaign, error) {
+	filter := bson.M{"tenant_id": tenantID, "deleted_at": bson.M{"$exists": false}}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*entity.LinkCampaign{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
+
+func (r *linkCampaignRepo) UpdateLinkCampaign(ctx context.Context, campaign *entity.LinkCampaign) error {
+	campaign.SetUpdatedAt()
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":       campaign.Name,
+			"starts_at":  campaign.StartsAt,
+			"ends_at":    campaign.EndsAt,
+			"budget":     campaign.Budget,
+			"currency":   campaign.Currency,
+			"owner":      campaign.Owner,
+			"updated_at": campaign.UpdatedAt,
+		},
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": campaign.ID, "deleted_at": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update link campaign: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeleteLinkCampaign soft deletes the campaign
+func (r *linkCampaignRepo) DeleteLinkCampaign(ctx context.Context, id bson.ObjectID) error {
+	now := time.Now().UTC()
+	update := bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return fmt.Errorf("failed to delete link campaign: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
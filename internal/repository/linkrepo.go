@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github/michaellimmm/turakkingu/internal/entity"
 	"log/slog"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -16,8 +17,19 @@ type LinkRepo interface {
 	CreateLink(context.Context, *entity.Link) error
 	FindLinkByID(context.Context, string) (*entity.Link, error)
 	FindLinkByShortID(context.Context, string) (*entity.Link, error)
+	FindLinkByDomainAndShortID(ctx context.Context, domain string, shortID string) (*entity.Link, error)
 	FindAllLinkbyTenantID(ctx context.Context, tenantID string) ([]*entity.Link, error)
-	SearchLinks(ctx context.Context, tenantID string, keywords string) ([]*entity.Link, error)
+	SearchLinks(ctx context.Context, tenantID string, search entity.LinkSearch) (*entity.LinkSearchResult, error)
+	IncrementLinkClicks(ctx context.Context, id bson.ObjectID, maxClicks int64) (bool, error)
+	SetLinkClicks(ctx context.Context, id bson.ObjectID, clicks int64) error
+	UpdateLinkLifecycle(ctx context.Context, id bson.ObjectID, lifecycle entity.LinkLifecycle) error
+	UpdateLinkRules(ctx context.Context, id bson.ObjectID, rules []entity.RedirectRule) error
+	UpdateLinkRedirect(ctx context.Context, id bson.ObjectID, redirect entity.LinkRedirect) error
+	UpdateLinkTags(ctx context.Context, id bson.ObjectID, tags []string) error
+	AssignLinksToCampaign(ctx context.Context, tenantID string, ids []bson.ObjectID, campaignID bson.ObjectID) (int64, error)
+	ClearLinksCampaign(ctx context.Context, campaignID bson.ObjectID) (int64, error)
+	UpdateLinkDetails(ctx context.Context, link *entity.Link) error
+	RemoveLinks(ctx context.Context, ids []bson.ObjectID) (int64, error)
 }
 
 type linkRepo struct {
@@ -38,6 +50,7 @@ func (r *linkRepo) CreateLink(ctx context.Context, link *entity.Link) error {
 		return fmt.Errorf("failed to set short id")
 	}
 
+	link.SetSearchFields()
 	link.SetCreatedAt()
 	link.SetUpdatedAt()
 
@@ -68,10 +81,17 @@ func (r *linkRepo) FindLinkByID(ctx context.Context, id string) (*entity.Link, e
 	return &link, nil
 }
 
+// FindLinkByShortID finds a link of the default domain
 func (r *linkRepo) FindLinkByShortID(ctx context.Context, id string) (*entity.Link, error) {
+	return r.FindLinkByDomainAndShortID(ctx, "", id)
+}
+
+// FindLinkByDomainAndShortID finds a link by slug, slugs are unique per domain
+func (r *linkRepo) FindLinkByDomainAndShortID(ctx context.Context, domain string, shortID string) (*entity.Link, error) {
 	var link entity.Link
 	filter := bson.M{
-		"short_id":   id,
+		"domain":     domain,
+		"short_id":   shortID,
 		"deleted_at": bson.M{"$exists": false},
 	}
 
@@ -101,29 +121,242 @@ func (r *linkRepo) FindAllLinkbyTenantID(ctx context.Context, tenantID string) (
 	return results, nil
 }
 
-func (r *linkRepo) SearchLinks(ctx context.Context, tenantID string, keywords string) ([]*entity.Link, error) {
-	filter := bson.M{
-		"tenant_id": tenantID,
-		"$or": []bson.M{
-			{"url": bson.M{"$regex": keywords, "$options": "i"}},
-			{"name": bson.M{"$regex": keywords, "$options": "i"}},
-		},
+// SearchLinks ranks the links matching the words of the query in the text index, the links whose
+// name or short id starts with the query are matched too
+func (r *linkRepo) SearchLinks(ctx context.Context, tenantID string, search entity.LinkSearch) (*entity.LinkSearchResult, error) {
+	search.Normalize()
+
+	filter := bson.M{"tenant_id": tenantID, "deleted_at": bson.M{"$exists": search.Deleted}}
+	if len(search.Tags) > 0 {
+		filter["tags"] = bson.M{"$all": search.Tags}
+	}
+	if search.CampaignID != nil {
+		filter["campaign_id"] = *search.CampaignID
+	}
+
+	createdAt := bson.M{}
+	if search.CreatedFrom != nil {
+		createdAt["$gte"] = *search.CreatedFrom
+	}
+	if search.CreatedTo != nil {
+		createdAt["$lt"] = *search.CreatedTo
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+
+	sort := bson.D{{Key: "updated_at", Value: -1}}
+	if search.Query != "" {
+		// $text can only be in an $or of indexed clauses
+		prefix := bson.M{"$regex": search.PrefixPattern()}
+		or := []bson.M{{"search_name": prefix}, {"search_short_id": prefix}}
+		if text := search.TextSearch(); text != "" {
+			or = append(or, bson.M{"$text": bson.M{"$search": text}})
+			sort = append(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}}, sort...)
+		}
+		filter["$or"] = or
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		slog.Error("failed to count links", slog.String("error", err.Error()))
+		return nil, err
 	}
 
 	opts := options.Find().
-		SetSort(bson.D{{Key: "updated_at", Value: -1}})
+		SetSort(sort).
+		SetSkip(int64((search.Page - 1) * search.PageSize)).
+		SetLimit(int64(search.PageSize))
 	cursor, err := r.collection.Find(ctx, filter, opts)
-	if errors.Is(err, mongo.ErrNoDocuments) {
-		return nil, ErrNoEvents
-	} else if err != nil {
+	if err != nil {
 		slog.Error("failed to search link", slog.String("error", err.Error()))
 		return nil, err
 	}
 
-	var results []*entity.Link
+	results := []*entity.Link{}
 	if err := cursor.All(ctx, &results); err != nil {
 		slog.Error("failed to search link", slog.String("error", err.Error()))
 		return nil, fmt.Errorf("failed to decode result: %w", err)
 	}
-	return results, nil
+
+	return &entity.LinkSearchResult{
+		Links:    results,
+		Total:    total,
+		Page:     search.Page,
+		PageSize: search.PageSize,
+	}, nil
+}
+
+// IncrementLinkClicks counts a click unless the link already has maxClicks, 0 is unlimited
+func (r *linkRepo) IncrementLinkClicks(ctx context.Context, id bson.ObjectID, maxClicks int64) (bool, error) {
+	filter := bson.M{"_id": id}
+	if maxClicks > 0 {
+		filter["clicks"] = bson.M{"$lt": maxClicks}
+	}
+
+	res, err := r.collection.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"clicks": 1}})
+	if err != nil {
+		return false, fmt.Errorf("failed to increment link clicks: %w", err)
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// SetLinkClicks restarts the click counter of the link from the stored clicks
+func (r *linkRepo) SetLinkClicks(ctx context.Context, id bson.ObjectID, clicks int64) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"clicks": clicks}})
+	if err != nil {
+		return fmt.Errorf("failed to set link clicks: %w", err)
+	}
+	return nil
+}
+
+func (r *linkRepo) UpdateLinkLifecycle(ctx context.Context, id bson.ObjectID, lifecycle entity.LinkLifecycle) error {
+	set := bson.M{
+		"max_clicks":      lifecycle.MaxClicks,
+		"fallback_url":    lifecycle.FallbackUrl,
+		"expired_message": lifecycle.ExpiredMessage,
+		"updated_at":      time.Now().UTC(),
+	}
+	unset := bson.M{}
+	if lifecycle.StartsAt != nil {
+		set["starts_at"] = lifecycle.StartsAt
+	} else {
+		unset["starts_at"] = ""
+	}
+	if lifecycle.EndsAt != nil {
+		set["ends_at"] = lifecycle.EndsAt
+	} else {
+		unset["ends_at"] = ""
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update link lifecycle: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateLinkRules replaces the redirect rules, in their order
+func (r *linkRepo) UpdateLinkRules(ctx context.Context, id bson.ObjectID, rules []entity.RedirectRule) error {
+	update := bson.M{"$set": bson.M{"rules": rules, "updated_at": time.Now().UTC()}}
+	_, err := r.collection.UpdateByID(ctx, id, update)
+	return err
+}
+
+// UpdateLinkRedirect replaces the redirect settings, the unset ones are removed
+func (r *linkRepo) UpdateLinkRedirect(ctx context.Context, id bson.ObjectID, redirect entity.LinkRedirect) error {
+	raw, err := bson.Marshal(redirect)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{}
+	if err := bson.Unmarshal(raw, &set); err != nil {
+		return err
+	}
+	set["updated_at"] = time.Now().UTC()
+
+	unset := bson.M{}
+	for _, field := range []string{"param_policy", "allowed_params", "strip_params", "redirect_status", "interstitial"} {
+		if _, ok := set[field]; !ok {
+			unset[field] = ""
+		}
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	_, err = r.collection.UpdateByID(ctx, id, update)
+	return err
+}
+
+// UpdateLinkTags replaces the tags of the link
+func (r *linkRepo) UpdateLinkTags(ctx context.Context, id bson.ObjectID, tags []string) error {
+	update := bson.M{"$set": bson.M{"tags": tags, "updated_at": time.Now().UTC()}}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update link tags: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// AssignLinksToCampaign moves the links of the tenant to the campaign, a zero campaign id
+// removes them from their campaign. It returns the number of links found.
+func (r *linkRepo) AssignLinksToCampaign(ctx context.Context, tenantID string, ids []bson.ObjectID,
+	campaignID bson.ObjectID) (int64, error) {
+	update := bson.M{"$set": bson.M{"campaign_id": campaignID, "updated_at": time.Now().UTC()}}
+	if campaignID.IsZero() {
+		update = bson.M{"$unset": bson.M{"campaign_id": ""}, "$set": bson.M{"updated_at": time.Now().UTC()}}
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": ids}, "tenant_id": tenantID, "deleted_at": bson.M{"$exists": false}}
+	res, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to assign links to campaign: %w", err)
+	}
+	return res.MatchedCount, nil
+}
+
+// ClearLinksCampaign removes the links of the campaign from it
+func (r *linkRepo) ClearLinksCampaign(ctx context.Context, campaignID bson.ObjectID) (int64, error) {
+	update := bson.M{"$unset": bson.M{"campaign_id": ""}, "$set": bson.M{"updated_at": time.Now().UTC()}}
+	res, err := r.collection.UpdateMany(ctx, bson.M{"campaign_id": campaignID}, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear links campaign: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
+
+// UpdateLinkDetails replaces the name, url, tags and campaign of the link
+func (r *linkRepo) UpdateLinkDetails(ctx context.Context, link *entity.Link) error {
+	link.SetSearchFields()
+	link.SetUpdatedAt()
+
+	set := bson.M{
+		"name":        link.Name,
+		"search_name": link.SearchName,
+		"url":         link.Url,
+		"tags":        link.Tags,
+		"updated_at":  link.UpdatedAt,
+	}
+	update := bson.M{"$set": set}
+	if link.CampaignID.IsZero() {
+		update["$unset"] = bson.M{"campaign_id": ""}
+	} else {
+		set["campaign_id"] = link.CampaignID
+	}
+
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": link.ID, "deleted_at": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update link: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// RemoveLinks deletes the links for good, only for links that never redirected, e.g. the ones of
+// an import that is rolled back. Links are soft deleted otherwise.
+func (r *linkRepo) RemoveLinks(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	res, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove links: %w", err)
+	}
+	return res.DeletedCount, nil
 }
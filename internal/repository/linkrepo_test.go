@@ -3,9 +3,11 @@ package repository_test
 import (
 	"context"
 	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
 	"github/michaellimmm/turakkingu/internal/entity"
 	"github/michaellimmm/turakkingu/internal/repository"
 	"testing"
+	"time"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"github.com/stretchr/testify/assert"
@@ -36,7 +38,13 @@ func setupTestSuiteLinkRepo() (*TestSuiteLinkRepo, error) {
 		return nil, err
 	}
 
-	client, err := mongo.Connect(options.Client().ApplyURI(fmt.Sprintf("mongodb://%s", endpoint)))
+	uri := fmt.Sprintf("mongodb://%s", endpoint)
+	// the search needs the text index
+	if err := repository.RunMigrations(&core.Config{MongoDBUri: uri, MongoDBName: "test"}); err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
 	if err != nil {
 		return nil, err
 	}
@@ -154,6 +162,37 @@ func TestLinkRepo_FindByShortID(t *testing.T) {
 	})
 }
 
+func TestLinkRepo_FindLinkByDomainAndShortID(t *testing.T) {
+	suite, err := setupTestSuiteLinkRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	defaultLink := &entity.Link{TenantID: "tenant1", Url: "https://www.github.com", ShortID: "summer-sale"}
+	brandedLink := &entity.Link{TenantID: "tenant1", Url: "https://www.gitlab.com", ShortID: "summer-sale", Domain: "go.example.com"}
+	assert.NoError(t, suite.repo.CreateLink(ctx, defaultLink))
+	assert.NoError(t, suite.repo.CreateLink(ctx, brandedLink))
+
+	t.Run("should find the link of the domain", func(t *testing.T) {
+		link, err := suite.repo.FindLinkByDomainAndShortID(ctx, "go.example.com", "summer-sale")
+		assert.NoError(t, err)
+		assert.Equal(t, brandedLink.ID, link.ID)
+		assert.Equal(t, "https://go.example.com/summer-sale", link.ConstructFixedUrl("https://tracker.local"))
+	})
+
+	t.Run("should keep the default domain apart", func(t *testing.T) {
+		link, err := suite.repo.FindLinkByShortID(ctx, "summer-sale")
+		assert.NoError(t, err)
+		assert.Equal(t, defaultLink.ID, link.ID)
+		assert.Equal(t, "https://tracker.local/r/summer-sale", link.ConstructFixedUrl("https://tracker.local"))
+	})
+
+	t.Run("should not find the slug on another domain", func(t *testing.T) {
+		_, err := suite.repo.FindLinkByDomainAndShortID(ctx, "links.example.org", "summer-sale")
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+}
+
 func TestLinkRepo_FindAllLinkbyTenantID(t *testing.T) {
 	suite, err := setupTestSuiteLinkRepo()
 	assert.NoError(t, err)
@@ -212,9 +251,310 @@ func TestLinkRepo_SearchLinks(t *testing.T) {
 		err = suite.repo.CreateLink(ctx, link3)
 		assert.NoError(t, err)
 
-		foundLink, err := suite.repo.SearchLinks(ctx, "tenant1", "hacker news")
+		foundLink, err := suite.repo.SearchLinks(ctx, "tenant1", entity.LinkSearch{Query: "hacker news"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, len(foundLink.Links), 1)
+	})
+
+	t.Run("should match the query literally", func(t *testing.T) {
+		for _, query := range []string{".", "(", "a+)+$", `"-github"`} {
+			_, err := suite.repo.SearchLinks(ctx, "tenant1", entity.LinkSearch{Query: query})
+			assert.NoError(t, err)
+		}
+
+		foundLink, err := suite.repo.SearchLinks(ctx, "tenant1", entity.LinkSearch{Query: "."})
+		assert.NoError(t, err)
+		assert.Empty(t, foundLink.Links)
+	})
+
+	t.Run("should match the prefix of the name", func(t *testing.T) {
+		foundLink, err := suite.repo.SearchLinks(ctx, "tenant1", entity.LinkSearch{Query: "cryp"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(foundLink.Links))
+		assert.Equal(t, "crypto 101", foundLink.Links[0].Name)
+	})
+
+	t.Run("should match the prefix of the name and the short id in any case", func(t *testing.T) {
+		link := &entity.Link{
+			TenantID: "tenant3",
+			Name:     "Spring Campaign",
+			Url:      "https://example.com/1",
+			ShortID:  "SpringSale",
+		}
+		assert.NoError(t, suite.repo.CreateLink(ctx, link))
+		assert.Equal(t, "spring campaign", link.SearchName)
+		assert.Equal(t, "springsale", link.SearchShortID)
+
+		for _, query := range []string{"SPRING C", "spring camp", "springs", "SPRINGSA"} {
+			result, err := suite.repo.SearchLinks(ctx, "tenant3", entity.LinkSearch{Query: query})
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), result.Total, query)
+		}
+
+		link.Name = "Autumn Campaign"
+		assert.NoError(t, suite.repo.UpdateLinkDetails(ctx, link))
+
+		result, err := suite.repo.SearchLinks(ctx, "tenant3", entity.LinkSearch{Query: "autu"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.Total)
+
+		result, err = suite.repo.SearchLinks(ctx, "tenant3", entity.LinkSearch{Query: "spring c"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), result.Total)
+	})
+
+	t.Run("should filter by tag and deleted and page the results", func(t *testing.T) {
+		deletedAt := time.Now().UTC()
+		links := []*entity.Link{
+			{TenantID: "tenant2", Name: "spring sale", Url: "https://example.com/1", Tags: []string{"sale"}},
+			{TenantID: "tenant2", Name: "summer sale", Url: "https://example.com/2", Tags: []string{"sale", "summer"}},
+			{TenantID: "tenant2", Name: "winter sale", Url: "https://example.com/3", Tags: []string{"sale"}},
+			{TenantID: "tenant2", Name: "old sale", Url: "https://example.com/4", Tags: []string{"sale"},
+				BaseEntity: entity.BaseEntity{DeletedAt: &deletedAt}},
+		}
+		for _, link := range links {
+			assert.NoError(t, suite.repo.CreateLink(ctx, link))
+		}
+
+		result, err := suite.repo.SearchLinks(ctx, "tenant2", entity.LinkSearch{Tags: []string{"Sale"}, PageSize: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.Total)
+		assert.Equal(t, 2, len(result.Links))
+		assert.True(t, result.HasNext())
+
+		result, err = suite.repo.SearchLinks(ctx, "tenant2", entity.LinkSearch{Tags: []string{"sale", "summer"}})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(result.Links))
+
+		result, err = suite.repo.SearchLinks(ctx, "tenant2", entity.LinkSearch{Query: "sale", Deleted: true})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(result.Links))
+		assert.Equal(t, "old sale", result.Links[0].Name)
+	})
+}
+
+func TestLinkRepo_IncrementLinkClicks(t *testing.T) {
+	suite, err := setupTestSuiteLinkRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	t.Run("should stop counting at max clicks", func(t *testing.T) {
+		link := &entity.Link{
+			TenantID: "tenat1",
+			Url:      "https://www.github.com",
+		}
+		err := suite.repo.CreateLink(ctx, link)
+		assert.NoError(t, err)
+
+		for range 2 {
+			counted, err := suite.repo.IncrementLinkClicks(ctx, link.ID, 2)
+			assert.NoError(t, err)
+			assert.True(t, counted)
+		}
+
+		counted, err := suite.repo.IncrementLinkClicks(ctx, link.ID, 2)
+		assert.NoError(t, err)
+		assert.False(t, counted)
+
+		foundLink, err := suite.repo.FindLinkByID(ctx, link.ID.Hex())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), foundLink.Clicks)
+	})
+
+	t.Run("should count from the set clicks", func(t *testing.T) {
+		link := &entity.Link{
+			TenantID: "tenat1",
+			Url:      "https://www.github.com",
+		}
+		err := suite.repo.CreateLink(ctx, link)
+		assert.NoError(t, err)
+
+		err = suite.repo.SetLinkClicks(ctx, link.ID, 2)
+		assert.NoError(t, err)
+
+		counted, err := suite.repo.IncrementLinkClicks(ctx, link.ID, 2)
+		assert.NoError(t, err)
+		assert.False(t, counted)
+	})
+}
+
+func TestLinkRepo_UpdateLinkLifecycle(t *testing.T) {
+	suite, err := setupTestSuiteLinkRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	t.Run("should update and clear the schedule", func(t *testing.T) {
+		link := &entity.Link{
+			TenantID: "tenat1",
+			Url:      "https://www.github.com",
+		}
+		err := suite.repo.CreateLink(ctx, link)
+		assert.NoError(t, err)
+
+		endsAt := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Millisecond)
+		lifecycle := entity.LinkLifecycle{EndsAt: &endsAt, MaxClicks: 100, FallbackUrl: "https://www.github.com/fallback"}
+		err = suite.repo.UpdateLinkLifecycle(ctx, link.ID, lifecycle)
+		assert.NoError(t, err)
+
+		foundLink, err := suite.repo.FindLinkByID(ctx, link.ID.Hex())
+		assert.NoError(t, err)
+		assert.Equal(t, lifecycle, foundLink.LinkLifecycle)
+
+		err = suite.repo.UpdateLinkLifecycle(ctx, link.ID, entity.LinkLifecycle{})
+		assert.NoError(t, err)
+
+		foundLink, err = suite.repo.FindLinkByID(ctx, link.ID.Hex())
+		assert.NoError(t, err)
+		assert.Nil(t, foundLink.EndsAt)
+	})
+}
+
+func TestLinkRepo_UpdateLinkRules(t *testing.T) {
+	suite, err := setupTestSuiteLinkRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	t.Run("should keep the order of the rules", func(t *testing.T) {
+		link := &entity.Link{
+			TenantID: "tenat1",
+			Url:      "https://www.github.com",
+		}
+		err := suite.repo.CreateLink(ctx, link)
+		assert.NoError(t, err)
+
+		rules := []entity.RedirectRule{
+			{Name: "ios", Conditions: entity.RuleConditions{OS: []string{"iOS"}}, Url: "https://apps.apple.com/app/id1"},
+			{Name: "android", Conditions: entity.RuleConditions{OS: []string{"Android"}}, Url: "https://play.google.com/store/apps/details?id=app"},
+		}
+		err = suite.repo.UpdateLinkRules(ctx, link.ID, rules)
+		assert.NoError(t, err)
+
+		foundLink, err := suite.repo.FindLinkByID(ctx, link.ID.Hex())
+		assert.NoError(t, err)
+		assert.Equal(t, rules, foundLink.Rules)
+		assert.Equal(t, "android", foundLink.MatchRule(entity.RuleRequest{OS: "Android"}).Rule.Name)
+	})
+}
+
+func TestLinkRepo_UpdateLinkRedirect(t *testing.T) {
+	suite, err := setupTestSuiteLinkRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	t.Run("should update and clear the redirect settings", func(t *testing.T) {
+		link := &entity.Link{
+			TenantID: "tenat1",
+			Url:      "https://www.github.com",
+		}
+		err := suite.repo.CreateLink(ctx, link)
+		assert.NoError(t, err)
+
+		redirect := entity.LinkRedirect{
+			ParamPolicy:    entity.ParamPolicyAllowlist,
+			AllowedParams:  []string{"utm_campaign"},
+			StripParams:    []string{"fbclid"},
+			RedirectStatus: 307,
+			Interstitial:   &entity.Interstitial{Delay: 1, Scripts: []string{"https://www.github.com/analytics.js"}},
+		}
+		err = suite.repo.UpdateLinkRedirect(ctx, link.ID, redirect)
+		assert.NoError(t, err)
+
+		foundLink, err := suite.repo.FindLinkByID(ctx, link.ID.Hex())
+		assert.NoError(t, err)
+		assert.Equal(t, redirect, foundLink.LinkRedirect)
+
+		err = suite.repo.UpdateLinkRedirect(ctx, link.ID, entity.LinkRedirect{RedirectStatus: 301})
+		assert.NoError(t, err)
+
+		foundLink, err = suite.repo.FindLinkByID(ctx, link.ID.Hex())
+		assert.NoError(t, err)
+		assert.Equal(t, entity.LinkRedirect{RedirectStatus: 301}, foundLink.LinkRedirect)
+	})
+}
+
+func TestLinkRepo_AssignLinksToCampaign(t *testing.T) {
+	suite, err := setupTestSuiteLinkRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	t.Run("should assign the links of the tenant only", func(t *testing.T) {
+		link := &entity.Link{TenantID: "tenat1", Url: "https://www.github.com"}
+		err := suite.repo.CreateLink(ctx, link)
+		assert.NoError(t, err)
+
+		other := &entity.Link{TenantID: "tenat2", Url: "https://www.github.com"}
+		err = suite.repo.CreateLink(ctx, other)
+		assert.NoError(t, err)
+
+		campaignID := bson.NewObjectID()
+		count, err := suite.repo.AssignLinksToCampaign(ctx, "tenat1", []bson.ObjectID{link.ID, other.ID}, campaignID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		result, err := suite.repo.SearchLinks(ctx, "tenat1", entity.LinkSearch{CampaignID: &campaignID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.Total)
+		assert.Equal(t, link.ID, result.Links[0].ID)
+
+		cleared, err := suite.repo.ClearLinksCampaign(ctx, campaignID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), cleared)
+
+		foundLink, err := suite.repo.FindLinkByID(ctx, link.ID.Hex())
+		assert.NoError(t, err)
+		assert.True(t, foundLink.CampaignID.IsZero())
+	})
+}
+
+func TestLinkRepo_UpdateLinkDetails(t *testing.T) {
+	suite, err := setupTestSuiteLinkRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	t.Run("should replace the details and remove the campaign", func(t *testing.T) {
+		link := &entity.Link{
+			TenantID:   "tenat1",
+			Name:       "github",
+			Url:        "https://www.github.com",
+			Tags:       []string{"code"},
+			CampaignID: bson.NewObjectID(),
+		}
+		err := suite.repo.CreateLink(ctx, link)
+		assert.NoError(t, err)
+
+		link.Name = "gitlab"
+		link.Url = "https://www.gitlab.com"
+		link.Tags = []string{"code", "ci"}
+		link.CampaignID = bson.ObjectID{}
+		err = suite.repo.UpdateLinkDetails(ctx, link)
+		assert.NoError(t, err)
+
+		foundLink, err := suite.repo.FindLinkByID(ctx, link.ID.Hex())
+		assert.NoError(t, err)
+		assert.Equal(t, "gitlab", foundLink.Name)
+		assert.Equal(t, "https://www.gitlab.com", foundLink.Url)
+		assert.Equal(t, []string{"code", "ci"}, foundLink.Tags)
+		assert.True(t, foundLink.CampaignID.IsZero())
+	})
+
+	t.Run("should remove the links for good", func(t *testing.T) {
+		link := &entity.Link{TenantID: "tenat1", Url: "https://www.github.com"}
+		err := suite.repo.CreateLink(ctx, link)
+		assert.NoError(t, err)
 
+		count, err := suite.repo.RemoveLinks(ctx, []bson.ObjectID{link.ID})
 		assert.NoError(t, err)
-		assert.Equal(t, len(foundLink), 1)
+		assert.Equal(t, int64(1), count)
+
+		_, err = suite.repo.FindLinkByID(ctx, link.ID.Hex())
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
 	})
 }
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/migrations"
+	mongodb "github/michaellimmm/turakkingu/pkg/migrate"
+	"log/slog"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// RunMigrations applies the embedded migrations (indexes, TTL indexes, ...) on the configured database.
+// It uses its own client because closing the migrator disconnects it.
+func RunMigrations(config *core.Config) error {
+	source, err := iofs.New(migrations.MigrationFS, ".")
+	if err != nil {
+		return fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(config.MongoDBUri))
+	if err != nil {
+		return err
+	}
+
+	driver, err := mongodb.WithInstance(client, &mongodb.Config{
+		DatabaseName: config.MongoDBName,
+		Locking: mongodb.Locking{
+			Enabled: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, config.MongoDBName, driver)
+	if err != nil {
+		return fmt.Errorf("failed to create migration: %w", err)
+	}
+	defer func() {
+		_, _ = m.Close()
+	}()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	version, dirty, _ := m.Version()
+	slog.Info("migrations applied", slog.Int("version", int(version)), slog.Bool("dirty", dirty))
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
 
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -15,6 +16,15 @@ type Repo interface {
 	TrackRepo
 	ThankYouPageRepo
 	EventRepo
+	IdentityRepo
+	DataSubjectRequestRepo
+	RetentionRepo
+	ClickRepo
+	LinkCampaignRepo
+	ReportRepo
+	RollupRepo
+	FunnelRepo
+	CacheRepo
 }
 
 type RepoCloser interface {
@@ -23,15 +33,29 @@ type RepoCloser interface {
 }
 
 type repo struct {
-	client *mongo.Client
+	client      *mongo.Client
+	cache       *repoCache
+	stopWatcher context.CancelFunc
 	LinkRepo
 	TrackingSettingRepo
 	TrackRepo
 	ThankYouPageRepo
 	EventRepo
+	IdentityRepo
+	DataSubjectRequestRepo
+	RetentionRepo
+	ClickRepo
+	LinkCampaignRepo
+	ReportRepo
+	RollupRepo
+	FunnelRepo
 }
 
 func NewRepo(config *core.Config) (RepoCloser, error) {
+	if err := RunMigrations(config); err != nil {
+		return nil, err
+	}
+
 	client, err := mongo.Connect(options.Client().ApplyURI(config.MongoDBUri))
 	if err != nil {
 		return nil, err
@@ -39,22 +63,67 @@ func NewRepo(config *core.Config) (RepoCloser, error) {
 
 	db := client.Database(config.MongoDBName)
 
+	var cache *repoCache
 	linkRepo := NewLinkRepo(db)
 	trackingSettingRepo := NewTrackingSettingRepo(db)
+	if config.CacheSize > 0 {
+		cache = newRepoCache(config.CacheSize, config.CacheTTL)
+		linkRepo = &cachedLinkRepo{LinkRepo: linkRepo, cache: cache}
+		trackingSettingRepo = &cachedTrackingSettingRepo{TrackingSettingRepo: trackingSettingRepo, cache: cache}
+	}
+
 	trackRepo := NewTrackRepo(db, trackingSettingRepo)
 	thankYouPageRepo := NewThankYouPageRepo(db, trackingSettingRepo)
+	if cache != nil {
+		trackRepo = &cachedTrackRepo{TrackRepo: trackRepo, cache: cache}
+		thankYouPageRepo = &cachedThankYouPageRepo{ThankYouPageRepo: thankYouPageRepo, cache: cache}
+	}
+
 	eventRepo := NewEventRepo(db, trackRepo)
+	identityRepo := NewIdentityRepo(db)
+	dataSubjectRequestRepo := NewDataSubjectRequestRepo(db)
+	retentionRepo := NewRetentionRepo(db)
+	clickRepo := NewClickRepo(db)
+	linkCampaignRepo := NewLinkCampaignRepo(db)
+	reportRepo := NewReportRepo(db)
+	rollupRepo := NewRollupRepo(db)
+	funnelRepo := NewFunnelRepo(db)
+
+	stopWatcher := func() {}
+	if cache != nil && config.CacheChangeStream {
+		var ctx context.Context
+		ctx, stopWatcher = context.WithCancel(context.Background())
+		go cache.watch(ctx, db)
+	}
 
 	return &repo{
-		client:              client,
-		LinkRepo:            linkRepo,
-		TrackingSettingRepo: trackingSettingRepo,
-		TrackRepo:           trackRepo,
-		ThankYouPageRepo:    thankYouPageRepo,
-		EventRepo:           eventRepo,
+		client:                 client,
+		cache:                  cache,
+		stopWatcher:            stopWatcher,
+		LinkRepo:               linkRepo,
+		TrackingSettingRepo:    trackingSettingRepo,
+		TrackRepo:              trackRepo,
+		ThankYouPageRepo:       thankYouPageRepo,
+		EventRepo:              eventRepo,
+		IdentityRepo:           identityRepo,
+		DataSubjectRequestRepo: dataSubjectRequestRepo,
+		RetentionRepo:          retentionRepo,
+		ClickRepo:              clickRepo,
+		LinkCampaignRepo:       linkCampaignRepo,
+		ReportRepo:             reportRepo,
+		RollupRepo:             rollupRepo,
+		FunnelRepo:             funnelRepo,
 	}, nil
 }
 
+func (r *repo) CacheStats() []entity.CacheStats {
+	if r.cache == nil {
+		return []entity.CacheStats{}
+	}
+	return r.cache.stats()
+}
+
 func (r *repo) Close(ctx context.Context) error {
+	r.stopWatcher()
 	return r.client.Disconnect(ctx)
 }
@@ -13,6 +13,7 @@ import (
 	context "context"
 	entity "github/michaellimmm/turakkingu/internal/entity"
 	reflect "reflect"
+	time "time"
 
 	bson "go.mongodb.org/mongo-driver/v2/bson"
 	gomock "go.uber.org/mock/gomock"
@@ -42,355 +43,345 @@ func (m *MockRepo) EXPECT() *MockRepoMockRecorder {
 	return m.recorder
 }
 
-// CreateEvent mocks base method.
-func (m *MockRepo) CreateEvent(ctx context.Context, event *entity.Event) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEvent", ctx, event)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// CreateEvent indicates an expected call of CreateEvent.
-func (mr *MockRepoMockRecorder) CreateEvent(ctx, event any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockRepo)(nil).CreateEvent), ctx, event)
-}
-
-// CreateLink mocks base method.
-func (m *MockRepo) CreateLink(arg0 context.Context, arg1 *entity.Link) error {
+// AnonymizeClicksByIDs mocks base method.
+func (m *MockRepo) AnonymizeClicksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateLink", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "AnonymizeClicksByIDs", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CreateLink indicates an expected call of CreateLink.
-func (mr *MockRepoMockRecorder) CreateLink(arg0, arg1 any) *gomock.Call {
+// AnonymizeClicksByIDs indicates an expected call of AnonymizeClicksByIDs.
+func (mr *MockRepoMockRecorder) AnonymizeClicksByIDs(ctx, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLink", reflect.TypeOf((*MockRepo)(nil).CreateLink), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeClicksByIDs", reflect.TypeOf((*MockRepo)(nil).AnonymizeClicksByIDs), ctx, ids)
 }
 
-// CreatePage mocks base method.
-func (m *MockRepo) CreatePage(arg0 context.Context, arg1 *entity.ThankYouPage) error {
+// AnonymizeEventsByIDs mocks base method.
+func (m *MockRepo) AnonymizeEventsByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePage", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "AnonymizeEventsByIDs", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CreatePage indicates an expected call of CreatePage.
-func (mr *MockRepoMockRecorder) CreatePage(arg0, arg1 any) *gomock.Call {
+// AnonymizeEventsByIDs indicates an expected call of AnonymizeEventsByIDs.
+func (mr *MockRepoMockRecorder) AnonymizeEventsByIDs(ctx, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePage", reflect.TypeOf((*MockRepo)(nil).CreatePage), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeEventsByIDs", reflect.TypeOf((*MockRepo)(nil).AnonymizeEventsByIDs), ctx, ids)
 }
 
-// CreateTrack mocks base method.
-func (m *MockRepo) CreateTrack(ctx context.Context, track *entity.Track) error {
+// AnonymizeTracksByIDs mocks base method.
+func (m *MockRepo) AnonymizeTracksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTrack", ctx, track)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "AnonymizeTracksByIDs", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CreateTrack indicates an expected call of CreateTrack.
-func (mr *MockRepoMockRecorder) CreateTrack(ctx, track any) *gomock.Call {
+// AnonymizeTracksByIDs indicates an expected call of AnonymizeTracksByIDs.
+func (mr *MockRepoMockRecorder) AnonymizeTracksByIDs(ctx, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrack", reflect.TypeOf((*MockRepo)(nil).CreateTrack), ctx, track)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeTracksByIDs", reflect.TypeOf((*MockRepo)(nil).AnonymizeTracksByIDs), ctx, ids)
 }
 
-// FindAllEventByTenantID mocks base method.
-func (m *MockRepo) FindAllEventByTenantID(ctx context.Context, tenantID string) ([]*entity.Event, error) {
+// AssignLinksToCampaign mocks base method.
+func (m *MockRepo) AssignLinksToCampaign(ctx context.Context, tenantID string, ids []bson.ObjectID, campaignID bson.ObjectID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllEventByTenantID", ctx, tenantID)
-	ret0, _ := ret[0].([]*entity.Event)
+	ret := m.ctrl.Call(m, "AssignLinksToCampaign", ctx, tenantID, ids, campaignID)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindAllEventByTenantID indicates an expected call of FindAllEventByTenantID.
-func (mr *MockRepoMockRecorder) FindAllEventByTenantID(ctx, tenantID any) *gomock.Call {
+// AssignLinksToCampaign indicates an expected call of AssignLinksToCampaign.
+func (mr *MockRepoMockRecorder) AssignLinksToCampaign(ctx, tenantID, ids, campaignID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllEventByTenantID", reflect.TypeOf((*MockRepo)(nil).FindAllEventByTenantID), ctx, tenantID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignLinksToCampaign", reflect.TypeOf((*MockRepo)(nil).AssignLinksToCampaign), ctx, tenantID, ids, campaignID)
 }
 
-// FindAllEventByTrackID mocks base method.
-func (m *MockRepo) FindAllEventByTrackID(ctx context.Context, trackID bson.ObjectID) ([]*entity.Event, error) {
+// CacheStats mocks base method.
+func (m *MockRepo) CacheStats() []entity.CacheStats {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllEventByTrackID", ctx, trackID)
-	ret0, _ := ret[0].([]*entity.Event)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CacheStats")
+	ret0, _ := ret[0].([]entity.CacheStats)
+	return ret0
 }
 
-// FindAllEventByTrackID indicates an expected call of FindAllEventByTrackID.
-func (mr *MockRepoMockRecorder) FindAllEventByTrackID(ctx, trackID any) *gomock.Call {
+// CacheStats indicates an expected call of CacheStats.
+func (mr *MockRepoMockRecorder) CacheStats() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllEventByTrackID", reflect.TypeOf((*MockRepo)(nil).FindAllEventByTrackID), ctx, trackID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheStats", reflect.TypeOf((*MockRepo)(nil).CacheStats))
 }
 
-// FindAllLinkbyTenantID mocks base method.
-func (m *MockRepo) FindAllLinkbyTenantID(ctx context.Context, tenantID string) ([]*entity.Link, error) {
+// ClearLinksCampaign mocks base method.
+func (m *MockRepo) ClearLinksCampaign(ctx context.Context, campaignID bson.ObjectID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllLinkbyTenantID", ctx, tenantID)
-	ret0, _ := ret[0].([]*entity.Link)
+	ret := m.ctrl.Call(m, "ClearLinksCampaign", ctx, campaignID)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindAllLinkbyTenantID indicates an expected call of FindAllLinkbyTenantID.
-func (mr *MockRepoMockRecorder) FindAllLinkbyTenantID(ctx, tenantID any) *gomock.Call {
+// ClearLinksCampaign indicates an expected call of ClearLinksCampaign.
+func (mr *MockRepoMockRecorder) ClearLinksCampaign(ctx, campaignID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllLinkbyTenantID", reflect.TypeOf((*MockRepo)(nil).FindAllLinkbyTenantID), ctx, tenantID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearLinksCampaign", reflect.TypeOf((*MockRepo)(nil).ClearLinksCampaign), ctx, campaignID)
 }
 
-// FindLastEventByFingerprint mocks base method.
-func (m *MockRepo) FindLastEventByFingerprint(ctx context.Context, fingerprint string) (*entity.Event, error) {
+// CountBotClicks mocks base method.
+func (m *MockRepo) CountBotClicks(ctx context.Context, linkID bson.ObjectID, from, to time.Time) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindLastEventByFingerprint", ctx, fingerprint)
-	ret0, _ := ret[0].(*entity.Event)
+	ret := m.ctrl.Call(m, "CountBotClicks", ctx, linkID, from, to)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindLastEventByFingerprint indicates an expected call of FindLastEventByFingerprint.
-func (mr *MockRepoMockRecorder) FindLastEventByFingerprint(ctx, fingerprint any) *gomock.Call {
+// CountBotClicks indicates an expected call of CountBotClicks.
+func (mr *MockRepoMockRecorder) CountBotClicks(ctx, linkID, from, to any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLastEventByFingerprint", reflect.TypeOf((*MockRepo)(nil).FindLastEventByFingerprint), ctx, fingerprint)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBotClicks", reflect.TypeOf((*MockRepo)(nil).CountBotClicks), ctx, linkID, from, to)
 }
 
-// FindLinkByID mocks base method.
-func (m *MockRepo) FindLinkByID(arg0 context.Context, arg1 string) (*entity.Link, error) {
+// CountClicksByDimension mocks base method.
+func (m *MockRepo) CountClicksByDimension(ctx context.Context, linkID bson.ObjectID, dimension entity.ClickDimension, from, to time.Time) ([]*entity.ClickBreakdownRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindLinkByID", arg0, arg1)
-	ret0, _ := ret[0].(*entity.Link)
+	ret := m.ctrl.Call(m, "CountClicksByDimension", ctx, linkID, dimension, from, to)
+	ret0, _ := ret[0].([]*entity.ClickBreakdownRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindLinkByID indicates an expected call of FindLinkByID.
-func (mr *MockRepoMockRecorder) FindLinkByID(arg0, arg1 any) *gomock.Call {
+// CountClicksByDimension indicates an expected call of CountClicksByDimension.
+func (mr *MockRepoMockRecorder) CountClicksByDimension(ctx, linkID, dimension, from, to any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinkByID", reflect.TypeOf((*MockRepo)(nil).FindLinkByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountClicksByDimension", reflect.TypeOf((*MockRepo)(nil).CountClicksByDimension), ctx, linkID, dimension, from, to)
 }
 
-// FindLinkByShortID mocks base method.
-func (m *MockRepo) FindLinkByShortID(arg0 context.Context, arg1 string) (*entity.Link, error) {
+// CountClicksByLinks mocks base method.
+func (m *MockRepo) CountClicksByLinks(ctx context.Context, linkIDs []bson.ObjectID, from, to time.Time) ([]*entity.ClickBreakdownRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindLinkByShortID", arg0, arg1)
-	ret0, _ := ret[0].(*entity.Link)
+	ret := m.ctrl.Call(m, "CountClicksByLinks", ctx, linkIDs, from, to)
+	ret0, _ := ret[0].([]*entity.ClickBreakdownRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindLinkByShortID indicates an expected call of FindLinkByShortID.
-func (mr *MockRepoMockRecorder) FindLinkByShortID(arg0, arg1 any) *gomock.Call {
+// CountClicksByLinks indicates an expected call of CountClicksByLinks.
+func (mr *MockRepoMockRecorder) CountClicksByLinks(ctx, linkIDs, from, to any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinkByShortID", reflect.TypeOf((*MockRepo)(nil).FindLinkByShortID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountClicksByLinks", reflect.TypeOf((*MockRepo)(nil).CountClicksByLinks), ctx, linkIDs, from, to)
 }
 
-// FindOrCreateWithPagesByTenantID mocks base method.
-func (m *MockRepo) FindOrCreateWithPagesByTenantID(ctx context.Context, tenantID string) (*entity.TrackingSettingWithPages, error) {
+// CountLinkTracksByVariant mocks base method.
+func (m *MockRepo) CountLinkTracksByVariant(ctx context.Context, linkID bson.ObjectID, from, to time.Time) ([]*entity.BreakdownRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindOrCreateWithPagesByTenantID", ctx, tenantID)
-	ret0, _ := ret[0].(*entity.TrackingSettingWithPages)
+	ret := m.ctrl.Call(m, "CountLinkTracksByVariant", ctx, linkID, from, to)
+	ret0, _ := ret[0].([]*entity.BreakdownRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindOrCreateWithPagesByTenantID indicates an expected call of FindOrCreateWithPagesByTenantID.
-func (mr *MockRepoMockRecorder) FindOrCreateWithPagesByTenantID(ctx, tenantID any) *gomock.Call {
+// CountLinkTracksByVariant indicates an expected call of CountLinkTracksByVariant.
+func (mr *MockRepoMockRecorder) CountLinkTracksByVariant(ctx, linkID, from, to any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreateWithPagesByTenantID", reflect.TypeOf((*MockRepo)(nil).FindOrCreateWithPagesByTenantID), ctx, tenantID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLinkTracksByVariant", reflect.TypeOf((*MockRepo)(nil).CountLinkTracksByVariant), ctx, linkID, from, to)
 }
 
-// FindTrackByID mocks base method.
-func (m *MockRepo) FindTrackByID(ctx context.Context, id bson.ObjectID) (*entity.Track, error) {
+// CountReportClicks mocks base method.
+func (m *MockRepo) CountReportClicks(ctx context.Context, tenantID string, rng ReportRange) ([]*entity.ReportCount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindTrackByID", ctx, id)
-	ret0, _ := ret[0].(*entity.Track)
+	ret := m.ctrl.Call(m, "CountReportClicks", ctx, tenantID, rng)
+	ret0, _ := ret[0].([]*entity.ReportCount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindTrackByID indicates an expected call of FindTrackByID.
-func (mr *MockRepoMockRecorder) FindTrackByID(ctx, id any) *gomock.Call {
+// CountReportClicks indicates an expected call of CountReportClicks.
+func (mr *MockRepoMockRecorder) CountReportClicks(ctx, tenantID, rng any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackByID", reflect.TypeOf((*MockRepo)(nil).FindTrackByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReportClicks", reflect.TypeOf((*MockRepo)(nil).CountReportClicks), ctx, tenantID, rng)
 }
 
-// FindTrackByIDWithThankYouPages mocks base method.
-func (m *MockRepo) FindTrackByIDWithThankYouPages(ctx context.Context, id bson.ObjectID) (*entity.TrackWithThankYouPages, error) {
+// CountReportEvents mocks base method.
+func (m *MockRepo) CountReportEvents(ctx context.Context, tenantID string, rng ReportRange) ([]*entity.ReportCount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindTrackByIDWithThankYouPages", ctx, id)
-	ret0, _ := ret[0].(*entity.TrackWithThankYouPages)
+	ret := m.ctrl.Call(m, "CountReportEvents", ctx, tenantID, rng)
+	ret0, _ := ret[0].([]*entity.ReportCount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindTrackByIDWithThankYouPages indicates an expected call of FindTrackByIDWithThankYouPages.
-func (mr *MockRepoMockRecorder) FindTrackByIDWithThankYouPages(ctx, id any) *gomock.Call {
+// CountReportEvents indicates an expected call of CountReportEvents.
+func (mr *MockRepoMockRecorder) CountReportEvents(ctx, tenantID, rng any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackByIDWithThankYouPages", reflect.TypeOf((*MockRepo)(nil).FindTrackByIDWithThankYouPages), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReportEvents", reflect.TypeOf((*MockRepo)(nil).CountReportEvents), ctx, tenantID, rng)
 }
 
-// FindTrackingSettingByID mocks base method.
-func (m *MockRepo) FindTrackingSettingByID(ctx context.Context, id bson.ObjectID) (*entity.TrackingSetting, error) {
+// CountReportRollups mocks base method.
+func (m *MockRepo) CountReportRollups(ctx context.Context, tenantID string, rng ReportRange, daily bool) ([]*entity.ReportCount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindTrackingSettingByID", ctx, id)
-	ret0, _ := ret[0].(*entity.TrackingSetting)
+	ret := m.ctrl.Call(m, "CountReportRollups", ctx, tenantID, rng, daily)
+	ret0, _ := ret[0].([]*entity.ReportCount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindTrackingSettingByID indicates an expected call of FindTrackingSettingByID.
-func (mr *MockRepoMockRecorder) FindTrackingSettingByID(ctx, id any) *gomock.Call {
+// CountReportRollups indicates an expected call of CountReportRollups.
+func (mr *MockRepoMockRecorder) CountReportRollups(ctx, tenantID, rng, daily any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackingSettingByID", reflect.TypeOf((*MockRepo)(nil).FindTrackingSettingByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReportRollups", reflect.TypeOf((*MockRepo)(nil).CountReportRollups), ctx, tenantID, rng, daily)
 }
 
-// FindTrackingSettingWithPagesByID mocks base method.
-func (m *MockRepo) FindTrackingSettingWithPagesByID(ctx context.Context, trackingSettingID bson.ObjectID) (*entity.TrackingSettingWithPages, error) {
+// CountReportTracks mocks base method.
+func (m *MockRepo) CountReportTracks(ctx context.Context, trackingSettingID bson.ObjectID, rng ReportRange) ([]*entity.ReportCount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindTrackingSettingWithPagesByID", ctx, trackingSettingID)
-	ret0, _ := ret[0].(*entity.TrackingSettingWithPages)
+	ret := m.ctrl.Call(m, "CountReportTracks", ctx, trackingSettingID, rng)
+	ret0, _ := ret[0].([]*entity.ReportCount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindTrackingSettingWithPagesByID indicates an expected call of FindTrackingSettingWithPagesByID.
-func (mr *MockRepoMockRecorder) FindTrackingSettingWithPagesByID(ctx, trackingSettingID any) *gomock.Call {
+// CountReportTracks indicates an expected call of CountReportTracks.
+func (mr *MockRepoMockRecorder) CountReportTracks(ctx, trackingSettingID, rng any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackingSettingWithPagesByID", reflect.TypeOf((*MockRepo)(nil).FindTrackingSettingWithPagesByID), ctx, trackingSettingID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReportTracks", reflect.TypeOf((*MockRepo)(nil).CountReportTracks), ctx, trackingSettingID, rng)
 }
 
-// IsTrackIDExist mocks base method.
-func (m *MockRepo) IsTrackIDExist(ctx context.Context, id bson.ObjectID) (bool, error) {
+// CountTracksByDimension mocks base method.
+func (m *MockRepo) CountTracksByDimension(ctx context.Context, trackingSettingID bson.ObjectID, dimension entity.TrackDimension, from, to time.Time) ([]*entity.BreakdownRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsTrackIDExist", ctx, id)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "CountTracksByDimension", ctx, trackingSettingID, dimension, from, to)
+	ret0, _ := ret[0].([]*entity.BreakdownRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsTrackIDExist indicates an expected call of IsTrackIDExist.
-func (mr *MockRepoMockRecorder) IsTrackIDExist(ctx, id any) *gomock.Call {
+// CountTracksByDimension indicates an expected call of CountTracksByDimension.
+func (mr *MockRepoMockRecorder) CountTracksByDimension(ctx, trackingSettingID, dimension, from, to any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTrackIDExist", reflect.TypeOf((*MockRepo)(nil).IsTrackIDExist), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTracksByDimension", reflect.TypeOf((*MockRepo)(nil).CountTracksByDimension), ctx, trackingSettingID, dimension, from, to)
 }
 
-// IsTrackingSettingIDExist mocks base method.
-func (m *MockRepo) IsTrackingSettingIDExist(ctx context.Context, id bson.ObjectID) (bool, error) {
+// CountTracksByLinks mocks base method.
+func (m *MockRepo) CountTracksByLinks(ctx context.Context, linkIDs []bson.ObjectID, from, to time.Time) ([]*entity.BreakdownRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsTrackingSettingIDExist", ctx, id)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "CountTracksByLinks", ctx, linkIDs, from, to)
+	ret0, _ := ret[0].([]*entity.BreakdownRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsTrackingSettingIDExist indicates an expected call of IsTrackingSettingIDExist.
-func (mr *MockRepoMockRecorder) IsTrackingSettingIDExist(ctx, id any) *gomock.Call {
+// CountTracksByLinks indicates an expected call of CountTracksByLinks.
+func (mr *MockRepoMockRecorder) CountTracksByLinks(ctx, linkIDs, from, to any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTrackingSettingIDExist", reflect.TypeOf((*MockRepo)(nil).IsTrackingSettingIDExist), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTracksByLinks", reflect.TypeOf((*MockRepo)(nil).CountTracksByLinks), ctx, linkIDs, from, to)
 }
 
-// SearchLinks mocks base method.
-func (m *MockRepo) SearchLinks(ctx context.Context, tenantID, keywords string) ([]*entity.Link, error) {
+// CreateClick mocks base method.
+func (m *MockRepo) CreateClick(ctx context.Context, click *entity.Click) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchLinks", ctx, tenantID, keywords)
-	ret0, _ := ret[0].([]*entity.Link)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CreateClick", ctx, click)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// SearchLinks indicates an expected call of SearchLinks.
-func (mr *MockRepoMockRecorder) SearchLinks(ctx, tenantID, keywords any) *gomock.Call {
+// CreateClick indicates an expected call of CreateClick.
+func (mr *MockRepoMockRecorder) CreateClick(ctx, click any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchLinks", reflect.TypeOf((*MockRepo)(nil).SearchLinks), ctx, tenantID, keywords)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClick", reflect.TypeOf((*MockRepo)(nil).CreateClick), ctx, click)
 }
 
-// UpdatePageFieldsAndReturn mocks base method.
-func (m *MockRepo) UpdatePageFieldsAndReturn(arg0 context.Context, arg1 bson.ObjectID, arg2 *entity.ThankYouPage) (*entity.ThankYouPage, error) {
+// CreateDataSubjectRequest mocks base method.
+func (m *MockRepo) CreateDataSubjectRequest(ctx context.Context, request *entity.DataSubjectRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePageFieldsAndReturn", arg0, arg1, arg2)
-	ret0, _ := ret[0].(*entity.ThankYouPage)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CreateDataSubjectRequest", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// UpdatePageFieldsAndReturn indicates an expected call of UpdatePageFieldsAndReturn.
-func (mr *MockRepoMockRecorder) UpdatePageFieldsAndReturn(arg0, arg1, arg2 any) *gomock.Call {
+// CreateDataSubjectRequest indicates an expected call of CreateDataSubjectRequest.
+func (mr *MockRepoMockRecorder) CreateDataSubjectRequest(ctx, request any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePageFieldsAndReturn", reflect.TypeOf((*MockRepo)(nil).UpdatePageFieldsAndReturn), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDataSubjectRequest", reflect.TypeOf((*MockRepo)(nil).CreateDataSubjectRequest), ctx, request)
 }
 
-// MockRepoCloser is a mock of RepoCloser interface.
-type MockRepoCloser struct {
-	ctrl     *gomock.Controller
-	recorder *MockRepoCloserMockRecorder
-	isgomock struct{}
+// CreateEvent mocks base method.
+func (m *MockRepo) CreateEvent(ctx context.Context, event *entity.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// MockRepoCloserMockRecorder is the mock recorder for MockRepoCloser.
-type MockRepoCloserMockRecorder struct {
-	mock *MockRepoCloser
+// CreateEvent indicates an expected call of CreateEvent.
+func (mr *MockRepoMockRecorder) CreateEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockRepo)(nil).CreateEvent), ctx, event)
 }
 
-// NewMockRepoCloser creates a new mock instance.
-func NewMockRepoCloser(ctrl *gomock.Controller) *MockRepoCloser {
-	mock := &MockRepoCloser{ctrl: ctrl}
-	mock.recorder = &MockRepoCloserMockRecorder{mock}
-	return mock
+// CreateFunnel mocks base method.
+func (m *MockRepo) CreateFunnel(ctx context.Context, funnel *entity.Funnel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFunnel", ctx, funnel)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockRepoCloser) EXPECT() *MockRepoCloserMockRecorder {
-	return m.recorder
+// CreateFunnel indicates an expected call of CreateFunnel.
+func (mr *MockRepoMockRecorder) CreateFunnel(ctx, funnel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFunnel", reflect.TypeOf((*MockRepo)(nil).CreateFunnel), ctx, funnel)
 }
 
-// Close mocks base method.
-func (m *MockRepoCloser) Close(arg0 context.Context) error {
+// CreateIdentity mocks base method.
+func (m *MockRepo) CreateIdentity(ctx context.Context, identity *entity.Identity) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Close", arg0)
+	ret := m.ctrl.Call(m, "CreateIdentity", ctx, identity)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Close indicates an expected call of Close.
-func (mr *MockRepoCloserMockRecorder) Close(arg0 any) *gomock.Call {
+// CreateIdentity indicates an expected call of CreateIdentity.
+func (mr *MockRepoMockRecorder) CreateIdentity(ctx, identity any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRepoCloser)(nil).Close), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdentity", reflect.TypeOf((*MockRepo)(nil).CreateIdentity), ctx, identity)
 }
 
-// CreateEvent mocks base method.
-func (m *MockRepoCloser) CreateEvent(ctx context.Context, event *entity.Event) error {
+// CreateLink mocks base method.
+func (m *MockRepo) CreateLink(arg0 context.Context, arg1 *entity.Link) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEvent", ctx, event)
+	ret := m.ctrl.Call(m, "CreateLink", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateEvent indicates an expected call of CreateEvent.
-func (mr *MockRepoCloserMockRecorder) CreateEvent(ctx, event any) *gomock.Call {
+// CreateLink indicates an expected call of CreateLink.
+func (mr *MockRepoMockRecorder) CreateLink(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockRepoCloser)(nil).CreateEvent), ctx, event)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLink", reflect.TypeOf((*MockRepo)(nil).CreateLink), arg0, arg1)
 }
 
-// CreateLink mocks base method.
-func (m *MockRepoCloser) CreateLink(arg0 context.Context, arg1 *entity.Link) error {
+// CreateLinkCampaign mocks base method.
+func (m *MockRepo) CreateLinkCampaign(ctx context.Context, campaign *entity.LinkCampaign) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateLink", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateLinkCampaign", ctx, campaign)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateLink indicates an expected call of CreateLink.
-func (mr *MockRepoCloserMockRecorder) CreateLink(arg0, arg1 any) *gomock.Call {
+// CreateLinkCampaign indicates an expected call of CreateLinkCampaign.
+func (mr *MockRepoMockRecorder) CreateLinkCampaign(ctx, campaign any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLink", reflect.TypeOf((*MockRepoCloser)(nil).CreateLink), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLinkCampaign", reflect.TypeOf((*MockRepo)(nil).CreateLinkCampaign), ctx, campaign)
 }
 
 // CreatePage mocks base method.
-func (m *MockRepoCloser) CreatePage(arg0 context.Context, arg1 *entity.ThankYouPage) error {
+func (m *MockRepo) CreatePage(arg0 context.Context, arg1 *entity.ThankYouPage) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreatePage", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -398,13 +389,13 @@ func (m *MockRepoCloser) CreatePage(arg0 context.Context, arg1 *entity.ThankYouP
 }
 
 // CreatePage indicates an expected call of CreatePage.
-func (mr *MockRepoCloserMockRecorder) CreatePage(arg0, arg1 any) *gomock.Call {
+func (mr *MockRepoMockRecorder) CreatePage(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePage", reflect.TypeOf((*MockRepoCloser)(nil).CreatePage), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePage", reflect.TypeOf((*MockRepo)(nil).CreatePage), arg0, arg1)
 }
 
 // CreateTrack mocks base method.
-func (m *MockRepoCloser) CreateTrack(ctx context.Context, track *entity.Track) error {
+func (m *MockRepo) CreateTrack(ctx context.Context, track *entity.Track) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateTrack", ctx, track)
 	ret0, _ := ret[0].(error)
@@ -412,69 +403,1693 @@ func (m *MockRepoCloser) CreateTrack(ctx context.Context, track *entity.Track) e
 }
 
 // CreateTrack indicates an expected call of CreateTrack.
-func (mr *MockRepoCloserMockRecorder) CreateTrack(ctx, track any) *gomock.Call {
+func (mr *MockRepoMockRecorder) CreateTrack(ctx, track any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrack", reflect.TypeOf((*MockRepoCloser)(nil).CreateTrack), ctx, track)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrack", reflect.TypeOf((*MockRepo)(nil).CreateTrack), ctx, track)
 }
 
-// FindAllEventByTenantID mocks base method.
-func (m *MockRepoCloser) FindAllEventByTenantID(ctx context.Context, tenantID string) ([]*entity.Event, error) {
+// DeleteClicksByIDs mocks base method.
+func (m *MockRepo) DeleteClicksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllEventByTenantID", ctx, tenantID)
-	ret0, _ := ret[0].([]*entity.Event)
+	ret := m.ctrl.Call(m, "DeleteClicksByIDs", ctx, ids)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindAllEventByTenantID indicates an expected call of FindAllEventByTenantID.
-func (mr *MockRepoCloserMockRecorder) FindAllEventByTenantID(ctx, tenantID any) *gomock.Call {
+// DeleteClicksByIDs indicates an expected call of DeleteClicksByIDs.
+func (mr *MockRepoMockRecorder) DeleteClicksByIDs(ctx, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllEventByTenantID", reflect.TypeOf((*MockRepoCloser)(nil).FindAllEventByTenantID), ctx, tenantID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClicksByIDs", reflect.TypeOf((*MockRepo)(nil).DeleteClicksByIDs), ctx, ids)
 }
 
-// FindAllEventByTrackID mocks base method.
-func (m *MockRepoCloser) FindAllEventByTrackID(ctx context.Context, trackID bson.ObjectID) ([]*entity.Event, error) {
+// DeleteEventsByIDs mocks base method.
+func (m *MockRepo) DeleteEventsByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllEventByTrackID", ctx, trackID)
-	ret0, _ := ret[0].([]*entity.Event)
+	ret := m.ctrl.Call(m, "DeleteEventsByIDs", ctx, ids)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindAllEventByTrackID indicates an expected call of FindAllEventByTrackID.
-func (mr *MockRepoCloserMockRecorder) FindAllEventByTrackID(ctx, trackID any) *gomock.Call {
+// DeleteEventsByIDs indicates an expected call of DeleteEventsByIDs.
+func (mr *MockRepoMockRecorder) DeleteEventsByIDs(ctx, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllEventByTrackID", reflect.TypeOf((*MockRepoCloser)(nil).FindAllEventByTrackID), ctx, trackID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventsByIDs", reflect.TypeOf((*MockRepo)(nil).DeleteEventsByIDs), ctx, ids)
 }
 
-// FindAllLinkbyTenantID mocks base method.
-func (m *MockRepoCloser) FindAllLinkbyTenantID(ctx context.Context, tenantID string) ([]*entity.Link, error) {
+// DeleteFunnel mocks base method.
+func (m *MockRepo) DeleteFunnel(ctx context.Context, id bson.ObjectID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllLinkbyTenantID", ctx, tenantID)
-	ret0, _ := ret[0].([]*entity.Link)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "DeleteFunnel", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// FindAllLinkbyTenantID indicates an expected call of FindAllLinkbyTenantID.
-func (mr *MockRepoCloserMockRecorder) FindAllLinkbyTenantID(ctx, tenantID any) *gomock.Call {
+// DeleteFunnel indicates an expected call of DeleteFunnel.
+func (mr *MockRepoMockRecorder) DeleteFunnel(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllLinkbyTenantID", reflect.TypeOf((*MockRepoCloser)(nil).FindAllLinkbyTenantID), ctx, tenantID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFunnel", reflect.TypeOf((*MockRepo)(nil).DeleteFunnel), ctx, id)
 }
 
-// FindLastEventByFingerprint mocks base method.
-func (m *MockRepoCloser) FindLastEventByFingerprint(ctx context.Context, fingerprint string) (*entity.Event, error) {
+// DeleteIdentity mocks base method.
+func (m *MockRepo) DeleteIdentity(ctx context.Context, id bson.ObjectID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindLastEventByFingerprint", ctx, fingerprint)
-	ret0, _ := ret[0].(*entity.Event)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "DeleteIdentity", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// FindLastEventByFingerprint indicates an expected call of FindLastEventByFingerprint.
+// DeleteIdentity indicates an expected call of DeleteIdentity.
+func (mr *MockRepoMockRecorder) DeleteIdentity(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdentity", reflect.TypeOf((*MockRepo)(nil).DeleteIdentity), ctx, id)
+}
+
+// DeleteLinkCampaign mocks base method.
+func (m *MockRepo) DeleteLinkCampaign(ctx context.Context, id bson.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLinkCampaign", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLinkCampaign indicates an expected call of DeleteLinkCampaign.
+func (mr *MockRepoMockRecorder) DeleteLinkCampaign(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLinkCampaign", reflect.TypeOf((*MockRepo)(nil).DeleteLinkCampaign), ctx, id)
+}
+
+// DeleteTracksByIDs mocks base method.
+func (m *MockRepo) DeleteTracksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTracksByIDs", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTracksByIDs indicates an expected call of DeleteTracksByIDs.
+func (mr *MockRepoMockRecorder) DeleteTracksByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTracksByIDs", reflect.TypeOf((*MockRepo)(nil).DeleteTracksByIDs), ctx, ids)
+}
+
+// FindAllEventByTenantID mocks base method.
+func (m *MockRepo) FindAllEventByTenantID(ctx context.Context, tenantID string) ([]*entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllEventByTenantID", ctx, tenantID)
+	ret0, _ := ret[0].([]*entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllEventByTenantID indicates an expected call of FindAllEventByTenantID.
+func (mr *MockRepoMockRecorder) FindAllEventByTenantID(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllEventByTenantID", reflect.TypeOf((*MockRepo)(nil).FindAllEventByTenantID), ctx, tenantID)
+}
+
+// FindAllEventByTrackID mocks base method.
+func (m *MockRepo) FindAllEventByTrackID(ctx context.Context, trackID bson.ObjectID) ([]*entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllEventByTrackID", ctx, trackID)
+	ret0, _ := ret[0].([]*entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllEventByTrackID indicates an expected call of FindAllEventByTrackID.
+func (mr *MockRepoMockRecorder) FindAllEventByTrackID(ctx, trackID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllEventByTrackID", reflect.TypeOf((*MockRepo)(nil).FindAllEventByTrackID), ctx, trackID)
+}
+
+// FindAllLinkbyTenantID mocks base method.
+func (m *MockRepo) FindAllLinkbyTenantID(ctx context.Context, tenantID string) ([]*entity.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllLinkbyTenantID", ctx, tenantID)
+	ret0, _ := ret[0].([]*entity.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllLinkbyTenantID indicates an expected call of FindAllLinkbyTenantID.
+func (mr *MockRepoMockRecorder) FindAllLinkbyTenantID(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllLinkbyTenantID", reflect.TypeOf((*MockRepo)(nil).FindAllLinkbyTenantID), ctx, tenantID)
+}
+
+// FindArchivableDocuments mocks base method.
+func (m *MockRepo) FindArchivableDocuments(ctx context.Context, collection string, before time.Time, limit int64) ([]bson.Raw, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindArchivableDocuments", ctx, collection, before, limit)
+	ret0, _ := ret[0].([]bson.Raw)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindArchivableDocuments indicates an expected call of FindArchivableDocuments.
+func (mr *MockRepoMockRecorder) FindArchivableDocuments(ctx, collection, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindArchivableDocuments", reflect.TypeOf((*MockRepo)(nil).FindArchivableDocuments), ctx, collection, before, limit)
+}
+
+// FindClicksByTrackIDs mocks base method.
+func (m *MockRepo) FindClicksByTrackIDs(ctx context.Context, tenantID string, trackIDs []string) ([]*entity.Click, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindClicksByTrackIDs", ctx, tenantID, trackIDs)
+	ret0, _ := ret[0].([]*entity.Click)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindClicksByTrackIDs indicates an expected call of FindClicksByTrackIDs.
+func (mr *MockRepoMockRecorder) FindClicksByTrackIDs(ctx, tenantID, trackIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindClicksByTrackIDs", reflect.TypeOf((*MockRepo)(nil).FindClicksByTrackIDs), ctx, tenantID, trackIDs)
+}
+
+// FindDailyRollupTimeZones mocks base method.
+func (m *MockRepo) FindDailyRollupTimeZones(ctx context.Context, tenantID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDailyRollupTimeZones", ctx, tenantID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDailyRollupTimeZones indicates an expected call of FindDailyRollupTimeZones.
+func (mr *MockRepoMockRecorder) FindDailyRollupTimeZones(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDailyRollupTimeZones", reflect.TypeOf((*MockRepo)(nil).FindDailyRollupTimeZones), ctx, tenantID)
+}
+
+// FindDataSubjectRequestsByTrackingSettingID mocks base method.
+func (m *MockRepo) FindDataSubjectRequestsByTrackingSettingID(ctx context.Context, trackingSettingID bson.ObjectID) ([]*entity.DataSubjectRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDataSubjectRequestsByTrackingSettingID", ctx, trackingSettingID)
+	ret0, _ := ret[0].([]*entity.DataSubjectRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDataSubjectRequestsByTrackingSettingID indicates an expected call of FindDataSubjectRequestsByTrackingSettingID.
+func (mr *MockRepoMockRecorder) FindDataSubjectRequestsByTrackingSettingID(ctx, trackingSettingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDataSubjectRequestsByTrackingSettingID", reflect.TypeOf((*MockRepo)(nil).FindDataSubjectRequestsByTrackingSettingID), ctx, trackingSettingID)
+}
+
+// FindEventsBySubject mocks base method.
+func (m *MockRepo) FindEventsBySubject(ctx context.Context, tenantID string, trackIDs, fingerprints []string) ([]*entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindEventsBySubject", ctx, tenantID, trackIDs, fingerprints)
+	ret0, _ := ret[0].([]*entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEventsBySubject indicates an expected call of FindEventsBySubject.
+func (mr *MockRepoMockRecorder) FindEventsBySubject(ctx, tenantID, trackIDs, fingerprints any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEventsBySubject", reflect.TypeOf((*MockRepo)(nil).FindEventsBySubject), ctx, tenantID, trackIDs, fingerprints)
+}
+
+// FindFunnelByID mocks base method.
+func (m *MockRepo) FindFunnelByID(ctx context.Context, id bson.ObjectID) (*entity.Funnel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFunnelByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Funnel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFunnelByID indicates an expected call of FindFunnelByID.
+func (mr *MockRepoMockRecorder) FindFunnelByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFunnelByID", reflect.TypeOf((*MockRepo)(nil).FindFunnelByID), ctx, id)
+}
+
+// FindFunnelJourneys mocks base method.
+func (m *MockRepo) FindFunnelJourneys(ctx context.Context, tenantID string, from, to time.Time) ([]*entity.FunnelJourney, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFunnelJourneys", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]*entity.FunnelJourney)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFunnelJourneys indicates an expected call of FindFunnelJourneys.
+func (mr *MockRepoMockRecorder) FindFunnelJourneys(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFunnelJourneys", reflect.TypeOf((*MockRepo)(nil).FindFunnelJourneys), ctx, tenantID, from, to)
+}
+
+// FindFunnelsByTenantID mocks base method.
+func (m *MockRepo) FindFunnelsByTenantID(ctx context.Context, tenantID string) ([]*entity.Funnel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFunnelsByTenantID", ctx, tenantID)
+	ret0, _ := ret[0].([]*entity.Funnel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFunnelsByTenantID indicates an expected call of FindFunnelsByTenantID.
+func (mr *MockRepoMockRecorder) FindFunnelsByTenantID(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFunnelsByTenantID", reflect.TypeOf((*MockRepo)(nil).FindFunnelsByTenantID), ctx, tenantID)
+}
+
+// FindIdentitiesByIdentifier mocks base method.
+func (m *MockRepo) FindIdentitiesByIdentifier(ctx context.Context, trackingSettingID bson.ObjectID, identifier entity.Identifier) ([]*entity.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdentitiesByIdentifier", ctx, trackingSettingID, identifier)
+	ret0, _ := ret[0].([]*entity.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdentitiesByIdentifier indicates an expected call of FindIdentitiesByIdentifier.
+func (mr *MockRepoMockRecorder) FindIdentitiesByIdentifier(ctx, trackingSettingID, identifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentitiesByIdentifier", reflect.TypeOf((*MockRepo)(nil).FindIdentitiesByIdentifier), ctx, trackingSettingID, identifier)
+}
+
+// FindIdentitiesByIdentifiers mocks base method.
+func (m *MockRepo) FindIdentitiesByIdentifiers(ctx context.Context, trackingSettingID bson.ObjectID, identifiers []entity.Identifier) ([]*entity.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdentitiesByIdentifiers", ctx, trackingSettingID, identifiers)
+	ret0, _ := ret[0].([]*entity.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdentitiesByIdentifiers indicates an expected call of FindIdentitiesByIdentifiers.
+func (mr *MockRepoMockRecorder) FindIdentitiesByIdentifiers(ctx, trackingSettingID, identifiers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentitiesByIdentifiers", reflect.TypeOf((*MockRepo)(nil).FindIdentitiesByIdentifiers), ctx, trackingSettingID, identifiers)
+}
+
+// FindIdentityByID mocks base method.
+func (m *MockRepo) FindIdentityByID(ctx context.Context, id bson.ObjectID) (*entity.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdentityByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdentityByID indicates an expected call of FindIdentityByID.
+func (mr *MockRepoMockRecorder) FindIdentityByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentityByID", reflect.TypeOf((*MockRepo)(nil).FindIdentityByID), ctx, id)
+}
+
+// FindLastEventByFingerprint mocks base method.
+func (m *MockRepo) FindLastEventByFingerprint(ctx context.Context, fingerprint string) (*entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLastEventByFingerprint", ctx, fingerprint)
+	ret0, _ := ret[0].(*entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLastEventByFingerprint indicates an expected call of FindLastEventByFingerprint.
+func (mr *MockRepoMockRecorder) FindLastEventByFingerprint(ctx, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLastEventByFingerprint", reflect.TypeOf((*MockRepo)(nil).FindLastEventByFingerprint), ctx, fingerprint)
+}
+
+// FindLastEventByTrackIDs mocks base method.
+func (m *MockRepo) FindLastEventByTrackIDs(ctx context.Context, trackIDs []string) (*entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLastEventByTrackIDs", ctx, trackIDs)
+	ret0, _ := ret[0].(*entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLastEventByTrackIDs indicates an expected call of FindLastEventByTrackIDs.
+func (mr *MockRepoMockRecorder) FindLastEventByTrackIDs(ctx, trackIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLastEventByTrackIDs", reflect.TypeOf((*MockRepo)(nil).FindLastEventByTrackIDs), ctx, trackIDs)
+}
+
+// FindLinkByDomainAndShortID mocks base method.
+func (m *MockRepo) FindLinkByDomainAndShortID(ctx context.Context, domain, shortID string) (*entity.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLinkByDomainAndShortID", ctx, domain, shortID)
+	ret0, _ := ret[0].(*entity.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLinkByDomainAndShortID indicates an expected call of FindLinkByDomainAndShortID.
+func (mr *MockRepoMockRecorder) FindLinkByDomainAndShortID(ctx, domain, shortID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinkByDomainAndShortID", reflect.TypeOf((*MockRepo)(nil).FindLinkByDomainAndShortID), ctx, domain, shortID)
+}
+
+// FindLinkByID mocks base method.
+func (m *MockRepo) FindLinkByID(arg0 context.Context, arg1 string) (*entity.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLinkByID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLinkByID indicates an expected call of FindLinkByID.
+func (mr *MockRepoMockRecorder) FindLinkByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinkByID", reflect.TypeOf((*MockRepo)(nil).FindLinkByID), arg0, arg1)
+}
+
+// FindLinkByShortID mocks base method.
+func (m *MockRepo) FindLinkByShortID(arg0 context.Context, arg1 string) (*entity.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLinkByShortID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLinkByShortID indicates an expected call of FindLinkByShortID.
+func (mr *MockRepoMockRecorder) FindLinkByShortID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinkByShortID", reflect.TypeOf((*MockRepo)(nil).FindLinkByShortID), arg0, arg1)
+}
+
+// FindLinkCampaignByID mocks base method.
+func (m *MockRepo) FindLinkCampaignByID(ctx context.Context, id bson.ObjectID) (*entity.LinkCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLinkCampaignByID", ctx, id)
+	ret0, _ := ret[0].(*entity.LinkCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLinkCampaignByID indicates an expected call of FindLinkCampaignByID.
+func (mr *MockRepoMockRecorder) FindLinkCampaignByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinkCampaignByID", reflect.TypeOf((*MockRepo)(nil).FindLinkCampaignByID), ctx, id)
+}
+
+// FindLinkCampaignsByTenantID mocks base method.
+func (m *MockRepo) FindLinkCampaignsByTenantID(ctx context.Context, tenantID string) ([]*entity.LinkCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLinkCampaignsByTenantID", ctx, tenantID)
+	ret0, _ := ret[0].([]*entity.LinkCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLinkCampaignsByTenantID indicates an expected call of FindLinkCampaignsByTenantID.
+func (mr *MockRepoMockRecorder) FindLinkCampaignsByTenantID(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinkCampaignsByTenantID", reflect.TypeOf((*MockRepo)(nil).FindLinkCampaignsByTenantID), ctx, tenantID)
+}
+
+// FindOrCreateWithPagesByTenantID mocks base method.
+func (m *MockRepo) FindOrCreateWithPagesByTenantID(ctx context.Context, tenantID string) (*entity.TrackingSettingWithPages, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrCreateWithPagesByTenantID", ctx, tenantID)
+	ret0, _ := ret[0].(*entity.TrackingSettingWithPages)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrCreateWithPagesByTenantID indicates an expected call of FindOrCreateWithPagesByTenantID.
+func (mr *MockRepoMockRecorder) FindOrCreateWithPagesByTenantID(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreateWithPagesByTenantID", reflect.TypeOf((*MockRepo)(nil).FindOrCreateWithPagesByTenantID), ctx, tenantID)
+}
+
+// FindRollupWatermark mocks base method.
+func (m *MockRepo) FindRollupWatermark(ctx context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRollupWatermark", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRollupWatermark indicates an expected call of FindRollupWatermark.
+func (mr *MockRepoMockRecorder) FindRollupWatermark(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRollupWatermark", reflect.TypeOf((*MockRepo)(nil).FindRollupWatermark), ctx)
+}
+
+// FindTenantIDByLinkDomain mocks base method.
+func (m *MockRepo) FindTenantIDByLinkDomain(ctx context.Context, domain string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTenantIDByLinkDomain", ctx, domain)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTenantIDByLinkDomain indicates an expected call of FindTenantIDByLinkDomain.
+func (mr *MockRepoMockRecorder) FindTenantIDByLinkDomain(ctx, domain any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTenantIDByLinkDomain", reflect.TypeOf((*MockRepo)(nil).FindTenantIDByLinkDomain), ctx, domain)
+}
+
+// FindTrackByID mocks base method.
+func (m *MockRepo) FindTrackByID(ctx context.Context, id bson.ObjectID) (*entity.Track, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrackByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Track)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrackByID indicates an expected call of FindTrackByID.
+func (mr *MockRepoMockRecorder) FindTrackByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackByID", reflect.TypeOf((*MockRepo)(nil).FindTrackByID), ctx, id)
+}
+
+// FindTrackByIDWithThankYouPages mocks base method.
+func (m *MockRepo) FindTrackByIDWithThankYouPages(ctx context.Context, id bson.ObjectID) (*entity.TrackWithThankYouPages, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrackByIDWithThankYouPages", ctx, id)
+	ret0, _ := ret[0].(*entity.TrackWithThankYouPages)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrackByIDWithThankYouPages indicates an expected call of FindTrackByIDWithThankYouPages.
+func (mr *MockRepoMockRecorder) FindTrackByIDWithThankYouPages(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackByIDWithThankYouPages", reflect.TypeOf((*MockRepo)(nil).FindTrackByIDWithThankYouPages), ctx, id)
+}
+
+// FindTrackingSettingByID mocks base method.
+func (m *MockRepo) FindTrackingSettingByID(ctx context.Context, id bson.ObjectID) (*entity.TrackingSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrackingSettingByID", ctx, id)
+	ret0, _ := ret[0].(*entity.TrackingSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrackingSettingByID indicates an expected call of FindTrackingSettingByID.
+func (mr *MockRepoMockRecorder) FindTrackingSettingByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackingSettingByID", reflect.TypeOf((*MockRepo)(nil).FindTrackingSettingByID), ctx, id)
+}
+
+// FindTrackingSettingWithPagesByID mocks base method.
+func (m *MockRepo) FindTrackingSettingWithPagesByID(ctx context.Context, trackingSettingID bson.ObjectID) (*entity.TrackingSettingWithPages, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrackingSettingWithPagesByID", ctx, trackingSettingID)
+	ret0, _ := ret[0].(*entity.TrackingSettingWithPages)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrackingSettingWithPagesByID indicates an expected call of FindTrackingSettingWithPagesByID.
+func (mr *MockRepoMockRecorder) FindTrackingSettingWithPagesByID(ctx, trackingSettingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackingSettingWithPagesByID", reflect.TypeOf((*MockRepo)(nil).FindTrackingSettingWithPagesByID), ctx, trackingSettingID)
+}
+
+// FindTrackingSettingWithPagesByTenantID mocks base method.
+func (m *MockRepo) FindTrackingSettingWithPagesByTenantID(ctx context.Context, tenantID string) (*entity.TrackingSettingWithPages, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrackingSettingWithPagesByTenantID", ctx, tenantID)
+	ret0, _ := ret[0].(*entity.TrackingSettingWithPages)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrackingSettingWithPagesByTenantID indicates an expected call of FindTrackingSettingWithPagesByTenantID.
+func (mr *MockRepoMockRecorder) FindTrackingSettingWithPagesByTenantID(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackingSettingWithPagesByTenantID", reflect.TypeOf((*MockRepo)(nil).FindTrackingSettingWithPagesByTenantID), ctx, tenantID)
+}
+
+// FindTrackingSettingsByDomains mocks base method.
+func (m *MockRepo) FindTrackingSettingsByDomains(ctx context.Context, domains []string) ([]*entity.TrackingSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrackingSettingsByDomains", ctx, domains)
+	ret0, _ := ret[0].([]*entity.TrackingSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrackingSettingsByDomains indicates an expected call of FindTrackingSettingsByDomains.
+func (mr *MockRepoMockRecorder) FindTrackingSettingsByDomains(ctx, domains any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackingSettingsByDomains", reflect.TypeOf((*MockRepo)(nil).FindTrackingSettingsByDomains), ctx, domains)
+}
+
+// FindTracksBySubject mocks base method.
+func (m *MockRepo) FindTracksBySubject(ctx context.Context, trackingSettingID bson.ObjectID, ids []bson.ObjectID, endUserIDs []string) ([]*entity.Track, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTracksBySubject", ctx, trackingSettingID, ids, endUserIDs)
+	ret0, _ := ret[0].([]*entity.Track)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTracksBySubject indicates an expected call of FindTracksBySubject.
+func (mr *MockRepoMockRecorder) FindTracksBySubject(ctx, trackingSettingID, ids, endUserIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTracksBySubject", reflect.TypeOf((*MockRepo)(nil).FindTracksBySubject), ctx, trackingSettingID, ids, endUserIDs)
+}
+
+// IncrementLinkClicks mocks base method.
+func (m *MockRepo) IncrementLinkClicks(ctx context.Context, id bson.ObjectID, maxClicks int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementLinkClicks", ctx, id, maxClicks)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementLinkClicks indicates an expected call of IncrementLinkClicks.
+func (mr *MockRepoMockRecorder) IncrementLinkClicks(ctx, id, maxClicks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementLinkClicks", reflect.TypeOf((*MockRepo)(nil).IncrementLinkClicks), ctx, id, maxClicks)
+}
+
+// IsDomainRegistered mocks base method.
+func (m *MockRepo) IsDomainRegistered(ctx context.Context, domains []string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDomainRegistered", ctx, domains)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDomainRegistered indicates an expected call of IsDomainRegistered.
+func (mr *MockRepoMockRecorder) IsDomainRegistered(ctx, domains any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDomainRegistered", reflect.TypeOf((*MockRepo)(nil).IsDomainRegistered), ctx, domains)
+}
+
+// IsTrackIDExist mocks base method.
+func (m *MockRepo) IsTrackIDExist(ctx context.Context, id bson.ObjectID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTrackIDExist", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTrackIDExist indicates an expected call of IsTrackIDExist.
+func (mr *MockRepoMockRecorder) IsTrackIDExist(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTrackIDExist", reflect.TypeOf((*MockRepo)(nil).IsTrackIDExist), ctx, id)
+}
+
+// IsTrackingSettingIDExist mocks base method.
+func (m *MockRepo) IsTrackingSettingIDExist(ctx context.Context, id bson.ObjectID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTrackingSettingIDExist", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTrackingSettingIDExist indicates an expected call of IsTrackingSettingIDExist.
+func (mr *MockRepoMockRecorder) IsTrackingSettingIDExist(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTrackingSettingIDExist", reflect.TypeOf((*MockRepo)(nil).IsTrackingSettingIDExist), ctx, id)
+}
+
+// MarkDocumentsArchived mocks base method.
+func (m *MockRepo) MarkDocumentsArchived(ctx context.Context, collection string, ids []bson.ObjectID, archivedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDocumentsArchived", ctx, collection, ids, archivedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDocumentsArchived indicates an expected call of MarkDocumentsArchived.
+func (mr *MockRepoMockRecorder) MarkDocumentsArchived(ctx, collection, ids, archivedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDocumentsArchived", reflect.TypeOf((*MockRepo)(nil).MarkDocumentsArchived), ctx, collection, ids, archivedAt)
+}
+
+// RemoveLinks mocks base method.
+func (m *MockRepo) RemoveLinks(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveLinks", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveLinks indicates an expected call of RemoveLinks.
+func (mr *MockRepoMockRecorder) RemoveLinks(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveLinks", reflect.TypeOf((*MockRepo)(nil).RemoveLinks), ctx, ids)
+}
+
+// RemoveReportRollups mocks base method.
+func (m *MockRepo) RemoveReportRollups(ctx context.Context, granularity entity.ReportGranularity, tenantID string, from, to time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReportRollups", ctx, granularity, tenantID, from, to)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveReportRollups indicates an expected call of RemoveReportRollups.
+func (mr *MockRepoMockRecorder) RemoveReportRollups(ctx, granularity, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReportRollups", reflect.TypeOf((*MockRepo)(nil).RemoveReportRollups), ctx, granularity, tenantID, from, to)
+}
+
+// RestoreDocuments mocks base method.
+func (m *MockRepo) RestoreDocuments(ctx context.Context, collection string, documents []bson.Raw) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreDocuments", ctx, collection, documents)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreDocuments indicates an expected call of RestoreDocuments.
+func (mr *MockRepoMockRecorder) RestoreDocuments(ctx, collection, documents any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDocuments", reflect.TypeOf((*MockRepo)(nil).RestoreDocuments), ctx, collection, documents)
+}
+
+// RollupReportDays mocks base method.
+func (m *MockRepo) RollupReportDays(ctx context.Context, tenantID string, from, to time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupReportDays", ctx, tenantID, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollupReportDays indicates an expected call of RollupReportDays.
+func (mr *MockRepoMockRecorder) RollupReportDays(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupReportDays", reflect.TypeOf((*MockRepo)(nil).RollupReportDays), ctx, tenantID, from, to)
+}
+
+// RollupReportHours mocks base method.
+func (m *MockRepo) RollupReportHours(ctx context.Context, from, to time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupReportHours", ctx, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollupReportHours indicates an expected call of RollupReportHours.
+func (mr *MockRepoMockRecorder) RollupReportHours(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupReportHours", reflect.TypeOf((*MockRepo)(nil).RollupReportHours), ctx, from, to)
+}
+
+// SearchLinks mocks base method.
+func (m *MockRepo) SearchLinks(ctx context.Context, tenantID string, search entity.LinkSearch) (*entity.LinkSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchLinks", ctx, tenantID, search)
+	ret0, _ := ret[0].(*entity.LinkSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchLinks indicates an expected call of SearchLinks.
+func (mr *MockRepoMockRecorder) SearchLinks(ctx, tenantID, search any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchLinks", reflect.TypeOf((*MockRepo)(nil).SearchLinks), ctx, tenantID, search)
+}
+
+// SetLinkClicks mocks base method.
+func (m *MockRepo) SetLinkClicks(ctx context.Context, id bson.ObjectID, clicks int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLinkClicks", ctx, id, clicks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLinkClicks indicates an expected call of SetLinkClicks.
+func (mr *MockRepoMockRecorder) SetLinkClicks(ctx, id, clicks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLinkClicks", reflect.TypeOf((*MockRepo)(nil).SetLinkClicks), ctx, id, clicks)
+}
+
+// UpdateDataSubjectRequest mocks base method.
+func (m *MockRepo) UpdateDataSubjectRequest(ctx context.Context, request *entity.DataSubjectRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDataSubjectRequest", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDataSubjectRequest indicates an expected call of UpdateDataSubjectRequest.
+func (mr *MockRepoMockRecorder) UpdateDataSubjectRequest(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDataSubjectRequest", reflect.TypeOf((*MockRepo)(nil).UpdateDataSubjectRequest), ctx, request)
+}
+
+// UpdateFunnel mocks base method.
+func (m *MockRepo) UpdateFunnel(ctx context.Context, funnel *entity.Funnel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFunnel", ctx, funnel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFunnel indicates an expected call of UpdateFunnel.
+func (mr *MockRepoMockRecorder) UpdateFunnel(ctx, funnel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFunnel", reflect.TypeOf((*MockRepo)(nil).UpdateFunnel), ctx, funnel)
+}
+
+// UpdateIdentity mocks base method.
+func (m *MockRepo) UpdateIdentity(ctx context.Context, identity *entity.Identity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIdentity indicates an expected call of UpdateIdentity.
+func (mr *MockRepoMockRecorder) UpdateIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIdentity", reflect.TypeOf((*MockRepo)(nil).UpdateIdentity), ctx, identity)
+}
+
+// UpdateLinkCampaign mocks base method.
+func (m *MockRepo) UpdateLinkCampaign(ctx context.Context, campaign *entity.LinkCampaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkCampaign", ctx, campaign)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkCampaign indicates an expected call of UpdateLinkCampaign.
+func (mr *MockRepoMockRecorder) UpdateLinkCampaign(ctx, campaign any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkCampaign", reflect.TypeOf((*MockRepo)(nil).UpdateLinkCampaign), ctx, campaign)
+}
+
+// UpdateLinkDetails mocks base method.
+func (m *MockRepo) UpdateLinkDetails(ctx context.Context, link *entity.Link) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkDetails", ctx, link)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkDetails indicates an expected call of UpdateLinkDetails.
+func (mr *MockRepoMockRecorder) UpdateLinkDetails(ctx, link any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkDetails", reflect.TypeOf((*MockRepo)(nil).UpdateLinkDetails), ctx, link)
+}
+
+// UpdateLinkLifecycle mocks base method.
+func (m *MockRepo) UpdateLinkLifecycle(ctx context.Context, id bson.ObjectID, lifecycle entity.LinkLifecycle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkLifecycle", ctx, id, lifecycle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkLifecycle indicates an expected call of UpdateLinkLifecycle.
+func (mr *MockRepoMockRecorder) UpdateLinkLifecycle(ctx, id, lifecycle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkLifecycle", reflect.TypeOf((*MockRepo)(nil).UpdateLinkLifecycle), ctx, id, lifecycle)
+}
+
+// UpdateLinkRedirect mocks base method.
+func (m *MockRepo) UpdateLinkRedirect(ctx context.Context, id bson.ObjectID, redirect entity.LinkRedirect) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkRedirect", ctx, id, redirect)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkRedirect indicates an expected call of UpdateLinkRedirect.
+func (mr *MockRepoMockRecorder) UpdateLinkRedirect(ctx, id, redirect any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkRedirect", reflect.TypeOf((*MockRepo)(nil).UpdateLinkRedirect), ctx, id, redirect)
+}
+
+// UpdateLinkRules mocks base method.
+func (m *MockRepo) UpdateLinkRules(ctx context.Context, id bson.ObjectID, rules []entity.RedirectRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkRules", ctx, id, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkRules indicates an expected call of UpdateLinkRules.
+func (mr *MockRepoMockRecorder) UpdateLinkRules(ctx, id, rules any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkRules", reflect.TypeOf((*MockRepo)(nil).UpdateLinkRules), ctx, id, rules)
+}
+
+// UpdateLinkTags mocks base method.
+func (m *MockRepo) UpdateLinkTags(ctx context.Context, id bson.ObjectID, tags []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkTags", ctx, id, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkTags indicates an expected call of UpdateLinkTags.
+func (mr *MockRepoMockRecorder) UpdateLinkTags(ctx, id, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkTags", reflect.TypeOf((*MockRepo)(nil).UpdateLinkTags), ctx, id, tags)
+}
+
+// UpdatePageFieldsAndReturn mocks base method.
+func (m *MockRepo) UpdatePageFieldsAndReturn(arg0 context.Context, arg1 bson.ObjectID, arg2 *entity.ThankYouPage) (*entity.ThankYouPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePageFieldsAndReturn", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.ThankYouPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePageFieldsAndReturn indicates an expected call of UpdatePageFieldsAndReturn.
+func (mr *MockRepoMockRecorder) UpdatePageFieldsAndReturn(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePageFieldsAndReturn", reflect.TypeOf((*MockRepo)(nil).UpdatePageFieldsAndReturn), arg0, arg1, arg2)
+}
+
+// UpdateRollupWatermark mocks base method.
+func (m *MockRepo) UpdateRollupWatermark(ctx context.Context, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRollupWatermark", ctx, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRollupWatermark indicates an expected call of UpdateRollupWatermark.
+func (mr *MockRepoMockRecorder) UpdateRollupWatermark(ctx, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRollupWatermark", reflect.TypeOf((*MockRepo)(nil).UpdateRollupWatermark), ctx, until)
+}
+
+// UpdateTrackBot mocks base method.
+func (m *MockRepo) UpdateTrackBot(ctx context.Context, id bson.ObjectID, bot entity.Bot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTrackBot", ctx, id, bot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTrackBot indicates an expected call of UpdateTrackBot.
+func (mr *MockRepoMockRecorder) UpdateTrackBot(ctx, id, bot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrackBot", reflect.TypeOf((*MockRepo)(nil).UpdateTrackBot), ctx, id, bot)
+}
+
+// UpdateTrackDevice mocks base method.
+func (m *MockRepo) UpdateTrackDevice(ctx context.Context, id bson.ObjectID, device entity.Device) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTrackDevice", ctx, id, device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTrackDevice indicates an expected call of UpdateTrackDevice.
+func (mr *MockRepoMockRecorder) UpdateTrackDevice(ctx, id, device any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrackDevice", reflect.TypeOf((*MockRepo)(nil).UpdateTrackDevice), ctx, id, device)
+}
+
+// UpdateTrackGeo mocks base method.
+func (m *MockRepo) UpdateTrackGeo(ctx context.Context, id bson.ObjectID, geo entity.Geo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTrackGeo", ctx, id, geo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTrackGeo indicates an expected call of UpdateTrackGeo.
+func (mr *MockRepoMockRecorder) UpdateTrackGeo(ctx, id, geo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrackGeo", reflect.TypeOf((*MockRepo)(nil).UpdateTrackGeo), ctx, id, geo)
+}
+
+// UpdateTrackingSettingConfig mocks base method.
+func (m *MockRepo) UpdateTrackingSettingConfig(ctx context.Context, trackingSettingID bson.ObjectID, config entity.TrackingSettingConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTrackingSettingConfig", ctx, trackingSettingID, config)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTrackingSettingConfig indicates an expected call of UpdateTrackingSettingConfig.
+func (mr *MockRepoMockRecorder) UpdateTrackingSettingConfig(ctx, trackingSettingID, config any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrackingSettingConfig", reflect.TypeOf((*MockRepo)(nil).UpdateTrackingSettingConfig), ctx, trackingSettingID, config)
+}
+
+// MockRepoCloser is a mock of RepoCloser interface.
+type MockRepoCloser struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoCloserMockRecorder
+	isgomock struct{}
+}
+
+// MockRepoCloserMockRecorder is the mock recorder for MockRepoCloser.
+type MockRepoCloserMockRecorder struct {
+	mock *MockRepoCloser
+}
+
+// NewMockRepoCloser creates a new mock instance.
+func NewMockRepoCloser(ctrl *gomock.Controller) *MockRepoCloser {
+	mock := &MockRepoCloser{ctrl: ctrl}
+	mock.recorder = &MockRepoCloserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoCloser) EXPECT() *MockRepoCloserMockRecorder {
+	return m.recorder
+}
+
+// AnonymizeClicksByIDs mocks base method.
+func (m *MockRepoCloser) AnonymizeClicksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeClicksByIDs", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnonymizeClicksByIDs indicates an expected call of AnonymizeClicksByIDs.
+func (mr *MockRepoCloserMockRecorder) AnonymizeClicksByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeClicksByIDs", reflect.TypeOf((*MockRepoCloser)(nil).AnonymizeClicksByIDs), ctx, ids)
+}
+
+// AnonymizeEventsByIDs mocks base method.
+func (m *MockRepoCloser) AnonymizeEventsByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeEventsByIDs", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnonymizeEventsByIDs indicates an expected call of AnonymizeEventsByIDs.
+func (mr *MockRepoCloserMockRecorder) AnonymizeEventsByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeEventsByIDs", reflect.TypeOf((*MockRepoCloser)(nil).AnonymizeEventsByIDs), ctx, ids)
+}
+
+// AnonymizeTracksByIDs mocks base method.
+func (m *MockRepoCloser) AnonymizeTracksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeTracksByIDs", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnonymizeTracksByIDs indicates an expected call of AnonymizeTracksByIDs.
+func (mr *MockRepoCloserMockRecorder) AnonymizeTracksByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeTracksByIDs", reflect.TypeOf((*MockRepoCloser)(nil).AnonymizeTracksByIDs), ctx, ids)
+}
+
+// AssignLinksToCampaign mocks base method.
+func (m *MockRepoCloser) AssignLinksToCampaign(ctx context.Context, tenantID string, ids []bson.ObjectID, campaignID bson.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignLinksToCampaign", ctx, tenantID, ids, campaignID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignLinksToCampaign indicates an expected call of AssignLinksToCampaign.
+func (mr *MockRepoCloserMockRecorder) AssignLinksToCampaign(ctx, tenantID, ids, campaignID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignLinksToCampaign", reflect.TypeOf((*MockRepoCloser)(nil).AssignLinksToCampaign), ctx, tenantID, ids, campaignID)
+}
+
+// CacheStats mocks base method.
+func (m *MockRepoCloser) CacheStats() []entity.CacheStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheStats")
+	ret0, _ := ret[0].([]entity.CacheStats)
+	return ret0
+}
+
+// CacheStats indicates an expected call of CacheStats.
+func (mr *MockRepoCloserMockRecorder) CacheStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheStats", reflect.TypeOf((*MockRepoCloser)(nil).CacheStats))
+}
+
+// ClearLinksCampaign mocks base method.
+func (m *MockRepoCloser) ClearLinksCampaign(ctx context.Context, campaignID bson.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearLinksCampaign", ctx, campaignID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClearLinksCampaign indicates an expected call of ClearLinksCampaign.
+func (mr *MockRepoCloserMockRecorder) ClearLinksCampaign(ctx, campaignID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearLinksCampaign", reflect.TypeOf((*MockRepoCloser)(nil).ClearLinksCampaign), ctx, campaignID)
+}
+
+// Close mocks base method.
+func (m *MockRepoCloser) Close(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockRepoCloserMockRecorder) Close(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRepoCloser)(nil).Close), arg0)
+}
+
+// CountBotClicks mocks base method.
+func (m *MockRepoCloser) CountBotClicks(ctx context.Context, linkID bson.ObjectID, from, to time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBotClicks", ctx, linkID, from, to)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBotClicks indicates an expected call of CountBotClicks.
+func (mr *MockRepoCloserMockRecorder) CountBotClicks(ctx, linkID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBotClicks", reflect.TypeOf((*MockRepoCloser)(nil).CountBotClicks), ctx, linkID, from, to)
+}
+
+// CountClicksByDimension mocks base method.
+func (m *MockRepoCloser) CountClicksByDimension(ctx context.Context, linkID bson.ObjectID, dimension entity.ClickDimension, from, to time.Time) ([]*entity.ClickBreakdownRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountClicksByDimension", ctx, linkID, dimension, from, to)
+	ret0, _ := ret[0].([]*entity.ClickBreakdownRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountClicksByDimension indicates an expected call of CountClicksByDimension.
+func (mr *MockRepoCloserMockRecorder) CountClicksByDimension(ctx, linkID, dimension, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountClicksByDimension", reflect.TypeOf((*MockRepoCloser)(nil).CountClicksByDimension), ctx, linkID, dimension, from, to)
+}
+
+// CountClicksByLinks mocks base method.
+func (m *MockRepoCloser) CountClicksByLinks(ctx context.Context, linkIDs []bson.ObjectID, from, to time.Time) ([]*entity.ClickBreakdownRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountClicksByLinks", ctx, linkIDs, from, to)
+	ret0, _ := ret[0].([]*entity.ClickBreakdownRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountClicksByLinks indicates an expected call of CountClicksByLinks.
+func (mr *MockRepoCloserMockRecorder) CountClicksByLinks(ctx, linkIDs, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountClicksByLinks", reflect.TypeOf((*MockRepoCloser)(nil).CountClicksByLinks), ctx, linkIDs, from, to)
+}
+
+// CountLinkTracksByVariant mocks base method.
+func (m *MockRepoCloser) CountLinkTracksByVariant(ctx context.Context, linkID bson.ObjectID, from, to time.Time) ([]*entity.BreakdownRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLinkTracksByVariant", ctx, linkID, from, to)
+	ret0, _ := ret[0].([]*entity.BreakdownRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLinkTracksByVariant indicates an expected call of CountLinkTracksByVariant.
+func (mr *MockRepoCloserMockRecorder) CountLinkTracksByVariant(ctx, linkID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLinkTracksByVariant", reflect.TypeOf((*MockRepoCloser)(nil).CountLinkTracksByVariant), ctx, linkID, from, to)
+}
+
+// CountReportClicks mocks base method.
+func (m *MockRepoCloser) CountReportClicks(ctx context.Context, tenantID string, rng ReportRange) ([]*entity.ReportCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReportClicks", ctx, tenantID, rng)
+	ret0, _ := ret[0].([]*entity.ReportCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReportClicks indicates an expected call of CountReportClicks.
+func (mr *MockRepoCloserMockRecorder) CountReportClicks(ctx, tenantID, rng any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReportClicks", reflect.TypeOf((*MockRepoCloser)(nil).CountReportClicks), ctx, tenantID, rng)
+}
+
+// CountReportEvents mocks base method.
+func (m *MockRepoCloser) CountReportEvents(ctx context.Context, tenantID string, rng ReportRange) ([]*entity.ReportCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReportEvents", ctx, tenantID, rng)
+	ret0, _ := ret[0].([]*entity.ReportCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReportEvents indicates an expected call of CountReportEvents.
+func (mr *MockRepoCloserMockRecorder) CountReportEvents(ctx, tenantID, rng any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReportEvents", reflect.TypeOf((*MockRepoCloser)(nil).CountReportEvents), ctx, tenantID, rng)
+}
+
+// CountReportRollups mocks base method.
+func (m *MockRepoCloser) CountReportRollups(ctx context.Context, tenantID string, rng ReportRange, daily bool) ([]*entity.ReportCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReportRollups", ctx, tenantID, rng, daily)
+	ret0, _ := ret[0].([]*entity.ReportCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReportRollups indicates an expected call of CountReportRollups.
+func (mr *MockRepoCloserMockRecorder) CountReportRollups(ctx, tenantID, rng, daily any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReportRollups", reflect.TypeOf((*MockRepoCloser)(nil).CountReportRollups), ctx, tenantID, rng, daily)
+}
+
+// CountReportTracks mocks base method.
+func (m *MockRepoCloser) CountReportTracks(ctx context.Context, trackingSettingID bson.ObjectID, rng ReportRange) ([]*entity.ReportCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReportTracks", ctx, trackingSettingID, rng)
+	ret0, _ := ret[0].([]*entity.ReportCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReportTracks indicates an expected call of CountReportTracks.
+func (mr *MockRepoCloserMockRecorder) CountReportTracks(ctx, trackingSettingID, rng any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReportTracks", reflect.TypeOf((*MockRepoCloser)(nil).CountReportTracks), ctx, trackingSettingID, rng)
+}
+
+// CountTracksByDimension mocks base method.
+func (m *MockRepoCloser) CountTracksByDimension(ctx context.Context, trackingSettingID bson.ObjectID, dimension entity.TrackDimension, from, to time.Time) ([]*entity.BreakdownRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTracksByDimension", ctx, trackingSettingID, dimension, from, to)
+	ret0, _ := ret[0].([]*entity.BreakdownRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTracksByDimension indicates an expected call of CountTracksByDimension.
+func (mr *MockRepoCloserMockRecorder) CountTracksByDimension(ctx, trackingSettingID, dimension, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTracksByDimension", reflect.TypeOf((*MockRepoCloser)(nil).CountTracksByDimension), ctx, trackingSettingID, dimension, from, to)
+}
+
+// CountTracksByLinks mocks base method.
+func (m *MockRepoCloser) CountTracksByLinks(ctx context.Context, linkIDs []bson.ObjectID, from, to time.Time) ([]*entity.BreakdownRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTracksByLinks", ctx, linkIDs, from, to)
+	ret0, _ := ret[0].([]*entity.BreakdownRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTracksByLinks indicates an expected call of CountTracksByLinks.
+func (mr *MockRepoCloserMockRecorder) CountTracksByLinks(ctx, linkIDs, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTracksByLinks", reflect.TypeOf((*MockRepoCloser)(nil).CountTracksByLinks), ctx, linkIDs, from, to)
+}
+
+// CreateClick mocks base method.
+func (m *MockRepoCloser) CreateClick(ctx context.Context, click *entity.Click) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClick", ctx, click)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateClick indicates an expected call of CreateClick.
+func (mr *MockRepoCloserMockRecorder) CreateClick(ctx, click any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClick", reflect.TypeOf((*MockRepoCloser)(nil).CreateClick), ctx, click)
+}
+
+// CreateDataSubjectRequest mocks base method.
+func (m *MockRepoCloser) CreateDataSubjectRequest(ctx context.Context, request *entity.DataSubjectRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDataSubjectRequest", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDataSubjectRequest indicates an expected call of CreateDataSubjectRequest.
+func (mr *MockRepoCloserMockRecorder) CreateDataSubjectRequest(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDataSubjectRequest", reflect.TypeOf((*MockRepoCloser)(nil).CreateDataSubjectRequest), ctx, request)
+}
+
+// CreateEvent mocks base method.
+func (m *MockRepoCloser) CreateEvent(ctx context.Context, event *entity.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEvent indicates an expected call of CreateEvent.
+func (mr *MockRepoCloserMockRecorder) CreateEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockRepoCloser)(nil).CreateEvent), ctx, event)
+}
+
+// CreateFunnel mocks base method.
+func (m *MockRepoCloser) CreateFunnel(ctx context.Context, funnel *entity.Funnel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFunnel", ctx, funnel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFunnel indicates an expected call of CreateFunnel.
+func (mr *MockRepoCloserMockRecorder) CreateFunnel(ctx, funnel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFunnel", reflect.TypeOf((*MockRepoCloser)(nil).CreateFunnel), ctx, funnel)
+}
+
+// CreateIdentity mocks base method.
+func (m *MockRepoCloser) CreateIdentity(ctx context.Context, identity *entity.Identity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIdentity indicates an expected call of CreateIdentity.
+func (mr *MockRepoCloserMockRecorder) CreateIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdentity", reflect.TypeOf((*MockRepoCloser)(nil).CreateIdentity), ctx, identity)
+}
+
+// CreateLink mocks base method.
+func (m *MockRepoCloser) CreateLink(arg0 context.Context, arg1 *entity.Link) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLink", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLink indicates an expected call of CreateLink.
+func (mr *MockRepoCloserMockRecorder) CreateLink(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLink", reflect.TypeOf((*MockRepoCloser)(nil).CreateLink), arg0, arg1)
+}
+
+// CreateLinkCampaign mocks base method.
+func (m *MockRepoCloser) CreateLinkCampaign(ctx context.Context, campaign *entity.LinkCampaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLinkCampaign", ctx, campaign)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLinkCampaign indicates an expected call of CreateLinkCampaign.
+func (mr *MockRepoCloserMockRecorder) CreateLinkCampaign(ctx, campaign any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLinkCampaign", reflect.TypeOf((*MockRepoCloser)(nil).CreateLinkCampaign), ctx, campaign)
+}
+
+// CreatePage mocks base method.
+func (m *MockRepoCloser) CreatePage(arg0 context.Context, arg1 *entity.ThankYouPage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePage", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePage indicates an expected call of CreatePage.
+func (mr *MockRepoCloserMockRecorder) CreatePage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePage", reflect.TypeOf((*MockRepoCloser)(nil).CreatePage), arg0, arg1)
+}
+
+// CreateTrack mocks base method.
+func (m *MockRepoCloser) CreateTrack(ctx context.Context, track *entity.Track) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTrack", ctx, track)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTrack indicates an expected call of CreateTrack.
+func (mr *MockRepoCloserMockRecorder) CreateTrack(ctx, track any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrack", reflect.TypeOf((*MockRepoCloser)(nil).CreateTrack), ctx, track)
+}
+
+// DeleteClicksByIDs mocks base method.
+func (m *MockRepoCloser) DeleteClicksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClicksByIDs", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteClicksByIDs indicates an expected call of DeleteClicksByIDs.
+func (mr *MockRepoCloserMockRecorder) DeleteClicksByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClicksByIDs", reflect.TypeOf((*MockRepoCloser)(nil).DeleteClicksByIDs), ctx, ids)
+}
+
+// DeleteEventsByIDs mocks base method.
+func (m *MockRepoCloser) DeleteEventsByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventsByIDs", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEventsByIDs indicates an expected call of DeleteEventsByIDs.
+func (mr *MockRepoCloserMockRecorder) DeleteEventsByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventsByIDs", reflect.TypeOf((*MockRepoCloser)(nil).DeleteEventsByIDs), ctx, ids)
+}
+
+// DeleteFunnel mocks base method.
+func (m *MockRepoCloser) DeleteFunnel(ctx context.Context, id bson.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFunnel", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFunnel indicates an expected call of DeleteFunnel.
+func (mr *MockRepoCloserMockRecorder) DeleteFunnel(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFunnel", reflect.TypeOf((*MockRepoCloser)(nil).DeleteFunnel), ctx, id)
+}
+
+// DeleteIdentity mocks base method.
+func (m *MockRepoCloser) DeleteIdentity(ctx context.Context, id bson.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdentity", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdentity indicates an expected call of DeleteIdentity.
+func (mr *MockRepoCloserMockRecorder) DeleteIdentity(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdentity", reflect.TypeOf((*MockRepoCloser)(nil).DeleteIdentity), ctx, id)
+}
+
+// DeleteLinkCampaign mocks base method.
+func (m *MockRepoCloser) DeleteLinkCampaign(ctx context.Context, id bson.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLinkCampaign", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLinkCampaign indicates an expected call of DeleteLinkCampaign.
+func (mr *MockRepoCloserMockRecorder) DeleteLinkCampaign(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLinkCampaign", reflect.TypeOf((*MockRepoCloser)(nil).DeleteLinkCampaign), ctx, id)
+}
+
+// DeleteTracksByIDs mocks base method.
+func (m *MockRepoCloser) DeleteTracksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTracksByIDs", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTracksByIDs indicates an expected call of DeleteTracksByIDs.
+func (mr *MockRepoCloserMockRecorder) DeleteTracksByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTracksByIDs", reflect.TypeOf((*MockRepoCloser)(nil).DeleteTracksByIDs), ctx, ids)
+}
+
+// FindAllEventByTenantID mocks base method.
+func (m *MockRepoCloser) FindAllEventByTenantID(ctx context.Context, tenantID string) ([]*entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllEventByTenantID", ctx, tenantID)
+	ret0, _ := ret[0].([]*entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllEventByTenantID indicates an expected call of FindAllEventByTenantID.
+func (mr *MockRepoCloserMockRecorder) FindAllEventByTenantID(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllEventByTenantID", reflect.TypeOf((*MockRepoCloser)(nil).FindAllEventByTenantID), ctx, tenantID)
+}
+
+// FindAllEventByTrackID mocks base method.
+func (m *MockRepoCloser) FindAllEventByTrackID(ctx context.Context, trackID bson.ObjectID) ([]*entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllEventByTrackID", ctx, trackID)
+	ret0, _ := ret[0].([]*entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllEventByTrackID indicates an expected call of FindAllEventByTrackID.
+func (mr *MockRepoCloserMockRecorder) FindAllEventByTrackID(ctx, trackID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllEventByTrackID", reflect.TypeOf((*MockRepoCloser)(nil).FindAllEventByTrackID), ctx, trackID)
+}
+
+// FindAllLinkbyTenantID mocks base method.
+func (m *MockRepoCloser) FindAllLinkbyTenantID(ctx context.Context, tenantID string) ([]*entity.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllLinkbyTenantID", ctx, tenantID)
+	ret0, _ := ret[0].([]*entity.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllLinkbyTenantID indicates an expected call of FindAllLinkbyTenantID.
+func (mr *MockRepoCloserMockRecorder) FindAllLinkbyTenantID(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllLinkbyTenantID", reflect.TypeOf((*MockRepoCloser)(nil).FindAllLinkbyTenantID), ctx, tenantID)
+}
+
+// FindArchivableDocuments mocks base method.
+func (m *MockRepoCloser) FindArchivableDocuments(ctx context.Context, collection string, before time.Time, limit int64) ([]bson.Raw, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindArchivableDocuments", ctx, collection, before, limit)
+	ret0, _ := ret[0].([]bson.Raw)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindArchivableDocuments indicates an expected call of FindArchivableDocuments.
+func (mr *MockRepoCloserMockRecorder) FindArchivableDocuments(ctx, collection, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindArchivableDocuments", reflect.TypeOf((*MockRepoCloser)(nil).FindArchivableDocuments), ctx, collection, before, limit)
+}
+
+// FindClicksByTrackIDs mocks base method.
+func (m *MockRepoCloser) FindClicksByTrackIDs(ctx context.Context, tenantID string, trackIDs []string) ([]*entity.Click, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindClicksByTrackIDs", ctx, tenantID, trackIDs)
+	ret0, _ := ret[0].([]*entity.Click)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindClicksByTrackIDs indicates an expected call of FindClicksByTrackIDs.
+func (mr *MockRepoCloserMockRecorder) FindClicksByTrackIDs(ctx, tenantID, trackIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindClicksByTrackIDs", reflect.TypeOf((*MockRepoCloser)(nil).FindClicksByTrackIDs), ctx, tenantID, trackIDs)
+}
+
+// FindDailyRollupTimeZones mocks base method.
+func (m *MockRepoCloser) FindDailyRollupTimeZones(ctx context.Context, tenantID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDailyRollupTimeZones", ctx, tenantID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDailyRollupTimeZones indicates an expected call of FindDailyRollupTimeZones.
+func (mr *MockRepoCloserMockRecorder) FindDailyRollupTimeZones(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDailyRollupTimeZones", reflect.TypeOf((*MockRepoCloser)(nil).FindDailyRollupTimeZones), ctx, tenantID)
+}
+
+// FindDataSubjectRequestsByTrackingSettingID mocks base method.
+func (m *MockRepoCloser) FindDataSubjectRequestsByTrackingSettingID(ctx context.Context, trackingSettingID bson.ObjectID) ([]*entity.DataSubjectRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDataSubjectRequestsByTrackingSettingID", ctx, trackingSettingID)
+	ret0, _ := ret[0].([]*entity.DataSubjectRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDataSubjectRequestsByTrackingSettingID indicates an expected call of FindDataSubjectRequestsByTrackingSettingID.
+func (mr *MockRepoCloserMockRecorder) FindDataSubjectRequestsByTrackingSettingID(ctx, trackingSettingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDataSubjectRequestsByTrackingSettingID", reflect.TypeOf((*MockRepoCloser)(nil).FindDataSubjectRequestsByTrackingSettingID), ctx, trackingSettingID)
+}
+
+// FindEventsBySubject mocks base method.
+func (m *MockRepoCloser) FindEventsBySubject(ctx context.Context, tenantID string, trackIDs, fingerprints []string) ([]*entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindEventsBySubject", ctx, tenantID, trackIDs, fingerprints)
+	ret0, _ := ret[0].([]*entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEventsBySubject indicates an expected call of FindEventsBySubject.
+func (mr *MockRepoCloserMockRecorder) FindEventsBySubject(ctx, tenantID, trackIDs, fingerprints any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEventsBySubject", reflect.TypeOf((*MockRepoCloser)(nil).FindEventsBySubject), ctx, tenantID, trackIDs, fingerprints)
+}
+
+// FindFunnelByID mocks base method.
+func (m *MockRepoCloser) FindFunnelByID(ctx context.Context, id bson.ObjectID) (*entity.Funnel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFunnelByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Funnel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFunnelByID indicates an expected call of FindFunnelByID.
+func (mr *MockRepoCloserMockRecorder) FindFunnelByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFunnelByID", reflect.TypeOf((*MockRepoCloser)(nil).FindFunnelByID), ctx, id)
+}
+
+// FindFunnelJourneys mocks base method.
+func (m *MockRepoCloser) FindFunnelJourneys(ctx context.Context, tenantID string, from, to time.Time) ([]*entity.FunnelJourney, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFunnelJourneys", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]*entity.FunnelJourney)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFunnelJourneys indicates an expected call of FindFunnelJourneys.
+func (mr *MockRepoCloserMockRecorder) FindFunnelJourneys(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFunnelJourneys", reflect.TypeOf((*MockRepoCloser)(nil).FindFunnelJourneys), ctx, tenantID, from, to)
+}
+
+// FindFunnelsByTenantID mocks base method.
+func (m *MockRepoCloser) FindFunnelsByTenantID(ctx context.Context, tenantID string) ([]*entity.Funnel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFunnelsByTenantID", ctx, tenantID)
+	ret0, _ := ret[0].([]*entity.Funnel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFunnelsByTenantID indicates an expected call of FindFunnelsByTenantID.
+func (mr *MockRepoCloserMockRecorder) FindFunnelsByTenantID(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFunnelsByTenantID", reflect.TypeOf((*MockRepoCloser)(nil).FindFunnelsByTenantID), ctx, tenantID)
+}
+
+// FindIdentitiesByIdentifier mocks base method.
+func (m *MockRepoCloser) FindIdentitiesByIdentifier(ctx context.Context, trackingSettingID bson.ObjectID, identifier entity.Identifier) ([]*entity.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdentitiesByIdentifier", ctx, trackingSettingID, identifier)
+	ret0, _ := ret[0].([]*entity.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdentitiesByIdentifier indicates an expected call of FindIdentitiesByIdentifier.
+func (mr *MockRepoCloserMockRecorder) FindIdentitiesByIdentifier(ctx, trackingSettingID, identifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentitiesByIdentifier", reflect.TypeOf((*MockRepoCloser)(nil).FindIdentitiesByIdentifier), ctx, trackingSettingID, identifier)
+}
+
+// FindIdentitiesByIdentifiers mocks base method.
+func (m *MockRepoCloser) FindIdentitiesByIdentifiers(ctx context.Context, trackingSettingID bson.ObjectID, identifiers []entity.Identifier) ([]*entity.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdentitiesByIdentifiers", ctx, trackingSettingID, identifiers)
+	ret0, _ := ret[0].([]*entity.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdentitiesByIdentifiers indicates an expected call of FindIdentitiesByIdentifiers.
+func (mr *MockRepoCloserMockRecorder) FindIdentitiesByIdentifiers(ctx, trackingSettingID, identifiers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentitiesByIdentifiers", reflect.TypeOf((*MockRepoCloser)(nil).FindIdentitiesByIdentifiers), ctx, trackingSettingID, identifiers)
+}
+
+// FindIdentityByID mocks base method.
+func (m *MockRepoCloser) FindIdentityByID(ctx context.Context, id bson.ObjectID) (*entity.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdentityByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdentityByID indicates an expected call of FindIdentityByID.
+func (mr *MockRepoCloserMockRecorder) FindIdentityByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentityByID", reflect.TypeOf((*MockRepoCloser)(nil).FindIdentityByID), ctx, id)
+}
+
+// FindLastEventByFingerprint mocks base method.
+func (m *MockRepoCloser) FindLastEventByFingerprint(ctx context.Context, fingerprint string) (*entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLastEventByFingerprint", ctx, fingerprint)
+	ret0, _ := ret[0].(*entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLastEventByFingerprint indicates an expected call of FindLastEventByFingerprint.
 func (mr *MockRepoCloserMockRecorder) FindLastEventByFingerprint(ctx, fingerprint any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLastEventByFingerprint", reflect.TypeOf((*MockRepoCloser)(nil).FindLastEventByFingerprint), ctx, fingerprint)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLastEventByFingerprint", reflect.TypeOf((*MockRepoCloser)(nil).FindLastEventByFingerprint), ctx, fingerprint)
+}
+
+// FindLastEventByTrackIDs mocks base method.
+func (m *MockRepoCloser) FindLastEventByTrackIDs(ctx context.Context, trackIDs []string) (*entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLastEventByTrackIDs", ctx, trackIDs)
+	ret0, _ := ret[0].(*entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLastEventByTrackIDs indicates an expected call of FindLastEventByTrackIDs.
+func (mr *MockRepoCloserMockRecorder) FindLastEventByTrackIDs(ctx, trackIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLastEventByTrackIDs", reflect.TypeOf((*MockRepoCloser)(nil).FindLastEventByTrackIDs), ctx, trackIDs)
+}
+
+// FindLinkByDomainAndShortID mocks base method.
+func (m *MockRepoCloser) FindLinkByDomainAndShortID(ctx context.Context, domain, shortID string) (*entity.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLinkByDomainAndShortID", ctx, domain, shortID)
+	ret0, _ := ret[0].(*entity.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLinkByDomainAndShortID indicates an expected call of FindLinkByDomainAndShortID.
+func (mr *MockRepoCloserMockRecorder) FindLinkByDomainAndShortID(ctx, domain, shortID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinkByDomainAndShortID", reflect.TypeOf((*MockRepoCloser)(nil).FindLinkByDomainAndShortID), ctx, domain, shortID)
 }
 
 // FindLinkByID mocks base method.
@@ -507,6 +2122,36 @@ func (mr *MockRepoCloserMockRecorder) FindLinkByShortID(arg0, arg1 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinkByShortID", reflect.TypeOf((*MockRepoCloser)(nil).FindLinkByShortID), arg0, arg1)
 }
 
+// FindLinkCampaignByID mocks base method.
+func (m *MockRepoCloser) FindLinkCampaignByID(ctx context.Context, id bson.ObjectID) (*entity.LinkCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLinkCampaignByID", ctx, id)
+	ret0, _ := ret[0].(*entity.LinkCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLinkCampaignByID indicates an expected call of FindLinkCampaignByID.
+func (mr *MockRepoCloserMockRecorder) FindLinkCampaignByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinkCampaignByID", reflect.TypeOf((*MockRepoCloser)(nil).FindLinkCampaignByID), ctx, id)
+}
+
+// FindLinkCampaignsByTenantID mocks base method.
+func (m *MockRepoCloser) FindLinkCampaignsByTenantID(ctx context.Context, tenantID string) ([]*entity.LinkCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLinkCampaignsByTenantID", ctx, tenantID)
+	ret0, _ := ret[0].([]*entity.LinkCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLinkCampaignsByTenantID indicates an expected call of FindLinkCampaignsByTenantID.
+func (mr *MockRepoCloserMockRecorder) FindLinkCampaignsByTenantID(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinkCampaignsByTenantID", reflect.TypeOf((*MockRepoCloser)(nil).FindLinkCampaignsByTenantID), ctx, tenantID)
+}
+
 // FindOrCreateWithPagesByTenantID mocks base method.
 func (m *MockRepoCloser) FindOrCreateWithPagesByTenantID(ctx context.Context, tenantID string) (*entity.TrackingSettingWithPages, error) {
 	m.ctrl.T.Helper()
@@ -522,6 +2167,36 @@ func (mr *MockRepoCloserMockRecorder) FindOrCreateWithPagesByTenantID(ctx, tenan
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreateWithPagesByTenantID", reflect.TypeOf((*MockRepoCloser)(nil).FindOrCreateWithPagesByTenantID), ctx, tenantID)
 }
 
+// FindRollupWatermark mocks base method.
+func (m *MockRepoCloser) FindRollupWatermark(ctx context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRollupWatermark", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRollupWatermark indicates an expected call of FindRollupWatermark.
+func (mr *MockRepoCloserMockRecorder) FindRollupWatermark(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRollupWatermark", reflect.TypeOf((*MockRepoCloser)(nil).FindRollupWatermark), ctx)
+}
+
+// FindTenantIDByLinkDomain mocks base method.
+func (m *MockRepoCloser) FindTenantIDByLinkDomain(ctx context.Context, domain string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTenantIDByLinkDomain", ctx, domain)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTenantIDByLinkDomain indicates an expected call of FindTenantIDByLinkDomain.
+func (mr *MockRepoCloserMockRecorder) FindTenantIDByLinkDomain(ctx, domain any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTenantIDByLinkDomain", reflect.TypeOf((*MockRepoCloser)(nil).FindTenantIDByLinkDomain), ctx, domain)
+}
+
 // FindTrackByID mocks base method.
 func (m *MockRepoCloser) FindTrackByID(ctx context.Context, id bson.ObjectID) (*entity.Track, error) {
 	m.ctrl.T.Helper()
@@ -582,6 +2257,81 @@ func (mr *MockRepoCloserMockRecorder) FindTrackingSettingWithPagesByID(ctx, trac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackingSettingWithPagesByID", reflect.TypeOf((*MockRepoCloser)(nil).FindTrackingSettingWithPagesByID), ctx, trackingSettingID)
 }
 
+// FindTrackingSettingWithPagesByTenantID mocks base method.
+func (m *MockRepoCloser) FindTrackingSettingWithPagesByTenantID(ctx context.Context, tenantID string) (*entity.TrackingSettingWithPages, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrackingSettingWithPagesByTenantID", ctx, tenantID)
+	ret0, _ := ret[0].(*entity.TrackingSettingWithPages)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrackingSettingWithPagesByTenantID indicates an expected call of FindTrackingSettingWithPagesByTenantID.
+func (mr *MockRepoCloserMockRecorder) FindTrackingSettingWithPagesByTenantID(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackingSettingWithPagesByTenantID", reflect.TypeOf((*MockRepoCloser)(nil).FindTrackingSettingWithPagesByTenantID), ctx, tenantID)
+}
+
+// FindTrackingSettingsByDomains mocks base method.
+func (m *MockRepoCloser) FindTrackingSettingsByDomains(ctx context.Context, domains []string) ([]*entity.TrackingSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrackingSettingsByDomains", ctx, domains)
+	ret0, _ := ret[0].([]*entity.TrackingSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrackingSettingsByDomains indicates an expected call of FindTrackingSettingsByDomains.
+func (mr *MockRepoCloserMockRecorder) FindTrackingSettingsByDomains(ctx, domains any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrackingSettingsByDomains", reflect.TypeOf((*MockRepoCloser)(nil).FindTrackingSettingsByDomains), ctx, domains)
+}
+
+// FindTracksBySubject mocks base method.
+func (m *MockRepoCloser) FindTracksBySubject(ctx context.Context, trackingSettingID bson.ObjectID, ids []bson.ObjectID, endUserIDs []string) ([]*entity.Track, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTracksBySubject", ctx, trackingSettingID, ids, endUserIDs)
+	ret0, _ := ret[0].([]*entity.Track)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTracksBySubject indicates an expected call of FindTracksBySubject.
+func (mr *MockRepoCloserMockRecorder) FindTracksBySubject(ctx, trackingSettingID, ids, endUserIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTracksBySubject", reflect.TypeOf((*MockRepoCloser)(nil).FindTracksBySubject), ctx, trackingSettingID, ids, endUserIDs)
+}
+
+// IncrementLinkClicks mocks base method.
+func (m *MockRepoCloser) IncrementLinkClicks(ctx context.Context, id bson.ObjectID, maxClicks int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementLinkClicks", ctx, id, maxClicks)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementLinkClicks indicates an expected call of IncrementLinkClicks.
+func (mr *MockRepoCloserMockRecorder) IncrementLinkClicks(ctx, id, maxClicks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementLinkClicks", reflect.TypeOf((*MockRepoCloser)(nil).IncrementLinkClicks), ctx, id, maxClicks)
+}
+
+// IsDomainRegistered mocks base method.
+func (m *MockRepoCloser) IsDomainRegistered(ctx context.Context, domains []string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDomainRegistered", ctx, domains)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDomainRegistered indicates an expected call of IsDomainRegistered.
+func (mr *MockRepoCloserMockRecorder) IsDomainRegistered(ctx, domains any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDomainRegistered", reflect.TypeOf((*MockRepoCloser)(nil).IsDomainRegistered), ctx, domains)
+}
+
 // IsTrackIDExist mocks base method.
 func (m *MockRepoCloser) IsTrackIDExist(ctx context.Context, id bson.ObjectID) (bool, error) {
 	m.ctrl.T.Helper()
@@ -612,19 +2362,246 @@ func (mr *MockRepoCloserMockRecorder) IsTrackingSettingIDExist(ctx, id any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTrackingSettingIDExist", reflect.TypeOf((*MockRepoCloser)(nil).IsTrackingSettingIDExist), ctx, id)
 }
 
+// MarkDocumentsArchived mocks base method.
+func (m *MockRepoCloser) MarkDocumentsArchived(ctx context.Context, collection string, ids []bson.ObjectID, archivedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDocumentsArchived", ctx, collection, ids, archivedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDocumentsArchived indicates an expected call of MarkDocumentsArchived.
+func (mr *MockRepoCloserMockRecorder) MarkDocumentsArchived(ctx, collection, ids, archivedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDocumentsArchived", reflect.TypeOf((*MockRepoCloser)(nil).MarkDocumentsArchived), ctx, collection, ids, archivedAt)
+}
+
+// RemoveLinks mocks base method.
+func (m *MockRepoCloser) RemoveLinks(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveLinks", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveLinks indicates an expected call of RemoveLinks.
+func (mr *MockRepoCloserMockRecorder) RemoveLinks(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveLinks", reflect.TypeOf((*MockRepoCloser)(nil).RemoveLinks), ctx, ids)
+}
+
+// RemoveReportRollups mocks base method.
+func (m *MockRepoCloser) RemoveReportRollups(ctx context.Context, granularity entity.ReportGranularity, tenantID string, from, to time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReportRollups", ctx, granularity, tenantID, from, to)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveReportRollups indicates an expected call of RemoveReportRollups.
+func (mr *MockRepoCloserMockRecorder) RemoveReportRollups(ctx, granularity, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReportRollups", reflect.TypeOf((*MockRepoCloser)(nil).RemoveReportRollups), ctx, granularity, tenantID, from, to)
+}
+
+// RestoreDocuments mocks base method.
+func (m *MockRepoCloser) RestoreDocuments(ctx context.Context, collection string, documents []bson.Raw) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreDocuments", ctx, collection, documents)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreDocuments indicates an expected call of RestoreDocuments.
+func (mr *MockRepoCloserMockRecorder) RestoreDocuments(ctx, collection, documents any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDocuments", reflect.TypeOf((*MockRepoCloser)(nil).RestoreDocuments), ctx, collection, documents)
+}
+
+// RollupReportDays mocks base method.
+func (m *MockRepoCloser) RollupReportDays(ctx context.Context, tenantID string, from, to time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupReportDays", ctx, tenantID, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollupReportDays indicates an expected call of RollupReportDays.
+func (mr *MockRepoCloserMockRecorder) RollupReportDays(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupReportDays", reflect.TypeOf((*MockRepoCloser)(nil).RollupReportDays), ctx, tenantID, from, to)
+}
+
+// RollupReportHours mocks base method.
+func (m *MockRepoCloser) RollupReportHours(ctx context.Context, from, to time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupReportHours", ctx, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollupReportHours indicates an expected call of RollupReportHours.
+func (mr *MockRepoCloserMockRecorder) RollupReportHours(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupReportHours", reflect.TypeOf((*MockRepoCloser)(nil).RollupReportHours), ctx, from, to)
+}
+
 // SearchLinks mocks base method.
-func (m *MockRepoCloser) SearchLinks(ctx context.Context, tenantID, keywords string) ([]*entity.Link, error) {
+func (m *MockRepoCloser) SearchLinks(ctx context.Context, tenantID string, search entity.LinkSearch) (*entity.LinkSearchResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchLinks", ctx, tenantID, keywords)
-	ret0, _ := ret[0].([]*entity.Link)
+	ret := m.ctrl.Call(m, "SearchLinks", ctx, tenantID, search)
+	ret0, _ := ret[0].(*entity.LinkSearchResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SearchLinks indicates an expected call of SearchLinks.
-func (mr *MockRepoCloserMockRecorder) SearchLinks(ctx, tenantID, keywords any) *gomock.Call {
+func (mr *MockRepoCloserMockRecorder) SearchLinks(ctx, tenantID, search any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchLinks", reflect.TypeOf((*MockRepoCloser)(nil).SearchLinks), ctx, tenantID, search)
+}
+
+// SetLinkClicks mocks base method.
+func (m *MockRepoCloser) SetLinkClicks(ctx context.Context, id bson.ObjectID, clicks int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLinkClicks", ctx, id, clicks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLinkClicks indicates an expected call of SetLinkClicks.
+func (mr *MockRepoCloserMockRecorder) SetLinkClicks(ctx, id, clicks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLinkClicks", reflect.TypeOf((*MockRepoCloser)(nil).SetLinkClicks), ctx, id, clicks)
+}
+
+// UpdateDataSubjectRequest mocks base method.
+func (m *MockRepoCloser) UpdateDataSubjectRequest(ctx context.Context, request *entity.DataSubjectRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDataSubjectRequest", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDataSubjectRequest indicates an expected call of UpdateDataSubjectRequest.
+func (mr *MockRepoCloserMockRecorder) UpdateDataSubjectRequest(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDataSubjectRequest", reflect.TypeOf((*MockRepoCloser)(nil).UpdateDataSubjectRequest), ctx, request)
+}
+
+// UpdateFunnel mocks base method.
+func (m *MockRepoCloser) UpdateFunnel(ctx context.Context, funnel *entity.Funnel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFunnel", ctx, funnel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFunnel indicates an expected call of UpdateFunnel.
+func (mr *MockRepoCloserMockRecorder) UpdateFunnel(ctx, funnel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFunnel", reflect.TypeOf((*MockRepoCloser)(nil).UpdateFunnel), ctx, funnel)
+}
+
+// UpdateIdentity mocks base method.
+func (m *MockRepoCloser) UpdateIdentity(ctx context.Context, identity *entity.Identity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIdentity indicates an expected call of UpdateIdentity.
+func (mr *MockRepoCloserMockRecorder) UpdateIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIdentity", reflect.TypeOf((*MockRepoCloser)(nil).UpdateIdentity), ctx, identity)
+}
+
+// UpdateLinkCampaign mocks base method.
+func (m *MockRepoCloser) UpdateLinkCampaign(ctx context.Context, campaign *entity.LinkCampaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkCampaign", ctx, campaign)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkCampaign indicates an expected call of UpdateLinkCampaign.
+func (mr *MockRepoCloserMockRecorder) UpdateLinkCampaign(ctx, campaign any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkCampaign", reflect.TypeOf((*MockRepoCloser)(nil).UpdateLinkCampaign), ctx, campaign)
+}
+
+// UpdateLinkDetails mocks base method.
+func (m *MockRepoCloser) UpdateLinkDetails(ctx context.Context, link *entity.Link) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkDetails", ctx, link)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkDetails indicates an expected call of UpdateLinkDetails.
+func (mr *MockRepoCloserMockRecorder) UpdateLinkDetails(ctx, link any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkDetails", reflect.TypeOf((*MockRepoCloser)(nil).UpdateLinkDetails), ctx, link)
+}
+
+// UpdateLinkLifecycle mocks base method.
+func (m *MockRepoCloser) UpdateLinkLifecycle(ctx context.Context, id bson.ObjectID, lifecycle entity.LinkLifecycle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkLifecycle", ctx, id, lifecycle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkLifecycle indicates an expected call of UpdateLinkLifecycle.
+func (mr *MockRepoCloserMockRecorder) UpdateLinkLifecycle(ctx, id, lifecycle any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchLinks", reflect.TypeOf((*MockRepoCloser)(nil).SearchLinks), ctx, tenantID, keywords)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkLifecycle", reflect.TypeOf((*MockRepoCloser)(nil).UpdateLinkLifecycle), ctx, id, lifecycle)
+}
+
+// UpdateLinkRedirect mocks base method.
+func (m *MockRepoCloser) UpdateLinkRedirect(ctx context.Context, id bson.ObjectID, redirect entity.LinkRedirect) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkRedirect", ctx, id, redirect)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkRedirect indicates an expected call of UpdateLinkRedirect.
+func (mr *MockRepoCloserMockRecorder) UpdateLinkRedirect(ctx, id, redirect any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkRedirect", reflect.TypeOf((*MockRepoCloser)(nil).UpdateLinkRedirect), ctx, id, redirect)
+}
+
+// UpdateLinkRules mocks base method.
+func (m *MockRepoCloser) UpdateLinkRules(ctx context.Context, id bson.ObjectID, rules []entity.RedirectRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkRules", ctx, id, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkRules indicates an expected call of UpdateLinkRules.
+func (mr *MockRepoCloserMockRecorder) UpdateLinkRules(ctx, id, rules any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkRules", reflect.TypeOf((*MockRepoCloser)(nil).UpdateLinkRules), ctx, id, rules)
+}
+
+// UpdateLinkTags mocks base method.
+func (m *MockRepoCloser) UpdateLinkTags(ctx context.Context, id bson.ObjectID, tags []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkTags", ctx, id, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkTags indicates an expected call of UpdateLinkTags.
+func (mr *MockRepoCloserMockRecorder) UpdateLinkTags(ctx, id, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkTags", reflect.TypeOf((*MockRepoCloser)(nil).UpdateLinkTags), ctx, id, tags)
 }
 
 // UpdatePageFieldsAndReturn mocks base method.
@@ -641,3 +2618,73 @@ func (mr *MockRepoCloserMockRecorder) UpdatePageFieldsAndReturn(arg0, arg1, arg2
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePageFieldsAndReturn", reflect.TypeOf((*MockRepoCloser)(nil).UpdatePageFieldsAndReturn), arg0, arg1, arg2)
 }
+
+// UpdateRollupWatermark mocks base method.
+func (m *MockRepoCloser) UpdateRollupWatermark(ctx context.Context, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRollupWatermark", ctx, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRollupWatermark indicates an expected call of UpdateRollupWatermark.
+func (mr *MockRepoCloserMockRecorder) UpdateRollupWatermark(ctx, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRollupWatermark", reflect.TypeOf((*MockRepoCloser)(nil).UpdateRollupWatermark), ctx, until)
+}
+
+// UpdateTrackBot mocks base method.
+func (m *MockRepoCloser) UpdateTrackBot(ctx context.Context, id bson.ObjectID, bot entity.Bot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTrackBot", ctx, id, bot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTrackBot indicates an expected call of UpdateTrackBot.
+func (mr *MockRepoCloserMockRecorder) UpdateTrackBot(ctx, id, bot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrackBot", reflect.TypeOf((*MockRepoCloser)(nil).UpdateTrackBot), ctx, id, bot)
+}
+
+// UpdateTrackDevice mocks base method.
+func (m *MockRepoCloser) UpdateTrackDevice(ctx context.Context, id bson.ObjectID, device entity.Device) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTrackDevice", ctx, id, device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTrackDevice indicates an expected call of UpdateTrackDevice.
+func (mr *MockRepoCloserMockRecorder) UpdateTrackDevice(ctx, id, device any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrackDevice", reflect.TypeOf((*MockRepoCloser)(nil).UpdateTrackDevice), ctx, id, device)
+}
+
+// UpdateTrackGeo mocks base method.
+func (m *MockRepoCloser) UpdateTrackGeo(ctx context.Context, id bson.ObjectID, geo entity.Geo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTrackGeo", ctx, id, geo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTrackGeo indicates an expected call of UpdateTrackGeo.
+func (mr *MockRepoCloserMockRecorder) UpdateTrackGeo(ctx, id, geo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrackGeo", reflect.TypeOf((*MockRepoCloser)(nil).UpdateTrackGeo), ctx, id, geo)
+}
+
+// UpdateTrackingSettingConfig mocks base method.
+func (m *MockRepoCloser) UpdateTrackingSettingConfig(ctx context.Context, trackingSettingID bson.ObjectID, config entity.TrackingSettingConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTrackingSettingConfig", ctx, trackingSettingID, config)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTrackingSettingConfig indicates an expected call of UpdateTrackingSettingConfig.
+func (mr *MockRepoCloserMockRecorder) UpdateTrackingSettingConfig(ctx, trackingSettingID, config any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrackingSettingConfig", reflect.TypeOf((*MockRepoCloser)(nil).UpdateTrackingSettingConfig), ctx, trackingSettingID, config)
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ReportRange is what a report counts over, From is inclusive and To exclusive. Buckets start
+// at the Granularity boundaries of Location.
+type ReportRange struct {
+	From        time.Time
+	To          time.Time
+	Granularity entity.ReportGranularity
+	Location    *time.Location
+	Dimensions  []entity.ReportDimension // campaigns are counted by link
+}
+
+func (r ReportRange) has(dimension entity.ReportDimension) bool {
+	for _, d := range r.Dimensions {
+		if d == dimension {
+			return true
+		}
+	}
+	return false
+}
+
+type ReportRepo interface {
+	CountReportClicks(ctx context.Context, tenantID string, rng ReportRange) ([]*entity.ReportCount, error)
+	CountReportTracks(ctx context.Context, trackingSettingID bson.ObjectID, rng ReportRange) ([]*entity.ReportCount, error)
+	CountReportEvents(ctx context.Context, tenantID string, rng ReportRange) ([]*entity.ReportCount, error)
+	// CountReportRollups counts from the daily rollups of the time zone of the range or from the
+	// hourly rollups, the range must start and end at their buckets
+	CountReportRollups(ctx context.Context, tenantID string, rng ReportRange, daily bool) ([]*entity.ReportCount, error)
+}
+
+type reportRepo struct {
+	clicks *mongo.Collection
+	tracks *mongo.Collection
+	events *mongo.Collection
+	hourly *mongo.Collection
+	daily  *mongo.Collection
+}
+
+func NewReportRepo(db *mongo.Database) ReportRepo {
+	return &reportRepo{
+		clicks: db.Collection("click"),
+		tracks: db.Collection("track"),
+		events: db.Collection("event"),
+		hourly: db.Collection("report_rollup_hourly"),
+		daily:  db.Collection("report_rollup_daily"),
+	}
+}
+
+// reportKey groups by the time bucket of timeField and by the dimension expressions, the
+// dimensions without an expression are left empty
+func reportKey(rng ReportRange, timeField string, dimensions map[entity.ReportDimension]any) bson.M {
+	key := bson.M{}
+	if rng.Granularity != entity.ReportGranularityTotal {
+		trunc := bson.M{
+			"date":     "$" + timeField,
+			"unit":     string(rng.Granularity),
+			"timezone": rng.Location.String(),
+		}
+		if rng.Granularity == entity.ReportGranularityWeek {
+			trunc["startOfWeek"] = "monday"
+		}
+		key["time"] = bson.M{"$dateTrunc": trunc}
+	}
+
+	for dimension, expr := range dimensions {
+		if rng.has(dimension) || (dimension == entity.ReportDimensionLink && rng.has(entity.ReportDimensionCampaign)) {
+			key[string(dimension)] = bson.M{"$ifNull": bson.A{expr, ""}}
+		}
+	}
+	return key
+}
+
+func rangeFilter(match bson.M, rng ReportRange) bson.M {
+	match["created_at"] = bson.M{"$gte": rng.From, "$lt": rng.To}
+	match["bot.is_bot"] = bson.M{"$ne": true}
+	return match
+}
+
+func (r *reportRepo) aggregate(ctx context.Context, collection *mongo.Collection, pipeline []bson.M) ([]*entity.ReportCount, error) {
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate %s: %w", collection.Name(), err)
+	}
+	defer cursor.Close(ctx)
+
+	results := []*entity.ReportCount{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
+
+// CountReportClicks counts the human clicks of the links of the tenant
+func (r *reportRepo) CountReportClicks(ctx context.Context, tenantID string, rng ReportRange) ([]*entity.ReportCount, error) {
+	key := reportKey(rng, "created_at", map[entity.ReportDimension]any{
+		entity.ReportDimensionLink:   bson.M{"$toString": "$link_id"},
+		entity.ReportDimensionDevice: "$device.type",
+	})
+
+	pipeline := []bson.M{
+		{"$match": rangeFilter(bson.M{"tenant_id": tenantID}, rng)},
+		{"$group": bson.M{"_id": key, "clicks": bson.M{"$sum": 1}}},
+	}
+	return r.aggregate(ctx, r.clicks, pipeline)
+}
+
+// CountReportTracks counts the human tracks of the tracking setting
+func (r *reportRepo) CountReportTracks(ctx context.Context, trackingSettingID bson.ObjectID,
+	rng ReportRange) ([]*entity.ReportCount, error) {
+	key := reportKey(rng, "created_at", map[entity.ReportDimension]any{
+		entity.ReportDimensionLink:    bson.M{"$toString": "$link_id"},
+		entity.ReportDimensionChannel: "$channel",
+		entity.ReportDimensionDevice:  "$device.type",
+	})
+
+	pipeline := []bson.M{
+		{"$match": rangeFilter(bson.M{"tracking_setting_id": trackingSettingID}, rng)},
+		{"$group": bson.M{"_id": key, "tracks": bson.M{"$sum": 1}}},
+	}
+	return r.aggregate(ctx, r.tracks, pipeline)
+}
+
+// CountReportEvents counts the landings and conversions of the tenant at the time they happened,
+// the link, channel and device are the ones of their track
+func (r *reportRepo) CountReportEvents(ctx context.Context, tenantID string, rng ReportRange) ([]*entity.ReportCount, error) {
+	match := rangeFilter(bson.M{
+		"tenant_id":  tenantID,
+		"event_name": bson.M{"$in": bson.A{entity.EventNameLandingPage, entity.EventNameThankYouPage}},
+	}, rng)
+
+	pipeline := []bson.M{{"$match": match}}
+	if rng.has(entity.ReportDimensionLink) || rng.has(entity.ReportDimensionCampaign) ||
+		rng.has(entity.ReportDimensionChannel) || rng.has(entity.ReportDimensionDevice) {
+		// event.track_id is the hex string of the track id
+		pipeline = append(pipeline,
+			bson.M{"$addFields": bson.M{"track_oid": bson.M{
+				"$convert": bson.M{"input": "$track_id", "to": "objectId", "onError": nil, "onNull": nil},
+			}}},
+			bson.M{"$lookup": bson.M{
+				"from":         "track",
+				"localField":   "track_oid",
+				"foreignField": "_id",
+				"pipeline":     []bson.M{{"$project": bson.M{"link_id": 1, "channel": 1, "device.type": 1}}},
+				"as":           "track",
+			}},
+			bson.M{"$unwind": bson.M{"path": "$track", "preserveNullAndEmptyArrays": true}},
+		)
+	}
+
+	key := reportKey(rng, "created_at", map[entity.ReportDimension]any{
+		entity.ReportDimensionLink:         bson.M{"$toString": "$track.link_id"},
+		entity.ReportDimensionThankYouPage: bson.M{"$toString": "$thank_you_page_id"},
+		entity.ReportDimensionChannel:      "$track.channel",
+		entity.ReportDimensionDevice:       "$track.device.type",
+	})
+
+	isConversion := bson.M{"$eq": bson.A{"$event_name", entity.EventNameThankYouPage}}
+	pipeline = append(pipeline, bson.M{"$group": bson.M{
+		"_id": key,
+		"landings": bson.M{"$sum": bson.M{
+			"$cond": bson.A{bson.M{"$eq": bson.A{"$event_name", entity.EventNameLandingPage}}, 1, 0},
+		}},
+		"conversions": bson.M{"$sum": bson.M{"$cond": bson.A{isConversion, 1, 0}}},
+		"points":      bson.M{"$sum": bson.M{"$cond": bson.A{isConversion, "$points", 0}}},
+		"revenue":     bson.M{"$sum": bson.M{"$cond": bson.A{isConversion, "$revenue", 0}}},
+	}})
+	return r.aggregate(ctx, r.events, pipeline)
+}
+
+func (r *reportRepo) CountReportRollups(ctx context.Context, tenantID string, rng ReportRange,
+	daily bool) ([]*entity.ReportCount, error) {
+	collection := r.hourly
+	match := bson.M{"tenant_id": tenantID, "time": bson.M{"$gte": rng.From, "$lt": rng.To}}
+	if daily {
+		collection = r.daily
+		match["time_zone"] = rng.Location.String()
+	}
+
+	key := reportKey(rng, "time", map[entity.ReportDimension]any{
+		entity.ReportDimensionLink:         "$link",
+		entity.ReportDimensionThankYouPage: "$thank_you_page",
+		entity.ReportDimensionChannel:      "$channel",
+		entity.ReportDimensionDevice:       "$device",
+	})
+
+	group := bson.M{"_id": key}
+	for _, count := range []string{"clicks", "tracks", "landings", "conversions", "points", "revenue"} {
+		group[count] = bson.M{"$sum": "$" + count}
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": group},
+	}
+	return r.aggregate(ctx, collection, pipeline)
+}
@@ -0,0 +1,149 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type TestSuiteReportRepo struct {
+	mongoContainer testcontainers.Container
+	client         *mongo.Client
+	database       *mongo.Database
+	repo           repository.ReportRepo
+}
+
+func setupTestSuiteReportRepo() (*TestSuiteReportRepo, error) {
+	ctx := context.Background()
+
+	mongodbContainer, err := mongodb.Run(ctx, "mongo:8")
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := mongodbContainer.Endpoint(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(fmt.Sprintf("mongodb://%s", endpoint)))
+	if err != nil {
+		return nil, err
+	}
+
+	database := client.Database("test")
+	return &TestSuiteReportRepo{
+		mongoContainer: mongodbContainer,
+		client:         client,
+		database:       database,
+		repo:           repository.NewReportRepo(database),
+	}, nil
+}
+
+func (ts *TestSuiteReportRepo) Cleanup() {
+	ctx := context.Background()
+	if ts.client != nil {
+		ts.client.Disconnect(ctx)
+	}
+	if ts.mongoContainer != nil {
+		ts.mongoContainer.Terminate(ctx)
+	}
+}
+
+func TestReportRepo_Count(t *testing.T) {
+	suite, err := setupTestSuiteReportRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+
+	tenantID := "tenant1"
+	settingID := bson.NewObjectID()
+	linkID := bson.NewObjectID()
+	pageID := bson.NewObjectID()
+	// 23:30 UTC on the 1st is the 2nd in Tokyo
+	day1 := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 3, 1, 23, 30, 0, 0, time.UTC)
+
+	clicks := []any{
+		&entity.Click{LinkID: linkID, TenantID: tenantID, BaseEntity: entity.BaseEntity{CreatedAt: day1}},
+		&entity.Click{LinkID: linkID, TenantID: tenantID, BaseEntity: entity.BaseEntity{CreatedAt: day2}},
+		&entity.Click{LinkID: linkID, TenantID: tenantID, Bot: entity.Bot{IsBot: true}, BaseEntity: entity.BaseEntity{CreatedAt: day2}},
+		&entity.Click{LinkID: linkID, TenantID: "tenant2", BaseEntity: entity.BaseEntity{CreatedAt: day2}},
+	}
+	_, err = suite.database.Collection("click").InsertMany(ctx, clicks)
+	assert.NoError(t, err)
+
+	track := &entity.Track{
+		ID:                bson.NewObjectID(),
+		TrackingSettingID: settingID,
+		LinkID:            linkID,
+		Channel:           entity.ChannelPaidSearch,
+		BaseEntity:        entity.BaseEntity{CreatedAt: day1},
+	}
+	_, err = suite.database.Collection("track").InsertOne(ctx, track)
+	assert.NoError(t, err)
+
+	events := []any{
+		&entity.Event{TrackID: track.ID.Hex(), TenantID: tenantID, EventName: entity.EventNameLandingPage,
+			BaseEntity: entity.BaseEntity{CreatedAt: day1}},
+		&entity.Event{TrackID: track.ID.Hex(), TenantID: tenantID, EventName: entity.EventNameThankYouPage,
+			ThankYouPageID: pageID, Points: 5, Revenue: 1200, BaseEntity: entity.BaseEntity{CreatedAt: day2}},
+	}
+	_, err = suite.database.Collection("event").InsertMany(ctx, events)
+	assert.NoError(t, err)
+
+	rng := repository.ReportRange{
+		From:        time.Date(2025, 3, 1, 0, 0, 0, 0, tokyo),
+		To:          time.Date(2025, 3, 3, 0, 0, 0, 0, tokyo),
+		Granularity: entity.ReportGranularityDay,
+		Location:    tokyo,
+		Dimensions:  []entity.ReportDimension{entity.ReportDimensionChannel},
+	}
+
+	t.Run("should count human clicks by day of the time zone", func(t *testing.T) {
+		counts, err := suite.repo.CountReportClicks(ctx, tenantID, rng)
+		assert.NoError(t, err)
+		assert.Len(t, counts, 2)
+		for _, count := range counts {
+			assert.Equal(t, int64(1), count.Clicks)
+			assert.Equal(t, 0, count.Key.Time.In(tokyo).Hour(), "buckets start at midnight in Tokyo")
+		}
+	})
+
+	t.Run("should count tracks by channel", func(t *testing.T) {
+		counts, err := suite.repo.CountReportTracks(ctx, settingID, rng)
+		assert.NoError(t, err)
+		assert.Len(t, counts, 1)
+		assert.Equal(t, int64(1), counts[0].Tracks)
+		assert.Equal(t, string(entity.ChannelPaidSearch), counts[0].Key.Channel)
+	})
+
+	t.Run("should count events with the dimensions of their track", func(t *testing.T) {
+		total := rng
+		total.Granularity = entity.ReportGranularityTotal
+		total.Dimensions = []entity.ReportDimension{entity.ReportDimensionLink}
+
+		counts, err := suite.repo.CountReportEvents(ctx, tenantID, total)
+		assert.NoError(t, err)
+		assert.Len(t, counts, 1)
+		assert.Equal(t, linkID.Hex(), counts[0].Key.Link)
+		assert.True(t, counts[0].Key.Time.IsZero())
+		assert.Equal(t, int64(1), counts[0].Landings)
+		assert.Equal(t, int64(1), counts[0].Conversions)
+		assert.Equal(t, int64(5), counts[0].Points)
+		assert.Equal(t, float64(1200), counts[0].Revenue)
+	})
+}
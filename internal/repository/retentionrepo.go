@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ArchivableCollections are the collections with retention, see entity.Expirable
+var ArchivableCollections = []string{"event", "track", "click"}
+
+// RetentionRepo works on raw documents, so archives restore exactly what was stored
+type RetentionRepo interface {
+	FindArchivableDocuments(ctx context.Context, collection string, before time.Time, limit int64) ([]bson.Raw, error)
+	MarkDocumentsArchived(ctx context.Context, collection string, ids []bson.ObjectID, archivedAt time.Time) error
+	RestoreDocuments(ctx context.Context, collection string, documents []bson.Raw) (int64, error)
+}
+
+type retentionRepo struct {
+	db *mongo.Database
+}
+
+func NewRetentionRepo(db *mongo.Database) RetentionRepo {
+	return &retentionRepo{
+		db: db,
+	}
+}
+
+func (r *retentionRepo) collection(name string) (*mongo.Collection, error) {
+	for _, c := range ArchivableCollections {
+		if c == name {
+			return r.db.Collection(name), nil
+		}
+	}
+	return nil, fmt.Errorf("collection %q has no retention", name)
+}
+
+// FindArchivableDocuments returns documents whose retention ended and that are not archived yet.
+// Documents of tenants that don't archive already have expire_at and are left to the TTL index.
+func (r *retentionRepo) FindArchivableDocuments(ctx context.Context, collection string,
+	before time.Time, limit int64) ([]bson.Raw, error) {
+	c, err := r.collection(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"retain_until": bson.M{"$lte": before},
+		"expire_at":    bson.M{"$exists": false},
+		"archived_at":  bson.M{"$exists": false},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "retain_until", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := c.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []bson.Raw{}
+	for cursor.Next(ctx) {
+		results = append(results, append(bson.Raw{}, cursor.Current...))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
+
+// MarkDocumentsArchived hands the archived documents over to the TTL index
+func (r *retentionRepo) MarkDocumentsArchived(ctx context.Context, collection string,
+	ids []bson.ObjectID, archivedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	c, err := r.collection(collection)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"archived_at": archivedAt,
+			"expire_at":   archivedAt,
+		},
+	}
+	_, err = c.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+	if err != nil {
+		return fmt.Errorf("failed to mark documents archived: %w", err)
+	}
+	return nil
+}
+
+// RestoreDocuments upserts archived documents. Restored documents have no retention anymore,
+// otherwise the archival job would pick them up again on its next run.
+func (r *retentionRepo) RestoreDocuments(ctx context.Context, collection string, documents []bson.Raw) (int64, error) {
+	if len(documents) == 0 {
+		return 0, nil
+	}
+
+	c, err := r.collection(collection)
+	if err != nil {
+		return 0, err
+	}
+
+	models := make([]mongo.WriteModel, 0, len(documents))
+	for _, document := range documents {
+		doc := bson.M{}
+		if err := bson.Unmarshal(document, &doc); err != nil {
+			return 0, fmt.Errorf("failed to decode archived document: %w", err)
+		}
+
+		id, ok := doc["_id"]
+		if !ok {
+			return 0, fmt.Errorf("archived document has no _id")
+		}
+		delete(doc, "retain_until")
+		delete(doc, "expire_at")
+		delete(doc, "archived_at")
+
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetReplacement(doc).
+			SetUpsert(true))
+	}
+
+	res, err := c.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, fmt.Errorf("failed to restore documents: %w", err)
+	}
+	return res.UpsertedCount + res.MatchedCount, nil
+}
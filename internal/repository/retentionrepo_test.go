@@ -0,0 +1,136 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type TestSuiteRetentionRepo struct {
+	mongoContainer testcontainers.Container
+	client         *mongo.Client
+	database       *mongo.Database
+	repo           repository.RetentionRepo
+}
+
+func setupTestSuiteRetentionRepo() (*TestSuiteRetentionRepo, error) {
+	ctx := context.Background()
+
+	mongodbContainer, err := mongodb.Run(ctx, "mongo:8")
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := mongodbContainer.Endpoint(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(fmt.Sprintf("mongodb://%s", endpoint)))
+	if err != nil {
+		return nil, err
+	}
+
+	database := client.Database("test")
+	repo := repository.NewRetentionRepo(database)
+
+	return &TestSuiteRetentionRepo{
+		mongoContainer: mongodbContainer,
+		client:         client,
+		database:       database,
+		repo:           repo,
+	}, nil
+}
+
+func (ts *TestSuiteRetentionRepo) Cleanup() {
+	ctx := context.Background()
+	if ts.client != nil {
+		ts.client.Disconnect(ctx)
+	}
+	if ts.mongoContainer != nil {
+		ts.mongoContainer.Terminate(ctx)
+	}
+}
+
+func TestRetentionRepo_ArchiveAndRestore(t *testing.T) {
+	suite, err := setupTestSuiteRetentionRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	past := now.AddDate(0, 0, -1)
+	future := now.AddDate(0, 0, 1)
+
+	expired := &entity.Event{TrackID: "track1", Url: "https://example.com/lp"}
+	expired.RetainUntil = &past
+	kept := &entity.Event{TrackID: "track2", Url: "https://example.com/lp"}
+	kept.RetainUntil = &future
+	deleted := &entity.Event{TrackID: "track3", Url: "https://example.com/lp"}
+	deleted.RetainUntil = &past
+	deleted.ExpireAt = &past // tenant without archive, left to the TTL index
+	forever := &entity.Event{TrackID: "track4", Url: "https://example.com/lp"}
+
+	collection := suite.database.Collection("event")
+	for _, event := range []*entity.Event{expired, kept, deleted, forever} {
+		res, err := collection.InsertOne(ctx, event)
+		assert.NoError(t, err)
+		event.ID = res.InsertedID.(bson.ObjectID)
+	}
+
+	t.Run("should only find documents to archive", func(t *testing.T) {
+		documents, err := suite.repo.FindArchivableDocuments(ctx, "event", now, 10)
+		assert.NoError(t, err)
+		assert.Len(t, documents, 1)
+		assert.Equal(t, expired.ID, documents[0].Lookup("_id").ObjectID())
+	})
+
+	t.Run("should hand archived documents over to the TTL index", func(t *testing.T) {
+		err := suite.repo.MarkDocumentsArchived(ctx, "event", []bson.ObjectID{expired.ID}, now)
+		assert.NoError(t, err)
+
+		var event entity.Event
+		err = collection.FindOne(ctx, bson.M{"_id": expired.ID}).Decode(&event)
+		assert.NoError(t, err)
+		assert.NotNil(t, event.ExpireAt)
+		assert.NotNil(t, event.ArchivedAt)
+
+		documents, err := suite.repo.FindArchivableDocuments(ctx, "event", now, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, documents)
+	})
+
+	t.Run("should restore documents without retention", func(t *testing.T) {
+		raw, err := bson.Marshal(expired)
+		assert.NoError(t, err)
+
+		_, err = collection.DeleteOne(ctx, bson.M{"_id": expired.ID})
+		assert.NoError(t, err)
+
+		restored, err := suite.repo.RestoreDocuments(ctx, "event", []bson.Raw{raw})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), restored)
+
+		var event entity.Event
+		err = collection.FindOne(ctx, bson.M{"_id": expired.ID}).Decode(&event)
+		assert.NoError(t, err)
+		assert.Equal(t, "track1", event.TrackID)
+		assert.Nil(t, event.RetainUntil)
+		assert.Nil(t, event.ExpireAt)
+	})
+
+	t.Run("should reject collections without retention", func(t *testing.T) {
+		_, err := suite.repo.FindArchivableDocuments(ctx, "link", now, 10)
+		assert.Error(t, err)
+	})
+}
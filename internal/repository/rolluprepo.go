@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// reportWatermarkID is the watermark of the report rollups
+const reportWatermarkID = "report"
+
+// hourlyRollupKey and dailyRollupKey are the unique indexes the rollups are merged on
+var (
+	hourlyRollupKey = bson.A{"tenant_id", "time", "link", "thank_you_page", "channel", "device"}
+	dailyRollupKey  = bson.A{"tenant_id", "time_zone", "time", "link", "thank_you_page", "channel", "device"}
+)
+
+// MaxDayLength bounds the days of every time zone, with their offset and daylight saving time
+const MaxDayLength = 26 * time.Hour
+
+type RollupRepo interface {
+	// RollupReportHours counts again the hours of [from, to) from the clicks, tracks and events
+	RollupReportHours(ctx context.Context, from, to time.Time) error
+	// RollupReportDays counts again the days starting in [from, to) from the hourly rollups, for
+	// every tenant when tenantID is empty
+	RollupReportDays(ctx context.Context, tenantID string, from, to time.Time) error
+	RemoveReportRollups(ctx context.Context, granularity entity.ReportGranularity, tenantID string,
+		from, to time.Time) (int64, error)
+	// FindDailyRollupTimeZones returns the time zones the daily rollups of the tenant are in
+	FindDailyRollupTimeZones(ctx context.Context, tenantID string) ([]string, error)
+	FindRollupWatermark(ctx context.Context) (time.Time, error)
+	UpdateRollupWatermark(ctx context.Context, until time.Time) error
+}
+
+type rollupRepo struct {
+	db         *mongo.Database
+	hourly     *mongo.Collection
+	daily      *mongo.Collection
+	watermarks *mongo.Collection
+}
+
+func NewRollupRepo(db *mongo.Database) RollupRepo {
+	return &rollupRepo{
+		db:         db,
+		hourly:     db.Collection("report_rollup_hourly"),
+		daily:      db.Collection("report_rollup_daily"),
+		watermarks: db.Collection("rollup_watermark"),
+	}
+}
+
+// matchTimeRange adds the [from, to) condition of field to the filter, a zero bound is left open
+func matchTimeRange(filter bson.M, field string, from, to time.Time) bson.M {
+	r := bson.M{}
+	if !from.IsZero() {
+		r["$gte"] = from
+	}
+	if !to.IsZero() {
+		r["$lt"] = to
+	}
+	if len(r) > 0 {
+		filter[field] = r
+	}
+	return filter
+}
+
+// hourlyRollupStages flattens the group of a source and merges the counts it owns into the
+// hourly rollups, the counts of the other sources are kept
+func hourlyRollupStages(counts ...string) []bson.M {
+	project := bson.M{
+		"_id":            0,
+		"tenant_id":      "$_id.tenant_id",
+		"time":           "$_id.time",
+		"link":           bson.M{"$ifNull": bson.A{"$_id.link", ""}},
+		"thank_you_page": bson.M{"$ifNull": bson.A{"$_id.thank_you_page", ""}},
+		"channel":        bson.M{"$ifNull": bson.A{"$_id.channel", ""}},
+		"device":         bson.M{"$ifNull": bson.A{"$_id.device", ""}},
+	}
+	for _, count := range counts {
+		project[count] = 1
+	}
+
+	return []bson.M{
+		{"$project": project},
+		{"$merge": bson.M{
+			"into":           "report_rollup_hourly",
+			"on":             hourlyRollupKey,
+			"whenMatched":    "merge",
+			"whenNotMatched": "insert",
+		}},
+	}
+}
+
+func hourOf(field string) bson.M {
+	return bson.M{"$dateTrunc": bson.M{"date": "$" + field, "unit": "hour"}}
+}
+
+func (r *rollupRepo) aggregate(ctx context.Context, collection *mongo.Collection, pipeline []bson.M) error {
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to roll up %s: %w", collection.Name(), err)
+	}
+	return cursor.Close(ctx)
+}
+
+// RollupReportHours replaces the counts of the hours it touches, running it again over the same
+// hours gives the same rollups
+func (r *rollupRepo) RollupReportHours(ctx context.Context, from, to time.Time) error {
+	if !from.IsZero() {
+		from = from.Truncate(time.Hour)
+	}
+	match := matchTimeRange(bson.M{"bot.is_bot": bson.M{"$ne": true}}, "created_at", from, to)
+
+	clicks := append([]bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"tenant_id": "$tenant_id",
+				"time":      hourOf("created_at"),
+				"link":      bson.M{"$toString": "$link_id"},
+				"device":    "$device.type",
+			},
+			"clicks": bson.M{"$sum": 1},
+		}},
+	}, hourlyRollupStages("clicks")...)
+	if err := r.aggregate(ctx, r.db.Collection("click"), clicks); err != nil {
+		return err
+	}
+
+	// tracks have the tenant of their tracking setting
+	tracks := append([]bson.M{
+		{"$match": match},
+		{"$lookup": bson.M{
+			"from":         "tracking_setting",
+			"localField":   "tracking_setting_id",
+			"foreignField": "_id",
+			"pipeline":     []bson.M{{"$project": bson.M{"tenant_id": 1}}},
+			"as":           "tracking_setting",
+		}},
+		{"$unwind": "$tracking_setting"},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"tenant_id": "$tracking_setting.tenant_id",
+				"time":      hourOf("created_at"),
+				"link":      bson.M{"$toString": "$link_id"},
+				"channel":   "$channel",
+				"device":    "$device.type",
+			},
+			"tracks": bson.M{"$sum": 1},
+		}},
+	}, hourlyRollupStages("tracks")...)
+	if err := r.aggregate(ctx, r.db.Collection("track"), tracks); err != nil {
+		return err
+	}
+
+	eventMatch := matchTimeRange(bson.M{
+		"bot.is_bot": bson.M{"$ne": true},
+		"tenant_id":  bson.M{"$exists": true},
+		"event_name": bson.M{"$in": bson.A{entity.EventNameLandingPage, entity.EventNameThankYouPage}},
+	}, "created_at", from, to)
+	isConversion := bson.M{"$eq": bson.A{"$event_name", entity.EventNameThankYouPage}}
+	events := append([]bson.M{
+		{"$match": eventMatch},
+		// event.track_id is the hex string of the track id
+		{"$addFields": bson.M{"track_oid": bson.M{
+			"$convert": bson.M{"input": "$track_id", "to": "objectId", "onError": nil, "onNull": nil},
+		}}},
+		{"$lookup": bson.M{
+			"from":         "track",
+			"localField":   "track_oid",
+			"foreignField": "_id",
+			"pipeline":     []bson.M{{"$project": bson.M{"link_id": 1, "channel": 1, "device.type": 1}}},
+			"as":           "track",
+		}},
+		{"$unwind": bson.M{"path": "$track", "preserveNullAndEmptyArrays": true}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"tenant_id":      "$tenant_id",
+				"time":           hourOf("created_at"),
+				"link":           bson.M{"$toString": "$track.link_id"},
+				"thank_you_page": bson.M{"$toString": "$thank_you_page_id"},
+				"channel":        "$track.channel",
+				"device":         "$track.device.type",
+			},
+			"landings": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$eq": bson.A{"$event_name", entity.EventNameLandingPage}}, 1, 0},
+			}},
+			"conversions": bson.M{"$sum": bson.M{"$cond": bson.A{isConversion, 1, 0}}},
+			"points":      bson.M{"$sum": bson.M{"$cond": bson.A{isConversion, "$points", 0}}},
+			"revenue":     bson.M{"$sum": bson.M{"$cond": bson.A{isConversion, "$revenue", 0}}},
+		}},
+	}, hourlyRollupStages("landings", "conversions", "points", "revenue")...)
+	return r.aggregate(ctx, r.db.Collection("event"), events)
+}
+
+// RollupReportDays sums the hourly rollups by day of the time zone of each tenant and replaces
+// the daily rollups of the days starting in [from, to)
+func (r *rollupRepo) RollupReportDays(ctx context.Context, tenantID string, from, to time.Time) error {
+	end := to
+	if !end.IsZero() {
+		end = end.Add(MaxDayLength)
+	}
+
+	match := matchTimeRange(bson.M{}, "time", from, end)
+	if tenantID != "" {
+		match["tenant_id"] = tenantID
+	}
+
+	sum := func(field string) bson.M {
+		return bson.M{"$sum": bson.M{"$ifNull": bson.A{"$" + field, 0}}}
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$lookup": bson.M{
+			"from":         "tracking_setting",
+			"localField":   "tenant_id",
+			"foreignField": "tenant_id",
+			"pipeline":     []bson.M{{"$project": bson.M{"time_zone": 1}}},
+			"as":           "tracking_setting",
+		}},
+		{"$addFields": bson.M{"time_zone": bson.M{"$ifNull": bson.A{bson.M{"$first": "$tracking_setting.time_zone"}, ""}}}},
+		{"$addFields": bson.M{"time_zone": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$time_zone", ""}}, "UTC", "$time_zone"}}}},
+		{"$addFields": bson.M{"day": bson.M{"$dateTrunc": bson.M{"date": "$time", "unit": "day", "timezone": "$time_zone"}}}},
+		// the days starting before from are only partly in the hours
+		{"$match": matchTimeRange(bson.M{}, "day", from, to)},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"tenant_id":      "$tenant_id",
+				"time_zone":      "$time_zone",
+				"time":           "$day",
+				"link":           "$link",
+				"thank_you_page": "$thank_you_page",
+				"channel":        "$channel",
+				"device":         "$device",
+			},
+			"clicks":      sum("clicks"),
+			"tracks":      sum("tracks"),
+			"landings":    sum("landings"),
+			"conversions": sum("conversions"),
+			"points":      sum("points"),
+			"revenue":     sum("revenue"),
+		}},
+		{"$project": bson.M{
+			"_id":            0,
+			"tenant_id":      "$_id.tenant_id",
+			"time_zone":      "$_id.time_zone",
+			"time":           "$_id.time",
+			"link":           "$_id.link",
+			"thank_you_page": "$_id.thank_you_page",
+			"channel":        "$_id.channel",
+			"device":         "$_id.device",
+			"clicks":         1,
+			"tracks":         1,
+			"landings":       1,
+			"conversions":    1,
+			"points":         1,
+			"revenue":        1,
+		}},
+		{"$merge": bson.M{
+			"into":           "report_rollup_daily",
+			"on":             dailyRollupKey,
+			"whenMatched":    "replace",
+			"whenNotMatched": "insert",
+		}},
+	}
+	return r.aggregate(ctx, r.hourly, pipeline)
+}
+
+// RemoveReportRollups deletes the hourly or daily rollups starting in [from, to), of every tenant
+// when tenantID is empty
+func (r *rollupRepo) RemoveReportRollups(ctx context.Context, granularity entity.ReportGranularity,
+	tenantID string, from, to time.Time) (int64, error) {
+	collection := r.hourly
+	if granularity == entity.ReportGranularityDay {
+		collection = r.daily
+	}
+
+	filter := matchTimeRange(bson.M{}, "time", from, to)
+	if tenantID != "" {
+		filter["tenant_id"] = tenantID
+	}
+
+	res, err := collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove rollups: %w", err)
+	}
+	return res.DeletedCount, nil
+}
+
+func (r *rollupRepo) FindDailyRollupTimeZones(ctx context.Context, tenantID string) ([]string, error) {
+	timeZones := []string{}
+	err := r.daily.Distinct(ctx, "time_zone", bson.M{"tenant_id": tenantID}).Decode(&timeZones)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find daily rollup time zones: %w", err)
+	}
+	return timeZones, nil
+}
+
+// FindRollupWatermark is the zero time before the first rollup run
+func (r *rollupRepo) FindRollupWatermark(ctx context.Context) (time.Time, error) {
+	watermark := &entity.RollupWatermark{}
+	err := r.watermarks.FindOne(ctx, bson.M{"_id": reportWatermarkID}).Decode(watermark)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, fmt.Errorf("failed to find rollup watermark: %w", err)
+	}
+	return watermark.Until, nil
+}
+
+func (r *rollupRepo) UpdateRollupWatermark(ctx context.Context, until time.Time) error {
+	_, err := r.watermarks.UpdateOne(ctx,
+		bson.M{"_id": reportWatermarkID},
+		bson.M{"$set": bson.M{"until": until, "updated_at": time.Now().UTC()}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update rollup watermark: %w", err)
+	}
+	return nil
+}
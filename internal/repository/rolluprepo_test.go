@@ -0,0 +1,192 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type TestSuiteRollupRepo struct {
+	mongoContainer testcontainers.Container
+	client         *mongo.Client
+	database       *mongo.Database
+	repo           repository.RollupRepo
+	reportRepo     repository.ReportRepo
+}
+
+func setupTestSuiteRollupRepo() (*TestSuiteRollupRepo, error) {
+	ctx := context.Background()
+
+	mongodbContainer, err := mongodb.Run(ctx, "mongo:8")
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := mongodbContainer.Endpoint(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(fmt.Sprintf("mongodb://%s", endpoint)))
+	if err != nil {
+		return nil, err
+	}
+
+	database := client.Database("test")
+	// $merge needs the unique indexes of the rollups
+	for name, keys := range map[string]bson.D{
+		"report_rollup_hourly": {{Key: "tenant_id", Value: 1}, {Key: "time", Value: 1}, {Key: "link", Value: 1},
+			{Key: "thank_you_page", Value: 1}, {Key: "channel", Value: 1}, {Key: "device", Value: 1}},
+		"report_rollup_daily": {{Key: "tenant_id", Value: 1}, {Key: "time_zone", Value: 1}, {Key: "time", Value: 1},
+			{Key: "link", Value: 1}, {Key: "thank_you_page", Value: 1}, {Key: "channel", Value: 1}, {Key: "device", Value: 1}},
+	} {
+		_, err := database.Collection(name).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    keys,
+			Options: options.Index().SetUnique(true),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &TestSuiteRollupRepo{
+		mongoContainer: mongodbContainer,
+		client:         client,
+		database:       database,
+		repo:           repository.NewRollupRepo(database),
+		reportRepo:     repository.NewReportRepo(database),
+	}, nil
+}
+
+func (ts *TestSuiteRollupRepo) Cleanup() {
+	ctx := context.Background()
+	if ts.client != nil {
+		ts.client.Disconnect(ctx)
+	}
+	if ts.mongoContainer != nil {
+		ts.mongoContainer.Terminate(ctx)
+	}
+}
+
+func TestRollupRepo_RollupReports(t *testing.T) {
+	suite, err := setupTestSuiteRollupRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+
+	tenantID := "tenant1"
+	setting := &entity.TrackingSetting{
+		ID:                    bson.NewObjectID(),
+		TenantID:              tenantID,
+		TrackingSettingConfig: entity.TrackingSettingConfig{TimeZone: "Asia/Tokyo"},
+	}
+	_, err = suite.database.Collection("tracking_setting").InsertOne(ctx, setting)
+	assert.NoError(t, err)
+
+	linkID := bson.NewObjectID()
+	// 10:15 and 23:30 UTC on the 1st are the 1st and the 2nd in Tokyo
+	day1 := time.Date(2025, 3, 1, 10, 15, 0, 0, time.UTC)
+	day2 := time.Date(2025, 3, 1, 23, 30, 0, 0, time.UTC)
+
+	clicks := []any{
+		&entity.Click{LinkID: linkID, TenantID: tenantID, BaseEntity: entity.BaseEntity{CreatedAt: day1}},
+		&entity.Click{LinkID: linkID, TenantID: tenantID, BaseEntity: entity.BaseEntity{CreatedAt: day1.Add(time.Minute)}},
+		&entity.Click{LinkID: linkID, TenantID: tenantID, BaseEntity: entity.BaseEntity{CreatedAt: day2}},
+		&entity.Click{LinkID: linkID, TenantID: tenantID, Bot: entity.Bot{IsBot: true}, BaseEntity: entity.BaseEntity{CreatedAt: day2}},
+	}
+	_, err = suite.database.Collection("click").InsertMany(ctx, clicks)
+	assert.NoError(t, err)
+
+	track := &entity.Track{
+		ID:                bson.NewObjectID(),
+		TrackingSettingID: setting.ID,
+		LinkID:            linkID,
+		BaseEntity:        entity.BaseEntity{CreatedAt: day1},
+	}
+	_, err = suite.database.Collection("track").InsertOne(ctx, track)
+	assert.NoError(t, err)
+
+	event := &entity.Event{TrackID: track.ID.Hex(), TenantID: tenantID, EventName: entity.EventNameThankYouPage,
+		Points: 5, Revenue: 1200, BaseEntity: entity.BaseEntity{CreatedAt: day2}}
+	_, err = suite.database.Collection("event").InsertOne(ctx, event)
+	assert.NoError(t, err)
+
+	t.Run("should roll up hours and days again without counting twice", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, suite.repo.RollupReportHours(ctx, time.Time{}, time.Time{}))
+			assert.NoError(t, suite.repo.RollupReportDays(ctx, "", time.Time{}, time.Time{}))
+		}
+
+		hours, err := suite.database.Collection("report_rollup_hourly").CountDocuments(ctx, bson.M{})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), hours)
+
+		rollups := []*entity.ReportRollup{}
+		cursor, err := suite.database.Collection("report_rollup_daily").Find(ctx, bson.M{},
+			options.Find().SetSort(bson.M{"time": 1}))
+		assert.NoError(t, err)
+		assert.NoError(t, cursor.All(ctx, &rollups))
+
+		assert.Len(t, rollups, 2)
+		assert.Equal(t, "Asia/Tokyo", rollups[0].TimeZone)
+		assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, tokyo).UTC(), rollups[0].Time.UTC())
+		assert.Equal(t, int64(2), rollups[0].Clicks)
+		assert.Equal(t, int64(1), rollups[0].Tracks)
+		assert.Equal(t, int64(1), rollups[1].Clicks)
+		assert.Equal(t, int64(1), rollups[1].Conversions)
+		assert.Equal(t, float64(1200), rollups[1].Revenue)
+	})
+
+	t.Run("should count a report from the daily rollups", func(t *testing.T) {
+		counts, err := suite.reportRepo.CountReportRollups(ctx, tenantID, repository.ReportRange{
+			From:        time.Date(2025, 3, 1, 0, 0, 0, 0, tokyo),
+			To:          time.Date(2025, 3, 3, 0, 0, 0, 0, tokyo),
+			Granularity: entity.ReportGranularityTotal,
+			Location:    tokyo,
+			Dimensions:  []entity.ReportDimension{entity.ReportDimensionLink},
+		}, true)
+		assert.NoError(t, err)
+		assert.Len(t, counts, 1)
+		assert.Equal(t, linkID.Hex(), counts[0].Key.Link)
+		assert.Equal(t, int64(3), counts[0].Clicks)
+		assert.Equal(t, int64(5), counts[0].Points)
+	})
+
+	t.Run("should find the time zones of the daily rollups", func(t *testing.T) {
+		timeZones, err := suite.repo.FindDailyRollupTimeZones(ctx, tenantID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Asia/Tokyo"}, timeZones)
+
+		timeZones, err = suite.repo.FindDailyRollupTimeZones(ctx, "tenant2")
+		assert.NoError(t, err)
+		assert.Empty(t, timeZones)
+	})
+
+	t.Run("should remove the rollups and keep the watermark", func(t *testing.T) {
+		removed, err := suite.repo.RemoveReportRollups(ctx, entity.ReportGranularityHour, tenantID, time.Time{}, day2)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), removed)
+
+		watermark, err := suite.repo.FindRollupWatermark(ctx)
+		assert.NoError(t, err)
+		assert.True(t, watermark.IsZero())
+
+		assert.NoError(t, suite.repo.UpdateRollupWatermark(ctx, day2.Truncate(time.Hour)))
+		watermark, err = suite.repo.FindRollupWatermark(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, day2.Truncate(time.Hour), watermark.UTC())
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 // TODO: put unique index on tenant_id
@@ -16,9 +17,15 @@ import (
 // get tracking settings
 type TrackingSettingRepo interface {
 	FindOrCreateWithPagesByTenantID(ctx context.Context, tenantID string) (*entity.TrackingSettingWithPages, error)
+	// FindTrackingSettingWithPagesByTenantID returns mongo.ErrNoDocuments instead of creating the tracking setting
+	FindTrackingSettingWithPagesByTenantID(ctx context.Context, tenantID string) (*entity.TrackingSettingWithPages, error)
 	FindTrackingSettingByID(ctx context.Context, id bson.ObjectID) (*entity.TrackingSetting, error)
 	FindTrackingSettingWithPagesByID(ctx context.Context, trackingSettingID bson.ObjectID) (*entity.TrackingSettingWithPages, error)
 	IsTrackingSettingIDExist(ctx context.Context, id bson.ObjectID) (bool, error)
+	UpdateTrackingSettingConfig(ctx context.Context, trackingSettingID bson.ObjectID, config entity.TrackingSettingConfig) error
+	IsDomainRegistered(ctx context.Context, domains []string) (bool, error)
+	FindTenantIDByLinkDomain(ctx context.Context, domain string) (string, error)
+	FindTrackingSettingsByDomains(ctx context.Context, domains []string) ([]*entity.TrackingSetting, error)
 }
 
 type trackingSettingRepo struct {
@@ -35,7 +42,7 @@ func NewTrackingSettingRepo(db *mongo.Database) TrackingSettingRepo {
 
 func (r *trackingSettingRepo) FindOrCreateWithPagesByTenantID(ctx context.Context,
 	tenantID string) (*entity.TrackingSettingWithPages, error) {
-	existing, err := r.FindTrakingSettingWithPagesByTenantID(ctx, tenantID)
+	existing, err := r.FindTrackingSettingWithPagesByTenantID(ctx, tenantID)
 	if err == nil {
 		return existing, nil
 	}
@@ -103,7 +110,7 @@ func (r *trackingSettingRepo) FindTrackingSettingWithPagesByID(ctx context.Conte
 	return &results[0], nil
 }
 
-func (r *trackingSettingRepo) FindTrakingSettingWithPagesByTenantID(ctx context.Context,
+func (r *trackingSettingRepo) FindTrackingSettingWithPagesByTenantID(ctx context.Context,
 	tenantID string) (*entity.TrackingSettingWithPages, error) {
 	pipeline := []bson.M{
 		{
@@ -138,9 +145,28 @@ func (r *trackingSettingRepo) FindTrakingSettingWithPagesByTenantID(ctx context.
 	return &results[0], nil
 }
 
-// TODO: update fields (PATCH)
-func (r *trackingSettingRepo) UpdateFieldsAndReturn() {
+// UpdateTrackingSettingConfig replaces the tenant configurable fields
+func (r *trackingSettingRepo) UpdateTrackingSettingConfig(ctx context.Context, trackingSettingID bson.ObjectID,
+	config entity.TrackingSettingConfig) error {
+	fields, err := bson.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tracking setting config: %w", err)
+	}
+
+	set := bson.M{}
+	if err := bson.Unmarshal(fields, &set); err != nil {
+		return fmt.Errorf("failed to marshal tracking setting config: %w", err)
+	}
+	set["updated_at"] = time.Now().UTC()
 
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": trackingSettingID}, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("failed to update tracking setting: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 
 func (r *trackingSettingRepo) IsTrackingSettingIDExist(ctx context.Context, id bson.ObjectID) (bool, error) {
@@ -161,3 +187,37 @@ func (r *trackingSettingRepo) FindTrackingSettingByID(ctx context.Context, id bs
 
 	return &setting, nil
 }
+
+// IsDomainRegistered reports whether any tracking setting owns one of the domains
+func (r *trackingSettingRepo) IsDomainRegistered(ctx context.Context, domains []string) (bool, error) {
+	filter := bson.M{"domains": bson.M{"$in": domains}}
+
+	count, err := r.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+// FindTrackingSettingsByDomains returns the tracking settings owning one of the domains
+func (r *trackingSettingRepo) FindTrackingSettingsByDomains(ctx context.Context,
+	domains []string) ([]*entity.TrackingSetting, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"domains": bson.M{"$in": domains}})
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*entity.TrackingSetting{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
+
+// FindTenantIDByLinkDomain returns the tenant owning the branded short link domain
+func (r *trackingSettingRepo) FindTenantIDByLinkDomain(ctx context.Context, domain string) (string, error) {
+	var trackingSetting entity.TrackingSetting
+	opts := options.FindOne().SetProjection(bson.M{"tenant_id": 1})
+	err := r.collection.FindOne(ctx, bson.M{"link_domains": domain}, opts).Decode(&trackingSetting)
+	if err != nil {
+		return "", err
+	}
+	return trackingSetting.TenantID, nil
+}
@@ -3,12 +3,15 @@ package repository_test
 import (
 	"context"
 	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
 	"github/michaellimmm/turakkingu/internal/repository"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
@@ -32,7 +35,13 @@ func setupTestSuiteTrackingSettingRepo() (*TestSuiteTrackingSettingRepo, error)
 		return nil, err
 	}
 
-	client, err := mongo.Connect(options.Client().ApplyURI(fmt.Sprintf("mongodb://%s", endpoint)))
+	uri := fmt.Sprintf("mongodb://%s", endpoint)
+	// the link domains need their unique index
+	if err := repository.RunMigrations(&core.Config{MongoDBUri: uri, MongoDBName: "test"}); err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
 	if err != nil {
 		return nil, err
 	}
@@ -74,4 +83,60 @@ func TestTrackingSettingRepo_FindOrCreateWithPages(t *testing.T) {
 		assert.False(t, tracking.CreatedAt.IsZero(), "CreatedAt should be setted")
 		assert.False(t, tracking.UpdatedAt.IsZero(), "UpdatedAt should be setted")
 	})
+
+	t.Run("should find tracking setting without creating it", func(t *testing.T) {
+		tracking, err := suite.repo.FindTrackingSettingWithPagesByTenantID(ctx, "tenant1")
+		assert.NoError(t, err)
+		assert.Equal(t, "tenant1", tracking.TenantID)
+
+		_, err = suite.repo.FindTrackingSettingWithPagesByTenantID(ctx, "tenant2")
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+}
+
+func TestTrackingSettingRepo_UpdateTrackingSettingConfig(t *testing.T) {
+	suite, err := setupTestSuiteTrackingSettingRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+
+	t.Run("should update the tenant configurable fields", func(t *testing.T) {
+		tracking, err := suite.repo.FindOrCreateWithPagesByTenantID(ctx, "tenant1")
+		assert.NoError(t, err)
+
+		config := entity.TrackingSettingConfig{
+			Retention: entity.RetentionPolicy{EventDays: 90, TrackDays: 365, Archive: true},
+			Consent:   entity.ConsentPolicy{DeniedMode: entity.ConsentModeDrop},
+		}
+		err = suite.repo.UpdateTrackingSettingConfig(ctx, tracking.ID, config)
+		assert.NoError(t, err)
+
+		updated, err := suite.repo.FindTrackingSettingByID(ctx, tracking.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, config, updated.TrackingSettingConfig)
+		assert.Equal(t, "tenant1", updated.TenantID)
+	})
+
+	t.Run("should return no documents for unknown tracking setting", func(t *testing.T) {
+		err := suite.repo.UpdateTrackingSettingConfig(ctx, bson.NewObjectID(), entity.TrackingSettingConfig{})
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+
+	t.Run("should register a link domain for one tracking setting only", func(t *testing.T) {
+		tracking, err := suite.repo.FindOrCreateWithPagesByTenantID(ctx, "tenant2")
+		assert.NoError(t, err)
+		other, err := suite.repo.FindOrCreateWithPagesByTenantID(ctx, "tenant3")
+		assert.NoError(t, err)
+		_, err = suite.repo.FindOrCreateWithPagesByTenantID(ctx, "tenant4")
+		assert.NoError(t, err, "tracking settings without link domain should not conflict")
+
+		err = suite.repo.UpdateTrackingSettingConfig(ctx, tracking.ID,
+			entity.TrackingSettingConfig{LinkDomains: []string{"go.cardealer.local"}})
+		assert.NoError(t, err)
+
+		err = suite.repo.UpdateTrackingSettingConfig(ctx, other.ID,
+			entity.TrackingSettingConfig{LinkDomains: []string{"go.cardealer.local"}})
+		assert.True(t, mongo.IsDuplicateKeyError(err))
+	})
 }
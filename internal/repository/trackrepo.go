@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github/michaellimmm/turakkingu/internal/entity"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -15,6 +16,17 @@ type TrackRepo interface {
 	IsTrackIDExist(ctx context.Context, id bson.ObjectID) (bool, error)
 	FindTrackByID(ctx context.Context, id bson.ObjectID) (*entity.Track, error)
 	FindTrackByIDWithThankYouPages(ctx context.Context, id bson.ObjectID) (*entity.TrackWithThankYouPages, error)
+	FindTracksBySubject(ctx context.Context, trackingSettingID bson.ObjectID, ids []bson.ObjectID,
+		endUserIDs []string) ([]*entity.Track, error)
+	DeleteTracksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error)
+	AnonymizeTracksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error)
+	UpdateTrackDevice(ctx context.Context, id bson.ObjectID, device entity.Device) error
+	UpdateTrackGeo(ctx context.Context, id bson.ObjectID, geo entity.Geo) error
+	UpdateTrackBot(ctx context.Context, id bson.ObjectID, bot entity.Bot) error
+	CountTracksByDimension(ctx context.Context, trackingSettingID bson.ObjectID, dimension entity.TrackDimension,
+		from, to time.Time) ([]*entity.BreakdownRow, error)
+	CountLinkTracksByVariant(ctx context.Context, linkID bson.ObjectID, from, to time.Time) ([]*entity.BreakdownRow, error)
+	CountTracksByLinks(ctx context.Context, linkIDs []bson.ObjectID, from, to time.Time) ([]*entity.BreakdownRow, error)
 }
 
 type trackRepo struct {
@@ -131,3 +143,202 @@ func (r *trackRepo) FindTrackByIDWithThankYouPages(ctx context.Context, id bson.
 
 	return &results[0], nil
 }
+
+func (r *trackRepo) FindTracksBySubject(ctx context.Context, trackingSettingID bson.ObjectID, ids []bson.ObjectID,
+	endUserIDs []string) ([]*entity.Track, error) {
+	if len(ids) == 0 && len(endUserIDs) == 0 {
+		return []*entity.Track{}, nil
+	}
+
+	filter := bson.M{
+		"tracking_setting_id": trackingSettingID,
+		"$or": []bson.M{
+			{"_id": bson.M{"$in": ids}},
+			{"end_user_id": bson.M{"$in": endUserIDs}},
+		},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*entity.Track{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
+
+func (r *trackRepo) DeleteTracksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	res, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete tracks: %w", err)
+	}
+	return res.DeletedCount, nil
+}
+
+func (r *trackRepo) AnonymizeTracksByIDs(ctx context.Context, ids []bson.ObjectID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"end_user_id":    entity.AnonymizedValue,
+			"session_id":     "",
+			"metadata":       bson.M{},
+			"click_id.value": "",
+			"user_agent":     "",
+			"ip":             "",
+			"updated_at":     time.Now().UTC(),
+		},
+	}
+	res, err := r.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize tracks: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *trackRepo) UpdateTrackDevice(ctx context.Context, id bson.ObjectID, device entity.Device) error {
+	update := bson.M{
+		"$set": bson.M{
+			"device":     device,
+			"updated_at": time.Now().UTC(),
+		},
+	}
+	res, err := r.collection.UpdateByID(ctx, id, update)
+	if err != nil {
+		return fmt.Errorf("failed to update track device: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *trackRepo) UpdateTrackGeo(ctx context.Context, id bson.ObjectID, geo entity.Geo) error {
+	update := bson.M{
+		"$set": bson.M{
+			"geo":        geo,
+			"updated_at": time.Now().UTC(),
+		},
+	}
+	res, err := r.collection.UpdateByID(ctx, id, update)
+	if err != nil {
+		return fmt.Errorf("failed to update track geo: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *trackRepo) UpdateTrackBot(ctx context.Context, id bson.ObjectID, bot entity.Bot) error {
+	update := bson.M{
+		"$set": bson.M{
+			"bot":        bot,
+			"updated_at": time.Now().UTC(),
+		},
+	}
+	res, err := r.collection.UpdateByID(ctx, id, update)
+	if err != nil {
+		return fmt.Errorf("failed to update track bot: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// CountTracksByDimension groups the tracks created in [from, to) by the dimension, zero times are open bounds.
+// Bot tracks and events are left out.
+func (r *trackRepo) CountTracksByDimension(ctx context.Context, trackingSettingID bson.ObjectID,
+	dimension entity.TrackDimension, from, to time.Time) ([]*entity.BreakdownRow, error) {
+	field, ok := dimension.Field()
+	if !ok {
+		return nil, fmt.Errorf("unknown dimension %q", dimension)
+	}
+
+	return r.countTracks(ctx, trackFilter(bson.M{"tracking_setting_id": trackingSettingID}, from, to), "$"+field)
+}
+
+// CountLinkTracksByVariant groups the auto-created tracks of the link by A/B variant
+func (r *trackRepo) CountLinkTracksByVariant(ctx context.Context, linkID bson.ObjectID,
+	from, to time.Time) ([]*entity.BreakdownRow, error) {
+	return r.countTracks(ctx, trackFilter(bson.M{"link_id": linkID}, from, to), "$variant")
+}
+
+// CountTracksByLinks counts the auto-created tracks of each link, keyed by the hex link id
+func (r *trackRepo) CountTracksByLinks(ctx context.Context, linkIDs []bson.ObjectID,
+	from, to time.Time) ([]*entity.BreakdownRow, error) {
+	match := trackFilter(bson.M{"link_id": bson.M{"$in": linkIDs}}, from, to)
+	return r.countTracks(ctx, match, bson.M{"$toString": "$link_id"})
+}
+
+func trackFilter(match bson.M, from, to time.Time) bson.M {
+	match["bot.is_bot"] = bson.M{"$ne": true}
+	createdAt := bson.M{}
+	if !from.IsZero() {
+		createdAt["$gte"] = from
+	}
+	if !to.IsZero() {
+		createdAt["$lt"] = to
+	}
+	if len(createdAt) > 0 {
+		match["created_at"] = createdAt
+	}
+	return match
+}
+
+// countTracks counts the matching tracks, their landings and conversions by the key expression
+func (r *trackRepo) countTracks(ctx context.Context, match bson.M, key any) ([]*entity.BreakdownRow, error) {
+	pipeline := []bson.M{
+		{"$match": match},
+		{
+			// event.track_id is the hex string of the track id
+			"$lookup": bson.M{
+				"from": "event",
+				"let":  bson.M{"track_id": bson.M{"$toString": "$_id"}},
+				"pipeline": []bson.M{
+					{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$track_id", "$$track_id"}}, "bot.is_bot": bson.M{"$ne": true}}},
+					{"$project": bson.M{"event_name": 1}},
+				},
+				"as": "events",
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id":    bson.M{"$ifNull": bson.A{key, ""}},
+				"tracks": bson.M{"$sum": 1},
+				"landings": bson.M{"$sum": bson.M{
+					"$cond": bson.A{bson.M{"$in": bson.A{entity.EventNameLandingPage, "$events.event_name"}}, 1, 0},
+				}},
+				"conversions": bson.M{"$sum": bson.M{
+					"$cond": bson.A{bson.M{"$in": bson.A{entity.EventNameThankYouPage, "$events.event_name"}}, 1, 0},
+				}},
+			},
+		},
+		{"$sort": bson.D{{Key: "tracks", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate tracks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := []*entity.BreakdownRow{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return results, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github/michaellimmm/turakkingu/internal/entity"
 	"github/michaellimmm/turakkingu/internal/repository"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go"
@@ -139,3 +140,235 @@ func TestTrackRepo_FindTrackByIDWithThankYoPages(t *testing.T) {
 		assert.Equal(t, len(trackWithThankYouPages.ThankYouPages), 1)
 	})
 }
+
+func TestTrackRepo_EraseTracksBySubject(t *testing.T) {
+	suite, err := setupTestSuiteTrackRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	trackingSetting, err := suite.trackingSettingRepo.FindOrCreateWithPagesByTenantID(ctx, "tenant1")
+	assert.NoError(t, err)
+
+	t.Run("should find tracks by end user id", func(t *testing.T) {
+		track := &entity.Track{
+			TrackingSettingID: trackingSetting.ID,
+			Url:               "https://www.example.com",
+			EndUserID:         "EndUserToFind",
+		}
+		err := suite.trackRepo.CreateTrack(ctx, track)
+		assert.NoError(t, err)
+
+		tracks, err := suite.trackRepo.FindTracksBySubject(ctx, trackingSetting.ID, nil, []string{"EndUserToFind"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(tracks))
+		assert.Equal(t, track.ID, tracks[0].ID)
+	})
+
+	t.Run("should not find tracks of another tracking setting", func(t *testing.T) {
+		other, err := suite.trackingSettingRepo.FindOrCreateWithPagesByTenantID(ctx, "tenant2")
+		assert.NoError(t, err)
+
+		tracks, err := suite.trackRepo.FindTracksBySubject(ctx, other.ID, nil, []string{"EndUserToFind"})
+
+		assert.NoError(t, err)
+		assert.Empty(t, tracks)
+	})
+
+	t.Run("should anonymize tracks", func(t *testing.T) {
+		track := &entity.Track{
+			TrackingSettingID: trackingSetting.ID,
+			Url:               "https://www.example.com",
+			EndUserID:         "EndUserToAnonymize",
+			SessionID:         "SessionID12345",
+		}
+		err := suite.trackRepo.CreateTrack(ctx, track)
+		assert.NoError(t, err)
+
+		count, err := suite.trackRepo.AnonymizeTracksByIDs(ctx, []bson.ObjectID{track.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		found, err := suite.trackRepo.FindTrackByID(ctx, track.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.AnonymizedValue, found.EndUserID)
+		assert.Empty(t, found.SessionID)
+	})
+
+	t.Run("should delete tracks", func(t *testing.T) {
+		track := &entity.Track{
+			TrackingSettingID: trackingSetting.ID,
+			Url:               "https://www.example.com",
+			EndUserID:         "EndUserToDelete",
+		}
+		err := suite.trackRepo.CreateTrack(ctx, track)
+		assert.NoError(t, err)
+
+		count, err := suite.trackRepo.DeleteTracksByIDs(ctx, []bson.ObjectID{track.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		found, err := suite.trackRepo.FindTrackByID(ctx, track.ID)
+		assert.Error(t, err)
+		assert.Nil(t, found)
+	})
+}
+
+func TestTrackRepo_CountTracksByDimension(t *testing.T) {
+	suite, err := setupTestSuiteTrackRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	trackingSetting, err := suite.trackingSettingRepo.FindOrCreateWithPagesByTenantID(ctx, "tenant1")
+	assert.NoError(t, err)
+
+	tracks := []*entity.Track{
+		{Url: "https://www.example.com/?utm_source=google&utm_medium=cpc", UTM: entity.UTM{Source: "google", Medium: "cpc"}, Channel: entity.ChannelPaidSearch},
+		{Url: "https://www.example.com/?utm_source=google&utm_medium=cpc", UTM: entity.UTM{Source: "google", Medium: "cpc"}, Channel: entity.ChannelPaidSearch},
+		{Url: "https://www.example.com/?utm_source=newsletter", UTM: entity.UTM{Source: "newsletter"}, Channel: entity.ChannelEmail},
+		{Url: "https://www.example.com/", Channel: entity.ChannelDirect},
+	}
+	for _, track := range tracks {
+		track.TrackingSettingID = trackingSetting.ID
+		track.EndUserID = "EndUserID12345"
+		err := suite.trackRepo.CreateTrack(ctx, track)
+		assert.NoError(t, err)
+	}
+
+	events := suite.client.Database("test").Collection("event")
+	_, err = events.InsertMany(ctx, []any{
+		entity.Event{TrackID: tracks[0].ID.Hex(), EventName: entity.EventNameLandingPage},
+		entity.Event{TrackID: tracks[0].ID.Hex(), EventName: entity.EventNameThankYouPage},
+		entity.Event{TrackID: tracks[1].ID.Hex(), EventName: entity.EventNameLandingPage},
+	})
+	assert.NoError(t, err)
+
+	t.Run("should group tracks by channel", func(t *testing.T) {
+		rows, err := suite.trackRepo.CountTracksByDimension(ctx, trackingSetting.ID, entity.TrackDimensionChannel, time.Time{}, time.Time{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.BreakdownRow{
+			{Key: "paid_search", Tracks: 2, Landings: 2, Conversions: 1},
+			{Key: "direct", Tracks: 1},
+			{Key: "email", Tracks: 1},
+		}, rows)
+	})
+
+	t.Run("should group tracks without value under empty key", func(t *testing.T) {
+		rows, err := suite.trackRepo.CountTracksByDimension(ctx, trackingSetting.ID, entity.TrackDimensionUTMMedium, time.Time{}, time.Time{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(rows))
+		assert.Equal(t, "", rows[0].Key)
+		assert.Equal(t, int64(2), rows[0].Tracks)
+	})
+
+	t.Run("should filter by created date", func(t *testing.T) {
+		rows, err := suite.trackRepo.CountTracksByDimension(ctx, trackingSetting.ID, entity.TrackDimensionChannel,
+			time.Now().Add(time.Hour), time.Time{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, rows)
+	})
+}
+
+func TestTrackRepo_UpdateTrackDevice(t *testing.T) {
+	suite, err := setupTestSuiteTrackRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	t.Run("should update track device", func(t *testing.T) {
+		trackingSetting, err := suite.trackingSettingRepo.FindOrCreateWithPagesByTenantID(ctx, "tenant1")
+		assert.NoError(t, err)
+
+		track := &entity.Track{
+			TrackingSettingID: trackingSetting.ID,
+			Url:               "https://www.google.com",
+			EndUserID:         "EndUserID12345",
+		}
+		assert.NoError(t, suite.trackRepo.CreateTrack(ctx, track))
+
+		device := entity.Device{Type: entity.DeviceTypeMobile, Browser: "Safari", BrowserVersion: "17.4", OS: "iOS", OSVersion: "17.4"}
+		assert.NoError(t, suite.trackRepo.UpdateTrackDevice(ctx, track.ID, device))
+
+		found, err := suite.trackRepo.FindTrackByID(ctx, track.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, device, found.Device)
+	})
+
+	t.Run("should return error when track is not found", func(t *testing.T) {
+		err := suite.trackRepo.UpdateTrackDevice(ctx, bson.NewObjectID(), entity.Device{Type: entity.DeviceTypeDesktop})
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+}
+
+func TestTrackRepo_UpdateTrackGeo(t *testing.T) {
+	suite, err := setupTestSuiteTrackRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	t.Run("should update track geo", func(t *testing.T) {
+		trackingSetting, err := suite.trackingSettingRepo.FindOrCreateWithPagesByTenantID(ctx, "tenant1")
+		assert.NoError(t, err)
+
+		track := &entity.Track{
+			TrackingSettingID: trackingSetting.ID,
+			Url:               "https://www.google.com",
+			EndUserID:         "EndUserID12345",
+		}
+		assert.NoError(t, suite.trackRepo.CreateTrack(ctx, track))
+
+		geo := entity.Geo{Country: "JP", CountryName: "Japan", Region: "13", RegionName: "Tokyo", City: "Tokyo"}
+		assert.NoError(t, suite.trackRepo.UpdateTrackGeo(ctx, track.ID, geo))
+
+		found, err := suite.trackRepo.FindTrackByID(ctx, track.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, geo, found.Geo)
+	})
+}
+
+func TestTrackRepo_CountLinkTracksByVariant(t *testing.T) {
+	suite, err := setupTestSuiteTrackRepo()
+	assert.NoError(t, err)
+	defer suite.Cleanup()
+
+	ctx := context.Background()
+	trackingSetting, err := suite.trackingSettingRepo.FindOrCreateWithPagesByTenantID(ctx, "tenant1")
+	assert.NoError(t, err)
+
+	linkID := bson.NewObjectID()
+	tracks := []*entity.Track{
+		{LinkID: linkID, Variant: "A"},
+		{LinkID: linkID, Variant: "A"},
+		{LinkID: linkID, Variant: "B"},
+		{LinkID: linkID, Variant: "B", Bot: entity.Bot{IsBot: true}},
+		{LinkID: bson.NewObjectID(), Variant: "A"},
+	}
+	for _, track := range tracks {
+		track.TrackingSettingID = trackingSetting.ID
+		track.Url = "https://www.example.com/"
+		assert.NoError(t, suite.trackRepo.CreateTrack(ctx, track))
+	}
+
+	events := suite.client.Database("test").Collection("event")
+	_, err = events.InsertMany(ctx, []any{
+		entity.Event{TrackID: tracks[0].ID.Hex(), EventName: entity.EventNameLandingPage},
+		entity.Event{TrackID: tracks[2].ID.Hex(), EventName: entity.EventNameLandingPage},
+		entity.Event{TrackID: tracks[2].ID.Hex(), EventName: entity.EventNameThankYouPage},
+	})
+	assert.NoError(t, err)
+
+	t.Run("should group human tracks of the link by variant", func(t *testing.T) {
+		rows, err := suite.trackRepo.CountLinkTracksByVariant(ctx, linkID, time.Time{}, time.Time{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.BreakdownRow{
+			{Key: "A", Tracks: 2, Landings: 1},
+			{Key: "B", Tracks: 1, Landings: 1, Conversions: 1},
+		}, rows)
+	})
+}
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/enrichment"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"log/slog"
+	"time"
+)
+
+type ClickUseCase interface {
+	RecordClick(ctx context.Context, link *entity.Link, click *entity.Click) (*entity.Track, error)
+	GetClickBreakdown(ctx context.Context, linkID string, dimension entity.ClickDimension, from, to time.Time) (*entity.ClickBreakdown, error)
+	GetExperimentReport(ctx context.Context, linkID string, from, to time.Time) (*entity.ExperimentReport, error)
+}
+
+type clickUseCase struct {
+	repo         repository.Repo
+	config       *core.Config
+	geoIP        *enrichment.GeoIP
+	botDetector  *enrichment.BotDetector
+	trackUseCase TrackUseCase
+}
+
+func NewClickUseCase(config *core.Config, repo repository.Repo, geoIP *enrichment.GeoIP,
+	botDetector *enrichment.BotDetector, trackUseCase TrackUseCase) ClickUseCase {
+	return &clickUseCase{
+		repo:         repo,
+		config:       config,
+		geoIP:        geoIP,
+		botDetector:  botDetector,
+		trackUseCase: trackUseCase,
+	}
+}
+
+// RecordClick enriches and stores the click, bot clicks are dropped when the tenant discards them.
+// A matching redirect rule wins over the variants, the variant of the click (from the visitor's
+// cookie) is kept or one is assigned only without rule.
+// The lifecycle of the link decides the outcome, human clicks count against the click cap.
+// Human clicks followed to a tracking link destination get a track, whose id is handed to the landing page.
+func (uc *clickUseCase) RecordClick(ctx context.Context, link *entity.Link, click *entity.Click) (*entity.Track, error) {
+	click.LinkID = link.ID
+	click.TenantID = link.TenantID
+	click.Device = enrichment.ParseDevice(click.UserAgent, click.ClientHints)
+	click.Bot = uc.botDetector.Detect(enrichment.BotRequest{
+		UserAgent:   click.UserAgent,
+		Device:      click.Device,
+		ClientHints: click.ClientHints,
+		IP:          click.IP,
+		Language:    click.Language,
+		Browser:     true,
+		Redirect:    true,
+	})
+	click.Geo = uc.geoIP.Lookup(click.IP)
+	click.IP = enrichment.AnonymizeIP(click.IP, entity.IPMode(uc.config.GeoIPIPMode))
+
+	if !applyRules(link, click, time.Now().UTC()) {
+		assignVariant(link, click)
+	}
+	click.Url = link.Destination(click.Url, click.Query)
+
+	if err := uc.applyLifecycle(ctx, link, click); err != nil {
+		return nil, err
+	}
+
+	trackingSetting, err := uc.repo.FindOrCreateWithPagesByTenantID(ctx, link.TenantID)
+	if err != nil {
+		slog.Error("failed to find or create tracking setting with pages by tenant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if trackingSetting.Bots.Discards(click.Bot) {
+		return nil, nil
+	}
+	click.ApplyRetention(time.Now().UTC(), trackingSetting.Retention.ClickDays, trackingSetting.Retention.Archive)
+
+	var track *entity.Track
+	if link.CreatesTrack() && !click.Bot.IsBot && click.Outcome == entity.ClickOutcomeRedirect {
+		track = &entity.Track{
+			TrackingSettingID: trackingSetting.ID,
+			Url:               click.Url,
+			GeneratedFrom:     "link",
+			UserAgent:         click.UserAgent,
+			Device:            click.Device,
+			IP:                click.IP,
+			Geo:               click.Geo,
+			LinkID:            link.ID,
+			Variant:           click.Variant,
+		}
+		// the landing page looks the track up by its id, so it is stored before the redirect
+		if err := uc.trackUseCase.CreateTrack(ctx, track); err != nil {
+			return nil, err
+		}
+		click.TrackID = track.ID.Hex()
+	}
+
+	if err := uc.repo.CreateClick(ctx, click); err != nil {
+		slog.Error("failed to create click", slog.String("error", err.Error()))
+		return track, err
+	}
+
+	return track, nil
+}
+
+// applyRules sends the click to the destination of the first matching rule instead of its variant
+func applyRules(link *entity.Link, click *entity.Click, now time.Time) bool {
+	match := link.MatchRule(entity.RuleRequest{
+		DeviceType: click.Device.Type,
+		OS:         click.Device.OS,
+		Country:    click.Geo.Country,
+		Language:   click.Language,
+		Time:       now,
+		Query:      click.Query,
+	})
+	if match == nil {
+		return false
+	}
+
+	click.Url = match.Destination
+	click.Rule = match.Rule.Name
+	click.Variant = ""
+	return true
+}
+
+// assignVariant keeps the variant the click came with while the link still serves it, or else
+// picks one by the visitor key
+func assignVariant(link *entity.Link, click *entity.Click) {
+	variant := link.FindVariant(click.Variant)
+	if variant == nil {
+		variant = link.PickVariant(click.VisitorKey)
+	}
+	if variant == nil {
+		click.Variant = ""
+		return
+	}
+
+	click.Url = variant.Url
+	click.Variant = variant.ID
+}
+
+// applyLifecycle sets the outcome of the click, bots don't use up the click cap.
+// Only capped links count their clicks, the others are counted from the clicks.
+func (uc *clickUseCase) applyLifecycle(ctx context.Context, link *entity.Link, click *entity.Click) error {
+	click.Outcome = entity.ClickOutcomeRedirect
+	click.Reason = link.InactiveReason(time.Now().UTC())
+
+	if click.Reason == "" && !click.Bot.IsBot && link.MaxClicks > 0 {
+		counted, err := uc.repo.IncrementLinkClicks(ctx, link.ID, link.MaxClicks)
+		if err != nil {
+			slog.Error("failed to increment link clicks", slog.String("error", err.Error()))
+			return err
+		}
+		if !counted {
+			click.Reason = entity.LinkInactiveReasonClickLimit
+		}
+	}
+
+	if click.Reason == "" {
+		return nil
+	}
+
+	if link.FallbackUrl != "" {
+		click.Outcome = entity.ClickOutcomeFallback
+		click.Url = link.FallbackUrl
+	} else {
+		click.Outcome = entity.ClickOutcomeExpiredPage
+		click.Url = ""
+	}
+	click.Variant = ""
+	click.Rule = ""
+	return nil
+}
+
+func (uc *clickUseCase) GetClickBreakdown(ctx context.Context, linkID string, dimension entity.ClickDimension,
+	from, to time.Time) (*entity.ClickBreakdown, error) {
+	link, err := uc.repo.FindLinkByID(ctx, linkID)
+	if err != nil {
+		slog.Error("failed to get link", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	rows, err := uc.repo.CountClicksByDimension(ctx, link.ID, dimension, from, to)
+	if err != nil {
+		slog.Error("failed to count clicks by dimension", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	botClicks, err := uc.repo.CountBotClicks(ctx, link.ID, from, to)
+	if err != nil {
+		slog.Error("failed to count bot clicks", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &entity.ClickBreakdown{Dimension: dimension, Rows: rows, BotClicks: botClicks}, nil
+}
+
+// GetExperimentReport compares the conversion rate of every variant with the first one
+func (uc *clickUseCase) GetExperimentReport(ctx context.Context, linkID string, from, to time.Time) (*entity.ExperimentReport, error) {
+	link, err := uc.repo.FindLinkByID(ctx, linkID)
+	if err != nil {
+		slog.Error("failed to get link", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	clickRows, err := uc.repo.CountClicksByDimension(ctx, link.ID, entity.ClickDimensionVariant, from, to)
+	if err != nil {
+		slog.Error("failed to count clicks by variant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	trackRows, err := uc.repo.CountLinkTracksByVariant(ctx, link.ID, from, to)
+	if err != nil {
+		slog.Error("failed to count link tracks by variant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	clicks := map[string]int64{}
+	for _, row := range clickRows {
+		clicks[row.Key] = row.Clicks
+	}
+	tracks := map[string]*entity.BreakdownRow{}
+	for _, row := range trackRows {
+		tracks[row.Key] = row
+	}
+
+	report := &entity.ExperimentReport{LinkID: link.ID.Hex(), Variants: []*entity.VariantResult{}}
+	for _, variant := range link.Variants {
+		result := &entity.VariantResult{
+			ID:     variant.ID,
+			Name:   variant.Name,
+			Url:    variant.Url,
+			Weight: variant.Weight,
+			Clicks: clicks[variant.ID],
+			PValue: 1,
+		}
+		if row, ok := tracks[variant.ID]; ok {
+			result.Tracks = row.Tracks
+			result.Conversions = row.Conversions
+		}
+		if result.Tracks > 0 {
+			result.ConversionRate = float64(result.Conversions) / float64(result.Tracks)
+		}
+		report.Variants = append(report.Variants, result)
+	}
+
+	if len(report.Variants) == 0 {
+		return report, nil
+	}
+
+	control := report.Variants[0]
+	report.Control = control.ID
+	for _, result := range report.Variants[1:] {
+		result.ZScore, result.PValue = entity.TwoProportionZTest(control.Conversions, control.Tracks, result.Conversions, result.Tracks)
+		result.Significant = result.PValue < entity.SignificanceLevel
+		if control.ConversionRate > 0 {
+			result.Uplift = (result.ConversionRate - control.ConversionRate) / control.ConversionRate
+		}
+	}
+
+	return report, nil
+}
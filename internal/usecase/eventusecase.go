@@ -5,27 +5,40 @@ import (
 	"errors"
 	"fmt"
 	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/enrichment"
 	"github/michaellimmm/turakkingu/internal/entity"
 	"github/michaellimmm/turakkingu/internal/repository"
 	"log/slog"
 	"net/url"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
+// ErrEventOriginNotAllowed is returned for events sent from a page outside the tenant domains
+var ErrEventOriginNotAllowed = errors.New("event url is not on a tenant domain")
+
 type EventUseCase interface {
 	ProcessEvent(ctx context.Context, event *entity.Event) error
 }
 
 type eventUseCase struct {
-	repo   repository.Repo
-	config *core.Config
+	repo            repository.Repo
+	config          *core.Config
+	identityUseCase IdentityUseCase
+	geoIP           *enrichment.GeoIP
+	botDetector     *enrichment.BotDetector
 }
 
-func NewEventUseCase(config *core.Config, repo repository.Repo) EventUseCase {
+func NewEventUseCase(config *core.Config, repo repository.Repo, identityUseCase IdentityUseCase,
+	geoIP *enrichment.GeoIP, botDetector *enrichment.BotDetector) EventUseCase {
 	return &eventUseCase{
-		repo:   repo,
-		config: config,
+		repo:            repo,
+		config:          config,
+		identityUseCase: identityUseCase,
+		geoIP:           geoIP,
+		botDetector:     botDetector,
 	}
 }
 
@@ -34,21 +47,61 @@ func NewEventUseCase(config *core.Config, repo repository.Repo) EventUseCase {
 // Please add more scenario _/\_.
 func (uc *eventUseCase) ProcessEvent(ctx context.Context, event *entity.Event) error {
 	// TODO: check attribution window
+
+	// without consent the fingerprint is neither stored nor used for attribution
+	if event.Consent.IsDenied() {
+		event.Fingerprint = ""
+	}
+
+	event.Device = enrichment.ParseDevice(event.UserAgent, event.ClientHints)
+	event.Bot = uc.botDetector.Detect(enrichment.BotRequest{
+		UserAgent:   event.UserAgent,
+		Device:      event.Device,
+		ClientHints: event.ClientHints,
+		IP:          event.IP,
+		Language:    event.Language,
+		Webdriver:   event.Webdriver,
+		Browser:     true,
+	})
+	event.Geo = uc.geoIP.Lookup(event.IP)
+	event.IP = enrichment.AnonymizeIP(event.IP, entity.IPMode(uc.config.GeoIPIPMode))
+
 	trackID, err := event.GetTrackID()
 	if err != nil {
 		// check fingerprint
 		return uc.processFingerprint(ctx, event)
 	}
 
+	track, err := uc.repo.FindTrackByID(ctx, trackID)
+	if err != nil {
+		slog.Error("failed to get track by id", slog.String("error", err.Error()))
+		return err
+	}
+
+	trackingSetting, err := uc.repo.FindTrackingSettingByID(ctx, track.TrackingSettingID)
+	if err != nil {
+		slog.Error("failed to find tracking setting by id", slog.String("error", err.Error()))
+		return err
+	}
+
+	if !trackingSetting.IsOwnedURL(event.Url) {
+		return ErrEventOriginNotAllowed
+	}
+
+	if trackingSetting.Consent.Drops(event.Consent) || trackingSetting.Bots.Discards(event.Bot) {
+		return nil
+	}
+
+	uc.linkFingerprint(ctx, track, event)
+
 	existingEvents, err := uc.repo.FindAllEventByTrackID(ctx, trackID)
 	if err != nil && !errors.Is(err, repository.ErrNoEvents) {
 		slog.Error("failed to get event by track id", slog.String("error", err.Error()))
 		return err
 	} else if (err != nil && errors.Is(err, repository.ErrNoEvents)) || len(existingEvents) == 0 { // new event
-		track, err := uc.repo.FindTrackByID(ctx, trackID)
-		if err != nil {
-			slog.Error("failed to get track by id", slog.String("error", err.Error()))
-			return err
+		// a journey starts at the landing page, not at a custom event
+		if event.Name != "" {
+			return nil
 		}
 
 		// check if event url is equal with track url, it means user just open landing page
@@ -62,34 +115,57 @@ func (uc *eventUseCase) ProcessEvent(ctx context.Context, event *entity.Event) e
 		}
 
 		event.EventName = entity.EventNameLandingPage
+		event.TenantID = trackingSetting.TenantID
+		event.Revenue = 0 // only conversions have a revenue
+		uc.applyRetention(trackingSetting, event)
 		if err = uc.repo.CreateEvent(ctx, event); err != nil {
 			return err
 		}
 
+		uc.enrichTrack(ctx, track, event)
 		return nil
 	}
 
-	// check if last event is landing page
-	if existingEvents[0].EventName == entity.EventNameLandingPage {
-		// check if event url is in thank you page
-		return uc.checkAndSaveThankYouPageEvent(ctx, trackID, event)
+	// the journey of the landing page ends at its conversion
+	for _, existing := range existingEvents {
+		if existing.EventName == entity.EventNameThankYouPage {
+			return nil
+		}
 	}
 
-	return nil
+	return uc.saveJourneyEvent(ctx, trackingSetting, trackID, event)
 }
 
-func (uc *eventUseCase) checkAndSaveThankYouPageEvent(ctx context.Context, trackID bson.ObjectID, event *entity.Event) error {
+// saveJourneyEvent saves an event after the landing page: a custom event, a conversion when the url
+// is in a thank you page or else a page view, the steps of the funnels
+func (uc *eventUseCase) saveJourneyEvent(ctx context.Context, trackingSetting *entity.TrackingSetting,
+	trackID bson.ObjectID, event *entity.Event) error {
+	event.TenantID = trackingSetting.TenantID
+	if event.Name != "" {
+		event.EventName = entity.EventNameCustom
+		event.Revenue = 0
+		uc.applyRetention(trackingSetting, event)
+		return uc.repo.CreateEvent(ctx, event)
+	}
+
 	trackPages, err := uc.repo.FindTrackByIDWithThankYouPages(ctx, trackID)
 	if err != nil {
 		return err
 	}
 
-	pageID, _ := uc.matchUrlInThankYouPageList(event.Url, trackPages.ThankYouPages)
-	if pageID != nil {
-		return nil
+	page, _ := uc.matchUrlInThankYouPageList(event.Url, trackPages.ThankYouPages)
+	if page == nil {
+		event.EventName = entity.EventNamePageView
+		event.Revenue = 0
+		uc.applyRetention(trackingSetting, event)
+		return uc.repo.CreateEvent(ctx, event)
 	}
 
 	event.EventName = entity.EventNameThankYouPage
+	event.ThankYouPageID = page.ID
+	event.Points = page.Point
+	event.Revenue = page.ConversionRevenue(event.Revenue)
+	uc.applyRetention(trackingSetting, event)
 	if err = uc.repo.CreateEvent(ctx, event); err != nil {
 		return err
 	}
@@ -100,7 +176,33 @@ func (uc *eventUseCase) checkAndSaveThankYouPageEvent(ctx context.Context, track
 	return nil
 }
 
-func (uc *eventUseCase) matchUrlInThankYouPageList(currUrl string, pages []*entity.ThankYouPage) (*bson.ObjectID, error) {
+// enrichTrack fills the track device and geo from the landing page when the click didn't carry them,
+// a bot landing makes the track a bot
+func (uc *eventUseCase) enrichTrack(ctx context.Context, track *entity.Track, event *entity.Event) {
+	if track.Device.IsEmpty() && !event.Device.IsEmpty() {
+		if err := uc.repo.UpdateTrackDevice(ctx, track.ID, event.Device); err != nil {
+			slog.Error("failed to update track device", slog.String("error", err.Error()))
+		}
+	}
+
+	if track.Geo.IsEmpty() && !event.Geo.IsEmpty() {
+		if err := uc.repo.UpdateTrackGeo(ctx, track.ID, event.Geo); err != nil {
+			slog.Error("failed to update track geo", slog.String("error", err.Error()))
+		}
+	}
+
+	if !track.Bot.IsBot && event.Bot.IsBot {
+		if err := uc.repo.UpdateTrackBot(ctx, track.ID, event.Bot); err != nil {
+			slog.Error("failed to update track bot", slog.String("error", err.Error()))
+		}
+	}
+}
+
+func (uc *eventUseCase) applyRetention(trackingSetting *entity.TrackingSetting, event *entity.Event) {
+	event.ApplyRetention(time.Now().UTC(), trackingSetting.Retention.EventDays, trackingSetting.Retention.Archive)
+}
+
+func (uc *eventUseCase) matchUrlInThankYouPageList(currUrl string, pages []*entity.ThankYouPage) (*entity.ThankYouPage, error) {
 	for _, page := range pages {
 		found, err := uc.isQuerySubset(currUrl, page.URL)
 		if err != nil {
@@ -108,7 +210,7 @@ func (uc *eventUseCase) matchUrlInThankYouPageList(currUrl string, pages []*enti
 		}
 
 		if found {
-			return &page.ID, nil
+			return page, nil
 		}
 	}
 	return nil, nil
@@ -150,26 +252,106 @@ func (uc *eventUseCase) isQuerySubset(a, b string) (bool, error) {
 	return true, nil
 }
 
+// linkFingerprint records that the fingerprint was seen together with the track's ztid
+func (uc *eventUseCase) linkFingerprint(ctx context.Context, track *entity.Track, event *entity.Event) {
+	if event.Fingerprint == "" {
+		return
+	}
+
+	_, err := uc.identityUseCase.LinkIdentifiers(ctx, track.TrackingSettingID, entity.IdentityEdge{
+		From:       entity.Identifier{Type: entity.IdentifierTypeZTID, Value: track.ID.Hex()},
+		To:         entity.Identifier{Type: entity.IdentifierTypeFingerprint, Value: event.Fingerprint},
+		Confidence: entity.IdentityConfidenceMedium,
+		Source:     "event",
+	})
+	if err != nil {
+		slog.Error("failed to link fingerprint identity", slog.String("error", err.Error()))
+	}
+}
+
 func (uc *eventUseCase) processFingerprint(ctx context.Context, event *entity.Event) error {
-	// check last event from fingerprint
-	// if no event on this fingerprint return nil
-	// if yes then check if url match with thank you page
-	lastEvent, err := uc.repo.FindLastEventByFingerprint(ctx, event.Fingerprint)
+	// resolve the fingerprint through the identity graph of the page's tracking setting
+	// if the fingerprint is unknown, ambiguous or weakly linked, don't attribute the event
+	// if yes then check if url match with thank you page on the last track of the identity
+	if event.Fingerprint == "" {
+		return nil
+	}
+
+	trackingSetting, err := uc.findEventTrackingSetting(ctx, event)
+	if err != nil {
+		return err
+	} else if trackingSetting == nil {
+		// not process event when the tenant of the page is unknown
+		return nil
+	}
+
+	if !trackingSetting.IsOwnedURL(event.Url) {
+		return ErrEventOriginNotAllowed
+	}
+
+	fingerprint := entity.Identifier{Type: entity.IdentifierTypeFingerprint, Value: event.Fingerprint}
+	identity, err := uc.identityUseCase.ResolveIdentity(ctx, trackingSetting.ID, fingerprint)
+	if errors.Is(err, ErrIdentityNotFound) || errors.Is(err, ErrIdentityAmbiguous) {
+		// not process event when isn't tracked before or can't be attributed to a single user
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if identity.ConfidenceOf(fingerprint) < entity.IdentityConfidenceMedium {
+		return nil
+	}
+
+	lastEvent, err := uc.repo.FindLastEventByTrackIDs(ctx, identity.Values(entity.IdentifierTypeZTID))
 	if err != nil && errors.Is(err, repository.ErrNoEvents) {
-		// not process event when isn't tracked before
 		return nil
 	} else if err != nil {
 		return err
 	}
 
-	if lastEvent.EventName == entity.EventNameLandingPage {
+	if lastEvent.EventName != entity.EventNameThankYouPage {
 		trackID, err := lastEvent.GetTrackID()
 		if err != nil {
 			return nil
 		}
 
-		return uc.checkAndSaveThankYouPageEvent(ctx, trackID, event)
+		if trackingSetting.Bots.Discards(event.Bot) {
+			return nil
+		}
+
+		event.TrackID = lastEvent.TrackID
+		return uc.saveJourneyEvent(ctx, trackingSetting, trackID, event)
 	}
 
 	return nil
 }
+
+// findEventTrackingSetting returns the tracking setting of the script that sent the event, or else
+// the single tracking setting owning the event url, nil when it can't be told
+func (uc *eventUseCase) findEventTrackingSetting(ctx context.Context, event *entity.Event) (*entity.TrackingSetting, error) {
+	if !event.TrackingSettingID.IsZero() {
+		trackingSetting, err := uc.repo.FindTrackingSettingByID(ctx, event.TrackingSettingID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		} else if err != nil {
+			slog.Error("failed to find tracking setting by id", slog.String("error", err.Error()))
+			return nil, err
+		}
+		return trackingSetting, nil
+	}
+
+	u, err := url.Parse(event.Url)
+	if err != nil || u.Hostname() == "" {
+		return nil, nil
+	}
+
+	trackingSettings, err := uc.repo.FindTrackingSettingsByDomains(ctx, entity.ParentDomains(u.Hostname()))
+	if err != nil {
+		slog.Error("failed to find tracking settings by domains", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if len(trackingSettings) != 1 {
+		return nil, nil
+	}
+	return trackingSettings[0], nil
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"log/slog"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var ErrInvalidFunnelQuery = errors.New("invalid funnel query")
+
+const (
+	defaultFunnelRange = 30 * 24 * time.Hour
+	maxFunnelRange     = 92 * 24 * time.Hour
+)
+
+type FunnelUseCase interface {
+	CreateFunnel(ctx context.Context, funnel *entity.Funnel) error
+	GetFunnels(ctx context.Context, tenantID string) ([]*entity.Funnel, error)
+	UpdateFunnel(ctx context.Context, id string, funnel *entity.Funnel) (*entity.Funnel, error)
+	DeleteFunnel(ctx context.Context, id string) error
+	GetFunnelReport(ctx context.Context, id string, dimension entity.FunnelDimension,
+		from, to time.Time) (*entity.FunnelReport, error)
+}
+
+type funnelUseCase struct {
+	repo   repository.Repo
+	config *core.Config
+}
+
+func NewFunnelUseCase(config *core.Config, repo repository.Repo) FunnelUseCase {
+	return &funnelUseCase{
+		repo:   repo,
+		config: config,
+	}
+}
+
+func (uc *funnelUseCase) CreateFunnel(ctx context.Context, funnel *entity.Funnel) error {
+	if err := uc.repo.CreateFunnel(ctx, funnel); err != nil {
+		slog.Error("failed to create funnel", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (uc *funnelUseCase) GetFunnels(ctx context.Context, tenantID string) ([]*entity.Funnel, error) {
+	funnels, err := uc.repo.FindFunnelsByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to get funnels", slog.String("error", err.Error()))
+		return nil, err
+	}
+	return funnels, nil
+}
+
+// findFunnel returns mongo.ErrNoDocuments for ids that are not valid too
+func (uc *funnelUseCase) findFunnel(ctx context.Context, id string) (*entity.Funnel, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	funnel, err := uc.repo.FindFunnelByID(ctx, oid)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("failed to get funnel", slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+	return funnel, nil
+}
+
+func (uc *funnelUseCase) UpdateFunnel(ctx context.Context, id string, funnel *entity.Funnel) (*entity.Funnel, error) {
+	current, err := uc.findFunnel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	funnel.ID = current.ID
+	funnel.TenantID = current.TenantID
+	funnel.CreatedAt = current.CreatedAt
+	if err := uc.repo.UpdateFunnel(ctx, funnel); err != nil {
+		slog.Error("failed to update funnel", slog.String("error", err.Error()))
+		return nil, err
+	}
+	return funnel, nil
+}
+
+func (uc *funnelUseCase) DeleteFunnel(ctx context.Context, id string) error {
+	funnel, err := uc.findFunnel(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.DeleteFunnel(ctx, funnel.ID); err != nil {
+		slog.Error("failed to delete funnel", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+// GetFunnelReport counts the tracks reaching each step of the funnel, of the journeys whose first
+// step is in [from, to), by link or channel when a dimension is given. The last 30 days without range.
+func (uc *funnelUseCase) GetFunnelReport(ctx context.Context, id string, dimension entity.FunnelDimension,
+	from, to time.Time) (*entity.FunnelReport, error) {
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultFunnelRange)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidFunnelQuery)
+	}
+	if to.Sub(from) > maxFunnelRange {
+		return nil, fmt.Errorf("%w: range is at most %d days", ErrInvalidFunnelQuery, int(maxFunnelRange.Hours()/24))
+	}
+
+	funnel, err := uc.findFunnel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// a journey starting before to goes on for the window
+	journeys, err := uc.repo.FindFunnelJourneys(ctx, funnel.TenantID, from, to.Add(funnel.Window()))
+	if err != nil {
+		slog.Error("failed to get funnel journeys", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	report := &entity.FunnelReport{
+		FunnelID:  funnel.ID,
+		Name:      funnel.Name,
+		From:      from,
+		To:        to,
+		Dimension: dimension,
+		Total:     entity.NewFunnelRow(funnel, ""),
+		Rows:      []*entity.FunnelRow{},
+		Labels:    map[string]string{},
+	}
+
+	rows := map[string]*entity.FunnelRow{}
+	for _, journey := range journeys {
+		reached, start := funnel.Progress(journey.Events)
+		if reached == 0 || start.Before(from) || !start.Before(to) {
+			continue
+		}
+		report.Total.Add(reached)
+
+		if dimension == "" {
+			continue
+		}
+		value := journey.Channel
+		if dimension == entity.FunnelDimensionLink {
+			value = journey.Link
+		}
+		row, ok := rows[value]
+		if !ok {
+			row = entity.NewFunnelRow(funnel, value)
+			rows[value] = row
+		}
+		row.Add(reached)
+	}
+
+	report.Total.SetRates()
+	for _, row := range rows {
+		row.SetRates()
+		report.Rows = append(report.Rows, row)
+	}
+	// the biggest entries first
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if report.Rows[i].Steps[0].Tracks != report.Rows[j].Steps[0].Tracks {
+			return report.Rows[i].Steps[0].Tracks > report.Rows[j].Steps[0].Tracks
+		}
+		return report.Rows[i].Value < report.Rows[j].Value
+	})
+
+	if dimension == entity.FunnelDimensionLink && len(report.Rows) > 0 {
+		links, err := uc.repo.FindAllLinkbyTenantID(ctx, funnel.TenantID)
+		if err != nil {
+			slog.Error("failed to get links", slog.String("error", err.Error()))
+			return nil, err
+		}
+		for _, link := range links {
+			if _, ok := rows[link.ID.Hex()]; ok {
+				report.Labels[link.ID.Hex()] = link.Name
+			}
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var (
+	ErrIdentityNotFound  = errors.New("identity not found")
+	ErrIdentityAmbiguous = errors.New("identifier belongs to more than one identity")
+)
+
+type IdentityUseCase interface {
+	LinkIdentifiers(ctx context.Context, trackingSettingID bson.ObjectID, edge entity.IdentityEdge) (*entity.Identity, error)
+	ResolveIdentity(ctx context.Context, trackingSettingID bson.ObjectID, identifier entity.Identifier) (*entity.Identity, error)
+	GetIdentity(ctx context.Context, tenantID string, id bson.ObjectID) (*entity.Identity, error)
+	FindIdentities(ctx context.Context, tenantID string, identifier entity.Identifier) ([]*entity.Identity, error)
+	UnmergeIdentity(ctx context.Context, tenantID string, id bson.ObjectID,
+		identifier entity.Identifier) ([]*entity.Identity, error)
+}
+
+type identityUseCase struct {
+	repo   repository.Repo
+	config *core.Config
+}
+
+func NewIdentityUseCase(config *core.Config, repo repository.Repo) IdentityUseCase {
+	return &identityUseCase{
+		repo:   repo,
+		config: config,
+	}
+}
+
+// LinkIdentifiers stores a merge edge. Clusters joined by the edge are merged, except when a
+// probabilistic edge (fingerprint) would glue together clusters of different end users; in that
+// case the fingerprint is added to the deterministic side only and becomes ambiguous.
+func (uc *identityUseCase) LinkIdentifiers(ctx context.Context, trackingSettingID bson.ObjectID,
+	edge entity.IdentityEdge) (*entity.Identity, error) {
+	if edge.From.Value == "" || edge.To.Value == "" {
+		return nil, fmt.Errorf("identifier value can not be empty")
+	}
+
+	clusters, err := uc.repo.FindIdentitiesByIdentifiers(ctx, trackingSettingID,
+		[]entity.Identifier{edge.From, edge.To})
+	if err != nil {
+		slog.Error("failed to find identities", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if len(clusters) == 0 {
+		identity := &entity.Identity{TrackingSettingID: trackingSettingID}
+		identity.AddEdge(edge)
+		if err := uc.repo.CreateIdentity(ctx, identity); err != nil {
+			slog.Error("failed to create identity", slog.String("error", err.Error()))
+			return nil, err
+		}
+		return identity, nil
+	}
+
+	target := clusters[0]
+	merged := []*entity.Identity{}
+	if len(clusters) > 1 {
+		if !uc.canMerge(clusters, edge) {
+			target = uc.deterministicSide(clusters, edge)
+			slog.Warn("identity conflict, clusters are not merged",
+				slog.String("from", edge.From.String()),
+				slog.String("to", edge.To.String()),
+				slog.String("identity_id", target.ID.Hex()))
+		} else {
+			merged = clusters[1:]
+			for _, other := range merged {
+				target.Merge(other)
+			}
+		}
+	}
+
+	target.AddEdge(edge)
+	if err := uc.repo.UpdateIdentity(ctx, target); err != nil {
+		slog.Error("failed to update identity", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	// the merged clusters are only deleted once the target holds their identifiers, a failure
+	// leaves duplicates that the next link merges again instead of losing them
+	for _, other := range merged {
+		if err := uc.repo.DeleteIdentity(ctx, other.ID); err != nil {
+			slog.Error("failed to delete merged identity", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	return target, nil
+}
+
+func (uc *identityUseCase) canMerge(clusters []*entity.Identity, edge entity.IdentityEdge) bool {
+	if edge.Confidence >= entity.IdentityConfidenceHigh {
+		return true
+	}
+
+	endUsers := map[string]bool{}
+	for _, cluster := range clusters {
+		for _, endUserID := range cluster.Values(entity.IdentifierTypeEndUserID) {
+			endUsers[endUserID] = true
+		}
+	}
+	return len(endUsers) <= 1
+}
+
+func (uc *identityUseCase) deterministicSide(clusters []*entity.Identity, edge entity.IdentityEdge) *entity.Identity {
+	anchor := edge.From
+	if anchor.Type.IsProbabilistic() {
+		anchor = edge.To
+	}
+
+	for _, cluster := range clusters {
+		if cluster.Has(anchor) {
+			return cluster
+		}
+	}
+	return clusters[0]
+}
+
+// ResolveIdentity returns the single cluster of the identifier in the tracking setting's graph
+func (uc *identityUseCase) ResolveIdentity(ctx context.Context, trackingSettingID bson.ObjectID,
+	identifier entity.Identifier) (*entity.Identity, error) {
+	clusters, err := uc.repo.FindIdentitiesByIdentifier(ctx, trackingSettingID, identifier)
+	if err != nil {
+		slog.Error("failed to resolve identity", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	switch len(clusters) {
+	case 0:
+		return nil, ErrIdentityNotFound
+	case 1:
+		return clusters[0], nil
+	default:
+		return nil, ErrIdentityAmbiguous
+	}
+}
+
+func (uc *identityUseCase) GetIdentity(ctx context.Context, tenantID string, id bson.ObjectID) (*entity.Identity, error) {
+	return uc.findIdentity(ctx, tenantID, id)
+}
+
+// findIdentity returns mongo.ErrNoDocuments for the identities of other tenants and unknown tenants too
+func (uc *identityUseCase) findIdentity(ctx context.Context, tenantID string, id bson.ObjectID) (*entity.Identity, error) {
+	trackingSetting, err := uc.repo.FindTrackingSettingWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("failed to get tracking setting", slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+
+	identity, err := uc.repo.FindIdentityByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("failed to get identity", slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+	if identity.TrackingSettingID != trackingSetting.ID {
+		return nil, mongo.ErrNoDocuments
+	}
+	return identity, nil
+}
+
+func (uc *identityUseCase) FindIdentities(ctx context.Context, tenantID string,
+	identifier entity.Identifier) ([]*entity.Identity, error) {
+	trackingSetting, err := uc.repo.FindTrackingSettingWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("failed to get tracking setting", slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+
+	identities, err := uc.repo.FindIdentitiesByIdentifier(ctx, trackingSetting.ID, identifier)
+	if err != nil {
+		slog.Error("failed to find identities", slog.String("error", err.Error()))
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (uc *identityUseCase) UnmergeIdentity(ctx context.Context, tenantID string, id bson.ObjectID,
+	identifier entity.Identifier) ([]*entity.Identity, error) {
+	identity, err := uc.findIdentity(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !identity.Has(identifier) {
+		return nil, fmt.Errorf("identifier %s is not part of identity %s", identifier.String(), id.Hex())
+	}
+	if len(identity.Identifiers) == 1 {
+		return nil, fmt.Errorf("identifier %s is the only member of identity %s", identifier.String(), id.Hex())
+	}
+
+	clusters := identity.Remove(identifier)
+	for _, cluster := range clusters {
+		if cluster.ID == identity.ID {
+			err = uc.repo.UpdateIdentity(ctx, cluster)
+		} else {
+			err = uc.repo.CreateIdentity(ctx, cluster)
+		}
+		if err != nil {
+			slog.Error("failed to save unmerged identity", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	return clusters, nil
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"log/slog"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type LinkCampaignUseCase interface {
+	CreateLinkCampaign(ctx context.Context, campaign *entity.LinkCampaign) error
+	GetLinkCampaigns(ctx context.Context, tenantID string) ([]*entity.LinkCampaign, error)
+	UpdateLinkCampaign(ctx context.Context, id string, campaign *entity.LinkCampaign) (*entity.LinkCampaign, error)
+	DeleteLinkCampaign(ctx context.Context, id string) error
+	AssignLinksToCampaign(ctx context.Context, tenantID string, campaignID string, linkIDs []string) (int64, error)
+	GetLinkGroupReport(ctx context.Context, tenantID string, group entity.LinkGroup, from, to time.Time) (*entity.LinkGroupReport, error)
+}
+
+type linkCampaignUseCase struct {
+	repo   repository.Repo
+	config *core.Config
+}
+
+func NewLinkCampaignUseCase(config *core.Config, repo repository.Repo) LinkCampaignUseCase {
+	return &linkCampaignUseCase{
+		repo:   repo,
+		config: config,
+	}
+}
+
+func (uc *linkCampaignUseCase) CreateLinkCampaign(ctx context.Context, campaign *entity.LinkCampaign) error {
+	if err := uc.repo.CreateLinkCampaign(ctx, campaign); err != nil {
+		slog.Error("failed to create link campaign", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (uc *linkCampaignUseCase) GetLinkCampaigns(ctx context.Context, tenantID string) ([]*entity.LinkCampaign, error) {
+	campaigns, err := uc.repo.FindLinkCampaignsByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to get link campaigns", slog.String("error", err.Error()))
+		return nil, err
+	}
+	return campaigns, nil
+}
+
+// findLinkCampaign returns mongo.ErrNoDocuments for ids that are not valid too
+func (uc *linkCampaignUseCase) findLinkCampaign(ctx context.Context, id string) (*entity.LinkCampaign, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	campaign, err := uc.repo.FindLinkCampaignByID(ctx, oid)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("failed to get link campaign", slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+	return campaign, nil
+}
+
+func (uc *linkCampaignUseCase) UpdateLinkCampaign(ctx context.Context, id string,
+	campaign *entity.LinkCampaign) (*entity.LinkCampaign, error) {
+	current, err := uc.findLinkCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign.ID = current.ID
+	campaign.TenantID = current.TenantID
+	campaign.CreatedAt = current.CreatedAt
+	if err := uc.repo.UpdateLinkCampaign(ctx, campaign); err != nil {
+		slog.Error("failed to update link campaign", slog.String("error", err.Error()))
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// DeleteLinkCampaign deletes the campaign, its links are kept without campaign
+func (uc *linkCampaignUseCase) DeleteLinkCampaign(ctx context.Context, id string) error {
+	campaign, err := uc.findLinkCampaign(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.DeleteLinkCampaign(ctx, campaign.ID); err != nil {
+		slog.Error("failed to delete link campaign", slog.String("error", err.Error()))
+		return err
+	}
+
+	if _, err := uc.repo.ClearLinksCampaign(ctx, campaign.ID); err != nil {
+		slog.Error("failed to clear links campaign", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+// AssignLinksToCampaign moves the links to the campaign, an empty campaign id removes them from
+// their campaign. Every link has to belong to the tenant.
+func (uc *linkCampaignUseCase) AssignLinksToCampaign(ctx context.Context, tenantID string, campaignID string,
+	linkIDs []string) (int64, error) {
+	var oid bson.ObjectID
+	if campaignID != "" {
+		campaign, err := uc.findLinkCampaign(ctx, campaignID)
+		if err != nil {
+			return 0, err
+		}
+		if campaign.TenantID != tenantID {
+			return 0, mongo.ErrNoDocuments
+		}
+		oid = campaign.ID
+	}
+
+	ids := []bson.ObjectID{}
+	for _, linkID := range linkIDs {
+		id, err := bson.ObjectIDFromHex(linkID)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrLinkNotFound, linkID)
+		}
+		ids = append(ids, id)
+	}
+
+	count, err := uc.repo.AssignLinksToCampaign(ctx, tenantID, ids, oid)
+	if err != nil {
+		slog.Error("failed to assign links to campaign", slog.String("error", err.Error()))
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetLinkGroupReport sums the clicks and the outcomes of the links of the tenant by campaign or tag,
+// over [from, to). Conversions are the ones of the tracks the links created.
+func (uc *linkCampaignUseCase) GetLinkGroupReport(ctx context.Context, tenantID string, group entity.LinkGroup,
+	from, to time.Time) (*entity.LinkGroupReport, error) {
+	links, err := uc.repo.FindAllLinkbyTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to get links", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	report := &entity.LinkGroupReport{Group: group, Rows: []*entity.LinkGroupRow{}}
+	if len(links) == 0 {
+		return report, nil
+	}
+
+	ids := make([]bson.ObjectID, 0, len(links))
+	for _, link := range links {
+		ids = append(ids, link.ID)
+	}
+
+	clickRows, err := uc.repo.CountClicksByLinks(ctx, ids, from, to)
+	if err != nil {
+		slog.Error("failed to count clicks by links", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	trackRows, err := uc.repo.CountTracksByLinks(ctx, ids, from, to)
+	if err != nil {
+		slog.Error("failed to count tracks by links", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	clicks := map[string]int64{}
+	for _, row := range clickRows {
+		clicks[row.Key] = row.Clicks
+	}
+	tracks := map[string]*entity.BreakdownRow{}
+	for _, row := range trackRows {
+		tracks[row.Key] = row
+	}
+
+	rows := map[string]*entity.LinkGroupRow{}
+	add := func(key string, link *entity.Link) {
+		row, ok := rows[key]
+		if !ok {
+			row = &entity.LinkGroupRow{Key: key}
+			rows[key] = row
+		}
+
+		row.Links++
+		row.Clicks += clicks[link.ID.Hex()]
+		if t, ok := tracks[link.ID.Hex()]; ok {
+			row.Tracks += t.Tracks
+			row.Landings += t.Landings
+			row.Conversions += t.Conversions
+		}
+	}
+
+	for _, link := range links {
+		switch group {
+		case entity.LinkGroupCampaign:
+			key := ""
+			if !link.CampaignID.IsZero() {
+				key = link.CampaignID.Hex()
+			}
+			add(key, link)
+		case entity.LinkGroupTag:
+			if len(link.Tags) == 0 {
+				add("", link)
+			}
+			for _, tag := range link.Tags {
+				add(tag, link)
+			}
+		}
+	}
+
+	if group == entity.LinkGroupCampaign {
+		campaigns, err := uc.repo.FindLinkCampaignsByTenantID(ctx, tenantID)
+		if err != nil {
+			slog.Error("failed to get link campaigns", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		for _, campaign := range campaigns {
+			row, ok := rows[campaign.ID.Hex()]
+			if !ok {
+				row = &entity.LinkGroupRow{Key: campaign.ID.Hex()}
+				rows[row.Key] = row
+			}
+			row.Name = campaign.Name
+			row.Budget = campaign.Budget
+		}
+	}
+
+	for _, row := range rows {
+		if row.Clicks > 0 {
+			row.ConversionRate = float64(row.Conversions) / float64(row.Clicks)
+		}
+		if row.Conversions > 0 && row.Budget > 0 {
+			row.CostPerConversion = row.Budget / float64(row.Conversions)
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if report.Rows[i].Clicks != report.Rows[j].Clicks {
+			return report.Rows[i].Clicks > report.Rows[j].Clicks
+		}
+		return report.Rows[i].Key < report.Rows[j].Key
+	})
+	return report, nil
+}
@@ -0,0 +1,318 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// importContext holds what the rows of an import are checked against
+type importContext struct {
+	tenantID  string
+	links     map[string]*entity.Link // by hex id
+	campaigns map[bson.ObjectID]bool
+	domains   map[string]string // normalized by the domain of the rows, empty when not registered
+	ids       map[string]int    // row of each link id
+	slugs     map[string]int    // row of each new slug, by domain and slug
+}
+
+// ImportLinks checks every row before writing any. An atomic import writes nothing when a row
+// fails and rolls its writes back when one of them fails, the other imports skip the failing rows.
+// The rollback is not a transaction (no replica set is needed), the links it can't undo are reported.
+func (uc *linkUseCase) ImportLinks(ctx context.Context, tenantID string, rows []*entity.LinkImportRow,
+	atomic bool) (*entity.LinkImportReport, error) {
+	links, err := uc.repo.FindAllLinkbyTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to get links", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	campaigns, err := uc.repo.FindLinkCampaignsByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to get link campaigns", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	ic := &importContext{
+		tenantID:  tenantID,
+		links:     map[string]*entity.Link{},
+		campaigns: map[bson.ObjectID]bool{},
+		domains:   map[string]string{},
+		ids:       map[string]int{},
+		slugs:     map[string]int{},
+	}
+	for _, link := range links {
+		ic.links[link.ID.Hex()] = link
+	}
+	for _, campaign := range campaigns {
+		ic.campaigns[campaign.ID] = true
+	}
+
+	report := &entity.LinkImportReport{Atomic: atomic, Rows: []*entity.LinkImportRowResult{}}
+	prepared := make([]*entity.Link, len(rows))
+	for i, row := range rows {
+		result := &entity.LinkImportRowResult{Row: row.Row, Action: entity.LinkImportActionCreate}
+		if row.ID != "" {
+			result.Action = entity.LinkImportActionUpdate
+		}
+
+		link, errs, err := uc.prepareImportRow(ctx, ic, row)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Errors = errs
+		if result.Failed() {
+			report.Failed++
+		} else {
+			prepared[i] = link
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	if atomic && report.Failed > 0 {
+		return report, nil
+	}
+
+	created := []bson.ObjectID{}
+	originals := []*entity.Link{}
+	for i, link := range prepared {
+		if link == nil {
+			continue
+		}
+
+		result := report.Rows[i]
+		var err error
+		if result.Action == entity.LinkImportActionCreate {
+			err = uc.repo.CreateLink(ctx, link)
+		} else {
+			err = uc.repo.UpdateLinkDetails(ctx, link)
+		}
+
+		if err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				result.Errors = append(result.Errors, ErrSlugTaken.Error())
+			} else {
+				slog.Error("failed to import link", slog.Int("row", result.Row), slog.String("error", err.Error()))
+				result.Errors = append(result.Errors, "failed to save the link")
+			}
+			report.Failed++
+
+			if atomic {
+				report.NotRolledBack = uc.rollbackImport(ctx, created, originals)
+				report.Created = 0
+				report.Updated = 0
+				return report, nil
+			}
+			continue
+		}
+
+		result.LinkID = link.ID.Hex()
+		if result.Action == entity.LinkImportActionCreate {
+			created = append(created, link.ID)
+			report.Created++
+		} else {
+			originals = append(originals, ic.links[link.ID.Hex()])
+			report.Updated++
+		}
+	}
+
+	report.Applied = true
+	return report, nil
+}
+
+// prepareImportRow returns the link to write or the problems of the row, err is only set when
+// the row couldn't be checked
+func (uc *linkUseCase) prepareImportRow(ctx context.Context, ic *importContext,
+	row *entity.LinkImportRow) (*entity.Link, []string, error) {
+	errs := []string{}
+
+	if row.Name == "" {
+		errs = append(errs, "name can not be empty")
+	}
+
+	if row.Url == "" {
+		errs = append(errs, "url can not be empty")
+	} else if !entity.IsWebURL(row.Url) {
+		errs = append(errs, "url is not valid")
+	}
+
+	var campaignID bson.ObjectID
+	if row.CampaignID != "" {
+		oid, err := bson.ObjectIDFromHex(row.CampaignID)
+		if err != nil || !ic.campaigns[oid] {
+			errs = append(errs, fmt.Sprintf("%s: %s", ErrLinkCampaignNotFound.Error(), row.CampaignID))
+		}
+		campaignID = oid
+	}
+
+	domain := ""
+	if row.Domain != "" {
+		normalized, ok := ic.domains[row.Domain]
+		if !ok {
+			d, err := uc.findLinkDomain(ctx, ic.tenantID, row.Domain)
+			if err != nil && !errors.Is(err, ErrLinkDomainNotRegistered) {
+				return nil, nil, err
+			}
+			normalized = d
+			ic.domains[row.Domain] = normalized
+		}
+
+		if normalized == "" {
+			errs = append(errs, fmt.Sprintf("%s: %s", ErrLinkDomainNotRegistered.Error(), row.Domain))
+		}
+		domain = normalized
+	}
+
+	if row.ID != "" {
+		current, ok := ic.links[row.ID]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%s: %s", ErrLinkNotFound.Error(), row.ID))
+			return nil, errs, nil
+		}
+
+		if first, ok := ic.ids[row.ID]; ok {
+			errs = append(errs, fmt.Sprintf("id is already in row %d", first))
+		} else {
+			ic.ids[row.ID] = row.Row
+		}
+
+		if row.Slug != "" && row.Slug != current.ShortID {
+			errs = append(errs, "slug can not be changed")
+		}
+		if row.Domain != "" && domain != current.Domain {
+			errs = append(errs, "domain can not be changed")
+		}
+
+		link := *current
+		link.Name = row.Name
+		link.Url = row.Url
+		link.Tags = entity.NormalizeTags(row.Tags)
+		link.CampaignID = campaignID
+		return &link, errs, nil
+	}
+
+	if row.Slug != "" {
+		key := domain + "/" + row.Slug
+		if err := entity.ValidateSlug(row.Slug, uc.blockedWords); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", ErrInvalidSlug.Error(), err.Error()))
+		} else if first, ok := ic.slugs[key]; ok {
+			errs = append(errs, fmt.Sprintf("slug is already in row %d", first))
+		} else {
+			ic.slugs[key] = row.Row
+
+			_, err := uc.repo.FindLinkByDomainAndShortID(ctx, domain, row.Slug)
+			if err == nil {
+				errs = append(errs, ErrSlugTaken.Error())
+			} else if !errors.Is(err, mongo.ErrNoDocuments) {
+				slog.Error("failed to find link", slog.String("error", err.Error()))
+				return nil, nil, err
+			}
+		}
+	}
+
+	link := &entity.Link{
+		TenantID:   ic.tenantID,
+		Name:       row.Name,
+		Url:        row.Url,
+		ShortID:    row.Slug,
+		Domain:     domain,
+		Tags:       entity.NormalizeTags(row.Tags),
+		CampaignID: campaignID,
+	}
+	return link, errs, nil
+}
+
+// rollbackImport removes the created links and puts the updated ones back, as far as it can.
+// It returns the ids of the links left as the import wrote them.
+func (uc *linkUseCase) rollbackImport(ctx context.Context, created []bson.ObjectID, originals []*entity.Link) []string {
+	left := []string{}
+	if len(created) > 0 {
+		if _, err := uc.repo.RemoveLinks(ctx, created); err != nil {
+			slog.Error("failed to roll back created links", slog.String("error", err.Error()))
+			for _, id := range created {
+				left = append(left, id.Hex())
+			}
+		}
+	}
+
+	for _, link := range originals {
+		if err := uc.repo.UpdateLinkDetails(ctx, link); err != nil {
+			slog.Error("failed to roll back updated link", slog.String("id", link.ID.Hex()),
+				slog.String("error", err.Error()))
+			left = append(left, link.ID.Hex())
+		}
+	}
+
+	if len(left) == 0 {
+		return nil
+	}
+	return left
+}
+
+// ExportLinks lists the links of the tenant with their short links, clicks and outcomes
+func (uc *linkUseCase) ExportLinks(ctx context.Context, tenantID string) ([]*entity.LinkExportRow, error) {
+	links, err := uc.repo.FindAllLinkbyTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to get links", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	rows := []*entity.LinkExportRow{}
+	if len(links) == 0 {
+		return rows, nil
+	}
+
+	if err := uc.countUncappedClicks(ctx, links); err != nil {
+		return nil, err
+	}
+
+	ids := make([]bson.ObjectID, 0, len(links))
+	for _, link := range links {
+		ids = append(ids, link.ID)
+	}
+
+	trackRows, err := uc.repo.CountTracksByLinks(ctx, ids, time.Time{}, time.Time{})
+	if err != nil {
+		slog.Error("failed to count tracks by links", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	tracks := map[string]*entity.BreakdownRow{}
+	for _, row := range trackRows {
+		tracks[row.Key] = row
+	}
+
+	for _, link := range links {
+		row := &entity.LinkExportRow{
+			ID:        link.ID.Hex(),
+			Name:      link.Name,
+			Url:       link.Url,
+			Slug:      link.ShortID,
+			Domain:    link.Domain,
+			FixedUrl:  link.ConstructFixedUrl(uc.config.Domain),
+			Tags:      link.Tags,
+			Clicks:    link.Clicks,
+			CreatedAt: link.CreatedAt,
+		}
+		if row.Tags == nil {
+			row.Tags = []string{}
+		}
+		if !link.CampaignID.IsZero() {
+			row.CampaignID = link.CampaignID.Hex()
+		}
+		if t, ok := tracks[row.ID]; ok {
+			row.Tracks = t.Tracks
+			row.Landings = t.Landings
+			row.Conversions = t.Conversions
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -1,43 +1,198 @@
 package usecase
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/enrichment"
 	"github/michaellimmm/turakkingu/internal/entity"
 	"github/michaellimmm/turakkingu/internal/repository"
+	"github/michaellimmm/turakkingu/pkg/qrcode"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
 	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var (
+	ErrInvalidSlug             = errors.New("invalid slug")
+	ErrSlugTaken               = errors.New("slug is already taken")
+	ErrLinkDomainNotRegistered = errors.New("link domain is not registered by the tenant")
+	ErrInvalidQRLogo           = errors.New("invalid qr logo")
+	ErrLinkNotFound            = errors.New("link not found")
+	ErrLinkCampaignNotFound    = errors.New("link campaign not found")
 )
 
+// maxQRLogoSize caps the download of a QR logo
+const maxQRLogoSize = 1 << 20
+
+// maxQRLogoPixels caps the decoded QR logo, a small file can declare a huge image
+const maxQRLogoPixels = 2048 * 2048
+
 type LinkUseCase interface {
 	CreateLink(context.Context, *entity.Link) error
-	GetLink(context.Context, string) (*entity.Link, error)
+	ResolveLink(ctx context.Context, host string, slug string) (*entity.Link, error)
+	ResolveBrandedLink(ctx context.Context, host string, slug string) (*entity.Link, error)
 	GetAllLinks(ctx context.Context, tenantID string) ([]*entity.Link, error)
-	SearchLinks(ctx context.Context, tenantID string, keywords string) ([]*entity.Link, error)
+	SearchLinks(ctx context.Context, tenantID string, search entity.LinkSearch) (*entity.LinkSearchResult, error)
+	UpdateLinkLifecycle(ctx context.Context, id string, lifecycle entity.LinkLifecycle) (*entity.Link, error)
+	GetLinkQR(ctx context.Context, id string, opts entity.QROptions) (*entity.Link, []byte, error)
+	UpdateLinkRules(ctx context.Context, id string, rules []entity.RedirectRule) (*entity.Link, error)
+	UpdateLinkRedirect(ctx context.Context, id string, redirect entity.LinkRedirect) (*entity.Link, error)
+	DryRunLinkRules(ctx context.Context, id string, req entity.RuleRequest) (*entity.RuleMatch, error)
+	UpdateLinkTags(ctx context.Context, id string, tags []string) (*entity.Link, error)
+	ImportLinks(ctx context.Context, tenantID string, rows []*entity.LinkImportRow, atomic bool) (*entity.LinkImportReport, error)
+	ExportLinks(ctx context.Context, tenantID string) ([]*entity.LinkExportRow, error)
 }
 
 type linkUseCase struct {
-	repo   repository.Repo
-	config *core.Config
+	repo         repository.Repo
+	config       *core.Config
+	blockedWords []string
+	httpClient   *http.Client
 }
 
+// NewLinkUseCase loads the slug blocklist, a missing list only disables that check
 func NewLinkUseCase(config *core.Config, repo repository.Repo) LinkUseCase {
+	blockedWords, err := loadWords(config.SlugBlocklist)
+	if err != nil {
+		slog.Warn("slug blocklist not loaded", slog.String("path", config.SlugBlocklist), slog.String("error", err.Error()))
+	}
+
 	return &linkUseCase{
-		repo:   repo,
-		config: config,
+		repo:         repo,
+		config:       config,
+		blockedWords: blockedWords,
+		// logos are not followed out of the owned domains, nor fetched from internal addresses
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout: 5 * time.Second,
+					Control: publicAddressOnly,
+				}).DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+			},
+		},
+	}
+}
+
+// loadWords reads one lowercased word per line, blank lines and # comments are skipped
+func loadWords(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
 	}
+	defer f.Close()
+
+	words := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.ToLower(strings.TrimSpace(line)); line != "" {
+			words = append(words, line)
+		}
+	}
+
+	return words, scanner.Err()
 }
 
+// CreateLink keeps a custom slug as the short id, slugs are unique per domain and the
+// domain has to be one of the link domains of the tenant
 func (uc *linkUseCase) CreateLink(ctx context.Context, link *entity.Link) error {
+	if link.ShortID != "" {
+		if err := entity.ValidateSlug(link.ShortID, uc.blockedWords); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidSlug, err.Error())
+		}
+	}
+
+	if link.Domain != "" {
+		domain, err := uc.findLinkDomain(ctx, link.TenantID, link.Domain)
+		if err != nil {
+			return err
+		}
+		link.Domain = domain
+	}
+
+	if !link.CampaignID.IsZero() {
+		campaign, err := uc.repo.FindLinkCampaignByID(ctx, link.CampaignID)
+		if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && campaign.TenantID != link.TenantID) {
+			return fmt.Errorf("%w: %s", ErrLinkCampaignNotFound, link.CampaignID.Hex())
+		} else if err != nil {
+			slog.Error("failed to find link campaign", slog.String("error", err.Error()))
+			return err
+		}
+	}
+
+	link.Tags = entity.NormalizeTags(link.Tags)
 	err := uc.repo.CreateLink(ctx, link)
-	if err != nil {
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrSlugTaken
+	} else if err != nil {
 		slog.Error("failed to create link", slog.String("error", err.Error()))
 		return err
 	}
 	return nil
 }
 
-func (uc *linkUseCase) GetLink(ctx context.Context, id string) (*entity.Link, error) {
-	link, err := uc.repo.FindLinkByShortID(ctx, id)
+// findLinkDomain normalizes the domain, it has to be one of the link domains of the tenant
+func (uc *linkUseCase) findLinkDomain(ctx context.Context, tenantID string, domain string) (string, error) {
+	domain, err := entity.NormalizeDomain(domain)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrLinkDomainNotRegistered, err.Error())
+	}
+
+	owner, err := uc.repo.FindTenantIDByLinkDomain(ctx, domain)
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && owner != tenantID) {
+		return "", fmt.Errorf("%w: %s", ErrLinkDomainNotRegistered, domain)
+	} else if err != nil {
+		slog.Error("failed to find link domain", slog.String("error", err.Error()))
+		return "", err
+	}
+	return domain, nil
+}
+
+// ResolveLink finds the link of a slug on the host of the request. Branded domains only
+// serve their own links, any other host serves the links of the default domain.
+func (uc *linkUseCase) ResolveLink(ctx context.Context, host string, slug string) (*entity.Link, error) {
+	host = strings.ToLower(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	domain := ""
+	_, err := uc.repo.FindTenantIDByLinkDomain(ctx, host)
+	if err == nil {
+		domain = host
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		slog.Error("failed to find link domain", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	link, err := uc.repo.FindLinkByDomainAndShortID(ctx, domain, slug)
 	if err != nil {
 		slog.Error("failed to get link", slog.String("error", err.Error()))
 		return nil, err
@@ -45,6 +200,35 @@ func (uc *linkUseCase) GetLink(ctx context.Context, id string) (*entity.Link, er
 	return link, nil
 }
 
+// ResolveBrandedLink finds the link of a slug on a branded domain. The default domain and the hosts
+// that aren't link domains (favicon.ico, robots.txt, scanners) return mongo.ErrNoDocuments without
+// looking the slug up.
+func (uc *linkUseCase) ResolveBrandedLink(ctx context.Context, host string, slug string) (*entity.Link, error) {
+	domain, err := entity.NormalizeDomain(host)
+	if err != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+	if defaultDomain, _ := entity.NormalizeDomain(uc.config.Domain); domain == defaultDomain {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	if _, err := uc.repo.FindTenantIDByLinkDomain(ctx, domain); err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("failed to find link domain", slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+
+	link, err := uc.repo.FindLinkByDomainAndShortID(ctx, domain, slug)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("failed to get link", slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+	return link, nil
+}
+
 func (uc *linkUseCase) GetAllLinks(ctx context.Context, tenantID string) ([]*entity.Link, error) {
 	links, err := uc.repo.FindAllLinkbyTenantID(ctx, tenantID)
 	if err != nil {
@@ -55,12 +239,286 @@ func (uc *linkUseCase) GetAllLinks(ctx context.Context, tenantID string) ([]*ent
 	return links, err
 }
 
-func (uc *linkUseCase) SearchLinks(ctx context.Context, tenantID string, keywords string) ([]*entity.Link, error) {
-	links, err := uc.repo.SearchLinks(ctx, tenantID, keywords)
+func (uc *linkUseCase) SearchLinks(ctx context.Context, tenantID string, search entity.LinkSearch) (*entity.LinkSearchResult, error) {
+	result, err := uc.repo.SearchLinks(ctx, tenantID, search)
 	if err != nil {
 		slog.Error("failed to search links", slog.String("error", err.Error()))
 		return nil, err
 	}
 
-	return links, nil
+	if err := uc.countUncappedClicks(ctx, result.Links); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (uc *linkUseCase) UpdateLinkLifecycle(ctx context.Context, id string, lifecycle entity.LinkLifecycle) (*entity.Link, error) {
+	link, err := uc.repo.FindLinkByID(ctx, id)
+	if err != nil {
+		slog.Error("failed to get link", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	// uncapped links keep no counter, a new cap starts from the clicks so far
+	if link.MaxClicks == 0 && lifecycle.MaxClicks > 0 {
+		if err := uc.restartClickCounter(ctx, link); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.repo.UpdateLinkLifecycle(ctx, link.ID, lifecycle); err != nil {
+		slog.Error("failed to update link lifecycle", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	link.LinkLifecycle = lifecycle
+	return link, nil
+}
+
+// restartClickCounter sets the counter of the link to its human clicks
+func (uc *linkUseCase) restartClickCounter(ctx context.Context, link *entity.Link) error {
+	rows, err := uc.repo.CountClicksByLinks(ctx, []bson.ObjectID{link.ID}, time.Time{}, time.Time{})
+	if err != nil {
+		slog.Error("failed to count clicks by links", slog.String("error", err.Error()))
+		return err
+	}
+
+	link.Clicks = 0
+	for _, row := range rows {
+		link.Clicks = row.Clicks
+	}
+
+	if err := uc.repo.SetLinkClicks(ctx, link.ID, link.Clicks); err != nil {
+		slog.Error("failed to set link clicks", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+// countUncappedClicks fills the clicks of the uncapped links from the stored clicks, only the
+// capped links keep a counter
+func (uc *linkUseCase) countUncappedClicks(ctx context.Context, links []*entity.Link) error {
+	ids := []bson.ObjectID{}
+	for _, link := range links {
+		if link.MaxClicks == 0 {
+			ids = append(ids, link.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := uc.repo.CountClicksByLinks(ctx, ids, time.Time{}, time.Time{})
+	if err != nil {
+		slog.Error("failed to count clicks by links", slog.String("error", err.Error()))
+		return err
+	}
+
+	clicks := map[string]int64{}
+	for _, row := range rows {
+		clicks[row.Key] = row.Clicks
+	}
+	for _, link := range links {
+		if link.MaxClicks == 0 {
+			link.Clicks = clicks[link.ID.Hex()]
+		}
+	}
+	return nil
+}
+
+func (uc *linkUseCase) UpdateLinkRules(ctx context.Context, id string, rules []entity.RedirectRule) (*entity.Link, error) {
+	link, err := uc.repo.FindLinkByID(ctx, id)
+	if err != nil {
+		slog.Error("failed to get link", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := uc.repo.UpdateLinkRules(ctx, link.ID, rules); err != nil {
+		slog.Error("failed to update link rules", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	link.Rules = rules
+	return link, nil
+}
+
+func (uc *linkUseCase) UpdateLinkRedirect(ctx context.Context, id string, redirect entity.LinkRedirect) (*entity.Link, error) {
+	link, err := uc.repo.FindLinkByID(ctx, id)
+	if err != nil {
+		slog.Error("failed to get link", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := uc.repo.UpdateLinkRedirect(ctx, link.ID, redirect); err != nil {
+		slog.Error("failed to update link redirect", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	link.LinkRedirect = redirect
+	return link, nil
+}
+
+func (uc *linkUseCase) UpdateLinkTags(ctx context.Context, id string, tags []string) (*entity.Link, error) {
+	link, err := uc.repo.FindLinkByID(ctx, id)
+	if err != nil {
+		slog.Error("failed to get link", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	link.Tags = entity.NormalizeTags(tags)
+	if err := uc.repo.UpdateLinkTags(ctx, link.ID, link.Tags); err != nil {
+		slog.Error("failed to update link tags", slog.String("error", err.Error()))
+		return nil, err
+	}
+	return link, nil
+}
+
+// DryRunLinkRules tells which rule a click with the attributes would match, without recording it.
+// Index is -1 when the click goes to the link destination or its variants.
+func (uc *linkUseCase) DryRunLinkRules(ctx context.Context, id string, req entity.RuleRequest) (*entity.RuleMatch, error) {
+	link, err := uc.repo.FindLinkByID(ctx, id)
+	if err != nil {
+		slog.Error("failed to get link", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if req.UserAgent != "" {
+		device := enrichment.ParseDevice(req.UserAgent, entity.ClientHints{})
+		if req.DeviceType == "" {
+			req.DeviceType = device.Type
+		}
+		if req.OS == "" {
+			req.OS = device.OS
+		}
+	}
+
+	if match := link.MatchRule(req); match != nil {
+		return match, nil
+	}
+	return &entity.RuleMatch{Index: -1, Destination: link.Url}, nil
+}
+
+// GetLinkQR encodes the QR url of the link. A logo raises the error correction to at least Q,
+// it has to be hosted on an owned domain of the tenant.
+func (uc *linkUseCase) GetLinkQR(ctx context.Context, id string, opts entity.QROptions) (*entity.Link, []byte, error) {
+	link, err := uc.repo.FindLinkByID(ctx, id)
+	if err != nil {
+		slog.Error("failed to get link", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	level, err := qrcode.ParseLevel(opts.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var logo image.Image
+	if opts.Logo != "" {
+		logo, err = uc.fetchQRLogo(ctx, link.TenantID, opts.Logo)
+		if err != nil {
+			return nil, nil, err
+		}
+		level = max(level, qrcode.Quartile)
+	}
+
+	code, err := qrcode.Encode([]byte(link.ConstructQRUrl(uc.config.Domain)), level)
+	if err != nil {
+		slog.Error("failed to encode qr code", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	var b []byte
+	if opts.Format == entity.QRFormatSVG {
+		b, err = code.SVG(opts.Size, logo)
+	} else {
+		b, err = code.PNG(opts.Size, logo)
+	}
+	if err != nil {
+		slog.Error("failed to render qr code", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	return link, b, nil
+}
+
+// fetchQRLogo downloads the logo from an owned domain. The errors don't tell why the logo could
+// not be loaded, so the endpoint can't be used to probe hosts.
+func (uc *linkUseCase) fetchQRLogo(ctx context.Context, tenantID string, logoUrl string) (image.Image, error) {
+	trackingSetting, err := uc.repo.FindOrCreateWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to find or create tracking setting with pages by tenant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	u, err := url.Parse(logoUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("%w: logo must be an http or https url", ErrInvalidQRLogo)
+	}
+	host := strings.ToLower(u.Hostname())
+	if net.ParseIP(host) != nil || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return nil, fmt.Errorf("%w: logo must be on an owned domain", ErrInvalidQRLogo)
+	}
+	if len(trackingSetting.Domains) == 0 || !trackingSetting.IsOwnedURL(logoUrl) {
+		return nil, fmt.Errorf("%w: logo must be on an owned domain", ErrInvalidQRLogo)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: logo could not be loaded", ErrInvalidQRLogo)
+	}
+
+	res, err := uc.httpClient.Do(req)
+	if err != nil {
+		slog.Warn("failed to fetch qr logo", slog.String("url", logoUrl), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%w: logo could not be loaded", ErrInvalidQRLogo)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		slog.Warn("failed to fetch qr logo", slog.String("url", logoUrl), slog.Int("status", res.StatusCode))
+		return nil, fmt.Errorf("%w: logo could not be loaded", ErrInvalidQRLogo)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxQRLogoSize))
+	if err != nil {
+		slog.Warn("failed to read qr logo", slog.String("url", logoUrl), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%w: logo could not be loaded", ErrInvalidQRLogo)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		slog.Warn("failed to decode qr logo", slog.String("url", logoUrl), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%w: logo could not be loaded", ErrInvalidQRLogo)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxQRLogoPixels {
+		return nil, fmt.Errorf("%w: logo must be at most %d pixels", ErrInvalidQRLogo, maxQRLogoPixels)
+	}
+
+	logo, _, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		slog.Warn("failed to decode qr logo", slog.String("url", logoUrl), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%w: logo could not be loaded", ErrInvalidQRLogo)
+	}
+	return logo, nil
+}
+
+// publicAddressOnly refuses to connect to loopback, private, link-local and unspecified
+// addresses, an owned domain can still resolve to one of them
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return fmt.Errorf("address %s is not public", addr)
+	}
+	return nil
 }
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+)
+
+type MetricsUseCase interface {
+	GetCacheStats() []entity.CacheStats
+}
+
+type metricsUseCase struct {
+	repo   repository.Repo
+	config *core.Config
+}
+
+func NewMetricsUseCase(config *core.Config, repo repository.Repo) MetricsUseCase {
+	return &metricsUseCase{
+		repo:   repo,
+		config: config,
+	}
+}
+
+func (uc *metricsUseCase) GetCacheStats() []entity.CacheStats {
+	return uc.repo.CacheStats()
+}
@@ -0,0 +1,291 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type PrivacyUseCase interface {
+	ExportSubjectData(ctx context.Context, request *entity.DataSubjectRequest) (*entity.SubjectData, error)
+	EraseSubjectData(ctx context.Context, request *entity.DataSubjectRequest) error
+	GetDataSubjectRequests(ctx context.Context, tenantID string) ([]*entity.DataSubjectRequest, error)
+}
+
+type privacyUseCase struct {
+	repo   repository.Repo
+	config *core.Config
+}
+
+func NewPrivacyUseCase(config *core.Config, repo repository.Repo) PrivacyUseCase {
+	return &privacyUseCase{
+		repo:   repo,
+		config: config,
+	}
+}
+
+// ExportSubjectData returns every identity, track, event (including conversions, which are
+// stored as thank you page events) and short link click that belongs to the subject at the
+// request's tenant
+func (uc *privacyUseCase) ExportSubjectData(ctx context.Context,
+	request *entity.DataSubjectRequest) (*entity.SubjectData, error) {
+	request.Type = entity.DataSubjectRequestTypeExport
+	if err := uc.startRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	data, err := uc.collectSubjectData(ctx, request, request.Subject)
+	if err != nil {
+		uc.finishRequest(ctx, request, entity.DataSubjectRequestResult{}, err)
+		return nil, err
+	}
+
+	uc.finishRequest(ctx, request, entity.DataSubjectRequestResult{
+		Tracks:     int64(len(data.Tracks)),
+		Events:     int64(len(data.Events)),
+		Clicks:     int64(len(data.Clicks)),
+		Identities: int64(len(data.Identities)),
+	}, nil)
+
+	return data, nil
+}
+
+// EraseSubjectData hard-deletes or anonymizes the subject's tracks, events and clicks. The identity
+// clusters are deleted in both modes because the graph itself links personal identifiers. The audit
+// record only ever stores the hash of the subject, a failed erasure is retried with a new request.
+// Documents already moved to the retention archives are not erased.
+func (uc *privacyUseCase) EraseSubjectData(ctx context.Context, request *entity.DataSubjectRequest) error {
+	if request.Type != entity.DataSubjectRequestTypeDelete && request.Type != entity.DataSubjectRequestTypeAnonymize {
+		return fmt.Errorf("erase request type must be %s or %s",
+			entity.DataSubjectRequestTypeDelete, entity.DataSubjectRequestTypeAnonymize)
+	}
+
+	subject := request.Subject
+	request.HashSubject()
+	if err := uc.startRequest(ctx, request); err != nil {
+		return err
+	}
+
+	result, err := uc.eraseSubjectData(ctx, request, subject)
+	uc.finishRequest(ctx, request, result, err)
+	return err
+}
+
+func (uc *privacyUseCase) eraseSubjectData(ctx context.Context, request *entity.DataSubjectRequest,
+	subject entity.Identifier) (entity.DataSubjectRequestResult, error) {
+	result := entity.DataSubjectRequestResult{}
+
+	data, err := uc.collectSubjectData(ctx, request, subject)
+	if err != nil {
+		return result, err
+	}
+
+	trackIDs := []bson.ObjectID{}
+	for _, track := range data.Tracks {
+		trackIDs = append(trackIDs, track.ID)
+	}
+	eventIDs := []bson.ObjectID{}
+	for _, event := range data.Events {
+		eventIDs = append(eventIDs, event.ID)
+	}
+	clickIDs := []bson.ObjectID{}
+	for _, click := range data.Clicks {
+		clickIDs = append(clickIDs, click.ID)
+	}
+
+	if request.Type == entity.DataSubjectRequestTypeDelete {
+		if result.Events, err = uc.repo.DeleteEventsByIDs(ctx, eventIDs); err != nil {
+			return result, err
+		}
+		if result.Clicks, err = uc.repo.DeleteClicksByIDs(ctx, clickIDs); err != nil {
+			return result, err
+		}
+		if result.Tracks, err = uc.repo.DeleteTracksByIDs(ctx, trackIDs); err != nil {
+			return result, err
+		}
+	} else {
+		if result.Events, err = uc.repo.AnonymizeEventsByIDs(ctx, eventIDs); err != nil {
+			return result, err
+		}
+		if result.Clicks, err = uc.repo.AnonymizeClicksByIDs(ctx, clickIDs); err != nil {
+			return result, err
+		}
+		if result.Tracks, err = uc.repo.AnonymizeTracksByIDs(ctx, trackIDs); err != nil {
+			return result, err
+		}
+	}
+
+	for _, identity := range data.Identities {
+		if err := uc.repo.DeleteIdentity(ctx, identity.ID); err != nil {
+			return result, err
+		}
+		result.Identities++
+	}
+
+	return result, nil
+}
+
+// collectSubjectData expands the subject through the identity graph of the request's tracking
+// setting. Fingerprints that also belong to other clusters are shared with other people, so events
+// are only matched on them when the subject itself is that fingerprint.
+func (uc *privacyUseCase) collectSubjectData(ctx context.Context, request *entity.DataSubjectRequest,
+	subject entity.Identifier) (*entity.SubjectData, error) {
+	identities, err := uc.repo.FindIdentitiesByIdentifiers(ctx, request.TrackingSettingID, []entity.Identifier{subject})
+	if err != nil {
+		slog.Error("failed to find subject identities", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if subject.Type.IsProbabilistic() && len(identities) > 1 {
+		// a shared fingerprint must not pull in the records of every person using it
+		identities = []*entity.Identity{}
+	}
+
+	endUserIDs := map[string]bool{}
+	ztids := map[string]bool{}
+	fingerprints := map[string]bool{}
+	switch subject.Type {
+	case entity.IdentifierTypeEndUserID:
+		endUserIDs[subject.Value] = true
+	case entity.IdentifierTypeZTID:
+		ztids[subject.Value] = true
+	case entity.IdentifierTypeFingerprint:
+		fingerprints[subject.Value] = true
+	}
+
+	for _, identity := range identities {
+		for _, value := range identity.Values(entity.IdentifierTypeEndUserID) {
+			endUserIDs[value] = true
+		}
+		for _, value := range identity.Values(entity.IdentifierTypeZTID) {
+			ztids[value] = true
+		}
+		for _, value := range identity.Values(entity.IdentifierTypeFingerprint) {
+			shared, err := uc.isSharedFingerprint(ctx, request.TrackingSettingID, value, len(identities))
+			if err != nil {
+				return nil, err
+			}
+			if !shared {
+				fingerprints[value] = true
+			}
+		}
+	}
+
+	trackObjectIDs := []bson.ObjectID{}
+	for ztid := range ztids {
+		if oid, err := bson.ObjectIDFromHex(ztid); err == nil {
+			trackObjectIDs = append(trackObjectIDs, oid)
+		}
+	}
+
+	tracks, err := uc.repo.FindTracksBySubject(ctx, request.TrackingSettingID, trackObjectIDs, keys(endUserIDs))
+	if err != nil {
+		slog.Error("failed to find subject tracks", slog.String("error", err.Error()))
+		return nil, err
+	}
+	// only the ztids of the tenant's tracks, a ztid of another tenant must not match its events
+	trackIDs := []string{}
+	for _, track := range tracks {
+		trackIDs = append(trackIDs, track.ID.Hex())
+	}
+
+	events, err := uc.repo.FindEventsBySubject(ctx, request.TenantID, trackIDs, keys(fingerprints))
+	if err != nil {
+		slog.Error("failed to find subject events", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	clicks, err := uc.repo.FindClicksByTrackIDs(ctx, request.TenantID, trackIDs)
+	if err != nil {
+		slog.Error("failed to find subject clicks", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &entity.SubjectData{
+		Subject:    subject,
+		Identities: identities,
+		Tracks:     tracks,
+		Events:     events,
+		Clicks:     clicks,
+		ExportedAt: time.Now().UTC(),
+	}, nil
+}
+
+func (uc *privacyUseCase) isSharedFingerprint(ctx context.Context, trackingSettingID bson.ObjectID,
+	fingerprint string, subjectClusters int) (bool, error) {
+	clusters, err := uc.repo.FindIdentitiesByIdentifiers(ctx, trackingSettingID, []entity.Identifier{{
+		Type:  entity.IdentifierTypeFingerprint,
+		Value: fingerprint,
+	}})
+	if err != nil {
+		return false, err
+	}
+	return len(clusters) > subjectClusters, nil
+}
+
+func (uc *privacyUseCase) startRequest(ctx context.Context, request *entity.DataSubjectRequest) error {
+	trackingSetting, err := uc.repo.FindTrackingSettingWithPagesByTenantID(ctx, request.TenantID)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("failed to get tracking setting", slog.String("error", err.Error()))
+		}
+		return err
+	}
+
+	request.TrackingSettingID = trackingSetting.ID
+	request.Status = entity.DataSubjectRequestStatusPending
+	if err := uc.repo.CreateDataSubjectRequest(ctx, request); err != nil {
+		slog.Error("failed to create data subject request", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (uc *privacyUseCase) finishRequest(ctx context.Context, request *entity.DataSubjectRequest,
+	result entity.DataSubjectRequestResult, err error) {
+	if err != nil {
+		slog.Error("data subject request failed",
+			slog.String("request_id", request.ID.Hex()),
+			slog.String("error", err.Error()))
+		request.Fail(err)
+		request.Result = result
+	} else {
+		request.Complete(result)
+	}
+
+	if err := uc.repo.UpdateDataSubjectRequest(ctx, request); err != nil {
+		slog.Error("failed to update data subject request", slog.String("error", err.Error()))
+	}
+}
+
+func (uc *privacyUseCase) GetDataSubjectRequests(ctx context.Context, tenantID string) ([]*entity.DataSubjectRequest, error) {
+	trackingSetting, err := uc.repo.FindTrackingSettingWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("failed to get tracking setting", slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+
+	requests, err := uc.repo.FindDataSubjectRequestsByTrackingSettingID(ctx, trackingSetting.ID)
+	if err != nil {
+		slog.Error("failed to get data subject requests", slog.String("error", err.Error()))
+		return nil, err
+	}
+	return requests, nil
+}
+
+func keys(m map[string]bool) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	return res
+}
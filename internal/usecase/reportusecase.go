@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var ErrInvalidReportQuery = errors.New("invalid report query")
+
+type ReportUseCase interface {
+	GetReport(ctx context.Context, tenantID string, query entity.ReportQuery) (*entity.Report, error)
+}
+
+type reportUseCase struct {
+	repo   repository.Repo
+	config *core.Config
+}
+
+func NewReportUseCase(config *core.Config, repo repository.Repo) ReportUseCase {
+	return &reportUseCase{
+		repo:   repo,
+		config: config,
+	}
+}
+
+// reportLocation is the time zone of the reports of the tenant, UTC when unset or unknown
+func reportLocation(timeZone string) *time.Location {
+	if timeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		slog.Error("failed to load time zone", slog.String("time_zone", timeZone), slog.String("error", err.Error()))
+		return time.UTC
+	}
+	return loc
+}
+
+// GetReport counts the clicks, tracks and events of the tenant by time bucket and dimensions in
+// the time zone of the tenant
+func (uc *reportUseCase) GetReport(ctx context.Context, tenantID string, query entity.ReportQuery) (*entity.Report, error) {
+	setting, err := uc.repo.FindTrackingSettingWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("failed to get tracking setting", slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+
+	loc := reportLocation(setting.TimeZone)
+	query.Normalize()
+	from, to, err := query.Range(loc, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidReportQuery, err.Error())
+	}
+
+	rng := repository.ReportRange{
+		From:        from,
+		To:          to,
+		Granularity: query.Granularity,
+		Location:    loc,
+		Dimensions:  query.Dimensions,
+	}
+
+	counts, err := uc.countReport(ctx, tenantID, setting.ID, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := uc.repo.FindAllLinkbyTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to get links", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	campaigns, err := uc.repo.FindLinkCampaignsByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to get link campaigns", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	names := map[string]string{}
+	linkCampaigns := map[string]string{}
+	for _, link := range links {
+		names[link.ID.Hex()] = link.Name
+		if !link.CampaignID.IsZero() {
+			linkCampaigns[link.ID.Hex()] = link.CampaignID.Hex()
+		}
+	}
+	for _, campaign := range campaigns {
+		names[campaign.ID.Hex()] = campaign.Name
+	}
+	for _, page := range setting.ThankYouPages {
+		names[page.ID.Hex()] = page.Name
+	}
+
+	report := &entity.Report{
+		TimeZone:    loc.String(),
+		Granularity: query.Granularity,
+		From:        from,
+		To:          to,
+		Metrics:     query.Metrics,
+		Dimensions:  query.Dimensions,
+		Rows:        []*entity.ReportRow{},
+		Labels:      map[string]string{},
+		Totals:      &entity.ReportRow{Dimensions: map[entity.ReportDimension]string{}},
+	}
+	if report.Dimensions == nil {
+		report.Dimensions = []entity.ReportDimension{}
+	}
+
+	rows := map[string]*entity.ReportRow{}
+	for _, source := range counts {
+		for _, count := range source {
+			row := reportRow(rows, &query, count.Key, linkCampaigns, loc)
+			row.Add(count)
+			report.Totals.Add(count)
+		}
+	}
+
+	for _, row := range rows {
+		setConversionRate(row)
+		report.Rows = append(report.Rows, row)
+		for _, value := range row.Dimensions {
+			if name, ok := names[value]; ok {
+				report.Labels[value] = name
+			}
+		}
+	}
+	setConversionRate(report.Totals)
+	sortReportRows(report.Rows, query.Dimensions)
+
+	return report, nil
+}
+
+// countReport counts from the rollups when they cover the range, otherwise from the clicks,
+// tracks and events
+func (uc *reportUseCase) countReport(ctx context.Context, tenantID string, trackingSettingID bson.ObjectID,
+	rng repository.ReportRange) ([][]*entity.ReportCount, error) {
+	if daily, ok := uc.useRollups(ctx, tenantID, rng); ok {
+		counts, err := uc.repo.CountReportRollups(ctx, tenantID, rng, daily)
+		if err != nil {
+			slog.Error("failed to count rollups", slog.String("error", err.Error()))
+			return nil, err
+		}
+		return [][]*entity.ReportCount{counts}, nil
+	}
+
+	clicks, err := uc.repo.CountReportClicks(ctx, tenantID, rng)
+	if err != nil {
+		slog.Error("failed to count clicks", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	tracks, err := uc.repo.CountReportTracks(ctx, trackingSettingID, rng)
+	if err != nil {
+		slog.Error("failed to count tracks", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	events, err := uc.repo.CountReportEvents(ctx, tenantID, rng)
+	if err != nil {
+		slog.Error("failed to count events", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return [][]*entity.ReportCount{clicks, tracks, events}, nil
+}
+
+// useRollups tells if the range can be counted from the rollups and from which ones. The hourly
+// rollups start at the UTC hours so they only make the days of whole hour offsets. The range must
+// start at a bucket and end at one, or in the current one which is rolled up to the last run.
+// The daily rollups are only read while they are all in the time zone of the range.
+func (uc *reportUseCase) useRollups(ctx context.Context, tenantID string, rng repository.ReportRange) (daily bool, ok bool) {
+	if uc.config.RollupInterval <= 0 {
+		return false, false
+	}
+
+	watermark, err := uc.repo.FindRollupWatermark(ctx)
+	if err != nil {
+		slog.Error("failed to get rollup watermark", slog.String("error", err.Error()))
+		return false, false
+	}
+	if watermark.IsZero() {
+		return false, false
+	}
+
+	for _, t := range []time.Time{rng.From, rng.To} {
+		if _, offset := t.In(rng.Location).Zone(); offset%3600 != 0 {
+			return false, false
+		}
+	}
+
+	now := time.Now()
+	isHour := func(t time.Time) bool { return t.Equal(t.Truncate(time.Hour)) }
+	isDay := func(t time.Time) bool {
+		local := t.In(rng.Location)
+		return local.Equal(time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, rng.Location))
+	}
+	today := now.In(rng.Location)
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, rng.Location)
+
+	if rng.Granularity != entity.ReportGranularityHour && isDay(rng.From) &&
+		(isDay(rng.To) || !rng.To.Before(today)) && uc.isDailyRollupTimeZone(ctx, tenantID, rng.Location) {
+		return true, true
+	}
+
+	if isHour(rng.From) && (isHour(rng.To) || !rng.To.Before(now.Truncate(time.Hour))) {
+		return false, true
+	}
+
+	return false, false
+}
+
+// isDailyRollupTimeZone tells if the daily rollups of the tenant are days of loc. After a time zone
+// change they are built again, until then the hourly rollups make the days.
+func (uc *reportUseCase) isDailyRollupTimeZone(ctx context.Context, tenantID string, loc *time.Location) bool {
+	timeZones, err := uc.repo.FindDailyRollupTimeZones(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to find daily rollup time zones", slog.String("error", err.Error()))
+		return false
+	}
+	for _, timeZone := range timeZones {
+		if timeZone != loc.String() {
+			return false
+		}
+	}
+	return true
+}
+
+// reportRow returns the row of the key, the counts of the links of a campaign share its row
+func reportRow(rows map[string]*entity.ReportRow, query *entity.ReportQuery, key entity.ReportKey,
+	linkCampaigns map[string]string, loc *time.Location) *entity.ReportRow {
+	dimensions := map[entity.ReportDimension]string{}
+	for _, dimension := range query.Dimensions {
+		switch dimension {
+		case entity.ReportDimensionLink:
+			dimensions[dimension] = key.Link
+		case entity.ReportDimensionCampaign:
+			dimensions[dimension] = linkCampaigns[key.Link]
+		case entity.ReportDimensionThankYouPage:
+			dimensions[dimension] = key.ThankYouPage
+		case entity.ReportDimensionChannel:
+			dimensions[dimension] = key.Channel
+		case entity.ReportDimensionDevice:
+			dimensions[dimension] = key.Device
+		}
+	}
+
+	id := &strings.Builder{}
+	if query.Granularity != entity.ReportGranularityTotal {
+		id.WriteString(strconv.FormatInt(key.Time.Unix(), 10))
+	}
+	for _, dimension := range query.Dimensions {
+		id.WriteString("\x00" + dimensions[dimension])
+	}
+
+	row, ok := rows[id.String()]
+	if !ok {
+		row = &entity.ReportRow{Dimensions: dimensions}
+		if query.Granularity != entity.ReportGranularityTotal {
+			t := key.Time.In(loc)
+			row.Time = &t
+		}
+		rows[id.String()] = row
+	}
+	return row
+}
+
+func setConversionRate(row *entity.ReportRow) {
+	if row.Tracks > 0 {
+		row.ConversionRate = float64(row.Conversions) / float64(row.Tracks)
+	}
+}
+
+// sortReportRows orders the rows by time then by the values of the dimensions
+func sortReportRows(rows []*entity.ReportRow, dimensions []entity.ReportDimension) {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Time != nil && rows[j].Time != nil && !rows[i].Time.Equal(*rows[j].Time) {
+			return rows[i].Time.Before(*rows[j].Time)
+		}
+		for _, dimension := range dimensions {
+			if rows[i].Dimensions[dimension] != rows[j].Dimensions[dimension] {
+				return rows[i].Dimensions[dimension] < rows[j].Dimensions[dimension]
+			}
+		}
+		return false
+	})
+}
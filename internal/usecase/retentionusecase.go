@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"github/michaellimmm/turakkingu/pkg/archive"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var ErrArchiveNotConfigured = errors.New("archive store is not configured")
+
+const archiveBatchSize = 1000
+
+type RetentionUseCase interface {
+	UpdateRetentionPolicy(ctx context.Context, tenantID string, policy entity.RetentionPolicy) (*entity.TrackingSettingWithPages, error)
+	ArchiveExpired(ctx context.Context) ([]string, error)
+	RestoreArchive(ctx context.Context, key string) (int64, error)
+}
+
+type retentionUseCase struct {
+	repo   repository.Repo
+	config *core.Config
+	store  archive.Store
+}
+
+func NewRetentionUseCase(config *core.Config, repo repository.Repo) RetentionUseCase {
+	var store archive.Store
+	if config.ArchiveS3Bucket != "" {
+		store = archive.NewS3Store(archive.S3Config{
+			Endpoint:  config.ArchiveS3Endpoint,
+			Region:    config.ArchiveS3Region,
+			Bucket:    config.ArchiveS3Bucket,
+			AccessKey: config.ArchiveS3AccessKey,
+			SecretKey: config.ArchiveS3SecretKey,
+		})
+	} else if config.ArchiveDir != "" {
+		store = archive.NewLocalStore(config.ArchiveDir)
+	}
+
+	return &retentionUseCase{
+		repo:   repo,
+		config: config,
+		store:  store,
+	}
+}
+
+func (uc *retentionUseCase) UpdateRetentionPolicy(ctx context.Context, tenantID string,
+	policy entity.RetentionPolicy) (*entity.TrackingSettingWithPages, error) {
+	if policy.Archive && uc.store == nil {
+		return nil, ErrArchiveNotConfigured
+	}
+
+	trackingSetting, err := uc.repo.FindOrCreateWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to find or create tracking setting with pages by tenant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	trackingSetting.Retention = policy
+	if err := uc.repo.UpdateTrackingSettingConfig(ctx, trackingSetting.ID, trackingSetting.TrackingSettingConfig); err != nil {
+		slog.Error("failed to update tracking setting", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return trackingSetting, nil
+}
+
+// ArchiveExpired writes documents whose retention ended to the archive store and then lets the
+// TTL index delete them. It returns the keys of the written archive objects.
+func (uc *retentionUseCase) ArchiveExpired(ctx context.Context) ([]string, error) {
+	if uc.store == nil {
+		return nil, ErrArchiveNotConfigured
+	}
+
+	keys := []string{}
+	now := time.Now().UTC()
+	for _, collection := range repository.ArchivableCollections {
+		for {
+			documents, err := uc.repo.FindArchivableDocuments(ctx, collection, now, archiveBatchSize)
+			if err != nil {
+				slog.Error("failed to find archivable documents", slog.String("error", err.Error()))
+				return keys, err
+			}
+			if len(documents) == 0 {
+				break
+			}
+
+			key, err := uc.archiveDocuments(ctx, collection, documents, now)
+			if err != nil {
+				slog.Error("failed to archive documents", slog.String("key", key), slog.String("error", err.Error()))
+				return keys, err
+			}
+			keys = append(keys, key)
+			slog.Info("documents archived", slog.String("key", key), slog.Int("count", len(documents)))
+
+			if len(documents) < archiveBatchSize {
+				break
+			}
+		}
+	}
+
+	return keys, nil
+}
+
+func (uc *retentionUseCase) archiveDocuments(ctx context.Context, collection string,
+	documents []bson.Raw, now time.Time) (string, error) {
+	lines := make([][]byte, 0, len(documents))
+	ids := make([]bson.ObjectID, 0, len(documents))
+	for _, document := range documents {
+		line, err := bson.MarshalExtJSON(document, true, false)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode document: %w", err)
+		}
+		lines = append(lines, line)
+
+		if id, ok := document.Lookup("_id").ObjectIDOK(); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	key := archiveKey(collection, documents, ids)
+	buf := &bytes.Buffer{}
+	if err := archive.WriteNDJSON(buf, lines); err != nil {
+		return key, err
+	}
+	if err := uc.store.Put(ctx, key, buf); err != nil {
+		return key, err
+	}
+
+	// only expire the documents once the archive is safely stored
+	return key, uc.repo.MarkDocumentsArchived(ctx, collection, ids, now)
+}
+
+// archiveKey names the batch after its documents, under the day its retention ended. A batch
+// that is archived again, because its documents could not be marked, replaces its archive.
+func archiveKey(collection string, documents []bson.Raw, ids []bson.ObjectID) string {
+	day := time.Now().UTC()
+	if retainUntil, ok := documents[0].Lookup("retain_until").TimeOK(); ok {
+		day = retainUntil.UTC()
+	}
+
+	hash := sha256.New()
+	for _, id := range ids {
+		hash.Write(id[:])
+	}
+	return fmt.Sprintf("%s/%s/%s-%x.ndjson.gz", collection, day.Format("2006/01/02"), collection, hash.Sum(nil)[:8])
+}
+
+// RestoreArchive loads an archive object back into its collection, the collection is the first part of the key
+func (uc *retentionUseCase) RestoreArchive(ctx context.Context, key string) (int64, error) {
+	if uc.store == nil {
+		return 0, ErrArchiveNotConfigured
+	}
+
+	collection, _, _ := strings.Cut(key, "/")
+
+	rc, err := uc.store.Get(ctx, key)
+	if err != nil {
+		slog.Error("failed to get archive", slog.String("key", key), slog.String("error", err.Error()))
+		return 0, err
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+
+	documents := []bson.Raw{}
+	err = archive.ReadNDJSON(rc, func(line []byte) error {
+		var document bson.Raw
+		if err := bson.UnmarshalExtJSON(line, true, &document); err != nil {
+			return fmt.Errorf("failed to decode archived document: %w", err)
+		}
+		documents = append(documents, document)
+		return nil
+	})
+	if err != nil {
+		slog.Error("failed to read archive", slog.String("key", key), slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	restored, err := uc.repo.RestoreDocuments(ctx, collection, documents)
+	if err != nil {
+		slog.Error("failed to restore archive", slog.String("key", key), slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	return restored, nil
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/entity"
+	"github/michaellimmm/turakkingu/internal/repository"
+	"log/slog"
+	"time"
+)
+
+var ErrInvalidRollupRange = errors.New("invalid rollup range")
+
+// rollupLateness is how late a click, track or event can be written after its creation and still
+// be rolled up by the catch-up runs
+const rollupLateness = 5 * time.Minute
+
+type RollupUseCase interface {
+	RollupReports(ctx context.Context) (*entity.RollupRun, error)
+	RebuildRollups(ctx context.Context, from, to time.Time) (*entity.RollupRun, error)
+}
+
+type rollupUseCase struct {
+	repo   repository.Repo
+	config *core.Config
+}
+
+func NewRollupUseCase(config *core.Config, repo repository.Repo) RollupUseCase {
+	return &rollupUseCase{
+		repo:   repo,
+		config: config,
+	}
+}
+
+// RollupReports counts again the hours from the watermark to now and the days they are in, then
+// moves the watermark to the last hour every write should have reached. The first run counts
+// everything. Runs replace the counts, so overlapping runs of several instances are harmless.
+func (uc *rollupUseCase) RollupReports(ctx context.Context) (*entity.RollupRun, error) {
+	watermark, err := uc.repo.FindRollupWatermark(ctx)
+	if err != nil {
+		slog.Error("failed to get rollup watermark", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	run := &entity.RollupRun{From: watermark, To: now, Watermark: watermark}
+	if err := uc.rollup(ctx, watermark, now); err != nil {
+		return nil, err
+	}
+
+	next := now.Add(-rollupLateness).Truncate(time.Hour)
+	if next.After(watermark) {
+		if err := uc.repo.UpdateRollupWatermark(ctx, next); err != nil {
+			slog.Error("failed to update rollup watermark", slog.String("error", err.Error()))
+			return nil, err
+		}
+		run.Watermark = next
+	}
+
+	return run, nil
+}
+
+// RebuildRollups removes the rollups of [from, to) and counts them again from the raw data,
+// e.g. after a backfill. A zero to rebuilds up to now. from is required, the rollups outlive the
+// raw data removed by the retention and would be lost.
+func (uc *rollupUseCase) RebuildRollups(ctx context.Context, from, to time.Time) (*entity.RollupRun, error) {
+	if from.IsZero() {
+		return nil, fmt.Errorf("%w: from is required", ErrInvalidRollupRange)
+	}
+
+	from = from.Truncate(time.Hour)
+	if to.IsZero() {
+		to = time.Now().UTC()
+	} else {
+		to = to.Truncate(time.Hour)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidRollupRange)
+	}
+
+	if _, err := uc.repo.RemoveReportRollups(ctx, entity.ReportGranularityHour, "", from, to); err != nil {
+		slog.Error("failed to remove hourly rollups", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	days := from.Add(-repository.MaxDayLength)
+	if _, err := uc.repo.RemoveReportRollups(ctx, entity.ReportGranularityDay, "", days, to); err != nil {
+		slog.Error("failed to remove daily rollups", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := uc.rollup(ctx, from, to); err != nil {
+		return nil, err
+	}
+
+	watermark, err := uc.repo.FindRollupWatermark(ctx)
+	if err != nil {
+		slog.Error("failed to get rollup watermark", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &entity.RollupRun{From: from, To: to, Watermark: watermark}, nil
+}
+
+// rollup counts the hours of [from, to), then the days they are in, a day starts at most
+// MaxDayLength before its hours
+func (uc *rollupUseCase) rollup(ctx context.Context, from, to time.Time) error {
+	if err := uc.repo.RollupReportHours(ctx, from, to); err != nil {
+		slog.Error("failed to roll up hours", slog.String("error", err.Error()))
+		return err
+	}
+
+	days := from
+	if !days.IsZero() {
+		days = days.Add(-repository.MaxDayLength)
+	}
+	if err := uc.repo.RollupReportDays(ctx, "", days, to); err != nil {
+		slog.Error("failed to roll up days", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
@@ -2,18 +2,35 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github/michaellimmm/turakkingu/internal/core"
 	"github/michaellimmm/turakkingu/internal/entity"
 	"github/michaellimmm/turakkingu/internal/repository"
 	"log/slog"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var (
+	ErrInvalidDomain   = errors.New("invalid domain")
+	ErrLinkDomainTaken = errors.New("link domain is already registered")
 )
 
 type TrackingSettingUseCase interface {
 	GetTrackingSettingByTenantID(ctx context.Context, tenantID string) (*entity.TrackingSettingWithPages, error)
 	GetTrackingSettingByID(ctx context.Context, trackingSettingID bson.ObjectID) (*entity.TrackingSettingWithPages, error)
 	AddThankYouPage(ctx context.Context, thankYouPage *entity.ThankYouPage) error
+	UpdateConsentPolicy(ctx context.Context, tenantID string, policy entity.ConsentPolicy) (*entity.TrackingSettingWithPages, error)
+	UpdateDomains(ctx context.Context, tenantID string, domains []string) (*entity.TrackingSettingWithPages, error)
+	IsOriginAllowed(ctx context.Context, trackingSettingID *bson.ObjectID, host string) (bool, error)
+	UpdateChannelRules(ctx context.Context, tenantID string, rules []entity.ChannelRule) (*entity.TrackingSettingWithPages, error)
+	UpdateBotPolicy(ctx context.Context, tenantID string, policy entity.BotPolicy) (*entity.TrackingSettingWithPages, error)
+	UpdateLinkDomains(ctx context.Context, tenantID string, domains []string) (*entity.TrackingSettingWithPages, error)
+	UpdateTimeZone(ctx context.Context, tenantID string, timeZone string) (*entity.TrackingSettingWithPages, error)
 }
 
 type trackingSettingUseCase struct {
@@ -58,3 +75,172 @@ func (uc *trackingSettingUseCase) AddThankYouPage(ctx context.Context, thankYouP
 
 	return nil
 }
+
+func (uc *trackingSettingUseCase) UpdateConsentPolicy(ctx context.Context, tenantID string,
+	policy entity.ConsentPolicy) (*entity.TrackingSettingWithPages, error) {
+	trackingSetting, err := uc.repo.FindOrCreateWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to find or create tracking setting with pages by tenant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	trackingSetting.Consent = policy
+	if err := uc.repo.UpdateTrackingSettingConfig(ctx, trackingSetting.ID, trackingSetting.TrackingSettingConfig); err != nil {
+		slog.Error("failed to update tracking setting", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return trackingSetting, nil
+}
+
+func (uc *trackingSettingUseCase) UpdateDomains(ctx context.Context, tenantID string,
+	domains []string) (*entity.TrackingSettingWithPages, error) {
+	normalized, err := entity.NormalizeDomains(domains)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDomain, err.Error())
+	}
+
+	trackingSetting, err := uc.repo.FindOrCreateWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to find or create tracking setting with pages by tenant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	trackingSetting.Domains = normalized
+	if err := uc.repo.UpdateTrackingSettingConfig(ctx, trackingSetting.ID, trackingSetting.TrackingSettingConfig); err != nil {
+		slog.Error("failed to update tracking setting", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return trackingSetting, nil
+}
+
+// IsOriginAllowed checks a browser origin against the tenant domains. Without tracking setting,
+// the origin has to be owned by any tenant.
+func (uc *trackingSettingUseCase) IsOriginAllowed(ctx context.Context, trackingSettingID *bson.ObjectID, host string) (bool, error) {
+	if trackingSettingID == nil {
+		allowed, err := uc.repo.IsDomainRegistered(ctx, entity.ParentDomains(host))
+		if err != nil {
+			slog.Error("failed to check registered domain", slog.String("error", err.Error()))
+			return false, err
+		}
+		return allowed, nil
+	}
+
+	trackingSetting, err := uc.repo.FindTrackingSettingByID(ctx, *trackingSettingID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	} else if err != nil {
+		slog.Error("failed to find tracking setting by id", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	return trackingSetting.IsOwnedHost(host), nil
+}
+
+func (uc *trackingSettingUseCase) UpdateChannelRules(ctx context.Context, tenantID string,
+	rules []entity.ChannelRule) (*entity.TrackingSettingWithPages, error) {
+	trackingSetting, err := uc.repo.FindOrCreateWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to find or create tracking setting with pages by tenant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	trackingSetting.ChannelRules = rules
+	if err := uc.repo.UpdateTrackingSettingConfig(ctx, trackingSetting.ID, trackingSetting.TrackingSettingConfig); err != nil {
+		slog.Error("failed to update tracking setting", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return trackingSetting, nil
+}
+
+func (uc *trackingSettingUseCase) UpdateBotPolicy(ctx context.Context, tenantID string,
+	policy entity.BotPolicy) (*entity.TrackingSettingWithPages, error) {
+	trackingSetting, err := uc.repo.FindOrCreateWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to find or create tracking setting with pages by tenant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	trackingSetting.Bots = policy
+	if err := uc.repo.UpdateTrackingSettingConfig(ctx, trackingSetting.ID, trackingSetting.TrackingSettingConfig); err != nil {
+		slog.Error("failed to update tracking setting", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return trackingSetting, nil
+}
+
+// UpdateLinkDomains registers the branded short link domains of the tenant, a domain belongs
+// to one tenant only and the default domain can't be registered
+func (uc *trackingSettingUseCase) UpdateLinkDomains(ctx context.Context, tenantID string,
+	domains []string) (*entity.TrackingSettingWithPages, error) {
+	normalized, err := entity.NormalizeDomains(domains)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDomain, err.Error())
+	}
+
+	defaultDomain, _ := entity.NormalizeDomain(uc.config.Domain)
+	for _, domain := range normalized {
+		if domain == defaultDomain {
+			return nil, fmt.Errorf("%w: %s is the default domain", ErrInvalidDomain, domain)
+		}
+
+		owner, err := uc.repo.FindTenantIDByLinkDomain(ctx, domain)
+		if err == nil && owner != tenantID {
+			return nil, fmt.Errorf("%w: %s", ErrLinkDomainTaken, domain)
+		} else if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("failed to find link domain", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	trackingSetting, err := uc.repo.FindOrCreateWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to find or create tracking setting with pages by tenant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	// the unique index settles two tenants registering the same domain at once
+	trackingSetting.LinkDomains = normalized
+	err = uc.repo.UpdateTrackingSettingConfig(ctx, trackingSetting.ID, trackingSetting.TrackingSettingConfig)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("%w: %s", ErrLinkDomainTaken, strings.Join(normalized, ", "))
+	} else if err != nil {
+		slog.Error("failed to update tracking setting", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return trackingSetting, nil
+}
+
+// UpdateTimeZone sets the time zone the reports of the tenant are bucketed in
+func (uc *trackingSettingUseCase) UpdateTimeZone(ctx context.Context, tenantID string,
+	timeZone string) (*entity.TrackingSettingWithPages, error) {
+	trackingSetting, err := uc.repo.FindOrCreateWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to find or create tracking setting with pages by tenant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	trackingSetting.TimeZone = timeZone
+	if err := uc.repo.UpdateTrackingSettingConfig(ctx, trackingSetting.ID, trackingSetting.TrackingSettingConfig); err != nil {
+		slog.Error("failed to update tracking setting", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	// the daily rollups are days of the time zone, the hourly ones make the days of the new one
+	if uc.config.RollupInterval > 0 {
+		if _, err := uc.repo.RemoveReportRollups(ctx, entity.ReportGranularityDay, tenantID, time.Time{}, time.Time{}); err != nil {
+			slog.Error("failed to remove daily rollups", slog.String("error", err.Error()))
+			return nil, err
+		}
+		if err := uc.repo.RollupReportDays(ctx, tenantID, time.Time{}, time.Time{}); err != nil {
+			slog.Error("failed to roll up days", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	return trackingSetting, nil
+}
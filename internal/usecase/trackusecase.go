@@ -2,34 +2,107 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/enrichment"
 	"github/michaellimmm/turakkingu/internal/entity"
 	"github/michaellimmm/turakkingu/internal/repository"
 	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 type TrackUseCase interface {
 	CreateTrack(ctx context.Context, track *entity.Track) error
+	GetTrackBreakdown(ctx context.Context, tenantID string, dimension entity.TrackDimension, from, to time.Time) (*entity.Breakdown, error)
 }
 
 type trackUseCase struct {
-	repo   repository.Repo
-	config *core.Config
+	repo            repository.Repo
+	config          *core.Config
+	identityUseCase IdentityUseCase
+	geoIP           *enrichment.GeoIP
+	botDetector     *enrichment.BotDetector
 }
 
-func NewTrackUseCase(config *core.Config, repo repository.Repo) TrackUseCase {
+func NewTrackUseCase(config *core.Config, repo repository.Repo, identityUseCase IdentityUseCase,
+	geoIP *enrichment.GeoIP, botDetector *enrichment.BotDetector) TrackUseCase {
 	return &trackUseCase{
-		repo:   repo,
-		config: config,
+		repo:            repo,
+		config:          config,
+		identityUseCase: identityUseCase,
+		geoIP:           geoIP,
+		botDetector:     botDetector,
 	}
 }
 
 func (uc *trackUseCase) CreateTrack(ctx context.Context, track *entity.Track) error {
-	err := uc.repo.CreateTrack(ctx, track)
+	trackingSetting, err := uc.repo.FindTrackingSettingByID(ctx, track.TrackingSettingID)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("failed to find tracking setting by id", slog.String("error", err.Error()))
+		}
+		return err
+	}
+	track.ApplyRetention(time.Now().UTC(), trackingSetting.Retention.TrackDays, trackingSetting.Retention.Archive)
+
+	track.UTM, track.ClickID = enrichment.ParseCampaign(track.Url)
+	track.Channel = enrichment.ClassifyChannel(track.UTM, track.ClickID, trackingSetting.ChannelRules)
+	// tracks are created server side, so bot tracks are only tagged and never discarded.
+	// Tracks of a click come already enriched.
+	if track.UserAgent != "" && track.Device.IsEmpty() {
+		track.Device = enrichment.ParseDevice(track.UserAgent, entity.ClientHints{})
+		track.Bot = uc.botDetector.Detect(enrichment.BotRequest{
+			UserAgent: track.UserAgent,
+			Device:    track.Device,
+			IP:        track.IP,
+		})
+	}
+	if track.IP != "" && track.Geo.IsEmpty() {
+		track.Geo = uc.geoIP.Lookup(track.IP)
+		track.IP = enrichment.AnonymizeIP(track.IP, entity.IPMode(uc.config.GeoIPIPMode))
+	}
+
+	err = uc.repo.CreateTrack(ctx, track)
 	if err != nil {
 		slog.Error("failed to create track", slog.String("error", err.Error()))
 		return err
 	}
 
+	// auto-created tracks of a click have no end user yet
+	if track.EndUserID == "" {
+		return nil
+	}
+
+	// the track id is the ztid handed to the landing page, so it deterministically belongs to the end user
+	_, err = uc.identityUseCase.LinkIdentifiers(ctx, track.TrackingSettingID, entity.IdentityEdge{
+		From:       entity.Identifier{Type: entity.IdentifierTypeEndUserID, Value: track.EndUserID},
+		To:         entity.Identifier{Type: entity.IdentifierTypeZTID, Value: track.ID.Hex()},
+		Confidence: entity.IdentityConfidenceHigh,
+		Source:     "track",
+	})
+	if err != nil {
+		// attribution still works through ztid, don't fail the track creation
+		slog.Error("failed to link track identity", slog.String("error", err.Error()))
+	}
+
 	return nil
 }
+
+func (uc *trackUseCase) GetTrackBreakdown(ctx context.Context, tenantID string, dimension entity.TrackDimension,
+	from, to time.Time) (*entity.Breakdown, error) {
+	trackingSetting, err := uc.repo.FindOrCreateWithPagesByTenantID(ctx, tenantID)
+	if err != nil {
+		slog.Error("failed to find or create tracking setting with pages by tenant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	rows, err := uc.repo.CountTracksByDimension(ctx, trackingSetting.ID, dimension, from, to)
+	if err != nil {
+		slog.Error("failed to count tracks by dimension", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &entity.Breakdown{Dimension: dimension, Rows: rows}, nil
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"github/michaellimmm/turakkingu/internal/core"
+	"github/michaellimmm/turakkingu/internal/enrichment"
 	"github/michaellimmm/turakkingu/internal/repository"
 )
 
@@ -10,6 +11,15 @@ type UseCase interface {
 	TrackingSettingUseCase
 	TrackUseCase
 	EventUseCase
+	IdentityUseCase
+	PrivacyUseCase
+	RetentionUseCase
+	ClickUseCase
+	LinkCampaignUseCase
+	ReportUseCase
+	RollupUseCase
+	FunnelUseCase
+	MetricsUseCase
 }
 
 type usecase struct {
@@ -17,18 +27,47 @@ type usecase struct {
 	TrackingSettingUseCase
 	TrackUseCase
 	EventUseCase
+	IdentityUseCase
+	PrivacyUseCase
+	RetentionUseCase
+	ClickUseCase
+	LinkCampaignUseCase
+	ReportUseCase
+	RollupUseCase
+	FunnelUseCase
+	MetricsUseCase
 }
 
 func NewUseCase(config *core.Config, repo repository.Repo) UseCase {
 	linkUseCase := NewLinkUseCase(config, repo)
 	trackingSettingUseCase := NewTrackingSettingUseCase(config, repo)
-	trackUseCase := NewTrackUseCase(config, repo)
-	eventUseCase := NewEventUseCase(config, repo)
+	identityUseCase := NewIdentityUseCase(config, repo)
+	geoIP := enrichment.NewGeoIP(config.GeoIPDatabase)
+	botDetector := enrichment.NewBotDetector(config.BotDatacenterList, config.BotRateLimit, config.BotRateWindow)
+	trackUseCase := NewTrackUseCase(config, repo, identityUseCase, geoIP, botDetector)
+	eventUseCase := NewEventUseCase(config, repo, identityUseCase, geoIP, botDetector)
+	privacyUseCase := NewPrivacyUseCase(config, repo)
+	retentionUseCase := NewRetentionUseCase(config, repo)
+	clickUseCase := NewClickUseCase(config, repo, geoIP, botDetector, trackUseCase)
+	linkCampaignUseCase := NewLinkCampaignUseCase(config, repo)
+	reportUseCase := NewReportUseCase(config, repo)
+	rollupUseCase := NewRollupUseCase(config, repo)
+	funnelUseCase := NewFunnelUseCase(config, repo)
+	metricsUseCase := NewMetricsUseCase(config, repo)
 
 	return &usecase{
 		LinkUseCase:            linkUseCase,
 		TrackingSettingUseCase: trackingSettingUseCase,
 		TrackUseCase:           trackUseCase,
 		EventUseCase:           eventUseCase,
+		IdentityUseCase:        identityUseCase,
+		PrivacyUseCase:         privacyUseCase,
+		RetentionUseCase:       retentionUseCase,
+		ClickUseCase:           clickUseCase,
+		LinkCampaignUseCase:    linkCampaignUseCase,
+		ReportUseCase:          reportUseCase,
+		RollupUseCase:          rollupUseCase,
+		FunnelUseCase:          funnelUseCase,
+		MetricsUseCase:         metricsUseCase,
 	}
 }
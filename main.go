@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"github/michaellimmm/turakkingu/internal/adapter"
+	"github/michaellimmm/turakkingu/internal/adapter/cli"
 	"github/michaellimmm/turakkingu/internal/core"
 	"github/michaellimmm/turakkingu/internal/repository"
 	"github/michaellimmm/turakkingu/internal/usecase"
@@ -10,6 +11,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+
+	_ "time/tzdata" // time zones of the redirect rules, without relying on the host
 )
 
 func main() {
@@ -26,6 +29,18 @@ func main() {
 	}
 
 	uc := usecase.NewUseCase(config, repo)
+
+	// any argument runs a one-off command instead of the server
+	if len(os.Args) > 1 {
+		err := cli.NewCLI(config, uc).Run(context.Background(), os.Args[1:])
+		_ = repo.Close(context.Background())
+		if err != nil {
+			slog.Error("command failed", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		return
+	}
+
 	server := adapter.NewAdapter(config, uc)
 
 	sigChan := make(chan os.Signal, 1)
@@ -0,0 +1,57 @@
+// Package archive stores archived documents as compressed NDJSON objects.
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrNotFound = errors.New("archive object not found")
+
+// Store is a flat key/value object store
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// WriteNDJSON gzips one document per line
+func WriteNDJSON(w io.Writer, lines [][]byte) error {
+	gz := gzip.NewWriter(w)
+	for _, line := range lines {
+		if _, err := gz.Write(line); err != nil {
+			return err
+		}
+		if _, err := gz.Write([]byte("\n")); err != nil {
+			return err
+		}
+	}
+	return gz.Close()
+}
+
+// ReadNDJSON calls fn for every line of a gzipped NDJSON stream
+func ReadNDJSON(r io.Reader, fn func(line []byte) error) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	defer func() {
+		_ = gz.Close()
+	}()
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
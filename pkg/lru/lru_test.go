@@ -0,0 +1,51 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("should evict the least recently used entry", func(t *testing.T) {
+		c := New[string, int](2, time.Minute)
+		c.Add("a", 1)
+		c.Add("b", 2)
+		_, _ = c.Get("a")
+		c.Add("c", 3)
+
+		_, ok := c.Get("b")
+		assert.False(t, ok)
+		v, ok := c.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+		assert.Equal(t, Stats{Hits: 2, Misses: 1, Evictions: 1, Size: 2}, c.Stats())
+	})
+
+	t.Run("should expire entries after the ttl", func(t *testing.T) {
+		now := time.Now()
+		c := New[string, int](2, time.Minute)
+		c.now = func() time.Time { return now }
+		c.Add("a", 1)
+
+		now = now.Add(time.Minute)
+		_, ok := c.Get("a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("should remove the matching entries", func(t *testing.T) {
+		c := New[string, int](10, time.Minute)
+		c.Add("a", 1)
+		c.Add("b", 2)
+		c.Add("c", 3)
+		c.RemoveFunc(func(_ string, v int) bool { return v%2 == 1 })
+
+		_, ok := c.Get("a")
+		assert.False(t, ok)
+		_, ok = c.Get("b")
+		assert.True(t, ok)
+		assert.Equal(t, 1, c.Len())
+	})
+}
@@ -0,0 +1,220 @@
+package mmdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+var errOutOfBounds = errors.New("unexpected end of data section")
+
+// maxDepth guards against pointer loops in a corrupted file
+const maxDepth = 64
+
+type decoder struct {
+	buf []byte
+}
+
+// decode returns the value at offset and the offset after it
+func (d decoder) decode(offset uint) (any, uint, error) {
+	return d.decodeDepth(offset, 0)
+}
+
+func (d decoder) decodeDepth(offset uint, depth int) (any, uint, error) {
+	if depth > maxDepth {
+		return nil, 0, fmt.Errorf("data nested deeper than %d", maxDepth)
+	}
+
+	typ, size, offset, err := d.decodeControl(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typ == typePointer {
+		pointer, next, err := d.decodePointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decodeDepth(pointer, depth+1)
+		return value, next, err
+	}
+
+	switch typ {
+	case typeMap:
+		m := make(map[string]any, size)
+		for range size {
+			key, next, err := d.decodeDepth(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			k, ok := key.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("map key is %T", key)
+			}
+
+			value, next, err := d.decodeDepth(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[k] = value
+			offset = next
+		}
+		return m, offset, nil
+	case typeArray:
+		a := make([]any, 0, size)
+		for range size {
+			value, next, err := d.decodeDepth(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			a = append(a, value)
+			offset = next
+		}
+		return a, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	case typeContainer, typeEndMarker:
+		return nil, offset, nil
+	}
+
+	b, err := d.bytes(offset, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	next := offset + size
+
+	switch typ {
+	case typeString:
+		return string(b), next, nil
+	case typeBytes:
+		return append([]byte(nil), b...), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("double of %d bytes", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("float of %d bytes", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("unsigned integer of %d bytes", size)
+		}
+		return uintFromBytes(b), next, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("int32 of %d bytes", size)
+		}
+		return int64(int32(uintFromBytes(b))), next, nil
+	case typeUint128:
+		return new(big.Int).SetBytes(b), next, nil
+	default:
+		return nil, 0, fmt.Errorf("unknown data type %d", typ)
+	}
+}
+
+func (d decoder) decodeControl(offset uint) (int, uint, uint, error) {
+	b, err := d.bytes(offset, 1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	offset++
+
+	ctrl := b[0]
+	typ := int(ctrl >> 5)
+	if typ == typeExtended {
+		b, err := d.bytes(offset, 1)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		typ = 7 + int(b[0])
+		offset++
+	}
+
+	// pointers encode their own size
+	if typ == typePointer {
+		return typ, uint(ctrl & 0x1F), offset, nil
+	}
+
+	size := uint(ctrl & 0x1F)
+	switch size {
+	case 29, 30, 31:
+		n := size - 28
+		b, err := d.bytes(offset, n)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		offset += n
+
+		extra := uint(uintFromBytes(b))
+		switch size {
+		case 29:
+			size = 29 + extra
+		case 30:
+			size = 285 + extra
+		default:
+			size = 65821 + extra
+		}
+	}
+
+	return typ, size, offset, nil
+}
+
+func (d decoder) decodePointer(ctrl uint, offset uint) (uint, uint, error) {
+	n := (ctrl >> 3) & 0x3
+	b, err := d.bytes(offset, n+1)
+	if err != nil {
+		return 0, 0, err
+	}
+	next := offset + n + 1
+
+	value := uint(ctrl & 0x7)
+	switch n {
+	case 0:
+		return value<<8 | uint(b[0]), next, nil
+	case 1:
+		return (value<<16 | uint(uintFromBytes(b))) + 2048, next, nil
+	case 2:
+		return (value<<24 | uint(uintFromBytes(b))) + 526336, next, nil
+	default:
+		return uint(uintFromBytes(b)), next, nil
+	}
+}
+
+func (d decoder) bytes(offset, size uint) ([]byte, error) {
+	if offset+size > uint(len(d.buf)) {
+		return nil, errOutOfBounds
+	}
+	return d.buf[offset : offset+size], nil
+}
+
+func uintFromBytes(b []byte) uint64 {
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n
+}
@@ -0,0 +1,161 @@
+// Package mmdb reads MaxMind DB files, the format of GeoLite2 and GeoIP2 databases.
+package mmdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+)
+
+var (
+	ErrInvalidDatabase = errors.New("invalid maxmind database")
+	ErrNotFound        = errors.New("address not found")
+)
+
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparator is the 16 zero bytes between the search tree and the data section
+const dataSectionSeparator = 16
+
+type Metadata struct {
+	NodeCount    uint
+	RecordSize   uint
+	IPVersion    uint
+	DatabaseType string
+	BuildEpoch   uint64
+}
+
+// Reader keeps the whole database in memory, it is safe for concurrent use
+type Reader struct {
+	tree      []byte
+	decoder   decoder
+	metadata  Metadata
+	ipv4Start uint
+}
+
+func Open(path string) (*Reader, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return FromBytes(buf)
+}
+
+func FromBytes(buf []byte) (*Reader, error) {
+	start := bytes.LastIndex(buf, metadataMarker)
+	if start == -1 {
+		return nil, fmt.Errorf("%w: metadata not found", ErrInvalidDatabase)
+	}
+
+	metadataStart := start + len(metadataMarker)
+	raw, _, err := decoder{buf: buf[metadataStart:]}.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDatabase, err)
+	}
+
+	fields, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: metadata is not a map", ErrInvalidDatabase)
+	}
+
+	metadata := Metadata{
+		NodeCount:  uint(toUint64(fields["node_count"])),
+		RecordSize: uint(toUint64(fields["record_size"])),
+		IPVersion:  uint(toUint64(fields["ip_version"])),
+		BuildEpoch: toUint64(fields["build_epoch"]),
+	}
+	metadata.DatabaseType, _ = fields["database_type"].(string)
+
+	switch metadata.RecordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("%w: unsupported record size %d", ErrInvalidDatabase, metadata.RecordSize)
+	}
+
+	treeSize := metadata.NodeCount * metadata.RecordSize / 4
+	if treeSize+dataSectionSeparator > uint(start) {
+		return nil, fmt.Errorf("%w: search tree is larger than the file", ErrInvalidDatabase)
+	}
+
+	r := &Reader{
+		tree:     buf[:treeSize],
+		decoder:  decoder{buf: buf[treeSize+dataSectionSeparator : start]},
+		metadata: metadata,
+	}
+
+	// ipv4 addresses live under ::/96 of an ipv6 tree
+	if metadata.IPVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < metadata.NodeCount; i++ {
+			node = r.readRecord(node, 0)
+		}
+		r.ipv4Start = node
+	}
+
+	return r, nil
+}
+
+func (r *Reader) Metadata() Metadata {
+	return r.metadata
+}
+
+// Lookup returns the record of the network containing the address, decoded into maps, slices and scalars
+func (r *Reader) Lookup(addr netip.Addr) (any, error) {
+	addr = addr.Unmap()
+
+	node := uint(0)
+	bits := addr.AsSlice()
+	if addr.Is4() {
+		node = r.ipv4Start
+	} else if r.metadata.IPVersion == 4 {
+		return nil, ErrNotFound
+	}
+
+	for i := 0; i < len(bits)*8 && node < r.metadata.NodeCount; i++ {
+		bit := (bits[i/8] >> (7 - uint(i%8))) & 1
+		node = r.readRecord(node, uint(bit))
+	}
+
+	if node <= r.metadata.NodeCount {
+		return nil, ErrNotFound
+	}
+
+	offset := node - r.metadata.NodeCount - dataSectionSeparator
+	value, _, err := r.decoder.decode(offset)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDatabase, err)
+	}
+	return value, nil
+}
+
+func (r *Reader) readRecord(node uint, bit uint) uint {
+	size := r.metadata.RecordSize / 4 // bytes per node
+	b := r.tree[node*size : (node+1)*size]
+
+	switch r.metadata.RecordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		b = b[bit*4:]
+		return uint(b[0])<<24 | uint(b[1])<<16 | uint(b[2])<<8 | uint(b[3])
+	}
+}
+
+func toUint64(v any) uint64 {
+	switch n := v.(type) {
+	case uint64:
+		return n
+	case int64:
+		return uint64(n)
+	default:
+		return 0
+	}
+}
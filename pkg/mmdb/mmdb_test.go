@@ -0,0 +1,111 @@
+package mmdb
+
+import (
+	"bytes"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeString(s string) []byte {
+	return append([]byte{byte(typeString<<5 | len(s))}, s...)
+}
+
+func encodeUint16(n uint16) []byte {
+	return []byte{byte(typeUint16<<5 | 2), byte(n >> 8), byte(n)}
+}
+
+func encodeMap(pairs ...[]byte) []byte {
+	b := []byte{byte(typeMap<<5 | len(pairs)/2)}
+	for _, p := range pairs {
+		b = append(b, p...)
+	}
+	return b
+}
+
+// buildDatabase writes an ipv4 database with 24 bit records holding a single /24 network
+func buildDatabase(t *testing.T, prefix netip.Prefix, data []byte) []byte {
+	t.Helper()
+
+	nodeCount := prefix.Bits()
+	empty := uint(nodeCount)
+	dataRecord := uint(nodeCount) + dataSectionSeparator
+
+	addr := prefix.Addr().As4()
+	tree := &bytes.Buffer{}
+	for i := 0; i < nodeCount; i++ {
+		next := uint(i + 1)
+		if i == nodeCount-1 {
+			next = dataRecord
+		}
+
+		left, right := empty, empty
+		if (addr[i/8]>>(7-uint(i%8)))&1 == 0 {
+			left = next
+		} else {
+			right = next
+		}
+		tree.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left), byte(right >> 16), byte(right >> 8), byte(right)})
+	}
+
+	buf := &bytes.Buffer{}
+	buf.Write(tree.Bytes())
+	buf.Write(make([]byte, dataSectionSeparator))
+	buf.Write(data)
+	buf.Write(metadataMarker)
+	buf.Write(encodeMap(
+		encodeString("node_count"), encodeUint16(uint16(nodeCount)),
+		encodeString("record_size"), encodeUint16(24),
+		encodeString("ip_version"), encodeUint16(4),
+		encodeString("database_type"), encodeString("Test-City"),
+	))
+	return buf.Bytes()
+}
+
+func TestReader_Lookup(t *testing.T) {
+	// the second "names" key is a pointer to the first one, after the two map headers, "country" and the iso code
+	names := encodeString("names")
+	namesOffset := 2 + len(encodeString("country")) + len(encodeString("iso_code")) + len(encodeString("JP"))
+	data := encodeMap(
+		encodeString("country"), encodeMap(
+			encodeString("iso_code"), encodeString("JP"),
+			names, encodeMap(encodeString("en"), encodeString("Japan")),
+		),
+		encodeString("city"), encodeMap(
+			[]byte{byte(typePointer << 5), byte(namesOffset)},
+			encodeMap(encodeString("en"), encodeString("Tokyo")),
+		),
+	)
+
+	reader, err := FromBytes(buildDatabase(t, netip.MustParsePrefix("1.2.3.0/24"), data))
+	assert.NoError(t, err)
+	assert.Equal(t, "Test-City", reader.Metadata().DatabaseType)
+
+	t.Run("should find the record of the network", func(t *testing.T) {
+		record, err := reader.Lookup(netip.MustParseAddr("1.2.3.4"))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"country": map[string]any{"iso_code": "JP", "names": map[string]any{"en": "Japan"}},
+			"city":    map[string]any{"names": map[string]any{"en": "Tokyo"}},
+		}, record)
+	})
+
+	t.Run("should find ipv4 mapped ipv6 addresses", func(t *testing.T) {
+		_, err := reader.Lookup(netip.MustParseAddr("::ffff:1.2.3.200"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return not found outside the network", func(t *testing.T) {
+		_, err := reader.Lookup(netip.MustParseAddr("1.2.4.1"))
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, err = reader.Lookup(netip.MustParseAddr("2001:db8::1"))
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("should reject a file without metadata", func(t *testing.T) {
+		_, err := FromBytes([]byte("not a database"))
+		assert.ErrorIs(t, err, ErrInvalidDatabase)
+	})
+}
@@ -0,0 +1,296 @@
+package qrcode
+
+// matrix is indexed [y][x], function marks the modules the codewords and the masks skip
+type matrix struct {
+	version  int
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+func newMatrix(version int) *matrix {
+	size := version*4 + 17
+	m := &matrix{version: version, size: size, modules: make([][]bool, size), function: make([][]bool, size)}
+	for i := range size {
+		m.modules[i] = make([]bool, size)
+		m.function[i] = make([]bool, size)
+	}
+	return m
+}
+
+func (m *matrix) setFunction(x, y int, dark bool) {
+	m.modules[y][x] = dark
+	m.function[y][x] = true
+}
+
+func (m *matrix) drawFunctionPatterns() {
+	for i := range m.size {
+		m.setFunction(6, i, i%2 == 0)
+		m.setFunction(i, 6, i%2 == 0)
+	}
+
+	m.drawFinderPattern(3, 3)
+	m.drawFinderPattern(m.size-4, 3)
+	m.drawFinderPattern(3, m.size-4)
+
+	positions := m.alignmentPatternPositions()
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// the corners are taken by the finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			m.drawAlignmentPattern(x, y)
+		}
+	}
+
+	// reserve the format area, the real bits are drawn once the mask is chosen
+	m.drawFormatBits(Low, 0)
+	m.drawVersion()
+}
+
+// drawFinderPattern draws the 7x7 finder and its light separator around the center
+func (m *matrix) drawFinderPattern(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || x >= m.size || y < 0 || y >= m.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			m.setFunction(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (m *matrix) drawAlignmentPattern(cx, cy int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			m.setFunction(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPatternPositions are the centers on each axis, evenly spaced from the end
+func (m *matrix) alignmentPatternPositions() []int {
+	if m.version == 1 {
+		return nil
+	}
+
+	numAlign := m.version/7 + 2
+	step := (m.version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, m.size-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+// formatBits is the level and the mask with their BCH code, xored with the format mask
+func formatBits(level Level, mask int) int {
+	data := level.formatBits()<<3 | mask
+	rem := data
+	for range 10 {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (m *matrix) drawFormatBits(level Level, mask int) {
+	bits := formatBits(level, mask)
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	// around the top left finder
+	for i := 0; i <= 5; i++ {
+		m.setFunction(8, i, bit(i))
+	}
+	m.setFunction(8, 7, bit(6))
+	m.setFunction(8, 8, bit(7))
+	m.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.setFunction(14-i, 8, bit(i))
+	}
+
+	// split between the top right and the bottom left finders
+	for i := 0; i < 8; i++ {
+		m.setFunction(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.setFunction(8, m.size-15+i, bit(i))
+	}
+	m.setFunction(8, m.size-8, true) // the dark module
+}
+
+// versionBits is the version with its BCH code, present from version 7
+func versionBits(version int) int {
+	rem := version
+	for range 12 {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	return version<<12 | rem
+}
+
+func (m *matrix) drawVersion() {
+	if m.version < 7 {
+		return
+	}
+
+	bits := versionBits(m.version)
+	for i := range 18 {
+		dark := (bits>>i)&1 == 1
+		a, b := m.size-11+i%3, i/3
+		m.setFunction(a, b, dark)
+		m.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords fills the data modules in the zigzag of two columns, bottom-up then top-down,
+// skipping the vertical timing pattern. The remainder bits stay light.
+func (m *matrix) drawCodewords(codewords []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := range m.size {
+			for j := range 2 {
+				x, y := right-j, vert
+				if upward {
+					y = m.size - 1 - vert
+				}
+				if m.function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				m.modules[y][x] = (codewords[i/8]>>(7-i%8))&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+func (m *matrix) applyMask(mask int) {
+	for y := range m.size {
+		for x := range m.size {
+			if m.function[y][x] {
+				continue
+			}
+
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			default:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			m.modules[y][x] = m.modules[y][x] != invert
+		}
+	}
+}
+
+// penalty scores a masked matrix, the mask with the lowest score is kept
+func (m *matrix) penalty() int {
+	result := 0
+	dark := 0
+
+	for i := range m.size {
+		row := make([]bool, m.size)
+		col := make([]bool, m.size)
+		for j := range m.size {
+			row[j] = m.modules[i][j]
+			col[j] = m.modules[j][i]
+			if row[j] {
+				dark++
+			}
+		}
+		result += runPenalty(row) + runPenalty(col)
+		result += finderPenalty(row) + finderPenalty(col)
+	}
+
+	for y := 0; y < m.size-1; y++ {
+		for x := 0; x < m.size-1; x++ {
+			c := m.modules[y][x]
+			if c == m.modules[y][x+1] && c == m.modules[y+1][x] && c == m.modules[y+1][x+1] {
+				result += 3
+			}
+		}
+	}
+
+	// 10 points for every 5% of dark modules away from 50%
+	total := m.size * m.size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * 10
+
+	return result
+}
+
+// runPenalty scores the runs of five or more modules of the same color
+func runPenalty(line []bool) int {
+	result := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			result += 3 + run - 5
+		}
+		run = 1
+	}
+	return result
+}
+
+var finderLike = []bool{true, false, true, true, true, false, true}
+
+// finderPenalty scores the 1:1:3:1:1 patterns with four light modules on one side
+func finderPenalty(line []bool) int {
+	result := 0
+	for i := 0; i+len(finderLike) <= len(line); i++ {
+		match := true
+		for j, dark := range finderLike {
+			if line[i+j] != dark {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		if isLight(line, i-4, i) || isLight(line, i+len(finderLike), i+len(finderLike)+4) {
+			result += 40
+		}
+	}
+	return result
+}
+
+// isLight reports whether line[from:to] is light, the quiet zone outside the line counts as light
+func isLight(line []bool, from, to int) bool {
+	for i := from; i < to; i++ {
+		if i >= 0 && i < len(line) && line[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
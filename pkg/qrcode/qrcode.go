@@ -0,0 +1,207 @@
+// Package qrcode encodes data into QR codes (ISO/IEC 18004), in byte mode, versions 1 to 40.
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrDataTooLong = errors.New("data too long for a qr code")
+
+// Level is the error correction level, the share of the codewords that can be restored
+type Level int
+
+const (
+	Low      Level = iota // ~7%
+	Medium                // ~15%
+	Quartile              // ~25%
+	High                  // ~30%
+)
+
+// ParseLevel accepts L, M, Q and H
+func ParseLevel(s string) (Level, error) {
+	switch s {
+	case "L", "l":
+		return Low, nil
+	case "M", "m":
+		return Medium, nil
+	case "Q", "q":
+		return Quartile, nil
+	case "H", "h":
+		return High, nil
+	default:
+		return 0, fmt.Errorf("unknown error correction level %q", s)
+	}
+}
+
+// formatBits is the level in the format information, not the order of the levels
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// eccCodewordsPerBlock and numBlocks are indexed by level and version, version 0 is unused
+var eccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var numBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// Code is the module matrix, true is dark
+type Code struct {
+	Version int
+	Level   Level
+	Size    int
+	modules [][]bool
+}
+
+// Dark reports whether the module at column x and row y is dark
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Encode picks the smallest version holding the data at the level
+func Encode(data []byte, level Level) (*Code, error) {
+	if level < Low || level > High {
+		return nil, fmt.Errorf("unknown error correction level %d", level)
+	}
+
+	version := 1
+	for ; version <= 40; version++ {
+		if dataBits(len(data), version) <= numDataCodewords(version, level)*8 {
+			break
+		}
+	}
+	if version > 40 {
+		return nil, ErrDataTooLong
+	}
+
+	codewords := addEccAndInterleave(dataCodewords(data, version, level), version, level)
+
+	m := newMatrix(version)
+	m.drawFunctionPatterns()
+	m.drawCodewords(codewords)
+
+	bestMask, bestPenalty := 0, -1
+	for mask := range 8 {
+		m.applyMask(mask)
+		m.drawFormatBits(level, mask)
+		if penalty := m.penalty(); bestPenalty == -1 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		m.applyMask(mask) // xor undoes it
+	}
+	m.applyMask(bestMask)
+	m.drawFormatBits(level, bestMask)
+
+	return &Code{Version: version, Level: level, Size: m.size, modules: m.modules}, nil
+}
+
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+func dataBits(n int, version int) int {
+	return 4 + charCountBits(version) + n*8
+}
+
+// numRawDataModules is the number of modules left for codewords once the function patterns are drawn
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func numDataCodewords(version int, level Level) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numBlocks[level][version]
+}
+
+// dataCodewords is the byte mode segment, the terminator and the padding
+func dataCodewords(data []byte, version int, level Level) []byte {
+	capacity := numDataCodewords(version, level) * 8
+
+	bb := &bitBuffer{}
+	bb.append(0x4, 4)
+	bb.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+	bb.append(0, min(4, capacity-bb.len))
+	bb.append(0, (8-bb.len%8)%8)
+	for pad := 0xEC; bb.len < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	return bb.bytes
+}
+
+type bitBuffer struct {
+	bytes []byte
+	len   int
+}
+
+func (b *bitBuffer) append(value int, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if b.len%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		if (value>>i)&1 == 1 {
+			b.bytes[b.len/8] |= 0x80 >> (b.len % 8)
+		}
+		b.len++
+	}
+}
+
+// addEccAndInterleave splits the data into blocks, the short blocks first, and interleaves
+// the data and then the error correction codewords of the blocks
+func addEccAndInterleave(data []byte, version int, level Level) []byte {
+	blocks := numBlocks[level][version]
+	eccLen := eccCodewordsPerBlock[level][version]
+	rawCodewords := numRawDataModules(version) / 8
+	numShortBlocks := blocks - rawCodewords%blocks
+	shortBlockLen := rawCodewords / blocks
+
+	divisor := reedSolomonDivisor(eccLen)
+	dataBlocks := make([][]byte, 0, blocks)
+	eccBlocks := make([][]byte, 0, blocks)
+	for i, k := 0, 0; i < blocks; i++ {
+		n := shortBlockLen - eccLen
+		if i >= numShortBlocks {
+			n++
+		}
+		dataBlocks = append(dataBlocks, data[k:k+n])
+		eccBlocks = append(eccBlocks, reedSolomonRemainder(data[k:k+n], divisor))
+		k += n
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := 0; i <= shortBlockLen-eccLen; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := range eccLen {
+		for _, block := range eccBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
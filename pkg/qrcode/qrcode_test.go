@@ -0,0 +1,186 @@
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// "HELLO WORLD" in alphanumeric mode at 1-M, the worked example of the specification
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	ecc := reedSolomonRemainder(data, reedSolomonDivisor(10))
+	assert.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, ecc)
+}
+
+func TestFunctionPatternBits(t *testing.T) {
+	assert.Equal(t, 0b111011111000100, formatBits(Low, 0))
+	assert.Equal(t, 0b101010000010010, formatBits(Medium, 0))
+	assert.Equal(t, 0b001011010001001, formatBits(High, 0))
+	assert.Equal(t, 0b000100000111011, formatBits(High, 7))
+	assert.Equal(t, 0b000111110010010100, versionBits(7))
+	assert.Equal(t, []int{6, 22, 38}, newMatrix(7).alignmentPatternPositions())
+	assert.Equal(t, []int{6, 34, 60, 86, 112, 138}, newMatrix(32).alignmentPatternPositions())
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("should pick the smallest version", func(t *testing.T) {
+		code, err := Encode([]byte("https://tracker.local/r/ab"), Medium)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, code.Version)
+		assert.Equal(t, 25, code.Size)
+
+		code, err = Encode(bytes.Repeat([]byte("a"), 14), Medium)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, code.Version)
+	})
+
+	t.Run("should read back the data", func(t *testing.T) {
+		for _, level := range []Level{Low, Medium, Quartile, High} {
+			for _, n := range []int{1, 30, 120, 400} {
+				data := []byte(strings.Repeat("https://go.cardealer.local/summer-sale?ztsrc=qr", n)[:n])
+				code, err := Encode(data, level)
+				assert.NoError(t, err)
+				assert.Equal(t, data, decode(t, code), "level %d, %d bytes", level, n)
+			}
+		}
+	})
+
+	t.Run("should reject data over version 40", func(t *testing.T) {
+		_, err := Encode(make([]byte, 2954), Low)
+		assert.ErrorIs(t, err, ErrDataTooLong)
+	})
+}
+
+func TestCode_PNG(t *testing.T) {
+	code, err := Encode([]byte("https://tracker.local/r/ab"), Medium)
+	assert.NoError(t, err)
+
+	logo := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for i := range 10 {
+		logo.Set(i, i, color.RGBA{R: 0xFF, A: 0xFF})
+	}
+
+	b, err := code.PNG(330, logo)
+	assert.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(b))
+	assert.NoError(t, err)
+	assert.Equal(t, 330, img.Bounds().Dx()) // 33 modules of 10 pixels
+
+	// the top left finder pattern, after the quiet zone
+	r, _, _, _ := img.At(45, 45).RGBA()
+	assert.Zero(t, r)
+	r, _, _, _ = img.At(5, 5).RGBA()
+	assert.NotZero(t, r)
+}
+
+// decode reads the format bits, unmasks and de-interleaves the codewords and parses the byte segment
+func decode(t *testing.T, code *Code) []byte {
+	t.Helper()
+
+	format := 0
+	for i := 0; i <= 5; i++ {
+		format |= bitAt(code, 8, i) << i
+	}
+	format |= bitAt(code, 8, 7)<<6 | bitAt(code, 8, 8)<<7 | bitAt(code, 7, 8)<<8
+	for i := 9; i < 15; i++ {
+		format |= bitAt(code, 14-i, 8) << i
+	}
+
+	level, mask := Level(-1), -1
+	for l := Low; l <= High; l++ {
+		for m := range 8 {
+			if formatBits(l, m) == format {
+				level, mask = l, m
+			}
+		}
+	}
+	assert.Equal(t, code.Level, level)
+
+	m := newMatrix(code.Version)
+	m.drawFunctionPatterns()
+	for y := range code.Size {
+		for x := range code.Size {
+			if !m.function[y][x] {
+				m.modules[y][x] = code.Dark(x, y)
+			}
+		}
+	}
+	m.applyMask(mask)
+
+	raw := make([]byte, numRawDataModules(code.Version)/8)
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range m.size {
+			for j := range 2 {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = m.size - 1 - vert
+				}
+				if m.function[y][x] || i >= len(raw)*8 {
+					continue
+				}
+				if m.modules[y][x] {
+					raw[i/8] |= 0x80 >> (i % 8)
+				}
+				i++
+			}
+		}
+	}
+
+	blocks := numBlocks[level][code.Version]
+	eccLen := eccCodewordsPerBlock[level][code.Version]
+	numShortBlocks := blocks - len(raw)%blocks
+	shortDataLen := len(raw)/blocks - eccLen
+	dataBlocks := make([][]byte, blocks)
+	k := 0
+	for i := 0; i <= shortDataLen; i++ {
+		for b := range blocks {
+			if i < shortDataLen || b >= numShortBlocks {
+				dataBlocks[b] = append(dataBlocks[b], raw[k])
+				k++
+			}
+		}
+	}
+	for b := range blocks {
+		ecc := make([]byte, eccLen)
+		for i := range eccLen {
+			ecc[i] = raw[k+i*blocks+b]
+		}
+		assert.Equal(t, reedSolomonRemainder(dataBlocks[b], reedSolomonDivisor(eccLen)), ecc)
+	}
+	data := bytes.Join(dataBlocks, nil)
+
+	bit := func(i int) int { return int(data[i/8]>>(7-i%8)) & 1 }
+	read := func(from, n int) int {
+		v := 0
+		for i := range n {
+			v = v<<1 | bit(from+i)
+		}
+		return v
+	}
+
+	assert.Equal(t, 0x4, read(0, 4))
+	countBits := charCountBits(code.Version)
+	n := read(4, countBits)
+	result := make([]byte, n)
+	for i := range n {
+		result[i] = byte(read(4+countBits+i*8, 8))
+	}
+	return result
+}
+
+func bitAt(code *Code, x, y int) int {
+	if code.Dark(x, y) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,141 @@
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+)
+
+// QuietZone is the light border around the code, in modules
+const QuietZone = 4
+
+// logoShare is the width of the logo over the width of the code, ~4% of the modules
+// stay readable with Quartile or High
+const logoShare = 5
+
+// Image renders the code into a square of about size pixels, modules are whole pixels so the
+// image can be a little smaller. The logo is scaled into the center on a light background.
+func (c *Code) Image(size int, logo image.Image) image.Image {
+	width := c.Size + 2*QuietZone
+	scale := max(size/width, 1)
+
+	img := image.NewRGBA(image.Rect(0, 0, width*scale, width*scale))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	for y := range c.Size {
+		for x := range c.Size {
+			if !c.Dark(x, y) {
+				continue
+			}
+			r := image.Rect((x+QuietZone)*scale, (y+QuietZone)*scale, (x+QuietZone+1)*scale, (y+QuietZone+1)*scale)
+			draw.Draw(img, r, image.Black, image.Point{}, draw.Src)
+		}
+	}
+
+	if logo != nil {
+		box := c.logoBox()
+		r := image.Rect((box.Min.X+QuietZone)*scale, (box.Min.Y+QuietZone)*scale,
+			(box.Max.X+QuietZone)*scale, (box.Max.Y+QuietZone)*scale)
+		draw.Draw(img, r, image.White, image.Point{}, draw.Src)
+		drawScaled(img, r.Inset(scale/2), logo)
+	}
+
+	return img
+}
+
+// PNG encodes Image
+func (c *Code) PNG(size int, logo image.Image) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, c.Image(size, logo)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders the code in a viewBox of modules scaled to size pixels, the logo is embedded as png
+func (c *Code) SVG(size int, logo image.Image) ([]byte, error) {
+	width := c.Size + 2*QuietZone
+
+	path := &strings.Builder{}
+	for y := range c.Size {
+		for x := range c.Size {
+			if c.Dark(x, y) {
+				fmt.Fprintf(path, "M%d %dh1v1h-1z", x+QuietZone, y+QuietZone)
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, width, width)
+	fmt.Fprintf(buf, `<rect width="%d" height="%d" fill="#fff"/>`, width, width)
+	fmt.Fprintf(buf, `<path d="%s" fill="#000"/>`, path.String())
+
+	if logo != nil {
+		encoded := &bytes.Buffer{}
+		if err := png.Encode(encoded, logo); err != nil {
+			return nil, err
+		}
+
+		box := c.logoBox().Add(image.Pt(QuietZone, QuietZone))
+		fmt.Fprintf(buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="#fff"/>`,
+			box.Min.X, box.Min.Y, box.Dx(), box.Dy())
+		fmt.Fprintf(buf, `<image x="%g" y="%g" width="%g" height="%g" preserveAspectRatio="xMidYMid meet" href="data:image/png;base64,%s"/>`,
+			float64(box.Min.X)+0.5, float64(box.Min.Y)+0.5, float64(box.Dx())-1, float64(box.Dy())-1,
+			base64.StdEncoding.EncodeToString(encoded.Bytes()))
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes(), nil
+}
+
+// logoBox is the centered square covered by the logo, in modules
+func (c *Code) logoBox() image.Rectangle {
+	side := c.Size / logoShare
+	if side%2 != c.Size%2 {
+		side++
+	}
+	start := (c.Size - side) / 2
+	return image.Rect(start, start, start+side, start+side)
+}
+
+// drawScaled draws src into r with nearest neighbour scaling, keeping its aspect ratio
+func drawScaled(dst draw.Image, r image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	if sb.Empty() || r.Empty() {
+		return
+	}
+
+	w, h := r.Dx(), r.Dy()
+	if sb.Dx()*h > sb.Dy()*w {
+		h = sb.Dy() * w / sb.Dx()
+	} else {
+		w = sb.Dx() * h / sb.Dy()
+	}
+	offset := image.Pt(r.Min.X+(r.Dx()-w)/2, r.Min.Y+(r.Dy()-h)/2)
+
+	for y := range h {
+		for x := range w {
+			c := src.At(sb.Min.X+x*sb.Dx()/w, sb.Min.Y+y*sb.Dy()/h)
+			px, py := offset.X+x, offset.Y+y
+			dst.Set(px, py, blend(dst.At(px, py), c))
+		}
+	}
+}
+
+// blend draws c over the background, transparent logos keep the light box
+func blend(bg color.Color, c color.Color) color.Color {
+	sr, sg, sb, sa := c.RGBA()
+	br, bgG, bb, _ := bg.RGBA()
+	inv := 0xFFFF - sa
+	return color.RGBA64{
+		R: uint16(sr + br*inv/0xFFFF),
+		G: uint16(sg + bgG*inv/0xFFFF),
+		B: uint16(sb + bb*inv/0xFFFF),
+		A: 0xFFFF,
+	}
+}
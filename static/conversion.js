@@ -4,7 +4,8 @@
   // TODO:
   // - right now, we don't count attribution window. get the config from API and save it to localstorage (expired in 1 hours) and calculate in FE
   // - other option don't need to save attribution window but always send to server and let server to decide if conversion is valid or not
-  // design question: should we put accepted domain on settings?
+
+  const currentScript = document.currentScript;
 
   // Configuration defaults
   const CONFIG = {
@@ -16,7 +17,20 @@
     sessionTimeout: 1800000,
     deduplicationWindow: 3600000,
     refreshInterval: 82800000,
-    propagateToDomains: [],
+    trackingId: (currentScript && currentScript.dataset.trackingId) || '',
+    propagateToDomains: [], // owned domains, loaded from the tracking setting
+    // when true, nothing is stored and no fingerprint is taken until consent is granted
+    consentRequired:
+      !!currentScript && currentScript.dataset.consentRequired === 'true',
+    // first-party identity endpoint under the client CNAME, e.g. https://t.client.com
+    identityEndpoint:
+      (currentScript && currentScript.dataset.identityEndpoint) || '',
+  };
+
+  const CONSENT = {
+    GRANTED: 'granted',
+    DENIED: 'denied',
+    UNKNOWN: 'unknown',
   };
 
   const PARAMS = {
@@ -51,6 +65,12 @@
         }
       );
     },
+    // the tracking id lets the server check the page origin against the tenant domains
+    apiUrl: function (base, path) {
+      const url = new URL(path, base);
+      if (CONFIG.trackingId) url.searchParams.set('tracking_id', CONFIG.trackingId);
+      return url.toString();
+    },
     hashString: function (str) {
       let hash = 0;
       for (let i = 0; i < str.length; i++) {
@@ -98,10 +118,71 @@
     },
   };
 
+  // Consent management
+  // initial state: window.ztConsent set before the script, or data-consent on the script tag
+  // runtime: window.zt.consent('granted' | 'denied')
+  class Consent {
+    constructor() {
+      this.state = CONSENT.UNKNOWN;
+      this.listeners = [];
+
+      const initial =
+        window.ztConsent || (currentScript && currentScript.dataset.consent);
+      if (this.isValid(initial)) this.state = initial;
+    }
+
+    isValid(state) {
+      return Object.values(CONSENT).includes(state);
+    }
+
+    // Global Privacy Control and Do Not Track always win over the consent signal
+    optedOut() {
+      return (
+        navigator.globalPrivacyControl === true ||
+        navigator.doNotTrack === '1' ||
+        window.doNotTrack === '1'
+      );
+    }
+
+    get() {
+      if (this.optedOut()) return CONSENT.DENIED;
+      return this.state;
+    }
+
+    set(state) {
+      if (!this.isValid(state)) {
+        console.log('Invalid consent state:', state);
+        return;
+      }
+
+      this.state = state;
+      this.listeners.forEach((listener) => listener(this.get()));
+    }
+
+    onChange(listener) {
+      this.listeners.push(listener);
+    }
+
+    // cookies, storage and fingerprinting
+    allowsStorage() {
+      const state = this.get();
+      if (state === CONSENT.UNKNOWN) return !CONFIG.consentRequired;
+      return state === CONSENT.GRANTED;
+    }
+  }
+
   // Identity management
   class Identity {
     constructor() {
-      this.cookieDomain = utils.detectCookieDomain();
+      this.cookieDomain = null;
+    }
+
+    // detecting the cookie domain writes a test cookie, so only do it once storage is allowed
+    getCookieDomain() {
+      if (!this.cookieDomain) {
+        this.cookieDomain = utils.detectCookieDomain();
+      }
+      return this.cookieDomain;
     }
 
     get() {
@@ -138,7 +219,7 @@
       utils.setCookie(
         CONFIG.cookieName,
         encoded,
-        this.cookieDomain,
+        this.getCookieDomain(),
         CONFIG.cookieMaxAge
       );
 
@@ -150,6 +231,53 @@
 
     isValid(identity) {
       if (!identity || !identity.ztid) return false;
+      return true;
+    }
+
+    // the endpoint sets an HttpOnly cookie from the server, which Safari ITP doesn't cap to 7 days
+    async fetchServer(method, body) {
+      if (!CONFIG.identityEndpoint) return null;
+
+      try {
+        const response = await fetch(
+          utils.apiUrl(CONFIG.identityEndpoint, '/v1/visitor/identity'),
+          {
+            method: method,
+            credentials: 'include',
+            headers: body ? { 'Content-Type': 'application/json' } : {},
+            body: body ? JSON.stringify(body) : undefined,
+          }
+        );
+        if (response.status !== 200) return null;
+
+        const identity = await response.json();
+        return this.isValid(identity) ? identity : null;
+      } catch (e) {
+        console.log('Identity endpoint failed:', e);
+        return null;
+      }
+    }
+
+    // prefer the server cookie, fall back to the javascript cookie and storage
+    async resolve(params) {
+      if (params) {
+        const identity = await this.fetchServer('POST', {
+          ztid: params.ztid,
+          ts: params.ts,
+        });
+        if (identity) {
+          utils.setStorage(CONFIG.storageKey, identity);
+          return identity;
+        }
+        return this.set(params.ztid, params.ts);
+      }
+
+      const identity = await this.fetchServer('GET');
+      if (identity) {
+        utils.setStorage(CONFIG.storageKey, identity);
+        return identity;
+      }
+      return this.get();
     }
 
     refresh() {
@@ -161,9 +289,10 @@
     }
 
     clear() {
-      document.cookie = `${CONFIG.cookieName}=; domain=${this.cookieDomain}; max-age=0; path=/`;
+      document.cookie = `${CONFIG.cookieName}=; domain=${this.getCookieDomain()}; max-age=0; path=/`;
       localStorage.removeItem(CONFIG.storageKey);
       sessionStorage.removeItem(CONFIG.storageKey);
+      this.fetchServer('DELETE');
     }
   }
 
@@ -171,7 +300,6 @@
     constructor() {
       this.fingerprint = null;
       this.thumbmarkLoaded = false;
-      this.loadThumbmark();
     }
 
     async loadThumbmark() {
@@ -294,25 +422,34 @@
 
   class ZealsTracker {
     constructor() {
+      this.consent = new Consent();
       this.identity = new Identity();
       this.fingerprint = new FingerprintManager();
       this.dedup = new Deduplication();
     }
 
     async run() {
+      this.consent.onChange(() => this.applyConsent());
+
+      if (!this.consent.allowsStorage()) {
+        await this.runCookieless();
+        return;
+      }
+
       const params = this.extractParams();
 
       if (params) {
-        this.session = this.identity.set(params.ztid, params.ts);
+        this.session = await this.identity.resolve(params);
         this.session.isNew = true; // flag if data is not come from storage
       } else {
-        this.session = this.identity.get();
-        console.log('go here');
-        console.log(this.session);
+        this.session = await this.identity.resolve(null);
 
         if (!this.session) {
           // if we can't find any data from storage
-          this.session = this.identity.set('original', Date.now());
+          this.session = await this.identity.resolve({
+            ztid: 'original',
+            ts: Date.now(),
+          });
           this.session.isNew = true; // flag if data is not come from storage
         }
       }
@@ -320,13 +457,16 @@
       // insert fingerprint
       this.session.fp = await this.fingerprint.generate();
 
-      if (this.isSafari()) {
+      // the server cookie doesn't need the refresh
+      if (this.isSafari() && !CONFIG.identityEndpoint) {
         // refresh cookie
         setInterval(() => this.identity.refresh(), CONFIG.refreshInterval);
       }
 
       this.setupAutoTracking();
 
+      await this.loadSettings();
+
       this.setupCrossDomainPropagation();
 
       // track session start
@@ -335,6 +475,57 @@
       }
     }
 
+    // without consent the ztid only lives in memory and no fingerprint is taken
+    async runCookieless() {
+      const params = this.extractParams();
+      this.session = params
+        ? { ztid: params.ztid, ts: params.ts, created: Date.now(), isNew: true }
+        : { ztid: 'original', ts: Date.now(), created: Date.now(), isNew: true };
+      this.session.fp = '';
+
+      this.setupAutoTracking();
+
+      await this.loadSettings();
+
+      this.setupCrossDomainPropagation();
+
+      this.track();
+    }
+
+    async loadSettings() {
+      if (!CONFIG.trackingId) return;
+
+      try {
+        const response = await fetch(
+          `${CONFIG.endpoint}/v1/tracking-settings/${CONFIG.trackingId}/script-config`
+        );
+        if (!response.ok) throw new Error('Failed');
+
+        const settings = await response.json();
+        CONFIG.propagateToDomains = settings.domains || [];
+      } catch (e) {
+        console.log('Failed to load tracking settings:', e);
+      }
+    }
+
+    async applyConsent() {
+      if (!this.session) return;
+
+      if (!this.consent.allowsStorage()) {
+        this.identity.clear();
+        this.session.fp = '';
+        return;
+      }
+
+      const isNew = this.session.isNew;
+      this.session = await this.identity.resolve({
+        ztid: this.session.ztid,
+        ts: this.session.ts,
+      });
+      this.session.isNew = isNew;
+      this.session.fp = await this.fingerprint.generate();
+    }
+
     extractParams() {
       const params = new URLSearchParams(window.location.search);
       const ztid = params.get(PARAMS.TRACKER_ID);
@@ -353,24 +544,45 @@
         session: this.session,
       };
 
-      if (!this.dedup.shouldSend(event)) {
-        console.log('Blocked duplicate:', event.url);
-        return;
-      }
+      // deduplication keeps its state in storage
+      if (this.consent.allowsStorage()) {
+        if (!this.dedup.shouldSend(event)) {
+          console.log('Blocked duplicate:', event.url);
+          return;
+        }
 
-      this.dedup.markSent(event);
+        this.dedup.markSent(event);
+      }
       this.send(event);
     }
 
+    // custom event of the page, e.g. a form submission, a step of the funnels
+    trackEvent(name) {
+      if (typeof name !== 'string' || !name || !this.session) return;
+
+      this.send({
+        url: window.location.href,
+        timestamp: Date.now(),
+        session: this.session,
+        name: name,
+      });
+    }
+
     async send(event) {
       const request = {
         track_id: event.session?.ztid,
         fp: event.session?.fp,
         url: event.url,
         published_at: event.timestamp,
+        consent: this.consent.get(),
+        wd: navigator.webdriver === true,
+        // order value of a thank you page, window.ztRevenue set before the script
+        revenue:
+          typeof window.ztRevenue === 'number' ? window.ztRevenue : undefined,
+        event: event.name,
       };
 
-      const url = CONFIG.endpoint + '/v1/tracks/events';
+      const url = utils.apiUrl(CONFIG.endpoint, '/v1/tracks/events');
       const data = JSON.stringify(request);
 
       try {
@@ -490,24 +702,28 @@
       });
     }
 
-    // TODO: should list all the client domain? for security purpose?
     shouldPropagateToDomain(hostname) {
       // Always propagate to same domain
       if (hostname === window.location.hostname) return true;
 
-      // If specific domains configured, only propagate to those
-      if (CONFIG.propagateToDomains && CONFIG.propagateToDomains.length > 0) {
-        return CONFIG.propagateToDomains.some(
-          (domain) => hostname === domain || hostname.endsWith('.' + domain)
-        );
-      }
-
-      // By default, propagate to all external domains
-      return true;
+      // Only propagate to the owned domains, never leak tracking IDs to third parties
+      return CONFIG.propagateToDomains.some(
+        (domain) => hostname === domain || hostname.endsWith('.' + domain)
+      );
     }
   }
 
   const zealsTracker = new ZealsTracker();
+
+  window.zt = window.zt || {};
+  window.zt.consent = function (state) {
+    if (state === undefined) return zealsTracker.consent.get();
+    zealsTracker.consent.set(state);
+  };
+  window.zt.track = function (name) {
+    zealsTracker.trackEvent(name);
+  };
+
   zealsTracker.run();
   console.log('script is loaded');
 })(window, document);
@@ -8,6 +8,11 @@ package web
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
+import (
+	"fmt"
+	"strconv"
+)
+
 // ConversionPoint represents a single conversion tracking point
 type ConversionPoint struct {
 	ID     string `json:"id"`
@@ -18,10 +23,27 @@ type ConversionPoint struct {
 
 // LandingPage represents a redirect URL and landing page association
 type LandingPage struct {
-	ID              string `json:"id"`
-	FixedURL        string `json:"fixed_url"`
-	LandingPageName string `json:"landing_page_name"`
-	LandingPageURL  string `json:"landing_page_url"`
+	ID              string   `json:"id"`
+	FixedURL        string   `json:"fixed_url"`
+	LandingPageName string   `json:"landing_page_name"`
+	LandingPageURL  string   `json:"landing_page_url"`
+	Tags            []string `json:"tags"`
+}
+
+// LandingPageCampaign is a campaign of the landing pages filter
+type LandingPageCampaign struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// LandingPageImportReport is the outcome of an upload of landing pages
+type LandingPageImportReport struct {
+	Applied bool
+	Created int
+	Updated int
+	Failed  int
+	Errors  []string // one line per failing row
+	Message string   // the file couldn't be read
 }
 
 // ConversionTracker handles conversion points data
@@ -112,6 +134,14 @@ func IndexPage(tracker *ConversionTracker) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, " ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = ImportLandingPagesModal().Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
 			return nil
 		})
 		templ_7745c5c3_Err = Layout().Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
@@ -144,7 +174,7 @@ func Header() templ.Component {
 			templ_7745c5c3_Var3 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "<div class=\"mb-8\"><h1 class=\"text-3xl font-bold text-gray-900\">Conversion Tracker</h1></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<div class=\"mb-8\"><h1 class=\"text-3xl font-bold text-gray-900\">Conversion Tracker</h1></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -174,7 +204,7 @@ func TabNavigation(tracker *ConversionTracker) templ.Component {
 			templ_7745c5c3_Var4 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<div class=\"border-b border-gray-200 mb-6\"><nav class=\"-mb-px flex space-x-8\"><button class=\"py-2 px-1 border-b-2 font-medium text-sm tab-active\">Tracking Settings</button> <button class=\"py-2 px-1 border-b-2 border-transparent text-gray-500 hover:text-gray-700 hover:border-gray-300 font-medium text-sm\">Tracking Snippet</button></nav></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<div class=\"border-b border-gray-200 mb-6\"><nav class=\"-mb-px flex space-x-8\"><button class=\"py-2 px-1 border-b-2 font-medium text-sm tab-active\">Tracking Settings</button> <button class=\"py-2 px-1 border-b-2 border-transparent text-gray-500 hover:text-gray-700 hover:border-gray-300 font-medium text-sm\">Tracking Snippet</button></nav></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -204,7 +234,7 @@ func MainContent(tracker *ConversionTracker) templ.Component {
 			templ_7745c5c3_Var5 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<div class=\"bg-white rounded-lg shadow\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<div class=\"bg-white rounded-lg shadow\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -212,7 +242,7 @@ func MainContent(tracker *ConversionTracker) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "</div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "</div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -246,7 +276,7 @@ func MainContentBody(tracker *ConversionTracker) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<div id=\"conversion-content\" class=\"tab-content\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "<div id=\"conversion-content\" class=\"tab-content\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -254,7 +284,7 @@ func MainContentBody(tracker *ConversionTracker) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</div><div id=\"landing-pages-content\" class=\"tab-content\" style=\"display: none;\"><div class=\"p-6\"><div class=\"text-center text-gray-500\">Loading landing pages...</div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "</div><div id=\"landing-pages-content\" class=\"tab-content\" style=\"display: none;\"><div class=\"p-6\"><div class=\"text-center text-gray-500\">Loading landing pages...</div></div></div><div id=\"domains-content\" class=\"tab-content\" style=\"display: none;\"><div class=\"p-6\"><div class=\"text-center text-gray-500\">Loading domains...</div></div></div><div id=\"dashboard-content\" class=\"tab-content\" style=\"display: none;\"><div class=\"p-6\"><div class=\"text-center text-gray-500\">Loading dashboard...</div></div></div><div id=\"funnels-content\" class=\"tab-content\" style=\"display: none;\"><div class=\"p-6\"><div class=\"text-center text-gray-500\">Loading funnels...</div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -284,7 +314,7 @@ func SubTabNavigation() templ.Component {
 			templ_7745c5c3_Var7 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "<div class=\"border-b border-gray-200\"><nav class=\"flex space-x-0\"><button id=\"conversion-tab\" class=\"py-3 px-4 text-sm font-medium border-r border-gray-200 sub-tab-active\" onclick=\"switchTab('conversion')\">Conversion Point URL</button> <button id=\"landing-pages-tab\" class=\"py-3 px-4 text-sm font-medium text-gray-500 hover:text-gray-700\" onclick=\"switchTab('landing-pages')\">Redirect URL & Landing Pages</button></nav></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "<div class=\"border-b border-gray-200\"><nav class=\"flex space-x-0\"><button id=\"conversion-tab\" class=\"py-3 px-4 text-sm font-medium border-r border-gray-200 sub-tab-active\" onclick=\"switchTab('conversion')\">Conversion Point URL</button> <button id=\"landing-pages-tab\" class=\"py-3 px-4 text-sm font-medium text-gray-500 hover:text-gray-700\" onclick=\"switchTab('landing-pages')\">Redirect URL & Landing Pages</button> <button id=\"domains-tab\" class=\"py-3 px-4 text-sm font-medium text-gray-500 hover:text-gray-700\" onclick=\"switchTab('domains')\">Owned Domains</button> <button id=\"dashboard-tab\" class=\"py-3 px-4 text-sm font-medium text-gray-500 hover:text-gray-700\" onclick=\"switchTab('dashboard')\">Dashboard</button> <button id=\"funnels-tab\" class=\"py-3 px-4 text-sm font-medium text-gray-500 hover:text-gray-700\" onclick=\"switchTab('funnels')\">Funnels</button></nav></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -314,7 +344,7 @@ func ConversionContent(tracker *ConversionTracker) templ.Component {
 			templ_7745c5c3_Var8 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "<div class=\"p-6\"><p class=\"text-sm text-gray-600 mb-6\">Enter the URL of the pages you want to set as Conversion Points (for example Thank you page)</p>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<div class=\"p-6\"><p class=\"text-sm text-gray-600 mb-6\">Enter the URL of the pages you want to set as Conversion Points (for example Thank you page)</p>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -322,7 +352,7 @@ func ConversionContent(tracker *ConversionTracker) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<div id=\"conversion-table\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "<div id=\"conversion-table\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -330,7 +360,7 @@ func ConversionContent(tracker *ConversionTracker) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "</div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "</div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -339,7 +369,7 @@ func ConversionContent(tracker *ConversionTracker) templ.Component {
 }
 
 // Landing Pages content
-func LandingPagesContent(pages []LandingPage) templ.Component {
+func LandingPagesContent(pages []LandingPage, campaigns []LandingPageCampaign) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -360,15 +390,15 @@ func LandingPagesContent(pages []LandingPage) templ.Component {
 			templ_7745c5c3_Var9 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "<div class=\"p-6\"><p class=\"text-sm text-gray-600 mb-6\">Generate a Redirect URL and associate a Landing page to it. The Redirect URL can be used inside scenarios.</p>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "<div class=\"p-6\"><p class=\"text-sm text-gray-600 mb-6\">Generate a Redirect URL and associate a Landing page to it. The Redirect URL can be used inside scenarios.</p>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = LandingPagesSearchAndControls().Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = LandingPagesSearchAndControls(campaigns).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "<div id=\"landing-pages-table\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "<div id=\"landing-pages-table\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -376,7 +406,7 @@ func LandingPagesContent(pages []LandingPage) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "</div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "</div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -406,7 +436,7 @@ func ConversionSearchAndControls() templ.Component {
 			templ_7745c5c3_Var10 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "<div class=\"flex items-center justify-between mb-6\"><div class=\"flex items-center space-x-4\"><div class=\"relative\"><input type=\"text\" placeholder=\"Search by Name or URL\" class=\"w-80 pl-10 pr-4 py-2 border border-gray-300 rounded-md focus:ring-blue-500 focus:border-blue-500\" hx-post=\"/search\" hx-target=\"#conversion-table\" hx-swap=\"innerHTML\" hx-trigger=\"keyup changed delay:300ms\" name=\"search\"><div class=\"absolute inset-y-0 left-0 pl-3 flex items-center pointer-events-none\"><svg class=\"h-5 w-5 text-gray-400\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M21 21l-6-6m2-5a7 7 0 11-14 0 7 7 0 0114 0z\"></path></svg></div></div><select class=\"border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" hx-post=\"/filter\" hx-target=\"#conversion-table\" hx-swap=\"innerHTML\" name=\"status\"><option value=\"\">Status</option> <option value=\"all\">All</option> <option value=\"Draft\">Draft</option> <option value=\"Active\">Active</option></select> <button class=\"inline-flex items-center px-4 py-2 border border-gray-300 rounded-md shadow-sm text-sm font-medium text-gray-700 bg-white hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500\" onclick=\"showAddModal()\"><svg class=\"h-4 w-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 6v6m0 0v6m0-6h6m-6 0H6\"></path></svg> Add</button> <button class=\"inline-flex items-center px-4 py-2 border border-gray-300 rounded-md shadow-sm text-sm font-medium text-gray-700 bg-white hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500\"><svg class=\"h-4 w-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M11 5H6a2 2 0 00-2 2v11a2 2 0 002 2h11a2 2 0 002-2v-5m-1.414-9.414a2 2 0 112.828 2.828L11.828 15H9v-2.828l8.586-8.586z\"></path></svg> Edit</button></div><button class=\"inline-flex items-center px-6 py-2 border border-transparent rounded-md shadow-sm text-sm font-medium text-white bg-blue-600 hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500\" hx-post=\"/start-tracking\" hx-target=\"#conversion-table\" hx-swap=\"innerHTML\" hx-include=\"[name='selected']\">Start Tracking Selected</button></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "<div class=\"flex items-center justify-between mb-6\"><div class=\"flex items-center space-x-4\"><div class=\"relative\"><input type=\"text\" placeholder=\"Search by Name or URL\" class=\"w-80 pl-10 pr-4 py-2 border border-gray-300 rounded-md focus:ring-blue-500 focus:border-blue-500\" hx-post=\"/search\" hx-target=\"#conversion-table\" hx-swap=\"innerHTML\" hx-trigger=\"keyup changed delay:300ms\" name=\"search\"><div class=\"absolute inset-y-0 left-0 pl-3 flex items-center pointer-events-none\"><svg class=\"h-5 w-5 text-gray-400\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M21 21l-6-6m2-5a7 7 0 11-14 0 7 7 0 0114 0z\"></path></svg></div></div><select class=\"border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" hx-post=\"/filter\" hx-target=\"#conversion-table\" hx-swap=\"innerHTML\" name=\"status\"><option value=\"\">Status</option> <option value=\"all\">All</option> <option value=\"Draft\">Draft</option> <option value=\"Active\">Active</option></select> <button class=\"inline-flex items-center px-4 py-2 border border-gray-300 rounded-md shadow-sm text-sm font-medium text-gray-700 bg-white hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500\" onclick=\"showAddModal()\"><svg class=\"h-4 w-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 6v6m0 0v6m0-6h6m-6 0H6\"></path></svg> Add</button> <button class=\"inline-flex items-center px-4 py-2 border border-gray-300 rounded-md shadow-sm text-sm font-medium text-gray-700 bg-white hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500\"><svg class=\"h-4 w-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M11 5H6a2 2 0 00-2 2v11a2 2 0 002 2h11a2 2 0 002-2v-5m-1.414-9.414a2 2 0 112.828 2.828L11.828 15H9v-2.828l8.586-8.586z\"></path></svg> Edit</button></div><button class=\"inline-flex items-center px-6 py-2 border border-transparent rounded-md shadow-sm text-sm font-medium text-white bg-blue-600 hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500\" hx-post=\"/start-tracking\" hx-target=\"#conversion-table\" hx-swap=\"innerHTML\" hx-include=\"[name='selected']\">Start Tracking Selected</button></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -415,7 +445,7 @@ func ConversionSearchAndControls() templ.Component {
 }
 
 // Landing pages search and controls
-func LandingPagesSearchAndControls() templ.Component {
+func LandingPagesSearchAndControls(campaigns []LandingPageCampaign) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -436,7 +466,43 @@ func LandingPagesSearchAndControls() templ.Component {
 			templ_7745c5c3_Var11 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "<div class=\"flex items-center justify-between mb-6\"><div class=\"flex items-center space-x-4\"><div class=\"relative\"><input type=\"text\" placeholder=\"Search by Name or URL\" class=\"w-80 pl-10 pr-4 py-2 border border-gray-300 rounded-md focus:ring-blue-500 focus:border-blue-500\" hx-post=\"/landing-pages/search\" hx-target=\"#landing-pages-table\" hx-swap=\"innerHTML\" hx-trigger=\"keyup changed delay:300ms\" name=\"search\"><div class=\"absolute inset-y-0 left-0 pl-3 flex items-center pointer-events-none\"><svg class=\"h-5 w-5 text-gray-400\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M21 21l-6-6m2-5a7 7 0 11-14 0 7 7 0 0114 0z\"></path></svg></div></div><button class=\"inline-flex items-center px-4 py-2 border border-gray-300 rounded-md shadow-sm text-sm font-medium text-gray-700 bg-white hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500\" onclick=\"showLandingPageModal()\"><svg class=\"h-4 w-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 6v6m0 0v6m0-6h6m-6 0H6\"></path></svg> Add</button> <button class=\"inline-flex items-center px-4 py-2 border border-gray-300 rounded-md shadow-sm text-sm font-medium text-gray-700 bg-white hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500\"><svg class=\"h-4 w-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M11 5H6a2 2 0 00-2 2v11a2 2 0 002 2h11a2 2 0 002-2v-5m-1.414-9.414a2 2 0 112.828 2.828L11.828 15H9v-2.828l8.586-8.586z\"></path></svg> Edit</button></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "<div class=\"flex items-center justify-between mb-6\"><div class=\"flex items-center space-x-4\"><div id=\"landing-pages-filters\" class=\"flex items-center space-x-4\"><div class=\"relative\"><input type=\"text\" placeholder=\"Search by Name, URL or Slug\" class=\"w-80 pl-10 pr-4 py-2 border border-gray-300 rounded-md focus:ring-blue-500 focus:border-blue-500\" hx-post=\"/landing-pages/search\" hx-target=\"#landing-pages-table\" hx-swap=\"innerHTML\" hx-trigger=\"keyup changed delay:300ms\" hx-include=\"#landing-pages-filters\" name=\"search\"><div class=\"absolute inset-y-0 left-0 pl-3 flex items-center pointer-events-none\"><svg class=\"h-5 w-5 text-gray-400\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M21 21l-6-6m2-5a7 7 0 11-14 0 7 7 0 0114 0z\"></path></svg></div></div><input type=\"text\" placeholder=\"Tag\" class=\"w-32 px-3 py-2 border border-gray-300 rounded-md focus:ring-blue-500 focus:border-blue-500\" hx-post=\"/landing-pages/search\" hx-target=\"#landing-pages-table\" hx-swap=\"innerHTML\" hx-trigger=\"keyup changed delay:300ms\" hx-include=\"#landing-pages-filters\" name=\"tag\"> <select class=\"border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" hx-post=\"/landing-pages/search\" hx-target=\"#landing-pages-table\" hx-swap=\"innerHTML\" hx-include=\"#landing-pages-filters\" name=\"deleted\"><option value=\"false\">Active</option> <option value=\"true\">Deleted</option></select> <select class=\"border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" hx-post=\"/landing-pages/search\" hx-target=\"#landing-pages-table\" hx-swap=\"innerHTML\" hx-include=\"#landing-pages-filters\" name=\"campaign_id\"><option value=\"\">All campaigns</option> ")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		for _, campaign := range campaigns {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "<option value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var12 string
+			templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(campaign.ID)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 315, Col: 33}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var13 string
+			templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(campaign.Name)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 315, Col: 51}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "</option>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "</select></div><button class=\"inline-flex items-center px-4 py-2 border border-gray-300 rounded-md shadow-sm text-sm font-medium text-gray-700 bg-white hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500\" onclick=\"showLandingPageModal()\"><svg class=\"h-4 w-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 6v6m0 0v6m0-6h6m-6 0H6\"></path></svg> Add</button> <button class=\"inline-flex items-center px-4 py-2 border border-gray-300 rounded-md shadow-sm text-sm font-medium text-gray-700 bg-white hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500\"><svg class=\"h-4 w-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M11 5H6a2 2 0 00-2 2v11a2 2 0 002 2h11a2 2 0 002-2v-5m-1.414-9.414a2 2 0 112.828 2.828L11.828 15H9v-2.828l8.586-8.586z\"></path></svg> Edit</button> <button class=\"inline-flex items-center px-4 py-2 border border-gray-300 rounded-md shadow-sm text-sm font-medium text-gray-700 bg-white hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500\" onclick=\"showImportLandingPagesModal()\"><svg class=\"h-4 w-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M4 16v1a3 3 0 003 3h10a3 3 0 003-3v-1m-4-8l-4-4m0 0L8 8m4-4v12\"></path></svg> Import</button> <a href=\"/landing-pages/export\" class=\"inline-flex items-center px-4 py-2 border border-gray-300 rounded-md shadow-sm text-sm font-medium text-gray-700 bg-white hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500\"><svg class=\"h-4 w-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M4 16v1a3 3 0 003 3h10a3 3 0 003-3v-1m-4-4l-4 4m0 0l-4-4m4 4V4\"></path></svg> Export</a></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -461,12 +527,12 @@ func ConversionPointsTable(points []ConversionPoint) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var12 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var12 == nil {
-			templ_7745c5c3_Var12 = templ.NopComponent
+		templ_7745c5c3_Var14 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var14 == nil {
+			templ_7745c5c3_Var14 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "<div class=\"overflow-hidden shadow ring-1 ring-black ring-opacity-5 md:rounded-lg\"><table class=\"min-w-full divide-y divide-gray-300\"><thead class=\"bg-gray-50\"><tr><th scope=\"col\" class=\"relative w-12 px-6 sm:w-16 sm:px-8\"><input type=\"checkbox\" class=\"absolute left-4 top-1/2 -mt-2 h-4 w-4 rounded border-gray-300 text-blue-600 focus:ring-blue-500\" onchange=\"toggleAllCheckboxes(this)\"></th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Name</th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Conversion Point URL</th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Status</th></tr></thead> <tbody class=\"bg-white divide-y divide-gray-200\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "<div class=\"overflow-hidden shadow ring-1 ring-black ring-opacity-5 md:rounded-lg\"><table class=\"min-w-full divide-y divide-gray-300\"><thead class=\"bg-gray-50\"><tr><th scope=\"col\" class=\"relative w-12 px-6 sm:w-16 sm:px-8\"><input type=\"checkbox\" class=\"absolute left-4 top-1/2 -mt-2 h-4 w-4 rounded border-gray-300 text-blue-600 focus:ring-blue-500\" onchange=\"toggleAllCheckboxes(this)\"></th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Name</th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Conversion Point URL</th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Status</th></tr></thead> <tbody class=\"bg-white divide-y divide-gray-200\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -476,7 +542,7 @@ func ConversionPointsTable(points []ConversionPoint) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "</tbody></table></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "</tbody></table></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -501,106 +567,106 @@ func ConversionPointRow(point ConversionPoint, isEven bool) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var13 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var13 == nil {
-			templ_7745c5c3_Var13 = templ.NopComponent
+		templ_7745c5c3_Var15 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var15 == nil {
+			templ_7745c5c3_Var15 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		var templ_7745c5c3_Var14 = []any{templ.KV("bg-white", isEven), templ.KV("bg-gray-50", !isEven)}
-		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var14...)
+		var templ_7745c5c3_Var16 = []any{templ.KV("bg-white", isEven), templ.KV("bg-gray-50", !isEven)}
+		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var16...)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "<tr class=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "<tr class=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var15 string
-		templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var14).String())
+		var templ_7745c5c3_Var17 string
+		templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var16).String())
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 1, Col: 0}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "\"><td class=\"relative w-12 px-6 sm:w-16 sm:px-8\"><input type=\"checkbox\" name=\"selected\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "\"><td class=\"relative w-12 px-6 sm:w-16 sm:px-8\"><input type=\"checkbox\" name=\"selected\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var16 string
-		templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(point.ID)
+		var templ_7745c5c3_Var18 string
+		templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs(point.ID)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 273, Col: 20}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 394, Col: 20}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "\" class=\"absolute left-4 top-1/2 -mt-2 h-4 w-4 rounded border-gray-300 text-blue-600 focus:ring-blue-500\"></td><td class=\"px-6 py-4 whitespace-nowrap text-sm text-gray-900\"><div class=\"max-w-xs truncate\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "\" class=\"absolute left-4 top-1/2 -mt-2 h-4 w-4 rounded border-gray-300 text-blue-600 focus:ring-blue-500\"></td><td class=\"px-6 py-4 whitespace-nowrap text-sm text-gray-900\"><div class=\"max-w-xs truncate\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var17 string
-		templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(point.Name)
+		var templ_7745c5c3_Var19 string
+		templ_7745c5c3_Var19, templ_7745c5c3_Err = templ.JoinStringErrs(point.Name)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 279, Col: 16}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 400, Col: 16}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var19))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "</div></td><td class=\"px-6 py-4 whitespace-nowrap text-sm text-gray-500\"><div class=\"max-w-md truncate\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "</div></td><td class=\"px-6 py-4 whitespace-nowrap text-sm text-gray-500\"><div class=\"max-w-md truncate\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var18 string
-		templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs(point.URL)
+		var templ_7745c5c3_Var20 string
+		templ_7745c5c3_Var20, templ_7745c5c3_Err = templ.JoinStringErrs(point.URL)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 284, Col: 15}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 405, Col: 15}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var20))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "</div></td><td class=\"px-6 py-4 whitespace-nowrap\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "</div></td><td class=\"px-6 py-4 whitespace-nowrap\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var19 = []any{"inline-flex px-2 py-1 text-xs font-semibold rounded-full",
+		var templ_7745c5c3_Var21 = []any{"inline-flex px-2 py-1 text-xs font-semibold rounded-full",
 			templ.KV("bg-yellow-100 text-yellow-800", point.Status == "Draft"),
 			templ.KV("bg-green-100 text-green-800", point.Status == "Active")}
-		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var19...)
+		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var21...)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "<span class=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "<span class=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var20 string
-		templ_7745c5c3_Var20, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var19).String())
+		var templ_7745c5c3_Var22 string
+		templ_7745c5c3_Var22, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var21).String())
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 1, Col: 0}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var20))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var22))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var21 string
-		templ_7745c5c3_Var21, templ_7745c5c3_Err = templ.JoinStringErrs(point.Status)
+		var templ_7745c5c3_Var23 string
+		templ_7745c5c3_Var23, templ_7745c5c3_Err = templ.JoinStringErrs(point.Status)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 293, Col: 18}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 414, Col: 18}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var21))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var23))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "</span></td></tr>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "</span></td></tr>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -625,12 +691,12 @@ func LandingPagesTable(pages []LandingPage) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var22 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var22 == nil {
-			templ_7745c5c3_Var22 = templ.NopComponent
+		templ_7745c5c3_Var24 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var24 == nil {
+			templ_7745c5c3_Var24 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "<div class=\"overflow-hidden shadow ring-1 ring-black ring-opacity-5 md:rounded-lg\"><table class=\"min-w-full divide-y divide-gray-300\"><thead class=\"bg-gray-50\"><tr><th scope=\"col\" class=\"relative w-12 px-6 sm:w-16 sm:px-8\"><input type=\"checkbox\" class=\"absolute left-4 top-1/2 -mt-2 h-4 w-4 rounded border-gray-300 text-blue-600 focus:ring-blue-500\" onchange=\"toggleAllCheckboxes(this)\"></th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Fixed URL</th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Landing Page Name</th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Landing Page URL</th></tr></thead> <tbody class=\"bg-white divide-y divide-gray-200\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "<div class=\"overflow-hidden shadow ring-1 ring-black ring-opacity-5 md:rounded-lg\"><table class=\"min-w-full divide-y divide-gray-300\"><thead class=\"bg-gray-50\"><tr><th scope=\"col\" class=\"relative w-12 px-6 sm:w-16 sm:px-8\"><input type=\"checkbox\" class=\"absolute left-4 top-1/2 -mt-2 h-4 w-4 rounded border-gray-300 text-blue-600 focus:ring-blue-500\" onchange=\"toggleAllCheckboxes(this)\"></th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Fixed URL</th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Landing Page Name</th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Landing Page URL</th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">Tags</th><th scope=\"col\" class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">QR Code</th></tr></thead> <tbody class=\"bg-white divide-y divide-gray-200\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -640,7 +706,100 @@ func LandingPagesTable(pages []LandingPage) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "</tbody></table></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "</tbody></table></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// Landing pages matching the search, with the pages of the results
+func LandingPagesSearchResults(pages []LandingPage, page int, hasNext bool) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var25 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var25 == nil {
+			templ_7745c5c3_Var25 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = LandingPagesTable(pages).Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "<div class=\"flex items-center justify-end space-x-2 mt-4\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if page > 1 {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "<button class=\"px-3 py-1 border border-gray-300 rounded-md text-sm text-gray-700 bg-white hover:bg-gray-50\" hx-post=\"/landing-pages/search\" hx-target=\"#landing-pages-table\" hx-swap=\"innerHTML\" hx-include=\"#landing-pages-filters\" hx-vals=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var26 string
+			templ_7745c5c3_Var26, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf(`{"page": %d}`, page-1))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 466, Col: 49}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var26))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "\">Previous</button> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "<span class=\"text-sm text-gray-500\">Page ")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var27 string
+		templ_7745c5c3_Var27, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(page))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 471, Col: 63}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var27))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "</span> ")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if hasNext {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, "<button class=\"px-3 py-1 border border-gray-300 rounded-md text-sm text-gray-700 bg-white hover:bg-gray-50\" hx-post=\"/landing-pages/search\" hx-target=\"#landing-pages-table\" hx-swap=\"innerHTML\" hx-include=\"#landing-pages-filters\" hx-vals=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var28 string
+			templ_7745c5c3_Var28, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf(`{"page": %d}`, page+1))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 479, Col: 49}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var28))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 44, "\">Next</button>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 45, "</div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -665,82 +824,131 @@ func LandingPageRow(page LandingPage, isEven bool) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var23 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var23 == nil {
-			templ_7745c5c3_Var23 = templ.NopComponent
+		templ_7745c5c3_Var29 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var29 == nil {
+			templ_7745c5c3_Var29 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		var templ_7745c5c3_Var24 = []any{templ.KV("bg-white", isEven), templ.KV("bg-gray-50", !isEven)}
-		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var24...)
+		var templ_7745c5c3_Var30 = []any{templ.KV("bg-white", isEven), templ.KV("bg-gray-50", !isEven)}
+		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var30...)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "<tr class=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 46, "<tr class=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var25 string
-		templ_7745c5c3_Var25, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var24).String())
+		var templ_7745c5c3_Var31 string
+		templ_7745c5c3_Var31, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var30).String())
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 1, Col: 0}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var25))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var31))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "\"><td class=\"relative w-12 px-6 sm:w-16 sm:px-8\"><input type=\"checkbox\" name=\"selected\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 47, "\"><td class=\"relative w-12 px-6 sm:w-16 sm:px-8\"><input type=\"checkbox\" name=\"selected\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var26 string
-		templ_7745c5c3_Var26, templ_7745c5c3_Err = templ.JoinStringErrs(page.ID)
+		var templ_7745c5c3_Var32 string
+		templ_7745c5c3_Var32, templ_7745c5c3_Err = templ.JoinStringErrs(page.ID)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 335, Col: 19}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 494, Col: 19}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var26))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var32))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "\" class=\"absolute left-4 top-1/2 -mt-2 h-4 w-4 rounded border-gray-300 text-blue-600 focus:ring-blue-500\" onchange=\"checkForBulkEdit()\"></td><td class=\"px-6 py-4 whitespace-nowrap text-sm text-gray-900\"><div class=\"max-w-xs truncate\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 48, "\" class=\"absolute left-4 top-1/2 -mt-2 h-4 w-4 rounded border-gray-300 text-blue-600 focus:ring-blue-500\" onchange=\"checkForBulkEdit()\"></td><td class=\"px-6 py-4 whitespace-nowrap text-sm text-gray-900\"><div class=\"max-w-xs truncate\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var27 string
-		templ_7745c5c3_Var27, templ_7745c5c3_Err = templ.JoinStringErrs(page.FixedURL)
+		var templ_7745c5c3_Var33 string
+		templ_7745c5c3_Var33, templ_7745c5c3_Err = templ.JoinStringErrs(page.FixedURL)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 342, Col: 19}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 501, Col: 19}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var27))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var33))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 49, "</div></td><td class=\"px-6 py-4 whitespace-nowrap text-sm text-gray-900\"><div class=\"max-w-xs truncate\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var34 string
+		templ_7745c5c3_Var34, templ_7745c5c3_Err = templ.JoinStringErrs(page.LandingPageName)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 506, Col: 26}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var34))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "</div></td><td class=\"px-6 py-4 whitespace-nowrap text-sm text-gray-900\"><div class=\"max-w-xs truncate\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 50, "</div></td><td class=\"px-6 py-4 whitespace-nowrap text-sm text-gray-500\"><div class=\"max-w-md truncate\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var28 string
-		templ_7745c5c3_Var28, templ_7745c5c3_Err = templ.JoinStringErrs(page.LandingPageName)
+		var templ_7745c5c3_Var35 string
+		templ_7745c5c3_Var35, templ_7745c5c3_Err = templ.JoinStringErrs(page.LandingPageURL)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 347, Col: 26}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 511, Col: 25}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var28))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var35))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "</div></td><td class=\"px-6 py-4 whitespace-nowrap text-sm text-gray-500\"><div class=\"max-w-md truncate\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 51, "</div></td><td class=\"px-6 py-4 whitespace-nowrap text-sm\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var29 string
-		templ_7745c5c3_Var29, templ_7745c5c3_Err = templ.JoinStringErrs(page.LandingPageURL)
+		for _, tag := range page.Tags {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 52, "<span class=\"inline-flex mr-1 px-2 py-1 text-xs font-semibold rounded-full bg-blue-100 text-blue-800\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var36 string
+			templ_7745c5c3_Var36, templ_7745c5c3_Err = templ.JoinStringErrs(tag)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 516, Col: 111}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var36))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 53, "</span>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 54, "</td><td class=\"px-6 py-4 whitespace-nowrap text-sm\"><a href=\"")
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 352, Col: 25}
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var37 templ.SafeURL
+		templ_7745c5c3_Var37, templ_7745c5c3_Err = templ.JoinURLErrs(templ.SafeURL("/landing-pages/" + page.ID + "/qr?format=png"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 520, Col: 74}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var29))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var37))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "</div></td></tr>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 55, "\" class=\"text-blue-600 hover:text-blue-900\" download>PNG</a> <a href=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var38 templ.SafeURL
+		templ_7745c5c3_Var38, templ_7745c5c3_Err = templ.JoinURLErrs(templ.SafeURL("/landing-pages/" + page.ID + "/qr?format=svg"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 521, Col: 74}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var38))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 56, "\" class=\"ml-2 text-blue-600 hover:text-blue-900\" download>SVG</a></td></tr>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -765,12 +973,12 @@ func AddModal() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var30 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var30 == nil {
-			templ_7745c5c3_Var30 = templ.NopComponent
+		templ_7745c5c3_Var39 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var39 == nil {
+			templ_7745c5c3_Var39 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "<div id=\"addModal\" class=\"modal fixed inset-0 bg-gray-600 bg-opacity-50 overflow-y-auto h-full w-full items-center justify-center\"><div class=\"relative p-5 border w-96 shadow-lg rounded-md bg-white\"><div class=\"mt-3\"><h3 class=\"text-lg font-medium text-gray-900 mb-4\">Add Conversion Point</h3><form hx-post=\"/add\" hx-target=\"#conversion-table\" hx-swap=\"innerHTML\" onsubmit=\"hideAddModal()\"><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">Name</label> <input type=\"text\" name=\"name\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" required></div><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">URL</label> <input type=\"url\" name=\"url\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" required></div><div class=\"flex items-center justify-end space-x-3\"><button type=\"button\" onclick=\"hideAddModal()\" class=\"px-4 py-2 text-sm font-medium text-gray-700 bg-white border border-gray-300 rounded-md hover:bg-gray-50\">Cancel</button> <button type=\"submit\" class=\"px-4 py-2 text-sm font-medium text-white bg-blue-600 border border-transparent rounded-md hover:bg-blue-700\">Add</button></div></form></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 57, "<div id=\"addModal\" class=\"modal fixed inset-0 bg-gray-600 bg-opacity-50 overflow-y-auto h-full w-full items-center justify-center\"><div class=\"relative p-5 border w-96 shadow-lg rounded-md bg-white\"><div class=\"mt-3\"><h3 class=\"text-lg font-medium text-gray-900 mb-4\">Add Conversion Point</h3><form hx-post=\"/add\" hx-target=\"#conversion-table\" hx-swap=\"innerHTML\" onsubmit=\"hideAddModal()\"><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">Name</label> <input type=\"text\" name=\"name\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" required></div><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">URL</label> <input type=\"url\" name=\"url\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" required></div><div class=\"flex items-center justify-end space-x-3\"><button type=\"button\" onclick=\"hideAddModal()\" class=\"px-4 py-2 text-sm font-medium text-gray-700 bg-white border border-gray-300 rounded-md hover:bg-gray-50\">Cancel</button> <button type=\"submit\" class=\"px-4 py-2 text-sm font-medium text-white bg-blue-600 border border-transparent rounded-md hover:bg-blue-700\">Add</button></div></form></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -795,12 +1003,12 @@ func AddLandingPageModal() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var31 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var31 == nil {
-			templ_7745c5c3_Var31 = templ.NopComponent
+		templ_7745c5c3_Var40 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var40 == nil {
+			templ_7745c5c3_Var40 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "<div id=\"addLandingPageModal\" class=\"modal fixed inset-0 bg-gray-600 bg-opacity-50 overflow-y-auto h-full w-full items-center justify-center\"><div class=\"relative p-5 border w-96 shadow-lg rounded-md bg-white\"><div class=\"mt-3\"><h3 class=\"text-lg font-medium text-gray-900 mb-4\">Add Landing Page</h3><form hx-post=\"/landing-pages/add\" hx-target=\"#landing-pages-table\" hx-swap=\"innerHTML\" onsubmit=\"hideLandingPageModal()\"><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">Landing Page Name</label> <input type=\"text\" name=\"landing_page_name\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" required></div><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">Landing Page URL</label> <input type=\"url\" name=\"landing_page_url\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" required></div><div class=\"flex items-center justify-end space-x-3\"><button type=\"button\" onclick=\"hideLandingPageModal()\" class=\"px-4 py-2 text-sm font-medium text-gray-700 bg-white border border-gray-300 rounded-md hover:bg-gray-50\">Cancel</button> <button type=\"submit\" class=\"px-4 py-2 text-sm font-medium text-white bg-blue-600 border border-transparent rounded-md hover:bg-blue-700\">Add</button></div></form></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 58, "<div id=\"addLandingPageModal\" class=\"modal fixed inset-0 bg-gray-600 bg-opacity-50 overflow-y-auto h-full w-full items-center justify-center\"><div class=\"relative p-5 border w-96 shadow-lg rounded-md bg-white\"><div class=\"mt-3\"><h3 class=\"text-lg font-medium text-gray-900 mb-4\">Add Landing Page</h3><form hx-post=\"/landing-pages/add\" hx-target=\"#landing-pages-table\" hx-swap=\"innerHTML\" onsubmit=\"hideLandingPageModal()\"><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">Landing Page Name</label> <input type=\"text\" name=\"landing_page_name\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" required></div><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">Landing Page URL</label> <input type=\"url\" name=\"landing_page_url\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" required></div><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">Custom Slug (optional)</label> <input type=\"text\" name=\"slug\" pattern=\"[A-Za-z0-9][A-Za-z0-9_\\-]{2,63}\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\"></div><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">Tags (optional, comma separated)</label> <input type=\"text\" name=\"tags\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\"></div><div class=\"flex items-center justify-end space-x-3\"><button type=\"button\" onclick=\"hideLandingPageModal()\" class=\"px-4 py-2 text-sm font-medium text-gray-700 bg-white border border-gray-300 rounded-md hover:bg-gray-50\">Cancel</button> <button type=\"submit\" class=\"px-4 py-2 text-sm font-medium text-white bg-blue-600 border border-transparent rounded-md hover:bg-blue-700\">Add</button></div></form></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -825,12 +1033,42 @@ func EditLandingPageModal() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var32 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var32 == nil {
-			templ_7745c5c3_Var32 = templ.NopComponent
+		templ_7745c5c3_Var41 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var41 == nil {
+			templ_7745c5c3_Var41 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 59, "<div id=\"editLandingPageModal\" class=\"modal fixed inset-0 bg-gray-600 bg-opacity-50 overflow-y-auto h-full w-full items-center justify-center\"><div class=\"relative p-5 border w-96 shadow-lg rounded-md bg-white\"><div class=\"mt-3\"><h3 class=\"text-lg font-medium text-gray-900 mb-4\">Edit Landing Page</h3><form id=\"editLandingPageForm\" hx-target=\"#landing-pages-table\" hx-swap=\"innerHTML\" onsubmit=\"hideEditLandingPageModal()\"><input type=\"hidden\" id=\"editLandingPageId\" name=\"id\"><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">Landing Page Name</label> <input type=\"text\" id=\"editLandingPageName\" name=\"landing_page_name\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" placeholder=\"Enter landing page name\" required></div><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">Landing Page URL</label> <input type=\"text\" id=\"editLandingPageUrl\" name=\"landing_page_url\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" placeholder=\"Enter landing page URL\" required></div><div class=\"flex items-center justify-end space-x-3\"><button type=\"button\" onclick=\"hideEditLandingPageModal()\" class=\"px-4 py-2 text-sm font-medium text-gray-700 bg-white border border-gray-300 rounded-md hover:bg-gray-50\">Cancel</button> <button type=\"submit\" class=\"px-4 py-2 text-sm font-medium text-white bg-blue-600 border border-transparent rounded-md hover:bg-blue-700\">Save Changes</button></div></form></div></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// Import landing pages modal
+func ImportLandingPagesModal() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var42 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var42 == nil {
+			templ_7745c5c3_Var42 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "<div id=\"editLandingPageModal\" class=\"modal fixed inset-0 bg-gray-600 bg-opacity-50 overflow-y-auto h-full w-full items-center justify-center\"><div class=\"relative p-5 border w-96 shadow-lg rounded-md bg-white\"><div class=\"mt-3\"><h3 class=\"text-lg font-medium text-gray-900 mb-4\">Edit Landing Page</h3><form id=\"editLandingPageForm\" hx-target=\"#landing-pages-table\" hx-swap=\"innerHTML\" onsubmit=\"hideEditLandingPageModal()\"><input type=\"hidden\" id=\"editLandingPageId\" name=\"id\"><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">Landing Page Name</label> <input type=\"text\" id=\"editLandingPageName\" name=\"landing_page_name\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" placeholder=\"Enter landing page name\" required></div><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">Landing Page URL</label> <input type=\"text\" id=\"editLandingPageUrl\" name=\"landing_page_url\" class=\"w-full border border-gray-300 rounded-md px-3 py-2 focus:ring-blue-500 focus:border-blue-500\" placeholder=\"Enter landing page URL\" required></div><div class=\"flex items-center justify-end space-x-3\"><button type=\"button\" onclick=\"hideEditLandingPageModal()\" class=\"px-4 py-2 text-sm font-medium text-gray-700 bg-white border border-gray-300 rounded-md hover:bg-gray-50\">Cancel</button> <button type=\"submit\" class=\"px-4 py-2 text-sm font-medium text-white bg-blue-600 border border-transparent rounded-md hover:bg-blue-700\">Save Changes</button></div></form></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 60, "<div id=\"importLandingPagesModal\" class=\"modal fixed inset-0 bg-gray-600 bg-opacity-50 overflow-y-auto h-full w-full items-center justify-center\"><div class=\"relative p-5 border w-96 shadow-lg rounded-md bg-white\"><div class=\"mt-3\"><h3 class=\"text-lg font-medium text-gray-900 mb-4\">Import Landing Pages</h3><p class=\"text-sm text-gray-600 mb-4\">A CSV with name and url columns, and optionally id, slug, domain, tags (separated by |) and campaign_id, or a JSON export. Rows with an id update their landing page.</p><form hx-post=\"/landing-pages/import\" hx-encoding=\"multipart/form-data\" hx-target=\"#landing-pages-import-report\" hx-swap=\"innerHTML\"><div class=\"mb-4\"><input type=\"file\" name=\"file\" accept=\".csv,.json\" class=\"w-full text-sm text-gray-700\" required></div><div class=\"mb-4\"><label class=\"inline-flex items-center text-sm text-gray-700\"><input type=\"checkbox\" name=\"atomic\" value=\"true\" class=\"h-4 w-4 mr-2 rounded border-gray-300 text-blue-600 focus:ring-blue-500\"> Import nothing if a row fails</label></div><div id=\"landing-pages-import-report\" class=\"mb-4\"></div><div class=\"flex items-center justify-end space-x-3\"><button type=\"button\" onclick=\"hideImportLandingPagesModal()\" class=\"px-4 py-2 text-sm font-medium text-gray-700 bg-white border border-gray-300 rounded-md hover:bg-gray-50\">Close</button> <button type=\"submit\" class=\"px-4 py-2 text-sm font-medium text-white bg-blue-600 border border-transparent rounded-md hover:bg-blue-700\">Import</button></div></form></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -838,6 +1076,130 @@ func EditLandingPageModal() templ.Component {
 	})
 }
 
+// Import report, the landing pages table is refreshed when the import was written
+func LandingPagesImportReport(report LandingPageImportReport, pages []LandingPage) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var43 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var43 == nil {
+			templ_7745c5c3_Var43 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if report.Message != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 61, "<p class=\"text-sm text-red-600\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var44 string
+			templ_7745c5c3_Var44, templ_7745c5c3_Err = templ.JoinStringErrs(report.Message)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 673, Col: 50}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var44))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 62, "</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		} else if report.Applied {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 63, "<p class=\"text-sm text-green-700\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var45 string
+			templ_7745c5c3_Var45, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d created, %d updated, %d failed", report.Created, report.Updated, report.Failed))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 675, Col: 133}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var45))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 64, "</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		} else {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 65, "<p class=\"text-sm text-red-600\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var46 string
+			templ_7745c5c3_Var46, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("Nothing was imported, %d rows failed", report.Failed))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 677, Col: 102}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var46))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 66, "</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		if len(report.Errors) > 0 {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 67, "<ul class=\"mt-2 max-h-48 overflow-y-auto text-sm text-red-600\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			for _, line := range report.Errors {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 68, "<li>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var47 string
+				templ_7745c5c3_Var47, templ_7745c5c3_Err = templ.JoinStringErrs(line)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/index.templ`, Line: 682, Col: 14}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var47))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 69, "</li>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 70, "</ul>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		if report.Applied {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 71, "<div id=\"landing-pages-table\" hx-swap-oob=\"innerHTML\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = LandingPagesTable(pages).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 72, "</div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		return nil
+	})
+}
+
 // JavaScript functions
 func Scripts() templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
@@ -855,12 +1217,12 @@ func Scripts() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var33 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var33 == nil {
-			templ_7745c5c3_Var33 = templ.NopComponent
+		templ_7745c5c3_Var48 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var48 == nil {
+			templ_7745c5c3_Var48 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "<script>\n\t\tfunction showAddModal() {\n\t\t\tdocument.getElementById('addModal').classList.add('show');\n\t\t}\n\n\t\tfunction hideAddModal() {\n\t\t\tdocument.getElementById('addModal').classList.remove('show');\n\t\t}\n\n\t\tfunction showLandingPageModal() {\n\t\t\tdocument.getElementById('addLandingPageModal').classList.add('show');\n\t\t}\n\n\t\tfunction hideLandingPageModal() {\n\t\t\tdocument.getElementById('addLandingPageModal').classList.remove('show');\n\t\t}\n\n\t\tfunction checkForBulkEdit() {\n\t\t\t// const selected = getSelectedLandingPages();\n\t\t\t// // Show bulk edit dialog if more than one item is selected\n\t\t\t// if (selected.length > 1) {\n\t\t\t// \tsetTimeout(() => showBulkEditLandingPageModal(), 100);\n\t\t\t// }\n\t\t}\n\n\t\tfunction getSelectedLandingPages() {\n\t\t\tconst checkboxes = document.querySelectorAll('input[name=\"selected\"]:checked');\n\t\t\treturn Array.from(checkboxes).map(cb => cb.value);\n\t\t}\n\n\t\t// Simple client-side tab switching with lazy loading\n\t\tfunction switchTab(tabName) {\n\t\t\t// Hide all tab contents\n\t\t\tconst tabContents = document.querySelectorAll('.tab-content');\n\t\t\ttabContents.forEach(content => content.style.display = 'none');\n\t\t\t\n\t\t\t// Remove active class from all tabs\n\t\t\tconst tabs = document.querySelectorAll('#conversion-tab, #landing-pages-tab');\n\t\t\ttabs.forEach(tab => {\n\t\t\t\ttab.classList.remove('sub-tab-active');\n\t\t\t\ttab.classList.add('text-gray-500', 'hover:text-gray-700');\n\t\t\t});\n\t\t\t\n\t\t\t// Show selected tab content\n\t\t\tconst targetContent = document.getElementById(tabName + '-content');\n\t\t\ttargetContent.style.display = 'block';\n\t\t\t\n\t\t\t// Activate selected tab\n\t\t\tconst activeTab = document.getElementById(tabName + '-tab');\n\t\t\tactiveTab.classList.add('sub-tab-active');\n\t\t\tactiveTab.classList.remove('text-gray-500', 'hover:text-gray-700');\n\t\t\t\n\t\t\t// Lazy load landing pages data when first accessed\n\t\t\tif (tabName === 'landing-pages') {\n\t\t\t\tconst landingPagesContent = targetContent.innerHTML;\n\t\t\t\tif (landingPagesContent.includes('Loading landing pages...')) {\n\t\t\t\t\tconsole.log('Loading landing pages data...');\n\t\t\t\t\t// Use HTMX to load the landing pages content\n\t\t\t\t\thtmx.ajax('GET', '/landing-pages', {\n\t\t\t\t\t\ttarget: '#landing-pages-content',\n\t\t\t\t\t\tswap: 'innerHTML'\n\t\t\t\t\t});\n\t\t\t\t}\n\t\t\t}\n\t\t}\n\n\t\tfunction showEditLandingPageModal(id, landingPageName, landingPageUrl) {\n\t\t\tconsole.log('Opening edit modal with data:', {id, landingPageName, landingPageUrl});\n\t\t\t\n\t\t\tdocument.getElementById('editLandingPageId').value = id;\n\t\t\tdocument.getElementById('editLandingPageName').value = landingPageName;\n\t\t\tdocument.getElementById('editLandingPageUrl').value = landingPageUrl;\n\t\t\t\t\t\t\n\t\t\t// Set the form action\n\t\t\tdocument.getElementById('editLandingPageForm').setAttribute('hx-post', '/landing-pages/edit/' + id);\n\t\t\t\n\t\t\tdocument.getElementById('editLandingPageModal').classList.add('show');\n\t\t\t\n\t\t\t// Focus on the first field to test editability\n\t\t\tsetTimeout(() => {\n\t\t\t\tdocument.getElementById('editLandingPageName').focus();\n\t\t\t\tconsole.log('Fixed URL field focused');\n\t\t\t}, 100);\n\t\t}\n\n\t\tfunction hideEditLandingPageModal() {\n\t\t\tdocument.getElementById('editLandingPageModal').classList.remove('show');\n\t\t}\n\n\t\t// Event delegation for edit buttons\n\t\tdocument.addEventListener('click', function(e) {\n\t\t\tif (e.target.classList.contains('edit-landing-page-btn')) {\n\t\t\t\tconsole.log('Edit button clicked!'); // Debug log\n\t\t\t\tconst id = e.target.getAttribute('data-id');\n\t\t\t\tconst fixedUrl = e.target.getAttribute('data-fixed-url');\n\t\t\t\tconst landingPageName = e.target.getAttribute('data-landing-page-name');\n\t\t\t\tconst landingPageUrl = e.target.getAttribute('data-landing-page-url');\n\t\t\t\tconst status = e.target.getAttribute('data-status');\n\t\t\t\t\n\t\t\t\tconsole.log('Data:', {id, landingPageName, landingPageUrl}); // Debug log\n\t\t\t\t\n\t\t\t\tshowEditLandingPageModal(id, landingPageName, landingPageUrl);\n\t\t\t}\n\t\t});\n\n\n\t\t// Initialize HTMX for dynamically loaded content\n\t\tdocument.addEventListener('htmx:afterSwap', function(event) {\n\t\t\t// Re-process any new content for HTMX\n\t\t\thtmx.process(event.detail.target);\n\t\t});\n\n\t\tfunction toggleAllCheckboxes(source) {\n\t\t\tconst checkboxes = document.querySelectorAll('input[name=\"selected\"]');\n\t\t\tcheckboxes.forEach(checkbox => {\n\t\t\t\tcheckbox.checked = source.checked;\n\t\t\t});\n\t\t\t\n\t\t\t// Check for bulk edit after toggling all\n\t\t\tif (source.checked) {\n\t\t\t\tcheckForBulkEdit();\n\t\t\t}\n\t\t}\n\n\t\t// Close modals when clicking outside\n\t\tdocument.getElementById('addModal').addEventListener('click', function(e) {\n\t\t\tif (e.target === this) {\n\t\t\t\thideAddModal();\n\t\t\t}\n\t\t});\n\n\t\tdocument.getElementById('addLandingPageModal').addEventListener('click', function(e) {\n\t\t\tif (e.target === this) {\n\t\t\t\thideLandingPageModal();\n\t\t\t}\n\t\t});\n\n\t\tdocument.getElementById('editLandingPageModal').addEventListener('click', function(e) {\n\t\t\tif (e.target === this) {\n\t\t\t\thideEditLandingPageModal();\n\t\t\t}\n\t\t});\n\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 73, "<script>\n\t\tfunction showAddModal() {\n\t\t\tdocument.getElementById('addModal').classList.add('show');\n\t\t}\n\n\t\tfunction hideAddModal() {\n\t\t\tdocument.getElementById('addModal').classList.remove('show');\n\t\t}\n\n\t\tfunction showLandingPageModal() {\n\t\t\tdocument.getElementById('addLandingPageModal').classList.add('show');\n\t\t}\n\n\t\tfunction hideLandingPageModal() {\n\t\t\tdocument.getElementById('addLandingPageModal').classList.remove('show');\n\t\t}\n\n\t\tfunction showImportLandingPagesModal() {\n\t\t\tdocument.getElementById('landing-pages-import-report').innerHTML = '';\n\t\t\tdocument.getElementById('importLandingPagesModal').classList.add('show');\n\t\t}\n\n\t\tfunction hideImportLandingPagesModal() {\n\t\t\tdocument.getElementById('importLandingPagesModal').classList.remove('show');\n\t\t}\n\n\t\tfunction checkForBulkEdit() {\n\t\t\t// const selected = getSelectedLandingPages();\n\t\t\t// // Show bulk edit dialog if more than one item is selected\n\t\t\t// if (selected.length > 1) {\n\t\t\t// \tsetTimeout(() => showBulkEditLandingPageModal(), 100);\n\t\t\t// }\n\t\t}\n\n\t\tfunction getSelectedLandingPages() {\n\t\t\tconst checkboxes = document.querySelectorAll('input[name=\"selected\"]:checked');\n\t\t\treturn Array.from(checkboxes).map(cb => cb.value);\n\t\t}\n\n\t\t// Simple client-side tab switching with lazy loading\n\t\tfunction switchTab(tabName) {\n\t\t\t// Hide all tab contents\n\t\t\tconst tabContents = document.querySelectorAll('.tab-content');\n\t\t\ttabContents.forEach(content => content.style.display = 'none');\n\t\t\t\n\t\t\t// Remove active class from all tabs\n\t\t\tconst tabs = document.querySelectorAll('#conversion-tab, #landing-pages-tab, #domains-tab, #dashboard-tab, #funnels-tab');\n\t\t\ttabs.forEach(tab => {\n\t\t\t\ttab.classList.remove('sub-tab-active');\n\t\t\t\ttab.classList.add('text-gray-500', 'hover:text-gray-700');\n\t\t\t});\n\t\t\t\n\t\t\t// Show selected tab content\n\t\t\tconst targetContent = document.getElementById(tabName + '-content');\n\t\t\ttargetContent.style.display = 'block';\n\t\t\t\n\t\t\t// Activate selected tab\n\t\t\tconst activeTab = document.getElementById(tabName + '-tab');\n\t\t\tactiveTab.classList.add('sub-tab-active');\n\t\t\tactiveTab.classList.remove('text-gray-500', 'hover:text-gray-700');\n\t\t\t\n\t\t\t// Lazy load landing pages data when first accessed\n\t\t\tif (tabName === 'landing-pages') {\n\t\t\t\tconst landingPagesContent = targetContent.innerHTML;\n\t\t\t\tif (landingPagesContent.includes('Loading landing pages...')) {\n\t\t\t\t\tconsole.log('Loading landing pages data...');\n\t\t\t\t\t// Use HTMX to load the landing pages content\n\t\t\t\t\thtmx.ajax('GET', '/landing-pages', {\n\t\t\t\t\t\ttarget: '#landing-pages-content',\n\t\t\t\t\t\tswap: 'innerHTML'\n\t\t\t\t\t});\n\t\t\t\t}\n\t\t\t}\n\n\t\t\tif (tabName === 'domains' && targetContent.innerHTML.includes('Loading domains...')) {\n\t\t\t\thtmx.ajax('GET', '/domains', {\n\t\t\t\t\ttarget: '#domains-content',\n\t\t\t\t\tswap: 'innerHTML'\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tif (tabName === 'dashboard' && targetContent.innerHTML.includes('Loading dashboard...')) {\n\t\t\t\thtmx.ajax('GET', '/dashboard', {\n\t\t\t\t\ttarget: '#dashboard-content',\n\t\t\t\t\tswap: 'innerHTML'\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tif (tabName === 'funnels' && targetContent.innerHTML.includes('Loading funnels...')) {\n\t\t\t\thtmx.ajax('GET', '/funnels', {\n\t\t\t\t\ttarget: '#funnels-content',\n\t\t\t\t\tswap: 'innerHTML'\n\t\t\t\t});\n\t\t\t}\n\t\t}\n\n\t\tfunction showEditLandingPageModal(id, landingPageName, landingPageUrl) {\n\t\t\tconsole.log('Opening edit modal with data:', {id, landingPageName, landingPageUrl});\n\t\t\t\n\t\t\tdocument.getElementById('editLandingPageId').value = id;\n\t\t\tdocument.getElementById('editLandingPageName').value = landingPageName;\n\t\t\tdocument.getElementById('editLandingPageUrl').value = landingPageUrl;\n\t\t\t\t\t\t\n\t\t\t// Set the form action\n\t\t\tdocument.getElementById('editLandingPageForm').setAttribute('hx-post', '/landing-pages/edit/' + id);\n\t\t\t\n\t\t\tdocument.getElementById('editLandingPageModal').classList.add('show');\n\t\t\t\n\t\t\t// Focus on the first field to test editability\n\t\t\tsetTimeout(() => {\n\t\t\t\tdocument.getElementById('editLandingPageName').focus();\n\t\t\t\tconsole.log('Fixed URL field focused');\n\t\t\t}, 100);\n\t\t}\n\n\t\tfunction hideEditLandingPageModal() {\n\t\t\tdocument.getElementById('editLandingPageModal').classList.remove('show');\n\t\t}\n\n\t\t// Event delegation for edit buttons\n\t\tdocument.addEventListener('click', function(e) {\n\t\t\tif (e.target.classList.contains('edit-landing-page-btn')) {\n\t\t\t\tconsole.log('Edit button clicked!'); // Debug log\n\t\t\t\tconst id = e.target.getAttribute('data-id');\n\t\t\t\tconst fixedUrl = e.target.getAttribute('data-fixed-url');\n\t\t\t\tconst landingPageName = e.target.getAttribute('data-landing-page-name');\n\t\t\t\tconst landingPageUrl = e.target.getAttribute('data-landing-page-url');\n\t\t\t\tconst status = e.target.getAttribute('data-status');\n\t\t\t\t\n\t\t\t\tconsole.log('Data:', {id, landingPageName, landingPageUrl}); // Debug log\n\t\t\t\t\n\t\t\t\tshowEditLandingPageModal(id, landingPageName, landingPageUrl);\n\t\t\t}\n\t\t});\n\n\n\t\t// Initialize HTMX for dynamically loaded content\n\t\tdocument.addEventListener('htmx:afterSwap', function(event) {\n\t\t\t// Re-process any new content for HTMX\n\t\t\thtmx.process(event.detail.target);\n\t\t});\n\n\t\tfunction toggleAllCheckboxes(source) {\n\t\t\tconst checkboxes = document.querySelectorAll('input[name=\"selected\"]');\n\t\t\tcheckboxes.forEach(checkbox => {\n\t\t\t\tcheckbox.checked = source.checked;\n\t\t\t});\n\t\t\t\n\t\t\t// Check for bulk edit after toggling all\n\t\t\tif (source.checked) {\n\t\t\t\tcheckForBulkEdit();\n\t\t\t}\n\t\t}\n\n\t\t// Close modals when clicking outside\n\t\tdocument.getElementById('addModal').addEventListener('click', function(e) {\n\t\t\tif (e.target === this) {\n\t\t\t\thideAddModal();\n\t\t\t}\n\t\t});\n\n\t\tdocument.getElementById('addLandingPageModal').addEventListener('click', function(e) {\n\t\t\tif (e.target === this) {\n\t\t\t\thideLandingPageModal();\n\t\t\t}\n\t\t});\n\n\t\tdocument.getElementById('editLandingPageModal').addEventListener('click', function(e) {\n\t\t\tif (e.target === this) {\n\t\t\t\thideEditLandingPageModal();\n\t\t\t}\n\t\t});\n\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}